@@ -9,6 +9,7 @@ var subCommandFuncs = []func() *cobra.Command{
 	listCmd,
 	detailsCmd,
 	deleteCmd,
+	retentionCmd,
 }
 
 var serviceCommands = []func(cmd *cobra.Command) *cobra.Command{
@@ -30,6 +31,8 @@ func AddCommands(cmd *cobra.Command) {
 			addBackupTo(subCommand)
 		}
 	}
+
+	addPruneCommand(backupC)
 }
 
 // The backup category of commands.
@@ -60,10 +60,11 @@ const (
 )
 
 const (
-	exchangeServiceCommand                 = "exchange"
-	exchangeServiceCommandCreateUseSuffix  = "--user <userId or email> | '" + utils.Wildcard + "'"
-	exchangeServiceCommandDeleteUseSuffix  = "--backup <backupId>"
-	exchangeServiceCommandDetailsUseSuffix = "--backup <backupId>"
+	exchangeServiceCommand                   = "exchange"
+	exchangeServiceCommandCreateUseSuffix    = "--user <userId or email> | '" + utils.Wildcard + "'"
+	exchangeServiceCommandDeleteUseSuffix    = "--backup <backupId>"
+	exchangeServiceCommandRetentionUseSuffix = "[--user <userId or email> | '" + utils.Wildcard + "']"
+	exchangeServiceCommandDetailsUseSuffix   = "--backup <backupId>"
 )
 
 const (
@@ -79,6 +80,18 @@ corso backup create exchange --user '*'`
 	exchangeServiceCommandDeleteExamples = `# Delete Exchange backup with ID 1234abcd-12ab-cd34-56de-1234abcd
 corso backup delete exchange --backup 1234abcd-12ab-cd34-56de-1234abcd`
 
+	exchangeServiceCommandRetentionExamples = `# Keep the last 7 daily and 4 weekly backups for every Exchange user
+corso backup retention exchange --keep-daily 7 --keep-weekly 4
+
+# Keep the last 10 Exchange backups for alice@example.com
+corso backup retention exchange --user alice@example.com --keep-last 10
+
+# Remove the retention policy for alice@example.com
+corso backup retention exchange --user alice@example.com --clear
+
+# List the Exchange retention policies
+corso backup retention exchange`
+
 	exchangeServiceCommandDetailsExamples = `# Explore Alice's items in backup 1234abcd-12ab-cd34-56de-1234abcd 
 corso backup details exchange --backup 1234abcd-12ab-cd34-56de-1234abcd --user alice@example.com
 
@@ -227,6 +240,18 @@ func addExchangeCommands(cmd *cobra.Command) *cobra.Command {
 			utils.BackupFN, "",
 			"ID of the backup to delete. (required)")
 		cobra.CheckErr(c.MarkFlagRequired(utils.BackupFN))
+
+	case retentionCommand:
+		c, fs = utils.AddCommand(cmd, exchangeRetentionCmd())
+
+		c.Use = c.Use + " " + exchangeServiceCommandRetentionUseSuffix
+		c.Example = exchangeServiceCommandRetentionExamples
+
+		fs.StringSliceVar(
+			&user,
+			utils.UserFN, nil,
+			"Apply the retention policy to a user ID; defaults to every user in the service.")
+		addRetentionFlags(fs)
 	}
 
 	return c
@@ -553,3 +578,22 @@ func deleteExchangeCmd(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// ------------------------------------------------------------------------------------------------
+// backup retention
+// ------------------------------------------------------------------------------------------------
+
+// `corso backup retention exchange [<flag>...]`
+func exchangeRetentionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   exchangeServiceCommand,
+		Short: "Manage the retention policies for M365 Exchange service backups",
+		RunE:  retentionExchangeCmd,
+		Args:  cobra.NoArgs,
+	}
+}
+
+// sets, clears, or lists the Exchange retention policies.
+func retentionExchangeCmd(cmd *cobra.Command, args []string) error {
+	return runRetentionCmd(cmd, path.ExchangeService, user)
+}
@@ -47,6 +47,10 @@ func (suite *ExchangeSuite) TestAddExchangeCommands() {
 			"delete exchange", deleteCommand, expectUse + " " + exchangeServiceCommandDeleteUseSuffix,
 			exchangeDeleteCmd().Short, deleteExchangeCmd,
 		},
+		{
+			"retention exchange", retentionCommand, expectUse + " " + exchangeServiceCommandRetentionUseSuffix,
+			exchangeRetentionCmd().Short, retentionExchangeCmd,
+		},
 	}
 	for _, test := range table {
 		suite.T().Run(test.name, func(t *testing.T) {
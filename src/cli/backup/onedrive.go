@@ -28,10 +28,11 @@ import (
 // ------------------------------------------------------------------------------------------------
 
 const (
-	oneDriveServiceCommand                 = "onedrive"
-	oneDriveServiceCommandCreateUseSuffix  = "--user <userId or email> | '" + utils.Wildcard + "'"
-	oneDriveServiceCommandDeleteUseSuffix  = "--backup <backupId>"
-	oneDriveServiceCommandDetailsUseSuffix = "--backup <backupId>"
+	oneDriveServiceCommand                   = "onedrive"
+	oneDriveServiceCommandCreateUseSuffix    = "--user <userId or email> | '" + utils.Wildcard + "'"
+	oneDriveServiceCommandDeleteUseSuffix    = "--backup <backupId>"
+	oneDriveServiceCommandRetentionUseSuffix = "[--user <userId or email> | '" + utils.Wildcard + "']"
+	oneDriveServiceCommandDetailsUseSuffix   = "--backup <backupId>"
 )
 
 const (
@@ -42,11 +43,26 @@ corso backup create onedrive --user alice@example.com
 corso backup create onedrive --user alice@example.com,bob@example.com
 
 # Backup all OneDrive data for all M365 users 
-corso backup create onedrive --user '*'`
+corso backup create onedrive --user '*'
+
+# Backup OneDrive data for Alice, including the prior versions of her files
+corso backup create onedrive --user alice@example.com --include-versions`
 
 	oneDriveServiceCommandDeleteExamples = `# Delete OneDrive backup with ID 1234abcd-12ab-cd34-56de-1234abcd
 corso backup delete onedrive --backup 1234abcd-12ab-cd34-56de-1234abcd`
 
+	oneDriveServiceCommandRetentionExamples = `# Keep the last 7 daily and 4 weekly backups for every OneDrive user
+corso backup retention onedrive --keep-daily 7 --keep-weekly 4
+
+# Keep the last 10 OneDrive backups for alice@example.com
+corso backup retention onedrive --user alice@example.com --keep-last 10
+
+# Remove the retention policy for alice@example.com
+corso backup retention onedrive --user alice@example.com --clear
+
+# List the OneDrive retention policies
+corso backup retention onedrive`
+
 	oneDriveServiceCommandDetailsExamples = `# Explore Alice's files from backup 1234abcd-12ab-cd34-56de-1234abcd 
 corso backup details onedrive --backup 1234abcd-12ab-cd34-56de-1234abcd --user alice@example.com
 
@@ -87,6 +103,7 @@ func addOneDriveCommands(cmd *cobra.Command) *cobra.Command {
 			utils.UserFN, nil,
 			"Backup OneDrive data by user ID; accepts '"+utils.Wildcard+"' to select all users. (required)")
 		options.AddOperationFlags(c)
+		options.AddVersionsBackupFlags(c)
 
 	case listCommand:
 		c, fs = utils.AddCommand(cmd, oneDriveListCmd())
@@ -148,6 +165,18 @@ func addOneDriveCommands(cmd *cobra.Command) *cobra.Command {
 			utils.BackupFN, "",
 			"ID of the backup to delete. (required)")
 		cobra.CheckErr(c.MarkFlagRequired(utils.BackupFN))
+
+	case retentionCommand:
+		c, fs = utils.AddCommand(cmd, oneDriveRetentionCmd())
+
+		c.Use = c.Use + " " + oneDriveServiceCommandRetentionUseSuffix
+		c.Example = oneDriveServiceCommandRetentionExamples
+
+		fs.StringSliceVar(
+			&user,
+			utils.UserFN, nil,
+			"Apply the retention policy to a user ID; defaults to every user in the service.")
+		addRetentionFlags(fs)
 	}
 
 	return c
@@ -441,3 +470,22 @@ func deleteOneDriveCmd(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// ------------------------------------------------------------------------------------------------
+// backup retention
+// ------------------------------------------------------------------------------------------------
+
+// `corso backup retention onedrive [<flag>...]`
+func oneDriveRetentionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   oneDriveServiceCommand,
+		Short: "Manage the retention policies for M365 OneDrive service backups",
+		RunE:  retentionOneDriveCmd,
+		Args:  cobra.NoArgs,
+	}
+}
+
+// sets, clears, or lists the OneDrive retention policies.
+func retentionOneDriveCmd(cmd *cobra.Command, args []string) error {
+	return runRetentionCmd(cmd, path.OneDriveService, user)
+}
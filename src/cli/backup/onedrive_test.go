@@ -46,6 +46,10 @@ func (suite *OneDriveSuite) TestAddOneDriveCommands() {
 			"delete onedrive", deleteCommand, expectUse + " " + oneDriveServiceCommandDeleteUseSuffix,
 			oneDriveDeleteCmd().Short, deleteOneDriveCmd,
 		},
+		{
+			"retention onedrive", retentionCommand, expectUse + " " + oneDriveServiceCommandRetentionUseSuffix,
+			oneDriveRetentionCmd().Short, retentionOneDriveCmd,
+		},
 	}
 	for _, test := range table {
 		suite.T().Run(test.name, func(t *testing.T) {
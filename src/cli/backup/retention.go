@@ -0,0 +1,248 @@
+package backup
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/alcionai/corso/src/cli/config"
+	"github.com/alcionai/corso/src/cli/options"
+	. "github.com/alcionai/corso/src/cli/print"
+	"github.com/alcionai/corso/src/cli/utils"
+	"github.com/alcionai/corso/src/internal/kopia"
+	"github.com/alcionai/corso/src/pkg/backup"
+	"github.com/alcionai/corso/src/pkg/backup/retention"
+	"github.com/alcionai/corso/src/pkg/path"
+	"github.com/alcionai/corso/src/pkg/repository"
+)
+
+// ------------------------------------------------------------------------------------------------
+// setup and globals
+// ------------------------------------------------------------------------------------------------
+
+// retention rules from flags
+var (
+	keepLast       int
+	keepDaily      int
+	keepWeekly     int
+	keepMonthly    int
+	keepYearly     int
+	clearRetention bool
+
+	dryRun bool
+)
+
+// retention flag names
+const (
+	keepLastFN    = "keep-last"
+	keepDailyFN   = "keep-daily"
+	keepWeeklyFN  = "keep-weekly"
+	keepMonthlyFN = "keep-monthly"
+	keepYearlyFN  = "keep-yearly"
+	clearFN       = "clear"
+	dryRunFN      = "dry-run"
+)
+
+const (
+	pruneCommandExamples = `# Preview the backups that fall outside of the retention policies
+corso backup prune --dry-run
+
+# Delete the backups that fall outside of the retention policies
+corso backup prune`
+)
+
+// adds the retention rule flags shared by every service.
+func addRetentionFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&keepLast, keepLastFN, 0, "Keep the most recent N backups.")
+	fs.IntVar(&keepDaily, keepDailyFN, 0, "Keep the most recent backup of each of the last N days with backups.")
+	fs.IntVar(&keepWeekly, keepWeeklyFN, 0, "Keep the most recent backup of each of the last N weeks with backups.")
+	fs.IntVar(&keepMonthly, keepMonthlyFN, 0, "Keep the most recent backup of each of the last N months with backups.")
+	fs.IntVar(&keepYearly, keepYearlyFN, 0, "Keep the most recent backup of each of the last N years with backups.")
+	fs.BoolVar(&clearRetention, clearFN, false, "Remove the retention policy, keeping all backups.")
+}
+
+// ------------------------------------------------------------------------------------------------
+// backup retention
+// ------------------------------------------------------------------------------------------------
+
+// The backup retention subcommand.
+// `corso backup retention <service> [<flag>...]`
+var retentionCommand = "retention"
+
+func retentionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   retentionCommand,
+		Short: "Manage the retention policies for a service",
+		Long: `Manage the rules that decide which backups are kept when running 'corso backup prune'.
+Policies apply to a single resource owner, or to every resource owner in the service
+that doesn't have its own policy.`,
+		RunE: handleRetentionCmd,
+		Args: cobra.NoArgs,
+	}
+}
+
+// Handler for calls to `corso backup retention`.
+// Produces the same output as `corso backup retention --help`.
+func handleRetentionCmd(cmd *cobra.Command, args []string) error {
+	return cmd.Help()
+}
+
+// runRetentionCmd sets, clears, or lists the service's retention policies.
+// Without any owners, the service-wide policy is changed.  Without any
+// rule flags, the service's policies are listed.
+func runRetentionCmd(cmd *cobra.Command, service path.ServiceType, owners []string) error {
+	ctx := cmd.Context()
+
+	s, acct, err := config.GetStorageAndAccount(ctx, true, nil)
+	if err != nil {
+		return Only(ctx, err)
+	}
+
+	r, err := repository.Connect(ctx, acct, s, options.Control())
+	if err != nil {
+		return Only(ctx, errors.Wrapf(err, "Failed to connect to the %s repository", s.Provider))
+	}
+
+	defer utils.CloseRepo(ctx, r)
+
+	owners = retentionOwners(owners)
+	fs := cmd.Flags()
+
+	switch {
+	case clearRetention:
+		for _, o := range owners {
+			err := r.DeleteRetentionPolicy(ctx, service, o)
+			if err != nil && !errors.Is(err, kopia.ErrNotFound) {
+				return Only(ctx, errors.Wrap(err, "Failed to remove the retention policy"))
+			}
+		}
+
+	case fs.Changed(keepLastFN) || fs.Changed(keepDailyFN) || fs.Changed(keepWeeklyFN) ||
+		fs.Changed(keepMonthlyFN) || fs.Changed(keepYearlyFN):
+		for _, o := range owners {
+			if err := r.SetRetentionPolicy(ctx, retentionPolicy(service, o)); err != nil {
+				return Only(ctx, errors.Wrap(err, "Failed to set the retention policy"))
+			}
+		}
+	}
+
+	return printRetentionPolicies(ctx, r, service)
+}
+
+// retentionOwners maps the owner flag values to policy owners.  No owners,
+// or the wildcard, refer to the service-wide policy.
+func retentionOwners(owners []string) []string {
+	if len(owners) == 0 {
+		return []string{""}
+	}
+
+	for _, o := range owners {
+		if o == utils.Wildcard {
+			return []string{""}
+		}
+	}
+
+	return owners
+}
+
+// retentionPolicy builds a policy for the owner out of the rule flags.
+func retentionPolicy(service path.ServiceType, owner string) *retention.Policy {
+	p := retention.New(service, owner)
+	p.KeepLast = keepLast
+	p.KeepDaily = keepDaily
+	p.KeepWeekly = keepWeekly
+	p.KeepMonthly = keepMonthly
+	p.KeepYearly = keepYearly
+
+	return p
+}
+
+func printRetentionPolicies(ctx context.Context, r repository.Repository, service path.ServiceType) error {
+	ps, err := r.RetentionPolicies(ctx)
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to list retention policies in the repository"))
+	}
+
+	filtered := []*retention.Policy{}
+
+	for _, p := range ps {
+		if p.Service == service.String() {
+			filtered = append(filtered, p)
+		}
+	}
+
+	retention.PrintAll(ctx, filtered)
+
+	return nil
+}
+
+// ------------------------------------------------------------------------------------------------
+// backup prune
+// ------------------------------------------------------------------------------------------------
+
+// The backup prune subcommand.
+// `corso backup prune [--dry-run]`
+var pruneCommand = "prune"
+
+func pruneCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     pruneCommand,
+		Short:   "Delete the backups that fall outside of the retention policies",
+		Example: pruneCommandExamples,
+		RunE:    handlePruneCmd,
+		Args:    cobra.NoArgs,
+	}
+}
+
+// adds the prune command to the parent.
+func addPruneCommand(cmd *cobra.Command) *cobra.Command {
+	c, fs := utils.AddCommand(cmd, pruneCmd())
+
+	fs.BoolVar(&dryRun, dryRunFN, false, "List the backups that would be deleted, without deleting them.")
+
+	return c
+}
+
+// deletes every backup which isn't retained by a retention policy.
+func handlePruneCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	s, acct, err := config.GetStorageAndAccount(ctx, true, nil)
+	if err != nil {
+		return Only(ctx, err)
+	}
+
+	r, err := repository.Connect(ctx, acct, s, options.Control())
+	if err != nil {
+		return Only(ctx, errors.Wrapf(err, "Failed to connect to the %s repository", s.Provider))
+	}
+
+	defer utils.CloseRepo(ctx, r)
+
+	bs, err := r.PruneBackups(ctx, dryRun)
+	if err != nil {
+		backup.PrintAll(ctx, bs)
+		return Only(ctx, errors.Wrap(err, "Failed to prune backups"))
+	}
+
+	if len(bs) == 0 {
+		Info(ctx, "No backups to prune")
+		return nil
+	}
+
+	if dryRun {
+		Info(ctx, "Backups that would be pruned:")
+	} else {
+		Info(ctx, "Pruned backups:")
+	}
+
+	backup.PrintAll(ctx, bs)
+
+	if !dryRun {
+		Info(ctx, "Run 'corso repo maintenance --full' to reclaim the storage held by pruned backups.")
+	}
+
+	return nil
+}
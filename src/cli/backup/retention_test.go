@@ -0,0 +1,66 @@
+package backup
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/cli/utils"
+	"github.com/alcionai/corso/src/internal/tester"
+)
+
+type RetentionSuite struct {
+	suite.Suite
+}
+
+func TestRetentionSuite(t *testing.T) {
+	suite.Run(t, new(RetentionSuite))
+}
+
+func (suite *RetentionSuite) TestAddPruneCommand() {
+	t := suite.T()
+	cmd := &cobra.Command{Use: "backup"}
+
+	c := addPruneCommand(cmd)
+	require.NotNil(t, c)
+
+	cmds := cmd.Commands()
+	require.Len(t, cmds, 1)
+
+	child := cmds[0]
+	assert.Equal(t, pruneCommand, child.Use)
+	assert.Equal(t, pruneCmd().Short, child.Short)
+	assert.NotNil(t, child.Flags().Lookup(dryRunFN))
+	tester.AreSameFunc(t, handlePruneCmd, child.RunE)
+}
+
+func (suite *RetentionSuite) TestRetentionOwners() {
+	table := []struct {
+		name   string
+		owners []string
+		expect []string
+	}{
+		{
+			name:   "no owners",
+			expect: []string{""},
+		},
+		{
+			name:   "wildcard",
+			owners: []string{"a", utils.Wildcard},
+			expect: []string{""},
+		},
+		{
+			name:   "owners",
+			owners: []string{"a", "b"},
+			expect: []string{"a", "b"},
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expect, retentionOwners(test.owners))
+		})
+	}
+}
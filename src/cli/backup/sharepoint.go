@@ -41,10 +41,11 @@ const (
 )
 
 const (
-	sharePointServiceCommand                 = "sharepoint"
-	sharePointServiceCommandCreateUseSuffix  = "--site <siteId> | '" + utils.Wildcard + "'"
-	sharePointServiceCommandDeleteUseSuffix  = "--backup <backupId>"
-	sharePointServiceCommandDetailsUseSuffix = "--backup <backupId>"
+	sharePointServiceCommand                   = "sharepoint"
+	sharePointServiceCommandCreateUseSuffix    = "--site <siteId> | '" + utils.Wildcard + "'"
+	sharePointServiceCommandDeleteUseSuffix    = "--backup <backupId>"
+	sharePointServiceCommandRetentionUseSuffix = "[--site <siteId> | '" + utils.Wildcard + "']"
+	sharePointServiceCommandDetailsUseSuffix   = "--backup <backupId>"
 )
 
 const (
@@ -62,6 +63,18 @@ corso backup create sharepoint --site '*'`
 	sharePointServiceCommandDeleteExamples = `# Delete SharePoint backup with ID 1234abcd-12ab-cd34-56de-1234abcd
 corso backup delete sharepoint --backup 1234abcd-12ab-cd34-56de-1234abcd`
 
+	sharePointServiceCommandRetentionExamples = `# Keep the last 7 daily and 4 weekly backups for every SharePoint site
+corso backup retention sharepoint --keep-daily 7 --keep-weekly 4
+
+# Keep the last 10 SharePoint backups for <site_id>
+corso backup retention sharepoint --site <site_id> --keep-last 10
+
+# Remove the retention policy for <site_id>
+corso backup retention sharepoint --site <site_id> --clear
+
+# List the SharePoint retention policies
+corso backup retention sharepoint`
+
 	sharePointServiceCommandDetailsExamples = `# Explore <site>'s files from backup 1234abcd-12ab-cd34-56de-1234abcd
 
 corso backup details sharepoint --backup 1234abcd-12ab-cd34-56de-1234abcd --site <site_id>`
@@ -151,6 +164,17 @@ func addSharePointCommands(cmd *cobra.Command) *cobra.Command {
 			utils.BackupFN, "",
 			"ID of the backup to delete. (required)")
 		cobra.CheckErr(c.MarkFlagRequired(utils.BackupFN))
+
+	case retentionCommand:
+		c, fs = utils.AddCommand(cmd, sharePointRetentionCmd(), utils.HideCommand())
+
+		c.Use = c.Use + " " + sharePointServiceCommandRetentionUseSuffix
+		c.Example = sharePointServiceCommandRetentionExamples
+
+		fs.StringArrayVar(&site,
+			utils.SiteFN, nil,
+			"Apply the retention policy to a site ID; defaults to every site in the service.")
+		addRetentionFlags(fs)
 	}
 
 	return c
@@ -485,3 +509,22 @@ func runDetailsSharePointCmd(
 
 	return sel.Reduce(ctx, d), nil
 }
+
+// ------------------------------------------------------------------------------------------------
+// backup retention
+// ------------------------------------------------------------------------------------------------
+
+// `corso backup retention sharepoint [<flag>...]`
+func sharePointRetentionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   sharePointServiceCommand,
+		Short: "Manage the retention policies for M365 SharePoint service backups",
+		RunE:  retentionSharePointCmd,
+		Args:  cobra.NoArgs,
+	}
+}
+
+// sets, clears, or lists the SharePoint retention policies.
+func retentionSharePointCmd(cmd *cobra.Command, args []string) error {
+	return runRetentionCmd(cmd, path.SharePointService, site)
+}
@@ -49,6 +49,10 @@ func (suite *SharePointSuite) TestAddSharePointCommands() {
 			"delete sharepoint", deleteCommand, expectUse + " " + sharePointServiceCommandDeleteUseSuffix,
 			sharePointDeleteCmd().Short, deleteSharePointCmd,
 		},
+		{
+			"retention sharepoint", retentionCommand, expectUse + " " + sharePointServiceCommandRetentionUseSuffix,
+			sharePointRetentionCmd().Short, retentionSharePointCmd,
+		},
 	}
 	for _, test := range table {
 		suite.T().Run(test.name, func(t *testing.T) {
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	. "github.com/alcionai/corso/src/cli/print"
+	"github.com/alcionai/corso/src/cli/utils"
+)
+
+const (
+	profilesCommand      = "profiles"
+	listProfilesCommand  = "list"
+	addProfileCommand    = "add"
+	removeProfileCommand = "remove"
+)
+
+// profile flags
+var kopiaConfigDir string
+
+const kopiaConfigDirFN = "kopia-config-dir"
+
+const addProfileExamples = `# Add a profile for each tenant, then connect each one to its repository
+corso config profiles add tenant-a
+corso repo init s3 --profile tenant-a --bucket tenant-a-backups
+corso config profiles add tenant-b
+corso repo connect s3 --profile tenant-b --bucket tenant-b-backups
+
+# Back up a tenant using its profile
+corso backup create exchange --profile tenant-a --user '*'`
+
+// AddCommands attaches all `corso config * *` commands to the parent.
+func AddCommands(cmd *cobra.Command) {
+	configCmd := configCmd()
+	profilesCmd := profilesCmd()
+
+	cmd.AddCommand(configCmd)
+	configCmd.AddCommand(profilesCmd)
+
+	utils.AddCommand(profilesCmd, listProfilesCmd())
+
+	_, fs := utils.AddCommand(profilesCmd, addProfileCmd())
+	fs.StringVar(
+		&kopiaConfigDir,
+		kopiaConfigDirFN, "",
+		"Directory for the profile's repository connection config; defaults to a directory named after the profile.")
+
+	utils.AddCommand(profilesCmd, removeProfileCmd())
+}
+
+// The config category of commands.
+// `corso config [<subcommand>] [<flag>...]`
+func configCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "config",
+		Short: "Manage your Corso configuration",
+		Long:  `Manage the Corso config file, and the named profiles it holds.`,
+		RunE:  handleHelpCmd,
+		Args:  cobra.NoArgs,
+	}
+}
+
+// The config profiles category of commands.
+// `corso config profiles [<subcommand>] [<flag>...]`
+func profilesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   profilesCommand,
+		Short: "Manage named config profiles",
+		Long: `Manage named profiles in the config file. Each profile holds the configuration
+for one repository and tenant, and keeps its own repository connection config, so
+that many repositories can be used from the same host. Select a profile with the
+--profile flag, or the ` + ProfileEnv + ` env var.`,
+		RunE: handleHelpCmd,
+		Args: cobra.NoArgs,
+	}
+}
+
+// Handler for flat calls to `corso config` and `corso config profiles`.
+// Produces the same output as `--help`.
+func handleHelpCmd(cmd *cobra.Command, args []string) error {
+	return cmd.Help()
+}
+
+// The config profiles list subcommand.
+// `corso config profiles list`
+func listProfilesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   listProfilesCommand,
+		Short: "List the config profiles",
+		RunE:  handleListProfilesCmd,
+		Args:  cobra.NoArgs,
+	}
+}
+
+// Handler for calls to `corso config profiles list`.
+func handleListProfilesCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	ps, err := ListProfiles(ctx)
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to list profiles"))
+	}
+
+	if len(ps) == 0 {
+		Info(ctx, "No profiles found")
+		return nil
+	}
+
+	All(ctx, printables(ps)...)
+
+	return nil
+}
+
+// The config profiles add subcommand.
+// `corso config profiles add <name> [--kopia-config-dir <dir>]`
+func addProfileCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   addProfileCommand + " <name>",
+		Short: "Add a config profile",
+		Long: `Add an empty profile to the config file. Run 'corso repo init' or 'corso repo connect'
+with the profile selected to configure its repository.`,
+		Example: addProfileExamples,
+		RunE:    handleAddProfileCmd,
+		Args:    cobra.ExactArgs(1),
+	}
+}
+
+// Handler for calls to `corso config profiles add`.
+func handleAddProfileCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	p, err := AddProfile(ctx, args[0], kopiaConfigDir)
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to add profile"))
+	}
+
+	Infof(ctx, "Added profile %s, with its connection config in %s", p.Name, p.KopiaConfigDir)
+
+	return nil
+}
+
+// The config profiles remove subcommand.
+// `corso config profiles remove <name>`
+func removeProfileCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   removeProfileCommand + " <name>",
+		Short: "Remove a config profile",
+		Long:  `Remove the profile from the config file. The repository it's connected to is not modified.`,
+		RunE:  handleRemoveProfileCmd,
+		Args:  cobra.ExactArgs(1),
+	}
+}
+
+// Handler for calls to `corso config profiles remove`.
+func handleRemoveProfileCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	p, err := RemoveProfile(ctx, args[0])
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to remove profile"))
+	}
+
+	Infof(ctx, "Removed profile %s", p.Name)
+
+	if len(p.KopiaConfigDir) > 0 {
+		Infof(ctx, "Its connection config was left in %s", p.KopiaConfigDir)
+	}
+
+	return nil
+}
+
+func printables(ps []ProfileInfo) []Printable {
+	result := make([]Printable, 0, len(ps))
+	for _, p := range ps {
+		result = append(result, p)
+	}
+
+	return result
+}
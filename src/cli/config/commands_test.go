@@ -0,0 +1,53 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/tester"
+)
+
+type CommandsSuite struct {
+	suite.Suite
+}
+
+func TestCommandsSuite(t *testing.T) {
+	suite.Run(t, new(CommandsSuite))
+}
+
+func (suite *CommandsSuite) TestAddCommands() {
+	t := suite.T()
+	cmd := &cobra.Command{Use: "corso"}
+
+	AddCommands(cmd)
+
+	c, _, err := cmd.Find([]string{"config", profilesCommand})
+	require.NoError(t, err)
+	assert.Equal(t, profilesCommand, c.Use)
+
+	table := []struct {
+		name        string
+		expectShort string
+		expectRunE  func(*cobra.Command, []string) error
+	}{
+		{listProfilesCommand, listProfilesCmd().Short, handleListProfilesCmd},
+		{addProfileCommand, addProfileCmd().Short, handleAddProfileCmd},
+		{removeProfileCommand, removeProfileCmd().Short, handleRemoveProfileCmd},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			child, _, err := c.Find([]string{test.name})
+			require.NoError(t, err)
+			assert.Equal(t, test.expectShort, child.Short)
+			tester.AreSameFunc(t, test.expectRunE, child.RunE)
+		})
+	}
+
+	add, _, err := c.Find([]string{addProfileCommand})
+	require.NoError(t, err)
+	assert.NotNil(t, add.Flags().Lookup(kopiaConfigDirFN))
+}
@@ -25,14 +25,33 @@ const (
 	DisableTLSKey             = "disable_tls"
 	DisableTLSVerificationKey = "disable_tls_verification"
 
+	// Filesystem config
+	FilesystemPathKey = "filesystem_path"
+
+	// Azure config
+	ContainerKey   = "container"
+	AccountNameKey = "account_name"
+
+	// SFTP config
+	SFTPHostKey           = "sftp_host"
+	SFTPPortKey           = "sftp_port"
+	SFTPUsernameKey       = "sftp_username"
+	SFTPKeyFileKey        = "sftp_key_file"
+	SFTPKnownHostsFileKey = "sftp_known_hosts_file"
+	SFTPPathKey           = "sftp_path"
+
 	// M365 config
 	AccountProviderTypeKey = "account_provider"
 	AzureTenantIDKey       = "azure_tenantid"
+
+	// Profile config
+	KopiaConfigDirKey = "kopia_config_dir"
 )
 
 var (
 	configFilePath     string
 	configFilePathFlag string
+	profileFlag        string
 	configDir          string
 	displayDefaultFP   = filepath.Join("$HOME", ".corso.toml")
 )
@@ -69,6 +88,11 @@ func AddConfigFlags(cmd *cobra.Command) {
 		"config-file",
 		displayDefaultFP,
 		"config file location")
+	fs.StringVar(
+		&profileFlag,
+		"profile",
+		"",
+		"name of the config profile to use; defaults to $"+ProfileEnv+", or the config file's top-level settings")
 }
 
 // ---------------------------------------------------------------------------------------------------------
@@ -179,27 +203,54 @@ func Read(ctx context.Context) error {
 }
 
 // WriteRepoConfig currently just persists corso config to the config file
-// It does not check for conflicts or existing data.
-func WriteRepoConfig(ctx context.Context, s3Config storage.S3Config, m365Config account.M365Config) error {
-	return writeRepoConfigWithViper(GetViper(ctx), s3Config, m365Config)
+// It does not check for conflicts or existing data.  When a profile is
+// selected, the config is written to that profile.
+func WriteRepoConfig(ctx context.Context, s storage.Storage, m365Config account.M365Config) error {
+	return writeRepoConfigWithViper(GetViper(ctx), Profile(), s, m365Config)
 }
 
 // writeRepoConfigWithViper implements WriteRepoConfig, but takes in a viper
 // struct for testing.
-func writeRepoConfigWithViper(vpr *viper.Viper, s3Config storage.S3Config, m365Config account.M365Config) error {
-	s3Config = s3Config.Normalize()
+func writeRepoConfigWithViper(
+	vpr *viper.Viper,
+	profile string,
+	s storage.Storage,
+	m365Config account.M365Config,
+) error {
+	cfgVpr := vpr
+
+	if len(profile) > 0 {
+		if err := readConfigIfExists(vpr); err != nil {
+			return err
+		}
+
+		pv, err := profileViper(vpr, profile)
+		if err != nil {
+			return err
+		}
+
+		cfgVpr = pv
+	}
+
 	// Rudimentary support for persisting repo config
-	// TODO: Handle conflicts, support other config types
-	vpr.Set(StorageProviderTypeKey, storage.ProviderS3.String())
-	vpr.Set(BucketNameKey, s3Config.Bucket)
-	vpr.Set(EndpointKey, s3Config.Endpoint)
-	vpr.Set(PrefixKey, s3Config.Prefix)
-	vpr.Set(DisableTLSKey, s3Config.DoNotUseTLS)
-	vpr.Set(DisableTLSVerificationKey, s3Config.DoNotVerifyTLS)
+	// TODO: Handle conflicts
+	if err := writeStorageConfigToViper(cfgVpr, s); err != nil {
+		return errors.Wrap(err, "writing storage configuration")
+	}
+
+	cfgVpr.Set(AccountProviderTypeKey, account.ProviderM365.String())
+	cfgVpr.Set(AzureTenantIDKey, m365Config.AzureTenantID)
 
-	vpr.Set(AccountProviderTypeKey, account.ProviderM365.String())
-	vpr.Set(AzureTenantIDKey, m365Config.AzureTenantID)
+	if len(profile) > 0 {
+		vpr.Set(profileKey(profile), cfgVpr.AllSettings())
+	}
 
+	return writeConfig(vpr)
+}
+
+// writeConfig writes the viper's settings to its config file, creating
+// the file if it doesn't exist.
+func writeConfig(vpr *viper.Viper) error {
 	if err := vpr.SafeWriteConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileAlreadyExistsError); ok {
 			return vpr.WriteConfig()
@@ -211,20 +262,50 @@ func writeRepoConfigWithViper(vpr *viper.Viper, s3Config storage.S3Config, m365C
 	return nil
 }
 
+// readConfigIfExists reads the viper's config file, if there is one.
+func readConfigIfExists(vpr *viper.Viper) error {
+	if err := vpr.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			return errors.Wrap(err, "reading corso config file: "+vpr.ConfigFileUsed())
+		}
+	}
+
+	return nil
+}
+
 // GetStorageAndAccount creates a storage and account instance by mediating all the possible
-// data sources (config file, env vars, flag overrides) and the config file.
+// data sources (config file, env vars, flag overrides) and the config file.  When a profile
+// is selected, only that profile's settings are read from the config file.
 func GetStorageAndAccount(
 	ctx context.Context,
 	readFromFile bool,
 	overrides map[string]string,
 ) (storage.Storage, account.Account, error) {
-	return getStorageAndAccountWithViper(GetViper(ctx), readFromFile, overrides)
+	return getStorageAndAccountWithViper(GetViper(ctx), Profile(), readFromFile, overrides)
+}
+
+// GetStorageAndAccountFromFile creates a storage and account instance from the
+// config file at configFP instead of the config file used by the current
+// command.  Env vars still supply any secrets.
+func GetStorageAndAccountFromFile(configFP string) (storage.Storage, account.Account, error) {
+	if len(configFP) == 0 {
+		return storage.Storage{}, account.Account{}, errors.New("a config file path is required")
+	}
+
+	vpr := viper.New()
+
+	if err := initWithViper(vpr, configFP); err != nil {
+		return storage.Storage{}, account.Account{}, err
+	}
+
+	return getStorageAndAccountWithViper(vpr, "", true, nil)
 }
 
 // getSorageAndAccountWithViper implements GetSorageAndAccount, but takes in a viper
 // struct for testing.
 func getStorageAndAccountWithViper(
 	vpr *viper.Viper,
+	profile string,
 	readFromFile bool,
 	overrides map[string]string,
 ) (storage.Storage, account.Account, error) {
@@ -236,8 +317,10 @@ func getStorageAndAccountWithViper(
 
 	readConfigFromViper := readFromFile
 
-	// possibly read the prior config from a .corso file
-	if readFromFile {
+	// possibly read the prior config from a .corso file.  Profiles are
+	// always read, since they hold the kopia config dir even before a
+	// repository is initialized.
+	if readFromFile || len(profile) > 0 {
 		err = vpr.ReadInConfig()
 		if err != nil {
 			if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
@@ -248,6 +331,16 @@ func getStorageAndAccountWithViper(
 		}
 	}
 
+	if len(profile) > 0 {
+		if vpr, err = profileViper(vpr, profile); err != nil {
+			return store, acct, err
+		}
+
+		// a profile that was added, but not yet initialized or connected,
+		// has no repo config to read.
+		readConfigFromViper = readConfigFromViper && vpr.IsSet(StorageProviderTypeKey)
+	}
+
 	acct, err = configureAccount(vpr, readConfigFromViper, overrides)
 	if err != nil {
 		return store, acct, errors.Wrap(err, "retrieving account configuration details")
@@ -271,6 +364,15 @@ var constToTomlKeyMap = map[string]string{
 	storage.Bucket:         BucketNameKey,
 	storage.Endpoint:       EndpointKey,
 	storage.Prefix:         PrefixKey,
+	storage.FilesystemPath: FilesystemPathKey,
+	storage.Container:      ContainerKey,
+	storage.AccountName:    AccountNameKey,
+	storage.Host:           SFTPHostKey,
+	storage.Port:           SFTPPortKey,
+	storage.Username:       SFTPUsernameKey,
+	storage.KeyFile:        SFTPKeyFileKey,
+	storage.KnownHostsFile: SFTPKnownHostsFileKey,
+	storage.SFTPPath:       SFTPPathKey,
 	StorageProviderTypeKey: StorageProviderTypeKey,
 }
 
@@ -88,7 +88,10 @@ func (suite *ConfigSuite) TestWriteReadConfig() {
 	s3Cfg := storage.S3Config{Bucket: bkt, DoNotUseTLS: true, DoNotVerifyTLS: true}
 	m365 := account.M365Config{AzureTenantID: tid}
 
-	require.NoError(t, writeRepoConfigWithViper(vpr, s3Cfg, m365), "writing repo config")
+	st, err := storage.NewStorage(storage.ProviderS3, s3Cfg)
+	require.NoError(t, err)
+
+	require.NoError(t, writeRepoConfigWithViper(vpr, "", st, m365), "writing repo config")
 	require.NoError(t, vpr.ReadInConfig(), "reading repo config")
 
 	readS3Cfg, err := s3ConfigsFromViper(vpr)
@@ -102,6 +105,116 @@ func (suite *ConfigSuite) TestWriteReadConfig() {
 	assert.Equal(t, readM365.AzureTenantID, m365.AzureTenantID)
 }
 
+func (suite *ConfigSuite) TestWriteReadConfig_filesystem() {
+	var (
+		t   = suite.T()
+		vpr = viper.New()
+	)
+
+	const tid = "2d1a5f96-9b1e-4a0e-8d5f-0c6a0b0f1b7e"
+
+	// Configure viper to read test config file
+	testConfigFilePath := filepath.Join(t.TempDir(), "corso.toml")
+	require.NoError(t, initWithViper(vpr, testConfigFilePath), "initializing repo config")
+
+	fsCfg := storage.FilesystemConfig{Path: t.TempDir()}
+	m365 := account.M365Config{AzureTenantID: tid}
+
+	st, err := storage.NewStorage(storage.ProviderFilesystem, fsCfg)
+	require.NoError(t, err)
+
+	require.NoError(t, writeRepoConfigWithViper(vpr, "", st, m365), "writing repo config")
+	require.NoError(t, vpr.ReadInConfig(), "reading repo config")
+
+	readFSCfg, err := filesystemConfigsFromViper(vpr)
+	require.NoError(t, err)
+	assert.Equal(t, fsCfg.Path, readFSCfg.Path)
+
+	_, err = s3ConfigsFromViper(vpr)
+	assert.Error(t, err, "reading s3 config from a filesystem repo config")
+
+	readM365, err := m365ConfigsFromViper(vpr)
+	require.NoError(t, err)
+	assert.Equal(t, readM365.AzureTenantID, m365.AzureTenantID)
+}
+
+func (suite *ConfigSuite) TestGetStorageAndAccountFromFile() {
+	var (
+		t   = suite.T()
+		vpr = viper.New()
+	)
+
+	const tid = "8a6e1f52-4f0b-4c5e-9a3d-71c2b8e9d4f0"
+
+	t.Setenv(credentials.CorsoPassphrase, "passphrase")
+	t.Setenv(credentials.AzureClientID, "client-id")
+	t.Setenv(credentials.AzureClientSecret, "client-secret")
+
+	testConfigFilePath := filepath.Join(t.TempDir(), "other.toml")
+	require.NoError(t, initWithViper(vpr, testConfigFilePath), "initializing repo config")
+
+	fsCfg := storage.FilesystemConfig{Path: t.TempDir()}
+
+	st, err := storage.NewStorage(storage.ProviderFilesystem, fsCfg)
+	require.NoError(t, err)
+
+	m365 := account.M365Config{AzureTenantID: tid}
+	require.NoError(t, writeRepoConfigWithViper(vpr, "", st, m365), "writing repo config")
+
+	s, acct, err := GetStorageAndAccountFromFile(testConfigFilePath)
+	require.NoError(t, err)
+	assert.Equal(t, storage.ProviderFilesystem, s.Provider)
+	assert.Equal(t, tid, acct.ID())
+
+	readFSCfg, err := s.FilesystemConfig()
+	require.NoError(t, err)
+	assert.Equal(t, fsCfg.Path, readFSCfg.Path)
+
+	_, _, err = GetStorageAndAccountFromFile("")
+	assert.Error(t, err, "no config file")
+
+	_, _, err = GetStorageAndAccountFromFile(filepath.Join(t.TempDir(), "missing.toml"))
+	assert.Error(t, err, "missing config file")
+}
+
+func (suite *ConfigSuite) TestWriteReadConfig_sftp() {
+	var (
+		t   = suite.T()
+		vpr = viper.New()
+	)
+
+	const tid = "3c0748d5-470a-4ba1-8c4b-a1e3a4b4d8a0"
+
+	// Configure viper to read test config file
+	testConfigFilePath := filepath.Join(t.TempDir(), "corso.toml")
+	require.NoError(t, initWithViper(vpr, testConfigFilePath), "initializing repo config")
+
+	sftpCfg := storage.SFTPConfig{
+		Host:     "backup.example.com",
+		Port:     2222,
+		Username: "corso",
+		KeyFile:  "/home/corso/.ssh/id_ed25519",
+		Path:     "/srv/corso",
+	}
+	m365 := account.M365Config{AzureTenantID: tid}
+
+	st, err := storage.NewStorage(storage.ProviderSFTP, sftpCfg)
+	require.NoError(t, err)
+
+	require.NoError(t, writeRepoConfigWithViper(vpr, "", st, m365), "writing repo config")
+	require.NoError(t, vpr.ReadInConfig(), "reading repo config")
+
+	readSFTPCfg, err := sftpConfigsFromViper(vpr)
+	require.NoError(t, err)
+	assert.Equal(t, sftpCfg, readSFTPCfg)
+
+	_, err = s3ConfigsFromViper(vpr)
+	assert.Error(t, err, "reading s3 config from an sftp repo config")
+
+	assert.NoError(t, mustMatchConfig(vpr, map[string]string{storage.Port: "2222"}))
+	assert.Error(t, mustMatchConfig(vpr, map[string]string{storage.Port: "22"}))
+}
+
 func (suite *ConfigSuite) TestMustMatchConfig() {
 	var (
 		t   = suite.T()
@@ -120,7 +233,10 @@ func (suite *ConfigSuite) TestMustMatchConfig() {
 	s3Cfg := storage.S3Config{Bucket: bkt}
 	m365 := account.M365Config{AzureTenantID: tid}
 
-	require.NoError(t, writeRepoConfigWithViper(vpr, s3Cfg, m365), "writing repo config")
+	st, err := storage.NewStorage(storage.ProviderS3, s3Cfg)
+	require.NoError(t, err)
+
+	require.NoError(t, writeRepoConfigWithViper(vpr, "", st, m365), "writing repo config")
 	require.NoError(t, vpr.ReadInConfig(), "reading repo config")
 
 	table := []struct {
@@ -223,10 +339,13 @@ func (suite *ConfigIntegrationSuite) TestGetStorageAndAccount() {
 	}
 	m365 := account.M365Config{AzureTenantID: tid}
 
-	require.NoError(t, writeRepoConfigWithViper(vpr, s3Cfg, m365), "writing repo config")
+	st, err := storage.NewStorage(storage.ProviderS3, s3Cfg)
+	require.NoError(t, err)
+
+	require.NoError(t, writeRepoConfigWithViper(vpr, "", st, m365), "writing repo config")
 	require.NoError(t, vpr.ReadInConfig(), "reading repo config")
 
-	st, ac, err := getStorageAndAccountWithViper(vpr, true, nil)
+	st, ac, err := getStorageAndAccountWithViper(vpr, "", true, nil)
 	require.NoError(t, err, "getting storage and account from config")
 
 	readS3Cfg, err := st.S3Config()
@@ -272,7 +391,7 @@ func (suite *ConfigIntegrationSuite) TestGetStorageAndAccount_noFileOnlyOverride
 		StorageProviderTypeKey: storage.ProviderS3.String(),
 	}
 
-	st, ac, err := getStorageAndAccountWithViper(vpr, false, overrides)
+	st, ac, err := getStorageAndAccountWithViper(vpr, "", false, overrides)
 	require.NoError(t, err, "getting storage and account from config")
 
 	readS3Cfg, err := st.S3Config()
@@ -0,0 +1,251 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+
+	"github.com/alcionai/corso/src/cli/print"
+	"github.com/alcionai/corso/src/internal/common"
+)
+
+const (
+	// ProfileEnv selects the config profile when --profile isn't set.
+	ProfileEnv = "CORSO_PROFILE"
+
+	// profiles are kept in a table of this name in the config file.
+	profilesKey = "profiles"
+)
+
+var validProfileName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Profile returns the name of the config profile selected by the --profile
+// flag or the CORSO_PROFILE env var.  An empty name means the config file's
+// top-level settings are used.
+func Profile() string {
+	return common.First(profileFlag, os.Getenv(ProfileEnv))
+}
+
+// ProfileInfo describes a named config profile, each of which holds the
+// settings for one repository and tenant.
+type ProfileInfo struct {
+	Name           string `json:"name"`
+	Provider       string `json:"provider"`
+	TenantID       string `json:"tenantID"`
+	KopiaConfigDir string `json:"kopiaConfigDir"`
+}
+
+// interface compliance checks
+var _ print.Printable = &ProfileInfo{}
+
+func profileKey(name string) string {
+	return profilesKey + "." + name
+}
+
+// validateProfileName ensures the name can be used as a key in the config
+// file.  Keys are case-insensitive, so names are restricted to lowercase.
+func validateProfileName(name string) error {
+	if !validProfileName.MatchString(name) {
+		return errors.Errorf(
+			"invalid profile name %q: use lowercase letters, numbers, dashes, and underscores",
+			name)
+	}
+
+	return nil
+}
+
+// defaultProfileKopiaDir is the kopia config dir used by a profile that
+// wasn't given one.
+func defaultProfileKopiaDir(name string) string {
+	return filepath.Join(configDir, ".corso", profilesKey, name)
+}
+
+// profileViper returns a viper holding only the settings of the named
+// profile.  Changes to the returned viper are not written back to vpr.
+func profileViper(vpr *viper.Viper, name string) (*viper.Viper, error) {
+	if err := validateProfileName(name); err != nil {
+		return nil, err
+	}
+
+	if !vpr.IsSet(profileKey(name)) {
+		return nil, errors.Errorf("profile %q not found; add it with 'corso config profiles add %s'", name, name)
+	}
+
+	pv := viper.New()
+
+	for k, v := range vpr.GetStringMap(profileKey(name)) {
+		pv.Set(k, v)
+	}
+
+	return pv, nil
+}
+
+// ListProfiles returns the profiles in the config file, sorted by name.
+func ListProfiles(ctx context.Context) ([]ProfileInfo, error) {
+	return listProfilesWithViper(GetViper(ctx))
+}
+
+// listProfilesWithViper implements ListProfiles, but takes in a viper
+// struct for testing.
+func listProfilesWithViper(vpr *viper.Viper) ([]ProfileInfo, error) {
+	if err := readConfigIfExists(vpr); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0)
+	for name := range vpr.GetStringMap(profilesKey) {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	ps := make([]ProfileInfo, 0, len(names))
+
+	for _, name := range names {
+		pv, err := profileViper(vpr, name)
+		if err != nil {
+			return nil, err
+		}
+
+		ps = append(ps, ProfileInfo{
+			Name:           name,
+			Provider:       pv.GetString(StorageProviderTypeKey),
+			TenantID:       pv.GetString(AzureTenantIDKey),
+			KopiaConfigDir: pv.GetString(KopiaConfigDirKey),
+		})
+	}
+
+	return ps, nil
+}
+
+// AddProfile adds an empty profile to the config file, to be populated by
+// running `corso repo init` or `corso repo connect` with the profile
+// selected.  If kopiaDir is empty, the profile's kopia config is kept in a
+// dir named after the profile, within the corso config dir.
+func AddProfile(ctx context.Context, name, kopiaDir string) (ProfileInfo, error) {
+	return addProfileWithViper(GetViper(ctx), name, kopiaDir)
+}
+
+// addProfileWithViper implements AddProfile, but takes in a viper struct
+// for testing.
+func addProfileWithViper(vpr *viper.Viper, name, kopiaDir string) (ProfileInfo, error) {
+	if err := validateProfileName(name); err != nil {
+		return ProfileInfo{}, err
+	}
+
+	if err := readConfigIfExists(vpr); err != nil {
+		return ProfileInfo{}, err
+	}
+
+	if vpr.IsSet(profileKey(name)) {
+		return ProfileInfo{}, errors.Errorf("profile %q already exists", name)
+	}
+
+	if len(kopiaDir) == 0 {
+		kopiaDir = defaultProfileKopiaDir(name)
+	}
+
+	kopiaDir, err := filepath.Abs(kopiaDir)
+	if err != nil {
+		return ProfileInfo{}, errors.Wrap(err, "resolving kopia config dir")
+	}
+
+	vpr.Set(profileKey(name), map[string]any{KopiaConfigDirKey: kopiaDir})
+
+	if err := writeConfig(vpr); err != nil {
+		return ProfileInfo{}, errors.Wrap(err, "writing corso config file")
+	}
+
+	return ProfileInfo{Name: name, KopiaConfigDir: kopiaDir}, nil
+}
+
+// RemoveProfile removes the profile from the config file.  The profile's
+// kopia config dir is left in place.
+func RemoveProfile(ctx context.Context, name string) (ProfileInfo, error) {
+	return removeProfileWithViper(GetViper(ctx), name)
+}
+
+// removeProfileWithViper implements RemoveProfile, but takes in a viper
+// struct for testing.
+func removeProfileWithViper(vpr *viper.Viper, name string) (ProfileInfo, error) {
+	if err := readConfigIfExists(vpr); err != nil {
+		return ProfileInfo{}, err
+	}
+
+	pv, err := profileViper(vpr, name)
+	if err != nil {
+		return ProfileInfo{}, err
+	}
+
+	// viper can't unset a key, so the config is rebuilt without the profile.
+	settings := vpr.AllSettings()
+
+	profiles, _ := settings[profilesKey].(map[string]any)
+	delete(profiles, name)
+
+	if len(profiles) == 0 {
+		delete(settings, profilesKey)
+	}
+
+	nv := viper.New()
+	nv.SetConfigFile(vpr.ConfigFileUsed())
+
+	if err := nv.MergeConfigMap(settings); err != nil {
+		return ProfileInfo{}, errors.Wrap(err, "removing profile")
+	}
+
+	if err := nv.WriteConfig(); err != nil {
+		return ProfileInfo{}, errors.Wrap(err, "writing corso config file")
+	}
+
+	// reload, so that vpr no longer holds the removed profile.  Values set
+	// on vpr outlive a reload, so the profile is cleared from those as well.
+	vpr.Set(profileKey(name), nil)
+
+	if err := vpr.ReadInConfig(); err != nil {
+		return ProfileInfo{}, errors.Wrap(err, "reading corso config file")
+	}
+
+	return ProfileInfo{
+		Name:           name,
+		Provider:       pv.GetString(StorageProviderTypeKey),
+		TenantID:       pv.GetString(AzureTenantIDKey),
+		KopiaConfigDir: pv.GetString(KopiaConfigDirKey),
+	}, nil
+}
+
+// --------------------------------------------------------------------------------
+// CLI Output
+// --------------------------------------------------------------------------------
+
+// MinimumPrintable reduces the ProfileInfo to its minimally printable details.
+func (pi ProfileInfo) MinimumPrintable() any {
+	return pi
+}
+
+// Headers returns the human-readable names of properties in a ProfileInfo
+// for printing out to a terminal in a columnar display.
+func (pi ProfileInfo) Headers() []string {
+	return []string{
+		"Name",
+		"Provider",
+		"Tenant ID",
+		"Kopia Config Dir",
+	}
+}
+
+// Values returns the values matching the Headers list for printing
+// out to a terminal in a columnar display.
+func (pi ProfileInfo) Values() []string {
+	return []string{
+		pi.Name,
+		pi.Provider,
+		pi.TenantID,
+		pi.KopiaConfigDir,
+	}
+}
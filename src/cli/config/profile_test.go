@@ -0,0 +1,152 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/pkg/account"
+	"github.com/alcionai/corso/src/pkg/credentials"
+	"github.com/alcionai/corso/src/pkg/storage"
+)
+
+type ProfileSuite struct {
+	suite.Suite
+}
+
+func TestProfileSuite(t *testing.T) {
+	suite.Run(t, new(ProfileSuite))
+}
+
+func (suite *ProfileSuite) TestValidateProfileName() {
+	table := []struct {
+		name      string
+		expectErr assert.ErrorAssertionFunc
+	}{
+		{"tenant-a", assert.NoError},
+		{"tenant_b2", assert.NoError},
+		{"", assert.Error},
+		{"Tenant", assert.Error},
+		{"tenant.a", assert.Error},
+		{"-tenant", assert.Error},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			test.expectErr(t, validateProfileName(test.name))
+		})
+	}
+}
+
+func (suite *ProfileSuite) TestProfiles() {
+	var (
+		t   = suite.T()
+		vpr = viper.New()
+		dir = t.TempDir()
+	)
+
+	const (
+		tidA = "0f2a2f4c-51a4-4a2b-bd3d-9c1d2a3e4f50"
+		tidB = "7b9e3c1d-2a4f-4e6b-8c0d-1e2f3a4b5c6d"
+	)
+
+	t.Setenv(credentials.CorsoPassphrase, "passphrase")
+	t.Setenv(credentials.AzureClientID, "client-id")
+	t.Setenv(credentials.AzureClientSecret, "client-secret")
+
+	testConfigFilePath := filepath.Join(dir, "corso.toml")
+	require.NoError(t, initWithViper(vpr, testConfigFilePath), "initializing repo config")
+
+	ps, err := listProfilesWithViper(vpr)
+	require.NoError(t, err)
+	assert.Empty(t, ps)
+
+	// the top-level config is kept alongside the profiles.
+	top, err := storage.NewStorage(storage.ProviderS3, storage.S3Config{Bucket: "top-bucket"})
+	require.NoError(t, err)
+	require.NoError(t, writeRepoConfigWithViper(vpr, "", top, account.M365Config{AzureTenantID: "top"}))
+
+	kopiaDirA := filepath.Join(dir, "kopia-a")
+
+	p, err := addProfileWithViper(vpr, "tenant-a", kopiaDirA)
+	require.NoError(t, err)
+	assert.Equal(t, kopiaDirA, p.KopiaConfigDir)
+
+	_, err = addProfileWithViper(vpr, "tenant-a", "")
+	assert.Error(t, err, "adding a duplicate profile")
+
+	_, err = addProfileWithViper(vpr, "tenant-b", "")
+	require.NoError(t, err)
+
+	_, err = addProfileWithViper(vpr, "Not.Valid", "")
+	assert.Error(t, err, "adding an invalid profile")
+
+	// an added profile can be used before it's connected to a repository.
+	fsCfg := storage.FilesystemConfig{Path: t.TempDir()}
+	overrides := map[string]string{
+		StorageProviderTypeKey: storage.ProviderFilesystem.String(),
+		storage.FilesystemPath: fsCfg.Path,
+		account.AzureTenantID:  tidA,
+	}
+
+	st, _, err := getStorageAndAccountWithViper(vpr, "tenant-a", true, overrides)
+	require.NoError(t, err)
+
+	cc, err := st.CommonConfig()
+	require.NoError(t, err)
+	assert.Equal(t, kopiaDirA, cc.KopiaCfgDir)
+
+	require.NoError(t, writeRepoConfigWithViper(vpr, "tenant-a", st, account.M365Config{AzureTenantID: tidA}))
+
+	stB, err := storage.NewStorage(storage.ProviderS3, storage.S3Config{Bucket: "bucket-b"})
+	require.NoError(t, err)
+	require.NoError(t, writeRepoConfigWithViper(vpr, "tenant-b", stB, account.M365Config{AzureTenantID: tidB}))
+
+	_, _, err = getStorageAndAccountWithViper(vpr, "missing", true, nil)
+	assert.Error(t, err, "reading a missing profile")
+
+	// each profile reads back its own config.
+	st, acct, err := getStorageAndAccountWithViper(vpr, "tenant-a", true, nil)
+	require.NoError(t, err)
+	assert.Equal(t, storage.ProviderFilesystem, st.Provider)
+	assert.Equal(t, tidA, acct.ID())
+
+	readFSCfg, err := st.FilesystemConfig()
+	require.NoError(t, err)
+	assert.Equal(t, fsCfg.Path, readFSCfg.Path)
+
+	// overrides must still match the profile's own config.
+	_, _, err = getStorageAndAccountWithViper(vpr, "tenant-a", true, map[string]string{account.AzureTenantID: tidB})
+	assert.Error(t, err, "overriding the profile's tenant")
+
+	ps, err = listProfilesWithViper(vpr)
+	require.NoError(t, err)
+	require.Len(t, ps, 2)
+	assert.Equal(t, ProfileInfo{"tenant-a", storage.ProviderFilesystem.String(), tidA, kopiaDirA}, ps[0])
+	assert.Equal(t, "tenant-b", ps[1].Name)
+	assert.Equal(t, tidB, ps[1].TenantID)
+	assert.Equal(t, defaultProfileKopiaDir("tenant-b"), ps[1].KopiaConfigDir)
+
+	p, err = removeProfileWithViper(vpr, "tenant-a")
+	require.NoError(t, err)
+	assert.Equal(t, kopiaDirA, p.KopiaConfigDir)
+
+	_, err = removeProfileWithViper(vpr, "tenant-a")
+	assert.Error(t, err, "removing a missing profile")
+
+	// reread the file from scratch, to check what was written.
+	vpr = viper.New()
+	require.NoError(t, initWithViper(vpr, testConfigFilePath))
+
+	ps, err = listProfilesWithViper(vpr)
+	require.NoError(t, err)
+	require.Len(t, ps, 1)
+	assert.Equal(t, "tenant-b", ps[0].Name)
+
+	s3Cfg, err := s3ConfigsFromViper(vpr)
+	require.NoError(t, err)
+	assert.Equal(t, "top-bucket", s3Cfg.Bucket)
+}
@@ -2,8 +2,10 @@ package config
 
 import (
 	"os"
+	"path"
 	"path/filepath"
 	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/defaults"
 	"github.com/pkg/errors"
@@ -15,6 +17,10 @@ import (
 	"github.com/alcionai/corso/src/pkg/storage"
 )
 
+// ---------------------------------------------------------------------------
+// S3
+// ---------------------------------------------------------------------------
+
 // prerequisite: readRepoConfig must have been run prior to this to populate the global viper values.
 func s3ConfigsFromViper(vpr *viper.Viper) (storage.S3Config, error) {
 	var s3Config storage.S3Config
@@ -44,22 +50,39 @@ func s3Overrides(in map[string]string) map[string]string {
 	}
 }
 
-// configureStorage builds a complete storage configuration from a mix of
+func writeS3ConfigToViper(vpr *viper.Viper, s storage.Storage) error {
+	s3Config, err := s.S3Config()
+	if err != nil {
+		return err
+	}
+
+	s3Config = s3Config.Normalize()
+
+	vpr.Set(StorageProviderTypeKey, storage.ProviderS3.String())
+	vpr.Set(BucketNameKey, s3Config.Bucket)
+	vpr.Set(EndpointKey, s3Config.Endpoint)
+	vpr.Set(PrefixKey, s3Config.Prefix)
+	vpr.Set(DisableTLSKey, s3Config.DoNotUseTLS)
+	vpr.Set(DisableTLSVerificationKey, s3Config.DoNotVerifyTLS)
+
+	return nil
+}
+
+// configureS3Storage builds the s3 storage configuration from a mix of
 // viper properties and manual overrides.
-func configureStorage(
+func configureS3Storage(
 	vpr *viper.Viper,
 	readConfigFromViper bool,
 	overrides map[string]string,
-) (storage.Storage, error) {
+) (storage.S3Config, error) {
 	var (
 		s3Cfg storage.S3Config
-		store storage.Storage
 		err   error
 	)
 
 	if readConfigFromViper {
 		if s3Cfg, err = s3ConfigsFromViper(vpr); err != nil {
-			return store, errors.Wrap(err, "reading s3 configs from corso config file")
+			return s3Cfg, errors.Wrap(err, "reading s3 configs from corso config file")
 		}
 
 		if b, ok := overrides[storage.Bucket]; ok {
@@ -71,13 +94,13 @@ func configureStorage(
 		}
 
 		if err := mustMatchConfig(vpr, s3Overrides(overrides)); err != nil {
-			return store, errors.Wrap(err, "verifying s3 configs in corso config file")
+			return s3Cfg, errors.Wrap(err, "verifying s3 configs in corso config file")
 		}
 	}
 
 	_, err = defaults.CredChain(defaults.Config().WithCredentialsChainVerboseErrors(true), defaults.Handlers()).Get()
 	if err != nil {
-		return store, errors.Wrap(err, "validating aws credentials")
+		return s3Cfg, errors.Wrap(err, "validating aws credentials")
 	}
 
 	s3Cfg = storage.S3Config{
@@ -92,8 +115,494 @@ func configureStorage(
 			overrides[storage.DoNotVerifyTLS],
 			strconv.FormatBool(s3Cfg.DoNotVerifyTLS),
 			os.Getenv(storage.PrefixKey))),
+		// retention is only applied when initializing a repository, after
+		// which it's kept in the repository's own config.
+		RetentionMode: overrides[storage.RetentionMode],
+	}
+
+	if rp := overrides[storage.RetentionPeriod]; len(rp) > 0 {
+		if s3Cfg.RetentionPeriod, err = time.ParseDuration(rp); err != nil {
+			return s3Cfg, errors.Wrap(err, "parsing retention period")
+		}
+	}
+
+	// ensure required properties are present
+	if err := utils.RequireProps(map[string]string{
+		storage.Bucket: s3Cfg.Bucket,
+	}); err != nil {
+		return s3Cfg, err
+	}
+
+	return s3Cfg, nil
+}
+
+// ---------------------------------------------------------------------------
+// Filesystem
+// ---------------------------------------------------------------------------
+
+// prerequisite: readRepoConfig must have been run prior to this to populate the global viper values.
+func filesystemConfigsFromViper(vpr *viper.Viper) (storage.FilesystemConfig, error) {
+	var fsConfig storage.FilesystemConfig
+
+	providerType := vpr.GetString(StorageProviderTypeKey)
+	if providerType != storage.ProviderFilesystem.String() {
+		return fsConfig, errors.New("unsupported storage provider: " + providerType)
+	}
+
+	fsConfig.Path = vpr.GetString(FilesystemPathKey)
+
+	return fsConfig, nil
+}
+
+func filesystemOverrides(in map[string]string) map[string]string {
+	return map[string]string{
+		storage.FilesystemPath: in[storage.FilesystemPath],
+		StorageProviderTypeKey: in[StorageProviderTypeKey],
+	}
+}
+
+func writeFilesystemConfigToViper(vpr *viper.Viper, s storage.Storage) error {
+	fsConfig, err := s.FilesystemConfig()
+	if err != nil {
+		return err
+	}
+
+	fsConfig = fsConfig.Normalize()
+
+	vpr.Set(StorageProviderTypeKey, storage.ProviderFilesystem.String())
+	vpr.Set(FilesystemPathKey, fsConfig.Path)
+
+	return nil
+}
+
+// configureFilesystemStorage builds the filesystem storage configuration from
+// a mix of viper properties and manual overrides.
+func configureFilesystemStorage(
+	vpr *viper.Viper,
+	readConfigFromViper bool,
+	overrides map[string]string,
+) (storage.FilesystemConfig, error) {
+	var (
+		fsCfg storage.FilesystemConfig
+		err   error
+	)
+
+	if readConfigFromViper {
+		if fsCfg, err = filesystemConfigsFromViper(vpr); err != nil {
+			return fsCfg, errors.Wrap(err, "reading filesystem configs from corso config file")
+		}
+
+		if p, ok := overrides[storage.FilesystemPath]; ok && len(p) > 0 {
+			overrides[storage.FilesystemPath] = filepath.Clean(p)
+		}
+
+		if err := mustMatchConfig(vpr, filesystemOverrides(overrides)); err != nil {
+			return fsCfg, errors.Wrap(err, "verifying filesystem configs in corso config file")
+		}
+	}
+
+	fsCfg = storage.FilesystemConfig{
+		Path: common.First(
+			overrides[storage.FilesystemPath],
+			fsCfg.Path,
+			os.Getenv(storage.FilesystemPathKey)),
+	}
+
+	// ensure required properties are present
+	if err := utils.RequireProps(map[string]string{
+		storage.FilesystemPath: fsCfg.Path,
+	}); err != nil {
+		return fsCfg, err
+	}
+
+	return fsCfg, nil
+}
+
+// ---------------------------------------------------------------------------
+// Azure
+// ---------------------------------------------------------------------------
+
+// prerequisite: readRepoConfig must have been run prior to this to populate the global viper values.
+func azureConfigsFromViper(vpr *viper.Viper) (storage.AzureConfig, error) {
+	var azConfig storage.AzureConfig
+
+	providerType := vpr.GetString(StorageProviderTypeKey)
+	if providerType != storage.ProviderAzure.String() {
+		return azConfig, errors.New("unsupported storage provider: " + providerType)
+	}
+
+	azConfig.Container = vpr.GetString(ContainerKey)
+	azConfig.AccountName = vpr.GetString(AccountNameKey)
+	azConfig.Prefix = vpr.GetString(PrefixKey)
+	azConfig.Endpoint = vpr.GetString(EndpointKey)
+
+	return azConfig, nil
+}
+
+func azureOverrides(in map[string]string) map[string]string {
+	return map[string]string{
+		storage.Container:      in[storage.Container],
+		storage.AccountName:    in[storage.AccountName],
+		storage.Prefix:         in[storage.Prefix],
+		storage.Endpoint:       in[storage.Endpoint],
+		StorageProviderTypeKey: in[StorageProviderTypeKey],
+	}
+}
+
+// writeAzureConfigToViper persists the non-secret azure properties.  The
+// account key and sas token are always sourced from the environment.
+func writeAzureConfigToViper(vpr *viper.Viper, s storage.Storage) error {
+	azConfig, err := s.AzureConfig()
+	if err != nil {
+		return err
+	}
+
+	azConfig = azConfig.Normalize()
+
+	vpr.Set(StorageProviderTypeKey, storage.ProviderAzure.String())
+	vpr.Set(ContainerKey, azConfig.Container)
+	vpr.Set(AccountNameKey, azConfig.AccountName)
+	vpr.Set(PrefixKey, azConfig.Prefix)
+	vpr.Set(EndpointKey, azConfig.Endpoint)
+
+	return nil
+}
+
+// configureAzureStorage builds the azure storage configuration from a mix of
+// viper properties, manual overrides, and environment credentials.
+func configureAzureStorage(
+	vpr *viper.Viper,
+	readConfigFromViper bool,
+	overrides map[string]string,
+) (storage.AzureConfig, error) {
+	var (
+		azCfg storage.AzureConfig
+		err   error
+	)
+
+	if readConfigFromViper {
+		if azCfg, err = azureConfigsFromViper(vpr); err != nil {
+			return azCfg, errors.Wrap(err, "reading azure configs from corso config file")
+		}
+
+		if p, ok := overrides[storage.Prefix]; ok {
+			overrides[storage.Prefix] = common.NormalizePrefix(p)
+		}
+
+		if err := mustMatchConfig(vpr, azureOverrides(overrides)); err != nil {
+			return azCfg, errors.Wrap(err, "verifying azure configs in corso config file")
+		}
+	}
+
+	creds := credentials.GetAzureStorage()
+	if err := creds.Validate(); err != nil {
+		return azCfg, errors.Wrap(err, "validating azure storage credentials")
+	}
+
+	azCfg = storage.AzureConfig{
+		AzureStorage: creds,
+		Container: common.First(
+			overrides[storage.Container],
+			azCfg.Container,
+			os.Getenv(storage.ContainerKey)),
+		AccountName: common.First(
+			overrides[storage.AccountName],
+			azCfg.AccountName,
+			os.Getenv(storage.AccountNameKey)),
+		Prefix:   common.First(overrides[storage.Prefix], azCfg.Prefix, os.Getenv(storage.PrefixKey)),
+		Endpoint: common.First(overrides[storage.Endpoint], azCfg.Endpoint, os.Getenv(storage.EndpointKey)),
+	}
+
+	// ensure required properties are present
+	if err := utils.RequireProps(map[string]string{
+		storage.Container:   azCfg.Container,
+		storage.AccountName: azCfg.AccountName,
+	}); err != nil {
+		return azCfg, err
+	}
+
+	return azCfg, nil
+}
+
+// ---------------------------------------------------------------------------
+// GCS
+// ---------------------------------------------------------------------------
+
+// prerequisite: readRepoConfig must have been run prior to this to populate the global viper values.
+func gcsConfigsFromViper(vpr *viper.Viper) (storage.GCSConfig, error) {
+	var gcsConfig storage.GCSConfig
+
+	providerType := vpr.GetString(StorageProviderTypeKey)
+	if providerType != storage.ProviderGCS.String() {
+		return gcsConfig, errors.New("unsupported storage provider: " + providerType)
+	}
+
+	gcsConfig.Bucket = vpr.GetString(BucketNameKey)
+	gcsConfig.Prefix = vpr.GetString(PrefixKey)
+	gcsConfig.Endpoint = vpr.GetString(EndpointKey)
+
+	return gcsConfig, nil
+}
+
+func gcsOverrides(in map[string]string) map[string]string {
+	return map[string]string{
+		storage.Bucket:         in[storage.Bucket],
+		storage.Prefix:         in[storage.Prefix],
+		storage.Endpoint:       in[storage.Endpoint],
+		StorageProviderTypeKey: in[StorageProviderTypeKey],
+	}
+}
+
+// writeGCSConfigToViper persists the non-secret gcs properties.  The
+// service account credentials are always sourced from the environment.
+func writeGCSConfigToViper(vpr *viper.Viper, s storage.Storage) error {
+	gcsConfig, err := s.GCSConfig()
+	if err != nil {
+		return err
+	}
+
+	gcsConfig = gcsConfig.Normalize()
+
+	vpr.Set(StorageProviderTypeKey, storage.ProviderGCS.String())
+	vpr.Set(BucketNameKey, gcsConfig.Bucket)
+	vpr.Set(PrefixKey, gcsConfig.Prefix)
+	vpr.Set(EndpointKey, gcsConfig.Endpoint)
+
+	return nil
+}
+
+// configureGCSStorage builds the gcs storage configuration from a mix of
+// viper properties, manual overrides, and environment credentials.
+func configureGCSStorage(
+	vpr *viper.Viper,
+	readConfigFromViper bool,
+	overrides map[string]string,
+) (storage.GCSConfig, error) {
+	var (
+		gcsCfg storage.GCSConfig
+		err    error
+	)
+
+	if readConfigFromViper {
+		if gcsCfg, err = gcsConfigsFromViper(vpr); err != nil {
+			return gcsCfg, errors.Wrap(err, "reading gcs configs from corso config file")
+		}
+
+		if b, ok := overrides[storage.Bucket]; ok {
+			overrides[storage.Bucket] = common.NormalizeGCSBucket(b)
+		}
+
+		if p, ok := overrides[storage.Prefix]; ok {
+			overrides[storage.Prefix] = common.NormalizePrefix(p)
+		}
+
+		if err := mustMatchConfig(vpr, gcsOverrides(overrides)); err != nil {
+			return gcsCfg, errors.Wrap(err, "verifying gcs configs in corso config file")
+		}
+	}
+
+	gcsCfg = storage.GCSConfig{
+		GCS:      credentials.GetGCS(),
+		Bucket:   common.First(overrides[storage.Bucket], gcsCfg.Bucket, os.Getenv(storage.BucketKey)),
+		Prefix:   common.First(overrides[storage.Prefix], gcsCfg.Prefix, os.Getenv(storage.PrefixKey)),
+		Endpoint: common.First(overrides[storage.Endpoint], gcsCfg.Endpoint, os.Getenv(storage.EndpointKey)),
+	}
+
+	// ensure required properties are present
+	if err := utils.RequireProps(map[string]string{
+		storage.Bucket: gcsCfg.Bucket,
+	}); err != nil {
+		return gcsCfg, err
 	}
 
+	return gcsCfg, nil
+}
+
+// ---------------------------------------------------------------------------
+// SFTP
+// ---------------------------------------------------------------------------
+
+// prerequisite: readRepoConfig must have been run prior to this to populate the global viper values.
+func sftpConfigsFromViper(vpr *viper.Viper) (storage.SFTPConfig, error) {
+	var sftpConfig storage.SFTPConfig
+
+	providerType := vpr.GetString(StorageProviderTypeKey)
+	if providerType != storage.ProviderSFTP.String() {
+		return sftpConfig, errors.New("unsupported storage provider: " + providerType)
+	}
+
+	sftpConfig.Host = vpr.GetString(SFTPHostKey)
+	sftpConfig.Port = vpr.GetInt(SFTPPortKey)
+	sftpConfig.Username = vpr.GetString(SFTPUsernameKey)
+	sftpConfig.KeyFile = vpr.GetString(SFTPKeyFileKey)
+	sftpConfig.KnownHostsFile = vpr.GetString(SFTPKnownHostsFileKey)
+	sftpConfig.Path = vpr.GetString(SFTPPathKey)
+
+	return sftpConfig, nil
+}
+
+func sftpOverrides(in map[string]string) map[string]string {
+	return map[string]string{
+		storage.Host:           in[storage.Host],
+		storage.Port:           in[storage.Port],
+		storage.Username:       in[storage.Username],
+		storage.KeyFile:        in[storage.KeyFile],
+		storage.KnownHostsFile: in[storage.KnownHostsFile],
+		storage.SFTPPath:       in[storage.SFTPPath],
+		StorageProviderTypeKey: in[StorageProviderTypeKey],
+	}
+}
+
+// writeSFTPConfigToViper persists the non-secret sftp properties.  The
+// password is always sourced from the environment.
+func writeSFTPConfigToViper(vpr *viper.Viper, s storage.Storage) error {
+	sftpConfig, err := s.SFTPConfig()
+	if err != nil {
+		return err
+	}
+
+	sftpConfig = sftpConfig.Normalize()
+
+	vpr.Set(StorageProviderTypeKey, storage.ProviderSFTP.String())
+	vpr.Set(SFTPHostKey, sftpConfig.Host)
+	vpr.Set(SFTPPortKey, sftpConfig.Port)
+	vpr.Set(SFTPUsernameKey, sftpConfig.Username)
+	vpr.Set(SFTPKeyFileKey, sftpConfig.KeyFile)
+	vpr.Set(SFTPKnownHostsFileKey, sftpConfig.KnownHostsFile)
+	vpr.Set(SFTPPathKey, sftpConfig.Path)
+
+	return nil
+}
+
+// configureSFTPStorage builds the sftp storage configuration from a mix of
+// viper properties, manual overrides, and environment credentials.
+func configureSFTPStorage(
+	vpr *viper.Viper,
+	readConfigFromViper bool,
+	overrides map[string]string,
+) (storage.SFTPConfig, error) {
+	var (
+		sftpCfg storage.SFTPConfig
+		err     error
+	)
+
+	if readConfigFromViper {
+		if sftpCfg, err = sftpConfigsFromViper(vpr); err != nil {
+			return sftpCfg, errors.Wrap(err, "reading sftp configs from corso config file")
+		}
+
+		if p, ok := overrides[storage.SFTPPath]; ok && len(p) > 0 {
+			overrides[storage.SFTPPath] = path.Clean(p)
+		}
+
+		if err := mustMatchConfig(vpr, sftpOverrides(overrides)); err != nil {
+			return sftpCfg, errors.Wrap(err, "verifying sftp configs in corso config file")
+		}
+	}
+
+	var port int
+
+	if p := common.First(
+		overrides[storage.Port],
+		portString(sftpCfg.Port),
+		os.Getenv(storage.SFTPPortKey)); len(p) > 0 {
+		if port, err = strconv.Atoi(p); err != nil {
+			return sftpCfg, errors.Wrap(err, "parsing sftp port")
+		}
+	}
+
+	sftpCfg = storage.SFTPConfig{
+		SFTP: credentials.GetSFTP(),
+		Host: common.First(overrides[storage.Host], sftpCfg.Host, os.Getenv(storage.SFTPHostKey)),
+		Port: port,
+		Username: common.First(
+			overrides[storage.Username],
+			sftpCfg.Username,
+			os.Getenv(storage.SFTPUsernameKey)),
+		KeyFile: common.First(
+			overrides[storage.KeyFile],
+			sftpCfg.KeyFile,
+			os.Getenv(storage.SFTPKeyFileKey)),
+		KnownHostsFile: common.First(
+			overrides[storage.KnownHostsFile],
+			sftpCfg.KnownHostsFile,
+			os.Getenv(storage.SFTPKnownHostsFileKey)),
+		Path: common.First(overrides[storage.SFTPPath], sftpCfg.Path, os.Getenv(storage.SFTPPathKey)),
+	}
+
+	// ensure required properties are present
+	if err := utils.RequireProps(map[string]string{
+		storage.Host:     sftpCfg.Host,
+		storage.Username: sftpCfg.Username,
+		storage.SFTPPath: sftpCfg.Path,
+	}); err != nil {
+		return sftpCfg, err
+	}
+
+	return sftpCfg, nil
+}
+
+// portString produces the string form of a port, treating the zero
+// value as unset.
+func portString(port int) string {
+	if port == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(port)
+}
+
+// ---------------------------------------------------------------------------
+// Storage
+// ---------------------------------------------------------------------------
+
+// writeStorageConfigToViper sets the provider-specific storage properties
+// in viper.
+func writeStorageConfigToViper(vpr *viper.Viper, s storage.Storage) error {
+	switch s.Provider {
+	case storage.ProviderS3:
+		return writeS3ConfigToViper(vpr, s)
+	case storage.ProviderFilesystem:
+		return writeFilesystemConfigToViper(vpr, s)
+	case storage.ProviderAzure:
+		return writeAzureConfigToViper(vpr, s)
+	case storage.ProviderGCS:
+		return writeGCSConfigToViper(vpr, s)
+	case storage.ProviderSFTP:
+		return writeSFTPConfigToViper(vpr, s)
+	default:
+		return errors.New("unsupported storage provider: " + s.Provider.String())
+	}
+}
+
+// storageProviderType identifies the storage provider to configure.  Flag
+// overrides take precedence over the config file.  If neither specifies a
+// provider, falls back to S3.
+func storageProviderType(
+	vpr *viper.Viper,
+	readConfigFromViper bool,
+	overrides map[string]string,
+) string {
+	var fromViper string
+	if readConfigFromViper {
+		fromViper = vpr.GetString(StorageProviderTypeKey)
+	}
+
+	return common.First(overrides[StorageProviderTypeKey], fromViper, storage.ProviderS3.String())
+}
+
+// configureStorage builds a complete storage configuration from a mix of
+// viper properties and manual overrides.
+func configureStorage(
+	vpr *viper.Viper,
+	readConfigFromViper bool,
+	overrides map[string]string,
+) (storage.Storage, error) {
+	var (
+		store    storage.Storage
+		provider = storageProviderType(vpr, readConfigFromViper, overrides)
+	)
+
 	// compose the common config and credentials
 	corso := credentials.GetCorso()
 	if err := corso.Validate(); err != nil {
@@ -102,6 +611,8 @@ func configureStorage(
 
 	cCfg := storage.CommonConfig{
 		Corso: corso,
+		// only set for profiles, which each keep their own kopia config.
+		KopiaCfgDir: vpr.GetString(KopiaConfigDirKey),
 	}
 	// the following is a hack purely for integration testing.
 	// the value is not required, and if empty, kopia will default
@@ -113,14 +624,64 @@ func configureStorage(
 
 	// ensure required properties are present
 	if err := utils.RequireProps(map[string]string{
-		storage.Bucket:              s3Cfg.Bucket,
 		credentials.CorsoPassphrase: corso.CorsoPassphrase,
 	}); err != nil {
 		return storage.Storage{}, err
 	}
 
+	var err error
+
 	// build the storage
-	store, err = storage.NewStorage(storage.ProviderS3, s3Cfg, cCfg)
+	switch provider {
+	case storage.ProviderS3.String():
+		var s3Cfg storage.S3Config
+
+		if s3Cfg, err = configureS3Storage(vpr, readConfigFromViper, overrides); err != nil {
+			return store, err
+		}
+
+		store, err = storage.NewStorage(storage.ProviderS3, s3Cfg, cCfg)
+
+	case storage.ProviderFilesystem.String():
+		var fsCfg storage.FilesystemConfig
+
+		if fsCfg, err = configureFilesystemStorage(vpr, readConfigFromViper, overrides); err != nil {
+			return store, err
+		}
+
+		store, err = storage.NewStorage(storage.ProviderFilesystem, fsCfg, cCfg)
+
+	case storage.ProviderAzure.String():
+		var azCfg storage.AzureConfig
+
+		if azCfg, err = configureAzureStorage(vpr, readConfigFromViper, overrides); err != nil {
+			return store, err
+		}
+
+		store, err = storage.NewStorage(storage.ProviderAzure, azCfg, cCfg)
+
+	case storage.ProviderGCS.String():
+		var gcsCfg storage.GCSConfig
+
+		if gcsCfg, err = configureGCSStorage(vpr, readConfigFromViper, overrides); err != nil {
+			return store, err
+		}
+
+		store, err = storage.NewStorage(storage.ProviderGCS, gcsCfg, cCfg)
+
+	case storage.ProviderSFTP.String():
+		var sftpCfg storage.SFTPConfig
+
+		if sftpCfg, err = configureSFTPStorage(vpr, readConfigFromViper, overrides); err != nil {
+			return store, err
+		}
+
+		store, err = storage.NewStorage(storage.ProviderSFTP, sftpCfg, cCfg)
+
+	default:
+		return store, errors.New("unsupported storage provider: " + provider)
+	}
+
 	if err != nil {
 		return store, errors.Wrap(err, "configuring repository storage")
 	}
@@ -0,0 +1,141 @@
+package export
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/alcionai/corso/src/cli/options"
+	"github.com/alcionai/corso/src/cli/utils"
+)
+
+// exchange bucket info from flags
+var (
+	user []string
+
+	contact       []string
+	contactFolder []string
+	email         []string
+	emailFolder   []string
+	event         []string
+	eventCalendar []string
+)
+
+// called by export.go to map subcommands to provider-specific handling.
+func addExchangeCommands(cmd *cobra.Command) *cobra.Command {
+	var (
+		c  *cobra.Command
+		fs *pflag.FlagSet
+	)
+
+	switch cmd.Use {
+	case exportCommand:
+		c, fs = utils.AddCommand(cmd, exchangeExportCmd())
+
+		c.Use = c.Use + " " + exchangeServiceCommandUseSuffix
+
+		// Flags addition ordering should follow the order we want them to appear in help and docs:
+		// More generic (ex: --user) and more frequently used flags take precedence.
+		fs.SortFlags = false
+
+		addExportFlags(c, fs)
+
+		fs.StringSliceVar(&user,
+			utils.UserFN, nil,
+			"Export data by user ID; accepts '"+utils.Wildcard+"' to select all users.")
+
+		// email flags
+		fs.StringSliceVar(&email,
+			utils.EmailFN, nil,
+			"Export emails by ID; accepts '"+utils.Wildcard+"' to select all emails.")
+		fs.StringSliceVar(
+			&emailFolder,
+			utils.EmailFolderFN, nil,
+			"Export emails within a folder; accepts '"+utils.Wildcard+"' to select all email folders.")
+
+		// event flags
+		fs.StringSliceVar(&event,
+			utils.EventFN, nil,
+			"Export events by event ID; accepts '"+utils.Wildcard+"' to select all events.")
+		fs.StringSliceVar(
+			&eventCalendar,
+			utils.EventCalendarFN, nil,
+			"Export events under a calendar; accepts '"+utils.Wildcard+"' to select all event calendars.")
+
+		// contacts flags
+		fs.StringSliceVar(
+			&contact,
+			utils.ContactFN, nil,
+			"Export contacts by contact ID; accepts '"+utils.Wildcard+"' to select all contacts.")
+		fs.StringSliceVar(
+			&contactFolder,
+			utils.ContactFolderFN, nil,
+			"Export contacts within a folder; accepts '"+utils.Wildcard+"' to select all contact folders.")
+
+		// others
+		options.AddExportFlags(c)
+		options.AddOperationFlags(c)
+	}
+
+	return c
+}
+
+const (
+	exchangeServiceCommand          = "exchange"
+	exchangeServiceCommandUseSuffix = "--backup <backupId> --output <directory>"
+
+	exchangeServiceCommandExportExamples = `# Export all of Alice's emails from a specific backup into ./alice
+corso export exchange --backup 1234abcd-12ab-cd34-56de-1234abcd \
+      --output ./alice --user alice@example.com --email '*'
+
+# Export Bob's entire calendar from a specific backup
+corso export exchange --backup 1234abcd-12ab-cd34-56de-1234abcd \
+      --output ./bob --user bob@example.com --event-calendar Calendar
+
+# Export Carol's Inbox as an mbox file for import into another mail client
+corso export exchange --backup 1234abcd-12ab-cd34-56de-1234abcd \
+      --output ./carol --user carol@example.com --email-folder Inbox --mail-format mbox
+
+# Export Dave's contacts as vCards
+corso export exchange --backup 1234abcd-12ab-cd34-56de-1234abcd \
+      --output ./dave --user dave@example.com --contact-folder '*'`
+)
+
+// `corso export exchange [<flag>...]`
+func exchangeExportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     exchangeServiceCommand,
+		Short:   "Export M365 Exchange service data",
+		RunE:    exportExchangeCmd,
+		Args:    cobra.NoArgs,
+		Example: exchangeServiceCommandExportExamples,
+	}
+}
+
+// processes an exchange service export.
+func exportExchangeCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if utils.HasNoFlagsAndShownHelp(cmd) {
+		return nil
+	}
+
+	opts := utils.ExchangeOpts{
+		Contact:       contact,
+		ContactFolder: contactFolder,
+		Email:         email,
+		EmailFolder:   emailFolder,
+		Event:         event,
+		EventCalendar: eventCalendar,
+		Users:         user,
+
+		Populated: utils.GetPopulatedFlags(cmd),
+	}
+
+	if err := utils.ValidateExchangeRestoreFlags(backupID, opts); err != nil {
+		return err
+	}
+
+	sel := utils.IncludeExchangeRestoreDataSelectors(opts)
+
+	return runExport(ctx, sel.Selector, "Exchange")
+}
@@ -0,0 +1,120 @@
+package export
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/alcionai/corso/src/cli/config"
+	"github.com/alcionai/corso/src/cli/options"
+	. "github.com/alcionai/corso/src/cli/print"
+	"github.com/alcionai/corso/src/cli/utils"
+	"github.com/alcionai/corso/src/internal/kopia"
+	"github.com/alcionai/corso/src/pkg/repository"
+	"github.com/alcionai/corso/src/pkg/selectors"
+)
+
+var exportCommands = []func(cmd *cobra.Command) *cobra.Command{
+	addExchangeCommands,
+	addOneDriveCommands,
+	addSharePointCommands,
+}
+
+// AddCommands attaches all `corso export * *` commands to the parent.
+func AddCommands(cmd *cobra.Command) {
+	exportC := exportCmd()
+	cmd.AddCommand(exportC)
+
+	for _, addExportTo := range exportCommands {
+		addExportTo(exportC)
+	}
+}
+
+const exportCommand = "export"
+
+// The export category of commands.
+// `corso export [<subcommand>] [<flag>...]`
+func exportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   exportCommand,
+		Short: "Export your service data",
+		Long: `Export the data stored in one of your M365 services to a local directory.
+Exported data is written to disk, and is not restored into M365.`,
+		RunE: handleExportCmd,
+		Args: cobra.NoArgs,
+	}
+}
+
+// Handler for flat calls to `corso export`.
+// Produces the same output as `corso export --help`.
+func handleExportCmd(cmd *cobra.Command, args []string) error {
+	return cmd.Help()
+}
+
+// export info from flags
+var (
+	backupID  string
+	outputDir string
+)
+
+// adds the flags shared by all export commands.
+func addExportFlags(c *cobra.Command, fs *pflag.FlagSet) {
+	fs.StringVar(&backupID,
+		utils.BackupFN, "",
+		"ID of the backup to export. (required)")
+	cobra.CheckErr(c.MarkFlagRequired(utils.BackupFN))
+
+	fs.StringVar(&outputDir,
+		utils.OutputFN, "",
+		"Directory that exported data is written to. (required)")
+	cobra.CheckErr(c.MarkFlagRequired(utils.OutputFN))
+}
+
+// runExport connects to the repository and exports the data selected by the
+// selector into the output directory.  The serviceName is used to
+// contextualize error messages.
+func runExport(ctx context.Context, sel selectors.Selector, serviceName string) error {
+	if len(outputDir) == 0 {
+		return errors.New("an output directory is required")
+	}
+
+	dir, err := filepath.Abs(outputDir)
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "resolving output directory"))
+	}
+
+	s, a, err := config.GetStorageAndAccount(ctx, true, nil)
+	if err != nil {
+		return Only(ctx, err)
+	}
+
+	r, err := repository.Connect(ctx, a, s, options.Control())
+	if err != nil {
+		return Only(ctx, errors.Wrapf(err, "Failed to connect to the %s repository", s.Provider))
+	}
+
+	defer utils.CloseRepo(ctx, r)
+
+	eo, err := r.NewExport(ctx, backupID, sel, dir)
+	if err != nil {
+		return Only(ctx, errors.Wrapf(err, "Failed to initialize %s export", serviceName))
+	}
+
+	ds, err := eo.Run(ctx)
+	if err != nil {
+		if errors.Is(err, kopia.ErrNotFound) {
+			return Only(ctx, errors.Errorf("Backup or backup details missing for id %s", backupID))
+		}
+
+		return Only(ctx, errors.Wrapf(err, "Failed to run %s export", serviceName))
+	}
+
+	Infof(ctx, "Exported %d items to %s\n", eo.Results.ItemsWritten, dir)
+
+	ds.PrintEntries(ctx)
+
+	return nil
+}
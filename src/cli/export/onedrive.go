@@ -0,0 +1,106 @@
+package export
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/alcionai/corso/src/cli/options"
+	"github.com/alcionai/corso/src/cli/utils"
+)
+
+// onedrive bucket info from flags
+var (
+	folderPaths []string
+	fileNames   []string
+)
+
+// called by export.go to map subcommands to provider-specific handling.
+func addOneDriveCommands(cmd *cobra.Command) *cobra.Command {
+	var (
+		c  *cobra.Command
+		fs *pflag.FlagSet
+	)
+
+	switch cmd.Use {
+	case exportCommand:
+		c, fs = utils.AddCommand(cmd, oneDriveExportCmd())
+
+		c.Use = c.Use + " " + oneDriveServiceCommandUseSuffix
+
+		// Flags addition ordering should follow the order we want them to appear in help and docs:
+		// More generic (ex: --user) and more frequently used flags take precedence.
+		fs.SortFlags = false
+
+		addExportFlags(c, fs)
+
+		fs.StringSliceVar(&user,
+			utils.UserFN, nil,
+			"Export data by user ID; accepts '"+utils.Wildcard+"' to select all users.")
+
+		// onedrive hierarchy (path/name) flags
+
+		fs.StringSliceVar(
+			&folderPaths,
+			utils.FolderFN, nil,
+			"Export items by OneDrive folder; defaults to root")
+
+		fs.StringSliceVar(
+			&fileNames,
+			utils.FileFN, nil,
+			"Export items by file name or ID")
+
+		// others
+		options.AddOperationFlags(c)
+	}
+
+	return c
+}
+
+const (
+	oneDriveServiceCommand          = "onedrive"
+	oneDriveServiceCommandUseSuffix = "--backup <backupId> --output <directory>"
+
+	oneDriveServiceCommandExportExamples = `# Export all of Alice's files from a specific backup into ./alice
+corso export onedrive --backup 1234abcd-12ab-cd34-56de-1234abcd \
+      --output ./alice --user alice@example.com
+
+# Export Bob's "Documents/Finance Reports" folder from a specific backup
+corso export onedrive --backup 1234abcd-12ab-cd34-56de-1234abcd \
+      --output ./bob --user bob@example.com --folder "Documents/Finance Reports"`
+)
+
+// `corso export onedrive [<flag>...]`
+func oneDriveExportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     oneDriveServiceCommand,
+		Short:   "Export M365 OneDrive service data",
+		RunE:    exportOneDriveCmd,
+		Args:    cobra.NoArgs,
+		Example: oneDriveServiceCommandExportExamples,
+	}
+}
+
+// processes an onedrive service export.
+func exportOneDriveCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if utils.HasNoFlagsAndShownHelp(cmd) {
+		return nil
+	}
+
+	opts := utils.OneDriveOpts{
+		Users: user,
+		Paths: folderPaths,
+		Names: fileNames,
+
+		Populated: utils.GetPopulatedFlags(cmd),
+	}
+
+	if err := utils.ValidateOneDriveRestoreFlags(backupID, opts); err != nil {
+		return err
+	}
+
+	sel := utils.IncludeOneDriveRestoreDataSelectors(opts)
+
+	return runExport(ctx, sel.Selector, "OneDrive")
+}
@@ -0,0 +1,123 @@
+package export
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/alcionai/corso/src/cli/options"
+	"github.com/alcionai/corso/src/cli/utils"
+)
+
+// sharepoint bucket info from flags
+var (
+	listItems    []string
+	listPaths    []string
+	libraryItems []string
+	libraryPaths []string
+	site         []string
+	weburl       []string
+)
+
+// called by export.go to map subcommands to provider-specific handling.
+func addSharePointCommands(cmd *cobra.Command) *cobra.Command {
+	var (
+		c  *cobra.Command
+		fs *pflag.FlagSet
+	)
+
+	switch cmd.Use {
+	case exportCommand:
+		c, fs = utils.AddCommand(cmd, sharePointExportCmd(), utils.HideCommand())
+
+		c.Use = c.Use + " " + sharePointServiceCommandUseSuffix
+
+		// Flags addition ordering should follow the order we want them to appear in help and docs:
+		// More generic (ex: --site) and more frequently used flags take precedence.
+		fs.SortFlags = false
+
+		addExportFlags(c, fs)
+
+		fs.StringSliceVar(&site,
+			utils.SiteFN, nil,
+			"Export data by site ID; accepts '"+utils.Wildcard+"' to select all sites.")
+
+		fs.StringSliceVar(&weburl,
+			utils.WebURLFN, nil,
+			"Export data by site webURL; accepts '"+utils.Wildcard+"' to select all sites.")
+
+		// sharepoint hierarchy (path/name) flags
+
+		fs.StringSliceVar(
+			&libraryPaths,
+			utils.LibraryFN, nil,
+			"Export library items by SharePoint library")
+
+		fs.StringSliceVar(
+			&libraryItems,
+			utils.LibraryItemFN, nil,
+			"Export library items by file name or ID")
+
+		fs.StringSliceVar(
+			&listPaths,
+			utils.ListFN, nil,
+			"Export list items by SharePoint list ID")
+
+		fs.StringSliceVar(
+			&listItems,
+			utils.ListItemFN, nil,
+			"Export list items by ID")
+
+		// others
+		options.AddOperationFlags(c)
+	}
+
+	return c
+}
+
+const (
+	sharePointServiceCommand          = "sharepoint"
+	sharePointServiceCommandUseSuffix = "--backup <backupId> --output <directory>"
+
+	sharePointServiceCommandExportExamples = `# Export all library files from <site> from a specific backup into ./site
+corso export sharepoint --backup 1234abcd-12ab-cd34-56de-1234abcd \
+      --output ./site --site <siteID> --library '*'`
+)
+
+// `corso export sharepoint [<flag>...]`
+func sharePointExportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     sharePointServiceCommand,
+		Short:   "Export M365 SharePoint service data",
+		RunE:    exportSharePointCmd,
+		Args:    cobra.NoArgs,
+		Example: sharePointServiceCommandExportExamples,
+	}
+}
+
+// processes an sharepoint service export.
+func exportSharePointCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if utils.HasNoFlagsAndShownHelp(cmd) {
+		return nil
+	}
+
+	opts := utils.SharePointOpts{
+		ListItems:    listItems,
+		ListPaths:    listPaths,
+		LibraryItems: libraryItems,
+		LibraryPaths: libraryPaths,
+		Sites:        site,
+		WebURLs:      weburl,
+
+		Populated: utils.GetPopulatedFlags(cmd),
+	}
+
+	if err := utils.ValidateSharePointRestoreFlags(backupID, opts); err != nil {
+		return err
+	}
+
+	sel := utils.IncludeSharePointRestoreDataSelectors(opts)
+
+	return runExport(ctx, sel.Selector, "SharePoint")
+}
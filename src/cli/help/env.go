@@ -65,6 +65,10 @@ var (
 	corsoEVs = []envVar{
 		{corso, "CORSO_PASSPHRASE", "Passphrase to protect encrypted repository contents. " +
 			"It is impossible to use the repository or recover any backups without this key."},
+		{corso, "CORSO_NEW_PASSPHRASE", "Replacement passphrase used by 'corso repo update-passphrase'. " +
+			"Once the passphrase is updated, CORSO_PASSPHRASE must be set to this value."},
+		{corso, "CORSO_SYNC_PASSPHRASE", "Passphrase of the repository that 'corso repo sync' copies backups into. " +
+			"Defaults to CORSO_PASSPHRASE."},
 	}
 	azureEVs = []envVar{
 		{azure, "AZURE_CLIENT_ID", "Client ID for your Azure AD application used to access your M365 tenant."},
@@ -1,9 +1,13 @@
 package options
 
 import (
+	"strings"
+
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
+	"github.com/alcionai/corso/src/internal/kopia"
 	"github.com/alcionai/corso/src/pkg/control"
 )
 
@@ -23,6 +27,44 @@ func Control() control.Options {
 		opt.ToggleFeatures.DisableIncrementals = true
 	}
 
+	if collisions.policy != control.Unknown {
+		opt.Collision = collisions.policy
+	}
+
+	if skipPermissions {
+		opt.Permissions.Skip = true
+	}
+
+	if len(principalMap) > 0 {
+		opt.Permissions.PrincipalMap = principalMap
+	}
+
+	if includeVersions {
+		opt.Versions.Backup = true
+	}
+
+	if len(restoreVersion) > 0 {
+		opt.Versions.Restore = restoreVersion
+	}
+
+	if len(mailFormat.format) > 0 {
+		opt.Export.MailFormat = mailFormat.format
+	}
+
+	if len(contactFormat.format) > 0 {
+		opt.Export.ContactFormat = contactFormat.format
+	}
+
+	if len(eventFormat.format) > 0 {
+		opt.Export.EventFormat = eventFormat.format
+	}
+
+	opt.Format = control.RepoFormat{
+		Hash:       hashAlgorithm.name,
+		Encryption: encryptionAlgorithm.name,
+		Splitter:   splitterAlgorithm.name,
+	}
+
 	return opt
 }
 
@@ -49,6 +91,248 @@ func AddGlobalOperationFlags(cmd *cobra.Command) {
 	fs.BoolVar(&noStats, "no-stats", false, "disable anonymous usage statistics gathering")
 }
 
+// ---------------------------------------------------------------------------
+// Restore Flags
+// ---------------------------------------------------------------------------
+
+const CollisionsFN = "collisions"
+
+var collisionPolicies = map[string]control.CollisionPolicy{
+	"copy":    control.Copy,
+	"skip":    control.Skip,
+	"replace": control.Replace,
+}
+
+// collisionFlag is a pflag.Value which only accepts the names of the
+// supported collision policies.
+type collisionFlag struct {
+	name   string
+	policy control.CollisionPolicy
+}
+
+var collisions = collisionFlag{name: "copy", policy: control.Copy}
+
+func (cf *collisionFlag) String() string { return cf.name }
+func (cf *collisionFlag) Type() string   { return "string" }
+
+func (cf *collisionFlag) Set(s string) error {
+	name := strings.ToLower(s)
+
+	cp, ok := collisionPolicies[name]
+	if !ok {
+		return errors.New("must be one of: copy, skip, replace")
+	}
+
+	cf.name = name
+	cf.policy = cp
+
+	return nil
+}
+
+// AddRestoreFlags adds the flags that control restore behavior.
+func AddRestoreFlags(cmd *cobra.Command) {
+	fs := cmd.Flags()
+	fs.Var(
+		&collisions,
+		CollisionsFN,
+		"How to handle items that already exist in the restore destination: "+
+			"copy (restore alongside the existing item), skip (leave the existing item in place), "+
+			"or replace (overwrite the existing item).")
+}
+
+// ---------------------------------------------------------------------------
+// Permissions Flags
+// ---------------------------------------------------------------------------
+
+const (
+	MapPrincipalFN    = "map-principal"
+	SkipPermissionsFN = "skip-permissions"
+)
+
+var (
+	skipPermissions bool
+	principalMap    map[string]string
+)
+
+// AddPermissionsFlags adds the flags that control how the permissions of
+// restored drive items are handled.
+func AddPermissionsFlags(cmd *cobra.Command) {
+	fs := cmd.Flags()
+	fs.BoolVar(
+		&skipPermissions,
+		SkipPermissionsFN,
+		false,
+		"Restore items without the permissions and sharing links granted on them at backup time.")
+	fs.StringToStringVar(
+		&principalMap,
+		MapPrincipalFN,
+		nil,
+		"Grant restored permissions to a different user, given as old=new. "+
+			"Users are identified by email or ID. May be repeated.")
+}
+
+// ---------------------------------------------------------------------------
+// Version History Flags
+// ---------------------------------------------------------------------------
+
+const (
+	IncludeVersionsFN = "include-versions"
+	VersionFN         = "version"
+)
+
+var (
+	includeVersions bool
+	restoreVersion  string
+)
+
+// AddVersionsBackupFlags adds the flag that opts into backing up the version
+// history of files.
+func AddVersionsBackupFlags(cmd *cobra.Command) {
+	fs := cmd.Flags()
+	fs.BoolVar(
+		&includeVersions,
+		IncludeVersionsFN,
+		false,
+		"Also back up the prior versions of each file.")
+}
+
+// AddVersionRestoreFlags adds the flag that picks the prior version of files
+// to restore.
+func AddVersionRestoreFlags(cmd *cobra.Command) {
+	fs := cmd.Flags()
+	fs.StringVar(
+		&restoreVersion,
+		VersionFN,
+		"",
+		"Restore this prior version of each file instead of its current content. "+
+			"Version IDs are listed by 'corso backup details'; files without the version are not restored.")
+}
+
+// ---------------------------------------------------------------------------
+// Export Flags
+// ---------------------------------------------------------------------------
+
+const (
+	ContactFormatFN = "contact-format"
+	EventFormatFN   = "event-format"
+	MailFormatFN    = "mail-format"
+)
+
+// formatFlag is a pflag.Value which only accepts the listed export formats.
+type formatFlag struct {
+	format  control.ExportFormat
+	allowed []control.ExportFormat
+}
+
+var (
+	mailFormat = formatFlag{
+		allowed: []control.ExportFormat{control.EMLFormat, control.MboxFormat, control.JSONFormat},
+	}
+	contactFormat = formatFlag{
+		allowed: []control.ExportFormat{control.VCFFormat, control.JSONFormat},
+	}
+	eventFormat = formatFlag{
+		allowed: []control.ExportFormat{control.ICSFormat, control.JSONFormat},
+	}
+)
+
+func (ff *formatFlag) String() string { return string(ff.format) }
+func (ff *formatFlag) Type() string   { return "string" }
+
+func (ff *formatFlag) Set(s string) error {
+	format := control.ExportFormat(strings.ToLower(s))
+	names := make([]string, 0, len(ff.allowed))
+
+	for _, a := range ff.allowed {
+		if a == format {
+			ff.format = format
+			return nil
+		}
+
+		names = append(names, string(a))
+	}
+
+	return errors.New("must be one of: " + strings.Join(names, ", "))
+}
+
+// AddExportFlags adds the flags that control the format of exported data.
+func AddExportFlags(cmd *cobra.Command) {
+	fs := cmd.Flags()
+	fs.Var(
+		&mailFormat,
+		MailFormatFN,
+		"Format of exported mail: eml (one file per message), mbox (one file per folder), "+
+			"or json (the raw Graph API message). Defaults to eml.")
+	fs.Var(
+		&contactFormat,
+		ContactFormatFN,
+		"Format of exported contacts: vcf (vCard 4.0) or json (the raw Graph API contact). Defaults to vcf.")
+	fs.Var(
+		&eventFormat,
+		EventFormatFN,
+		"Format of exported events: ics (iCalendar) or json (the raw Graph API event). Defaults to ics.")
+}
+
+// ---------------------------------------------------------------------------
+// Repository Format Flags
+// ---------------------------------------------------------------------------
+
+const (
+	EncryptionFN = "encryption"
+	HashFN       = "hash"
+	SplitterFN   = "splitter"
+)
+
+// algorithmFlag is a pflag.Value which only accepts the algorithms that
+// a new repository supports.
+type algorithmFlag struct {
+	name    string
+	allowed func() []string
+}
+
+var (
+	hashAlgorithm       = algorithmFlag{allowed: kopia.HashAlgorithms}
+	encryptionAlgorithm = algorithmFlag{allowed: kopia.EncryptionAlgorithms}
+	splitterAlgorithm   = algorithmFlag{allowed: kopia.SplitterAlgorithms}
+)
+
+func (af *algorithmFlag) String() string { return af.name }
+func (af *algorithmFlag) Type() string   { return "string" }
+
+func (af *algorithmFlag) Set(s string) error {
+	allowed := af.allowed()
+
+	for _, a := range allowed {
+		if strings.EqualFold(a, s) {
+			af.name = a
+			return nil
+		}
+	}
+
+	return errors.New("must be one of: " + strings.Join(allowed, ", "))
+}
+
+// AddRepoFormatFlags adds the flags that choose the algorithms used by a
+// new repository.
+func AddRepoFormatFlags(cmd *cobra.Command) {
+	defaults := kopia.DefaultRepoFormat()
+
+	fs := cmd.Flags()
+	fs.Var(
+		&encryptionAlgorithm,
+		EncryptionFN,
+		"Encryption algorithm for backup data: "+strings.Join(kopia.EncryptionAlgorithms(), " or ")+
+			". Defaults to "+defaults.Encryption+".")
+	fs.Var(
+		&hashAlgorithm,
+		HashFN,
+		"Hash algorithm used to identify backup data. Defaults to "+defaults.Hash+".")
+	fs.Var(
+		&splitterAlgorithm,
+		SplitterFN,
+		"Splitter used to break large items into chunks. Defaults to "+defaults.Splitter+".")
+}
+
 // ---------------------------------------------------------------------------
 // Feature Flags
 // ---------------------------------------------------------------------------
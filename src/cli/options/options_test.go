@@ -0,0 +1,216 @@
+package options
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/pkg/control"
+)
+
+type OptionsUnitSuite struct {
+	suite.Suite
+}
+
+func TestOptionsUnitSuite(t *testing.T) {
+	suite.Run(t, new(OptionsUnitSuite))
+}
+
+func (suite *OptionsUnitSuite) TestRestoreCollisionFlag() {
+	table := []struct {
+		name      string
+		args      []string
+		expect    control.CollisionPolicy
+		expectErr assert.ErrorAssertionFunc
+	}{
+		{"default", []string{}, control.Copy, assert.NoError},
+		{"copy", []string{"--" + CollisionsFN, "copy"}, control.Copy, assert.NoError},
+		{"skip", []string{"--" + CollisionsFN, "skip"}, control.Skip, assert.NoError},
+		{"replace", []string{"--" + CollisionsFN, "Replace"}, control.Replace, assert.NoError},
+		{"invalid", []string{"--" + CollisionsFN, "merge"}, control.Copy, assert.Error},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			collisions = collisionFlag{name: "copy", policy: control.Copy}
+
+			cmd := &cobra.Command{Use: "test"}
+			AddRestoreFlags(cmd)
+			require.NotNil(t, cmd.Flags().Lookup(CollisionsFN))
+
+			test.expectErr(t, cmd.ParseFlags(test.args))
+			assert.Equal(t, test.expect, Control().Collision)
+		})
+	}
+}
+
+func (suite *OptionsUnitSuite) TestExportMailFormatFlag() {
+	table := []struct {
+		name      string
+		args      []string
+		expect    control.ExportFormat
+		expectErr assert.ErrorAssertionFunc
+	}{
+		{"default", []string{}, control.EMLFormat, assert.NoError},
+		{"eml", []string{"--" + MailFormatFN, "eml"}, control.EMLFormat, assert.NoError},
+		{"mbox", []string{"--" + MailFormatFN, "MBOX"}, control.MboxFormat, assert.NoError},
+		{"json", []string{"--" + MailFormatFN, "json"}, control.JSONFormat, assert.NoError},
+		{"invalid", []string{"--" + MailFormatFN, "pst"}, control.EMLFormat, assert.Error},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			mailFormat.format = ""
+
+			cmd := &cobra.Command{Use: "test"}
+			AddExportFlags(cmd)
+			require.NotNil(t, cmd.Flags().Lookup(MailFormatFN))
+
+			test.expectErr(t, cmd.ParseFlags(test.args))
+			assert.Equal(t, test.expect, Control().Export.MailFormat)
+		})
+	}
+}
+
+func (suite *OptionsUnitSuite) TestExportContactAndEventFormatFlags() {
+	table := []struct {
+		name          string
+		args          []string
+		expectContact control.ExportFormat
+		expectEvent   control.ExportFormat
+		expectErr     assert.ErrorAssertionFunc
+	}{
+		{"default", []string{}, control.VCFFormat, control.ICSFormat, assert.NoError},
+		{
+			"json",
+			[]string{"--" + ContactFormatFN, "json", "--" + EventFormatFN, "JSON"},
+			control.JSONFormat, control.JSONFormat, assert.NoError,
+		},
+		{"invalid contact", []string{"--" + ContactFormatFN, "ics"}, control.VCFFormat, control.ICSFormat, assert.Error},
+		{"invalid event", []string{"--" + EventFormatFN, "vcf"}, control.VCFFormat, control.ICSFormat, assert.Error},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			contactFormat.format = ""
+			eventFormat.format = ""
+
+			cmd := &cobra.Command{Use: "test"}
+			AddExportFlags(cmd)
+
+			test.expectErr(t, cmd.ParseFlags(test.args))
+
+			opts := Control()
+			assert.Equal(t, test.expectContact, opts.Export.ContactFormat)
+			assert.Equal(t, test.expectEvent, opts.Export.EventFormat)
+		})
+	}
+}
+
+func (suite *OptionsUnitSuite) TestRepoFormatFlags() {
+	table := []struct {
+		name      string
+		args      []string
+		expect    control.RepoFormat
+		expectErr assert.ErrorAssertionFunc
+	}{
+		{"default", []string{}, control.RepoFormat{}, assert.NoError},
+		{
+			name: "all",
+			args: []string{
+				"--" + EncryptionFN, "chacha20-poly1305-hmac-sha256",
+				"--" + HashFN, "BLAKE3-256",
+				"--" + SplitterFN, "FIXED-4M",
+			},
+			expect: control.RepoFormat{
+				Hash:       "BLAKE3-256",
+				Encryption: "CHACHA20-POLY1305-HMAC-SHA256",
+				Splitter:   "FIXED-4M",
+			},
+			expectErr: assert.NoError,
+		},
+		{"invalid encryption", []string{"--" + EncryptionFN, "rot13"}, control.RepoFormat{}, assert.Error},
+		{"invalid hash", []string{"--" + HashFN, "md5"}, control.RepoFormat{}, assert.Error},
+		{"invalid splitter", []string{"--" + SplitterFN, "halves"}, control.RepoFormat{}, assert.Error},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			hashAlgorithm.name = ""
+			encryptionAlgorithm.name = ""
+			splitterAlgorithm.name = ""
+
+			cmd := &cobra.Command{Use: "test"}
+			AddRepoFormatFlags(cmd)
+
+			test.expectErr(t, cmd.ParseFlags(test.args))
+			assert.Equal(t, test.expect, Control().Format)
+		})
+	}
+}
+
+func (suite *OptionsUnitSuite) TestPermissionsFlags() {
+	table := []struct {
+		name          string
+		args          []string
+		expectSkip    bool
+		expectMapping map[string]string
+	}{
+		{"default", []string{}, false, nil},
+		{"skip", []string{"--" + SkipPermissionsFN}, true, nil},
+		{
+			"map principals",
+			[]string{
+				"--" + MapPrincipalFN, "a@example.com=b@example.com",
+				"--" + MapPrincipalFN, "old-id=new-id",
+			},
+			false,
+			map[string]string{"a@example.com": "b@example.com", "old-id": "new-id"},
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			skipPermissions = false
+			principalMap = nil
+
+			cmd := &cobra.Command{Use: "test"}
+			AddPermissionsFlags(cmd)
+			require.NotNil(t, cmd.Flags().Lookup(SkipPermissionsFN))
+			require.NotNil(t, cmd.Flags().Lookup(MapPrincipalFN))
+
+			require.NoError(t, cmd.ParseFlags(test.args))
+
+			opts := Control()
+			assert.Equal(t, test.expectSkip, opts.Permissions.Skip)
+			assert.Equal(t, test.expectMapping, opts.Permissions.PrincipalMap)
+		})
+	}
+}
+
+func (suite *OptionsUnitSuite) TestVersionsFlags() {
+	table := []struct {
+		name          string
+		args          []string
+		expectBackup  bool
+		expectRestore string
+	}{
+		{"default", []string{}, false, ""},
+		{"include versions", []string{"--" + IncludeVersionsFN}, true, ""},
+		{"restore version", []string{"--" + VersionFN, "3.0"}, false, "3.0"},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			includeVersions = false
+			restoreVersion = ""
+
+			cmd := &cobra.Command{Use: "test"}
+			AddVersionsBackupFlags(cmd)
+			AddVersionRestoreFlags(cmd)
+
+			require.NoError(t, cmd.ParseFlags(test.args))
+
+			opts := Control()
+			assert.Equal(t, test.expectBackup, opts.Versions.Backup)
+			assert.Equal(t, test.expectRestore, opts.Versions.Restore)
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package repo
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/alcionai/corso/src/cli/config"
+	"github.com/alcionai/corso/src/cli/options"
+	. "github.com/alcionai/corso/src/cli/print"
+	"github.com/alcionai/corso/src/cli/utils"
+	"github.com/alcionai/corso/src/pkg/account"
+	"github.com/alcionai/corso/src/pkg/credentials"
+	"github.com/alcionai/corso/src/pkg/repository"
+	"github.com/alcionai/corso/src/pkg/storage"
+)
+
+// azure container info from flags
+var (
+	container     string
+	accountName   string
+	azurePrefix   string
+	azureEndpoint string
+)
+
+// called by repo.go to map subcommands to provider-specific handling.
+func addAzureCommands(cmd *cobra.Command) *cobra.Command {
+	var (
+		c  *cobra.Command
+		fs *pflag.FlagSet
+	)
+
+	switch cmd.Use {
+	case initCommand:
+		c, fs = utils.AddCommand(cmd, azureInitCmd())
+	case connectCommand:
+		c, fs = utils.AddCommand(cmd, azureConnectCmd())
+	}
+
+	c.Use = c.Use + " " + azureProviderCommandUseSuffix
+	c.SetUsageTemplate(cmd.UsageTemplate())
+
+	// Flags addition ordering should follow the order we want them to appear in help and docs:
+	// More generic and more frequently used flags take precedence.
+	fs.StringVar(&container, "container", "", "Name of Azure Blob Storage container for repo. (required)")
+	cobra.CheckErr(c.MarkFlagRequired("container"))
+	fs.StringVar(&accountName, "account-name", "", "Azure Storage account name. (required)")
+	cobra.CheckErr(c.MarkFlagRequired("account-name"))
+	fs.StringVar(&azurePrefix, "prefix", "", "Repo prefix within container.")
+	fs.StringVar(&azureEndpoint, "endpoint", "", "Azure Blob Storage service endpoint.")
+
+	fs.BoolVar(&succeedIfExists, "succeed-if-exists", false, "Exit with success if the repo has already been initialized.")
+	cobra.CheckErr(fs.MarkHidden("succeed-if-exists"))
+
+	if cmd.Use == initCommand {
+		addInitFlags(c)
+	}
+
+	return c
+}
+
+const (
+	azureProviderCommand          = "azure"
+	azureProviderCommandUseSuffix = "--container <container> --account-name <account>"
+)
+
+const (
+	azureProviderCommandInitExamples = `# Create a new Corso repo in Azure container named "my-container"
+corso repo init azure --container my-container --account-name my-account
+
+# Create a new Corso repo in Azure container named "my-container" using a prefix
+corso repo init azure --container my-container --account-name my-account --prefix my-prefix
+
+# Create a new Corso repo in the Azurite storage emulator
+corso repo init azure --container my-container --account-name devstoreaccount1 \
+    --endpoint http://127.0.0.1:10000/devstoreaccount1`
+
+	azureProviderCommandConnectExamples = `# Connect to a Corso repo in Azure container named "my-container"
+corso repo connect azure --container my-container --account-name my-account
+
+# Connect to a Corso repo in Azure container named "my-container" using a prefix
+corso repo connect azure --container my-container --account-name my-account --prefix my-prefix`
+)
+
+// azure credentials are only ever read from the environment.
+const azureProviderCommandCredentialsHelp = `
+
+Authenticates using either the account key in $` + credentials.AzureStorageKey + `
+or the shared access signature in $` + credentials.AzureStorageSASToken + `.`
+
+// ---------------------------------------------------------------------------------------------------------
+// Init
+// ---------------------------------------------------------------------------------------------------------
+
+// `corso repo init azure [<flag>...]`
+func azureInitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     azureProviderCommand,
+		Short:   "Initialize an Azure Blob Storage repository",
+		Long:    `Bootstraps a new Azure Blob Storage repository and connects it to your m365 account.` + azureProviderCommandCredentialsHelp,
+		RunE:    initAzureCmd,
+		Args:    cobra.NoArgs,
+		Example: azureProviderCommandInitExamples,
+	}
+}
+
+// initializes an azure repo.
+func initAzureCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if utils.HasNoFlagsAndShownHelp(cmd) {
+		return nil
+	}
+
+	if err := validateInitCompression(); err != nil {
+		return Only(ctx, err)
+	}
+
+	s, a, err := config.GetStorageAndAccount(ctx, false, azureOverrides())
+	if err != nil {
+		return Only(ctx, err)
+	}
+
+	azCfg, err := s.AzureConfig()
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Retrieving azure configuration"))
+	}
+
+	m365, err := a.M365Config()
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to parse m365 account config"))
+	}
+
+	r, err := repository.Initialize(ctx, a, s, options.Control())
+	if err != nil {
+		if succeedIfExists && errors.Is(err, repository.ErrorRepoAlreadyExists) {
+			return nil
+		}
+
+		return Only(ctx, errors.Wrap(err, "Failed to initialize a new Azure repository"))
+	}
+
+	defer utils.CloseRepo(ctx, r)
+
+	Infof(ctx, "Initialized an Azure repository within container %s.", azCfg.Container)
+
+	if err = config.WriteRepoConfig(ctx, s, m365); err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to write repository configuration"))
+	}
+
+	if err := setInitCompression(ctx, r); err != nil {
+		return Only(ctx, err)
+	}
+
+	return nil
+}
+
+// ---------------------------------------------------------------------------------------------------------
+// Connect
+// ---------------------------------------------------------------------------------------------------------
+
+// `corso repo connect azure [<flag>...]`
+func azureConnectCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     azureProviderCommand,
+		Short:   "Connect to an Azure Blob Storage repository",
+		Long:    `Ensures a connection to an existing Azure Blob Storage repository.` + azureProviderCommandCredentialsHelp,
+		RunE:    connectAzureCmd,
+		Args:    cobra.NoArgs,
+		Example: azureProviderCommandConnectExamples,
+	}
+}
+
+// connects to an existing azure repo.
+func connectAzureCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if utils.HasNoFlagsAndShownHelp(cmd) {
+		return nil
+	}
+
+	s, a, err := config.GetStorageAndAccount(ctx, true, azureOverrides())
+	if err != nil {
+		return Only(ctx, err)
+	}
+
+	azCfg, err := s.AzureConfig()
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Retrieving azure configuration"))
+	}
+
+	m365, err := a.M365Config()
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to parse m365 account config"))
+	}
+
+	r, err := repository.Connect(ctx, a, s, options.Control())
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to connect to the Azure repository"))
+	}
+
+	defer utils.CloseRepo(ctx, r)
+
+	Infof(ctx, "Connected to Azure container %s.", azCfg.Container)
+
+	if err = config.WriteRepoConfig(ctx, s, m365); err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to write repository configuration"))
+	}
+
+	return nil
+}
+
+func azureOverrides() map[string]string {
+	return map[string]string{
+		config.AccountProviderTypeKey: account.ProviderM365.String(),
+		config.StorageProviderTypeKey: storage.ProviderAzure.String(),
+		storage.Container:             container,
+		storage.AccountName:           accountName,
+		storage.Prefix:                azurePrefix,
+		storage.Endpoint:              azureEndpoint,
+	}
+}
@@ -0,0 +1,189 @@
+package repo
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/alcionai/corso/src/cli/config"
+	"github.com/alcionai/corso/src/cli/options"
+	. "github.com/alcionai/corso/src/cli/print"
+	"github.com/alcionai/corso/src/cli/utils"
+	"github.com/alcionai/corso/src/internal/kopia"
+	"github.com/alcionai/corso/src/pkg/path"
+	"github.com/alcionai/corso/src/pkg/repository"
+)
+
+const setCompressionCommand = "set-compression"
+
+// compression flags
+var (
+	initCompressor   string
+	compressor       string
+	compressionSvc   string
+	resetCompression bool
+)
+
+const (
+	compressionFN = "compression"
+	compressorFN  = "compressor"
+	serviceFN     = "service"
+	resetFN       = "reset"
+)
+
+// services that can be given their own compressor, by flag value.
+var compressionServices = map[string]path.ServiceType{
+	path.ExchangeService.String():   path.ExchangeService,
+	path.OneDriveService.String():   path.OneDriveService,
+	path.SharePointService.String(): path.SharePointService,
+}
+
+const setCompressionExamples = `# Compress all new backup data with zstd
+corso repo set-compression --compressor zstd
+
+# Compress Exchange data with zstd, and store OneDrive data uncompressed
+corso repo set-compression --service exchange --compressor zstd
+corso repo set-compression --service onedrive --compressor none
+
+# Remove the OneDrive override, returning it to the repository's default
+corso repo set-compression --service onedrive --reset`
+
+// The repo set-compression subcommand.
+// `corso repo set-compression --compressor <name> [--service <service>]`
+func setCompressionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   setCompressionCommand,
+		Short: "Set the compression algorithm used for new backup data.",
+		Long: `Set the compressor used when writing new backup data to the connected repository, either
+as the default for all services, or for a single service. Data that is already compressed,
+such as OneDrive media, can skip compression with 'none'. Existing backups are not
+recompressed. Available compressors: ` + strings.Join(kopia.Compressors(), ", ") + `.`,
+		Example: setCompressionExamples,
+		RunE:    handleSetCompressionCmd,
+		Args:    cobra.NoArgs,
+	}
+}
+
+// adds the set-compression command to the parent.
+func addSetCompressionCommand(cmd *cobra.Command) *cobra.Command {
+	c, fs := utils.AddCommand(cmd, setCompressionCmd())
+
+	fs.StringVar(&compressor, compressorFN, "", "Name of the compressor to use.")
+	fs.StringVar(
+		&compressionSvc,
+		serviceFN, "",
+		"Only set the compressor for this service's data: "+strings.Join(compressionServiceNames(), ", ")+".")
+	fs.BoolVar(&resetCompression, resetFN, false, "Remove the service's compressor, returning it to the default.")
+
+	c.MarkFlagsMutuallyExclusive(compressorFN, resetFN)
+
+	return c
+}
+
+// adds the flag for choosing the compressor when initializing a repository.
+func addInitCompressionFlag(fs *pflag.FlagSet) {
+	fs.StringVar(
+		&initCompressor,
+		compressionFN, "",
+		"Compressor to use for backup data; defaults to s2-default. See 'corso repo set-compression'.")
+}
+
+// Handler for calls to `corso repo set-compression`.
+func handleSetCompressionCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	service := path.UnknownService
+
+	if len(compressionSvc) > 0 {
+		s, ok := compressionServices[compressionSvc]
+		if !ok {
+			return Only(ctx, errors.Errorf("Unknown service %q", compressionSvc))
+		}
+
+		service = s
+	}
+
+	switch {
+	case resetCompression && service == path.UnknownService:
+		return Only(ctx, errors.New("--reset requires a --service"))
+	case !resetCompression && len(compressor) == 0:
+		return Only(ctx, errors.New("--compressor is required"))
+	case len(compressor) > 0:
+		if err := kopia.ValidateCompressor(compressor); err != nil {
+			return Only(ctx, err)
+		}
+	}
+
+	s, acct, err := config.GetStorageAndAccount(ctx, true, nil)
+	if err != nil {
+		return Only(ctx, err)
+	}
+
+	r, err := repository.Connect(ctx, acct, s, options.Control())
+	if err != nil {
+		return Only(ctx, errors.Wrapf(err, "Failed to connect to the %s repository", s.Provider))
+	}
+
+	defer utils.CloseRepo(ctx, r)
+
+	if err := r.SetCompression(ctx, service, compressor); err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to set the compressor"))
+	}
+
+	return printCompression(ctx, r)
+}
+
+// validates the compressor chosen for repo init, so that a typo doesn't
+// leave behind a newly initialized repository.
+func validateInitCompression() error {
+	if len(initCompressor) == 0 {
+		return nil
+	}
+
+	return kopia.ValidateCompressor(initCompressor)
+}
+
+// sets the compressor chosen for repo init on the new repository.
+func setInitCompression(ctx context.Context, r repository.Repository) error {
+	if len(initCompressor) == 0 {
+		return nil
+	}
+
+	if err := r.SetCompression(ctx, path.UnknownService, initCompressor); err != nil {
+		return errors.Wrap(err, "Failed to set the compressor; retry with 'corso repo set-compression'")
+	}
+
+	return nil
+}
+
+func printCompression(ctx context.Context, r repository.Repository) error {
+	cp, err := r.Compression(ctx)
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to retrieve the compressors"))
+	}
+
+	Infof(ctx, "Default compressor: %s", cp.Default)
+
+	for _, name := range compressionServiceNames() {
+		if c, ok := cp.Services[compressionServices[name]]; ok {
+			Infof(ctx, "%s compressor: %s", name, c)
+		}
+	}
+
+	return nil
+}
+
+func compressionServiceNames() []string {
+	names := make([]string, 0, len(compressionServices))
+	for name := range compressionServices {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,63 @@
+package repo
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/tester"
+)
+
+type CompressionSuite struct {
+	suite.Suite
+}
+
+func TestCompressionSuite(t *testing.T) {
+	suite.Run(t, new(CompressionSuite))
+}
+
+func (suite *CompressionSuite) TestAddSetCompressionCommand() {
+	t := suite.T()
+	cmd := &cobra.Command{Use: "repo"}
+
+	c := addSetCompressionCommand(cmd)
+	require.NotNil(t, c)
+
+	cmds := cmd.Commands()
+	require.Len(t, cmds, 1)
+
+	child := cmds[0]
+	assert.Equal(t, setCompressionCommand, child.Use)
+	assert.Equal(t, setCompressionCmd().Short, child.Short)
+	tester.AreSameFunc(t, handleSetCompressionCmd, child.RunE)
+
+	for _, fn := range []string{compressorFN, serviceFN, resetFN} {
+		assert.NotNil(t, child.Flags().Lookup(fn), fn)
+	}
+}
+
+func (suite *CompressionSuite) TestCompressorAndResetExclusive() {
+	t := suite.T()
+	cmd := &cobra.Command{Use: "repo"}
+	c := addSetCompressionCommand(cmd)
+
+	require.NoError(t, c.ParseFlags([]string{"--" + compressorFN, "zstd", "--" + resetFN}))
+	assert.Error(t, c.ValidateFlagGroups())
+}
+
+func (suite *CompressionSuite) TestValidateInitCompression() {
+	t := suite.T()
+
+	defer func() { initCompressor = "" }()
+
+	assert.NoError(t, validateInitCompression())
+
+	initCompressor = "none"
+	assert.NoError(t, validateInitCompression())
+
+	initCompressor = "not-a-compressor"
+	assert.Error(t, validateInitCompression())
+}
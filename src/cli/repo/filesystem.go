@@ -0,0 +1,224 @@
+package repo
+
+import (
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/alcionai/corso/src/cli/config"
+	"github.com/alcionai/corso/src/cli/options"
+	. "github.com/alcionai/corso/src/cli/print"
+	"github.com/alcionai/corso/src/cli/utils"
+	"github.com/alcionai/corso/src/pkg/account"
+	"github.com/alcionai/corso/src/pkg/repository"
+	"github.com/alcionai/corso/src/pkg/storage"
+)
+
+// filesystem repo info from flags
+var fsPath string
+
+// called by repo.go to map subcommands to provider-specific handling.
+func addFilesystemCommands(cmd *cobra.Command) *cobra.Command {
+	var (
+		c  *cobra.Command
+		fs *pflag.FlagSet
+	)
+
+	switch cmd.Use {
+	case initCommand:
+		c, fs = utils.AddCommand(cmd, filesystemInitCmd())
+	case connectCommand:
+		c, fs = utils.AddCommand(cmd, filesystemConnectCmd())
+	}
+
+	c.Use = c.Use + " " + filesystemProviderCommandUseSuffix
+	c.SetUsageTemplate(cmd.UsageTemplate())
+
+	// Flags addition ordering should follow the order we want them to appear in help and docs:
+	// More generic and more frequently used flags take precedence.
+	fs.StringVar(&fsPath, "path", "", "Path to the local or mounted directory for the repo. (required)")
+	cobra.CheckErr(c.MarkFlagRequired("path"))
+
+	fs.BoolVar(&succeedIfExists, "succeed-if-exists", false, "Exit with success if the repo has already been initialized.")
+	cobra.CheckErr(fs.MarkHidden("succeed-if-exists"))
+
+	if cmd.Use == initCommand {
+		addInitFlags(c)
+	}
+
+	return c
+}
+
+const (
+	filesystemProviderCommand          = "filesystem"
+	filesystemProviderCommandUseSuffix = "--path <path>"
+)
+
+const (
+	filesystemProviderCommandInitExamples = `# Create a new Corso repo in the local directory "/var/corso/repo"
+corso repo init filesystem --path /var/corso/repo
+
+# Create a new Corso repo on a mounted network share
+corso repo init filesystem --path /mnt/nas/corso
+
+# Create a new Corso repo that compresses backup data with zstd
+corso repo init filesystem --path /var/corso/repo --compression zstd
+
+# Create a new Corso repo that encrypts backup data with ChaCha20-Poly1305
+corso repo init filesystem --path /var/corso/repo --encryption CHACHA20-POLY1305-HMAC-SHA256`
+
+	filesystemProviderCommandConnectExamples = `# Connect to a Corso repo in the local directory "/var/corso/repo"
+corso repo connect filesystem --path /var/corso/repo
+
+# Connect to a Corso repo on a mounted network share
+corso repo connect filesystem --path /mnt/nas/corso`
+)
+
+// ---------------------------------------------------------------------------------------------------------
+// Init
+// ---------------------------------------------------------------------------------------------------------
+
+// `corso repo init filesystem [<flag>...]`
+func filesystemInitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     filesystemProviderCommand,
+		Short:   "Initialize a filesystem repository",
+		Long:    `Bootstraps a new filesystem repository and connects it to your m365 account.`,
+		RunE:    initFilesystemCmd,
+		Args:    cobra.NoArgs,
+		Example: filesystemProviderCommandInitExamples,
+	}
+}
+
+// initializes a filesystem repo.
+func initFilesystemCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if utils.HasNoFlagsAndShownHelp(cmd) {
+		return nil
+	}
+
+	if err := validateInitCompression(); err != nil {
+		return Only(ctx, err)
+	}
+
+	overrides, err := filesystemOverrides()
+	if err != nil {
+		return Only(ctx, err)
+	}
+
+	s, a, err := config.GetStorageAndAccount(ctx, false, overrides)
+	if err != nil {
+		return Only(ctx, err)
+	}
+
+	fsCfg, err := s.FilesystemConfig()
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Retrieving filesystem configuration"))
+	}
+
+	m365, err := a.M365Config()
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to parse m365 account config"))
+	}
+
+	r, err := repository.Initialize(ctx, a, s, options.Control())
+	if err != nil {
+		if succeedIfExists && errors.Is(err, repository.ErrorRepoAlreadyExists) {
+			return nil
+		}
+
+		return Only(ctx, errors.Wrap(err, "Failed to initialize a new filesystem repository"))
+	}
+
+	defer utils.CloseRepo(ctx, r)
+
+	Infof(ctx, "Initialized a filesystem repository at %s.", fsCfg.Path)
+
+	if err = config.WriteRepoConfig(ctx, s, m365); err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to write repository configuration"))
+	}
+
+	if err := setInitCompression(ctx, r); err != nil {
+		return Only(ctx, err)
+	}
+
+	return nil
+}
+
+// ---------------------------------------------------------------------------------------------------------
+// Connect
+// ---------------------------------------------------------------------------------------------------------
+
+// `corso repo connect filesystem [<flag>...]`
+func filesystemConnectCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     filesystemProviderCommand,
+		Short:   "Connect to a filesystem repository",
+		Long:    `Ensures a connection to an existing filesystem repository.`,
+		RunE:    connectFilesystemCmd,
+		Args:    cobra.NoArgs,
+		Example: filesystemProviderCommandConnectExamples,
+	}
+}
+
+// connects to an existing filesystem repo.
+func connectFilesystemCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if utils.HasNoFlagsAndShownHelp(cmd) {
+		return nil
+	}
+
+	overrides, err := filesystemOverrides()
+	if err != nil {
+		return Only(ctx, err)
+	}
+
+	s, a, err := config.GetStorageAndAccount(ctx, true, overrides)
+	if err != nil {
+		return Only(ctx, err)
+	}
+
+	fsCfg, err := s.FilesystemConfig()
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Retrieving filesystem configuration"))
+	}
+
+	m365, err := a.M365Config()
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to parse m365 account config"))
+	}
+
+	r, err := repository.Connect(ctx, a, s, options.Control())
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to connect to the filesystem repository"))
+	}
+
+	defer utils.CloseRepo(ctx, r)
+
+	Infof(ctx, "Connected to filesystem repository at %s.", fsCfg.Path)
+
+	if err = config.WriteRepoConfig(ctx, s, m365); err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to write repository configuration"))
+	}
+
+	return nil
+}
+
+// filesystemOverrides resolves the --path flag to an absolute path so that the
+// persisted config doesn't depend on the working directory of the caller.
+func filesystemOverrides() (map[string]string, error) {
+	p, err := filepath.Abs(fsPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "resolving repository path")
+	}
+
+	return map[string]string{
+		config.AccountProviderTypeKey: account.ProviderM365.String(),
+		config.StorageProviderTypeKey: storage.ProviderFilesystem.String(),
+		storage.FilesystemPath:        p,
+	}, nil
+}
@@ -0,0 +1,56 @@
+package repo
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/cli/options"
+	"github.com/alcionai/corso/src/internal/tester"
+)
+
+type FilesystemSuite struct {
+	suite.Suite
+}
+
+func TestFilesystemSuite(t *testing.T) {
+	suite.Run(t, new(FilesystemSuite))
+}
+
+func (suite *FilesystemSuite) TestAddFilesystemCommands() {
+	expectUse := filesystemProviderCommand + " " + filesystemProviderCommandUseSuffix
+
+	table := []struct {
+		name        string
+		use         string
+		expectUse   string
+		expectShort string
+		expectRunE  func(*cobra.Command, []string) error
+		// only init can choose the repository's compressor and format.
+		expectInitFlags bool
+	}{
+		{"init filesystem", initCommand, expectUse, filesystemInitCmd().Short, initFilesystemCmd, true},
+		{"connect filesystem", connectCommand, expectUse, filesystemConnectCmd().Short, connectFilesystemCmd, false},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: test.use}
+
+			c := addFilesystemCommands(cmd)
+			require.NotNil(t, c)
+
+			cmds := cmd.Commands()
+			require.Len(t, cmds, 1)
+
+			child := cmds[0]
+			assert.Equal(t, test.expectUse, child.Use)
+			assert.Equal(t, test.expectShort, child.Short)
+			tester.AreSameFunc(t, test.expectRunE, child.RunE)
+			assert.Equal(t, test.expectInitFlags, child.Flags().Lookup(compressionFN) != nil)
+			assert.Equal(t, test.expectInitFlags, child.Flags().Lookup(options.EncryptionFN) != nil)
+		})
+	}
+}
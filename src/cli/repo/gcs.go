@@ -0,0 +1,218 @@
+package repo
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/alcionai/corso/src/cli/config"
+	"github.com/alcionai/corso/src/cli/options"
+	. "github.com/alcionai/corso/src/cli/print"
+	"github.com/alcionai/corso/src/cli/utils"
+	"github.com/alcionai/corso/src/pkg/account"
+	"github.com/alcionai/corso/src/pkg/credentials"
+	"github.com/alcionai/corso/src/pkg/repository"
+	"github.com/alcionai/corso/src/pkg/storage"
+)
+
+// gcs bucket info from flags
+var (
+	gcsBucket   string
+	gcsPrefix   string
+	gcsEndpoint string
+)
+
+// called by repo.go to map subcommands to provider-specific handling.
+func addGCSCommands(cmd *cobra.Command) *cobra.Command {
+	var (
+		c  *cobra.Command
+		fs *pflag.FlagSet
+	)
+
+	switch cmd.Use {
+	case initCommand:
+		c, fs = utils.AddCommand(cmd, gcsInitCmd())
+	case connectCommand:
+		c, fs = utils.AddCommand(cmd, gcsConnectCmd())
+	}
+
+	c.Use = c.Use + " " + gcsProviderCommandUseSuffix
+	c.SetUsageTemplate(cmd.UsageTemplate())
+
+	// Flags addition ordering should follow the order we want them to appear in help and docs:
+	// More generic and more frequently used flags take precedence.
+	fs.StringVar(&gcsBucket, "bucket", "", "Name of Google Cloud Storage bucket for repo. (required)")
+	cobra.CheckErr(c.MarkFlagRequired("bucket"))
+	fs.StringVar(&gcsPrefix, "prefix", "", "Repo prefix within bucket.")
+	fs.StringVar(&gcsEndpoint, "endpoint", "", "Google Cloud Storage service endpoint.")
+
+	fs.BoolVar(&succeedIfExists, "succeed-if-exists", false, "Exit with success if the repo has already been initialized.")
+	cobra.CheckErr(fs.MarkHidden("succeed-if-exists"))
+
+	if cmd.Use == initCommand {
+		addInitFlags(c)
+	}
+
+	return c
+}
+
+const (
+	gcsProviderCommand          = "gcs"
+	gcsProviderCommandUseSuffix = "--bucket <bucket>"
+)
+
+const (
+	gcsProviderCommandInitExamples = `# Create a new Corso repo in GCS bucket named "corso-repo"
+corso repo init gcs --bucket corso-repo
+
+# Create a new Corso repo in GCS bucket named "corso-repo" using a prefix
+corso repo init gcs --bucket corso-repo --prefix my-prefix
+
+# Create a new Corso repo in a local fake-gcs-server emulator
+corso repo init gcs --bucket corso-repo --endpoint http://127.0.0.1:4443`
+
+	gcsProviderCommandConnectExamples = `# Connect to a Corso repo in GCS bucket named "corso-repo"
+corso repo connect gcs --bucket corso-repo
+
+# Connect to a Corso repo in GCS bucket named "corso-repo" using a prefix
+corso repo connect gcs --bucket corso-repo --prefix my-prefix`
+)
+
+// gcs credentials are only ever read from the environment.
+const gcsProviderCommandCredentialsHelp = `
+
+Authenticates using the service account key file in $` + credentials.GCSCredentialsFile + `
+or the service account json in $` + credentials.GCSCredentialsJSON + `.  If neither
+is set, the application default credentials are used.`
+
+// ---------------------------------------------------------------------------------------------------------
+// Init
+// ---------------------------------------------------------------------------------------------------------
+
+// `corso repo init gcs [<flag>...]`
+func gcsInitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     gcsProviderCommand,
+		Short:   "Initialize a Google Cloud Storage repository",
+		Long:    `Bootstraps a new Google Cloud Storage repository and connects it to your m365 account.` + gcsProviderCommandCredentialsHelp,
+		RunE:    initGCSCmd,
+		Args:    cobra.NoArgs,
+		Example: gcsProviderCommandInitExamples,
+	}
+}
+
+// initializes a gcs repo.
+func initGCSCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if utils.HasNoFlagsAndShownHelp(cmd) {
+		return nil
+	}
+
+	if err := validateInitCompression(); err != nil {
+		return Only(ctx, err)
+	}
+
+	s, a, err := config.GetStorageAndAccount(ctx, false, gcsOverrides())
+	if err != nil {
+		return Only(ctx, err)
+	}
+
+	gcsCfg, err := s.GCSConfig()
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Retrieving gcs configuration"))
+	}
+
+	m365, err := a.M365Config()
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to parse m365 account config"))
+	}
+
+	r, err := repository.Initialize(ctx, a, s, options.Control())
+	if err != nil {
+		if succeedIfExists && errors.Is(err, repository.ErrorRepoAlreadyExists) {
+			return nil
+		}
+
+		return Only(ctx, errors.Wrap(err, "Failed to initialize a new GCS repository"))
+	}
+
+	defer utils.CloseRepo(ctx, r)
+
+	Infof(ctx, "Initialized a GCS repository within bucket %s.", gcsCfg.Bucket)
+
+	if err = config.WriteRepoConfig(ctx, s, m365); err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to write repository configuration"))
+	}
+
+	if err := setInitCompression(ctx, r); err != nil {
+		return Only(ctx, err)
+	}
+
+	return nil
+}
+
+// ---------------------------------------------------------------------------------------------------------
+// Connect
+// ---------------------------------------------------------------------------------------------------------
+
+// `corso repo connect gcs [<flag>...]`
+func gcsConnectCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     gcsProviderCommand,
+		Short:   "Connect to a Google Cloud Storage repository",
+		Long:    `Ensures a connection to an existing Google Cloud Storage repository.` + gcsProviderCommandCredentialsHelp,
+		RunE:    connectGCSCmd,
+		Args:    cobra.NoArgs,
+		Example: gcsProviderCommandConnectExamples,
+	}
+}
+
+// connects to an existing gcs repo.
+func connectGCSCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if utils.HasNoFlagsAndShownHelp(cmd) {
+		return nil
+	}
+
+	s, a, err := config.GetStorageAndAccount(ctx, true, gcsOverrides())
+	if err != nil {
+		return Only(ctx, err)
+	}
+
+	gcsCfg, err := s.GCSConfig()
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Retrieving gcs configuration"))
+	}
+
+	m365, err := a.M365Config()
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to parse m365 account config"))
+	}
+
+	r, err := repository.Connect(ctx, a, s, options.Control())
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to connect to the GCS repository"))
+	}
+
+	defer utils.CloseRepo(ctx, r)
+
+	Infof(ctx, "Connected to GCS bucket %s.", gcsCfg.Bucket)
+
+	if err = config.WriteRepoConfig(ctx, s, m365); err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to write repository configuration"))
+	}
+
+	return nil
+}
+
+func gcsOverrides() map[string]string {
+	return map[string]string{
+		config.AccountProviderTypeKey: account.ProviderM365.String(),
+		config.StorageProviderTypeKey: storage.ProviderGCS.String(),
+		storage.Bucket:                gcsBucket,
+		storage.Prefix:                gcsPrefix,
+		storage.Endpoint:              gcsEndpoint,
+	}
+}
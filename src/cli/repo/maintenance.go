@@ -0,0 +1,119 @@
+package repo
+
+import (
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/alcionai/corso/src/cli/config"
+	"github.com/alcionai/corso/src/cli/options"
+	. "github.com/alcionai/corso/src/cli/print"
+	"github.com/alcionai/corso/src/cli/utils"
+	"github.com/alcionai/corso/src/internal/kopia"
+	"github.com/alcionai/corso/src/pkg/repository"
+)
+
+const maintenanceCommand = "maintenance"
+
+// maintenance flags
+var (
+	fullMaintenance  bool
+	quickMaintenance bool
+	forceMaintenance bool
+)
+
+const (
+	fullFN  = "full"
+	quickFN = "quick"
+	forceFN = "force"
+)
+
+const maintenanceExamples = `# Compact the repository indexes
+corso repo maintenance
+
+# Reclaim the storage held by deleted backups
+corso repo maintenance --full
+
+# Take over maintenance from a host that is no longer in use
+corso repo maintenance --full --force`
+
+// The repo maintenance subcommand.
+// `corso repo maintenance [--full|--quick]`
+func maintenanceCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   maintenanceCommand,
+		Short: "Run maintenance on the connected repository.",
+		Long: `Compact repository indexes and, with --full, garbage collect the content of deleted backups.
+Maintenance is owned by the first host that runs it; other hosts must use --force to take ownership.
+Content is only removed once it is old enough to be safe from concurrent backups, so storage from
+recently deleted backups may be reclaimed over several runs.
+In repos with object lock, maintenance also extends the lock on the remaining data.  Backups don't
+extend it, so run maintenance at least once per retention period, such as on a schedule, to keep
+data shared with newer backups locked.`,
+		Example: maintenanceExamples,
+		RunE:    handleMaintenanceCmd,
+		Args:    cobra.NoArgs,
+	}
+}
+
+// adds the maintenance command to the parent.
+func addMaintenanceCommand(cmd *cobra.Command) *cobra.Command {
+	c, fs := utils.AddCommand(cmd, maintenanceCmd())
+
+	fs.BoolVar(&fullMaintenance, fullFN, false, "Garbage collect unreferenced content and delete unused blobs.")
+	fs.BoolVar(&quickMaintenance, quickFN, false, "Only compact indexes and small packs. (default)")
+	fs.BoolVar(&forceMaintenance, forceFN, false, "Take maintenance ownership from another host.")
+
+	c.MarkFlagsMutuallyExclusive(fullFN, quickFN)
+
+	return c
+}
+
+// Handler for calls to `corso repo maintenance`.
+func handleMaintenanceCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	mode := kopia.QuickMaintenance
+	if fullMaintenance {
+		mode = kopia.FullMaintenance
+	}
+
+	s, acct, err := config.GetStorageAndAccount(ctx, true, nil)
+	if err != nil {
+		return Only(ctx, err)
+	}
+
+	r, err := repository.Connect(ctx, acct, s, options.Control())
+	if err != nil {
+		return Only(ctx, errors.Wrapf(err, "Failed to connect to the %s repository", s.Provider))
+	}
+
+	defer utils.CloseRepo(ctx, r)
+
+	ms, err := r.Maintenance(ctx, mode, forceMaintenance)
+	if err != nil {
+		if errors.Is(err, kopia.ErrMaintenanceNotOwned) {
+			return Only(ctx, errors.Wrap(err, "Use --force to take ownership of repository maintenance"))
+		}
+
+		return Only(ctx, errors.Wrap(err, "Failed to run repository maintenance"))
+	}
+
+	Infof(ctx, "Completed %s maintenance as %s", ms.Mode, ms.Owner)
+
+	if ms.Mode == kopia.FullMaintenance {
+		Infof(ctx, "Unreferenced content: %d items (%s)",
+			ms.UnusedContentCount, humanize.Bytes(uint64(ms.UnusedContentBytes)))
+	}
+
+	Infof(ctx, "Reclaimed %s (%s -> %s)",
+		humanize.Bytes(uint64(ms.ReclaimedBytes())),
+		humanize.Bytes(uint64(ms.StorageBytesBefore)),
+		humanize.Bytes(uint64(ms.StorageBytesAfter)))
+
+	if ms.LocksExtended > 0 {
+		Infof(ctx, "Extended the object lock on %d blobs", ms.LocksExtended)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package repo
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/tester"
+)
+
+type MaintenanceSuite struct {
+	suite.Suite
+}
+
+func TestMaintenanceSuite(t *testing.T) {
+	suite.Run(t, new(MaintenanceSuite))
+}
+
+func (suite *MaintenanceSuite) TestAddMaintenanceCommand() {
+	t := suite.T()
+	cmd := &cobra.Command{Use: "repo"}
+
+	c := addMaintenanceCommand(cmd)
+	require.NotNil(t, c)
+
+	cmds := cmd.Commands()
+	require.Len(t, cmds, 1)
+
+	child := cmds[0]
+	assert.Equal(t, maintenanceCommand, child.Use)
+	assert.Equal(t, maintenanceCmd().Short, child.Short)
+	tester.AreSameFunc(t, handleMaintenanceCmd, child.RunE)
+
+	for _, fn := range []string{fullFN, quickFN, forceFN} {
+		assert.NotNil(t, child.Flags().Lookup(fn), fn)
+	}
+}
+
+func (suite *MaintenanceSuite) TestMaintenanceModesExclusive() {
+	t := suite.T()
+	cmd := &cobra.Command{Use: "repo"}
+	c := addMaintenanceCommand(cmd)
+
+	require.NoError(t, c.ParseFlags([]string{"--" + fullFN, "--" + quickFN}))
+	assert.Error(t, c.ValidateFlagGroups())
+}
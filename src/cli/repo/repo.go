@@ -2,6 +2,8 @@ package repo
 
 import (
 	"github.com/spf13/cobra"
+
+	"github.com/alcionai/corso/src/cli/options"
 )
 
 const (
@@ -11,6 +13,10 @@ const (
 
 var repoCommands = []func(cmd *cobra.Command) *cobra.Command{
 	addS3Commands,
+	addFilesystemCommands,
+	addAzureCommands,
+	addGCSCommands,
+	addSFTPCommands,
 }
 
 // AddCommands attaches all `corso repo * *` commands to the parent.
@@ -31,6 +37,13 @@ func AddCommands(cmd *cobra.Command) {
 		addRepoTo(initCmd)
 		addRepoTo(connectCmd)
 	}
+
+	addMaintenanceCommand(repoCmd)
+	addVerifyCommand(repoCmd)
+	addUpdatePassphraseCommand(repoCmd)
+	addSyncCommand(repoCmd)
+	addStatusCommand(repoCmd)
+	addSetCompressionCommand(repoCmd)
 }
 
 // The repo category of commands.
@@ -63,6 +76,12 @@ func initCmd() *cobra.Command {
 	}
 }
 
+// adds the flags that only apply when initializing a repository.
+func addInitFlags(c *cobra.Command) {
+	options.AddRepoFormatFlags(c)
+	addInitCompressionFlag(c.Flags())
+}
+
 // Handler for calls to `corso repo init`.
 func handleInitCmd(cmd *cobra.Command, args []string) error {
 	return cmd.Help()
@@ -2,6 +2,7 @@ package repo
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -24,6 +25,13 @@ var (
 	doNotUseTLS     bool
 	doNotVerifyTLS  bool
 	succeedIfExists bool
+	retentionMode   string
+	retentionPeriod time.Duration
+)
+
+const (
+	retentionModeFN   = "retention-mode"
+	retentionPeriodFN = "retention-period"
 )
 
 // called by repo.go to map subcommands to provider-specific handling.
@@ -57,6 +65,22 @@ func addS3Commands(cmd *cobra.Command) *cobra.Command {
 	fs.BoolVar(&succeedIfExists, "succeed-if-exists", false, "Exit with success if the repo has already been initialized.")
 	cobra.CheckErr(fs.MarkHidden("succeed-if-exists"))
 
+	if cmd.Use == initCommand {
+		addInitFlags(c)
+
+		fs.StringVar(
+			&retentionMode,
+			retentionModeFN, "",
+			"Object lock retention mode for the repo's data: GOVERNANCE or COMPLIANCE. "+
+				"Requires a bucket with object lock enabled.")
+		fs.DurationVar(
+			&retentionPeriod,
+			retentionPeriodFN, 0,
+			"How long the repo's data stays locked after it's written, such as 720h. Minimum 24h. "+
+				"Run `corso repo maintenance` at least once per period to keep the data of live backups locked.")
+		c.MarkFlagsRequiredTogether(retentionModeFN, retentionPeriodFN)
+	}
+
 	return c
 }
 
@@ -73,7 +97,10 @@ corso repo init s3 --bucket my-bucket
 corso repo init s3 --bucket my-bucket --prefix my-prefix
 
 # Create a new Corso repo in an S3 compliant storage provider
-corso repo init s3 --bucket my-bucket --endpoint https://my-s3-server-endpoint`
+corso repo init s3 --bucket my-bucket --endpoint https://my-s3-server-endpoint
+
+# Create a new Corso repo whose data is locked for 30 days after it's written
+corso repo init s3 --bucket my-bucket --retention-mode GOVERNANCE --retention-period 720h`
 
 	s3ProviderCommandConnectExamples = `# Connect to a Corso repo in AWS S3 bucket named "my-bucket"
 corso repo connect s3 --bucket my-bucket
@@ -109,6 +136,10 @@ func initS3Cmd(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	if err := validateInitCompression(); err != nil {
+		return Only(ctx, err)
+	}
+
 	s, a, err := config.GetStorageAndAccount(ctx, false, s3Overrides())
 	if err != nil {
 		return Only(ctx, err)
@@ -137,10 +168,14 @@ func initS3Cmd(cmd *cobra.Command, args []string) error {
 
 	Infof(ctx, "Initialized a S3 repository within bucket %s.", s3Cfg.Bucket)
 
-	if err = config.WriteRepoConfig(ctx, s3Cfg, m365); err != nil {
+	if err = config.WriteRepoConfig(ctx, s, m365); err != nil {
 		return Only(ctx, errors.Wrap(err, "Failed to write repository configuration"))
 	}
 
+	if err := setInitCompression(ctx, r); err != nil {
+		return Only(ctx, err)
+	}
+
 	return nil
 }
 
@@ -192,7 +227,7 @@ func connectS3Cmd(cmd *cobra.Command, args []string) error {
 
 	Infof(ctx, "Connected to S3 bucket %s.", s3Cfg.Bucket)
 
-	if err = config.WriteRepoConfig(ctx, s3Cfg, m365); err != nil {
+	if err = config.WriteRepoConfig(ctx, s, m365); err != nil {
 		return Only(ctx, errors.Wrap(err, "Failed to write repository configuration"))
 	}
 
@@ -208,5 +243,15 @@ func s3Overrides() map[string]string {
 		storage.Prefix:                prefix,
 		storage.DoNotUseTLS:           strconv.FormatBool(doNotUseTLS),
 		storage.DoNotVerifyTLS:        strconv.FormatBool(doNotVerifyTLS),
+		storage.RetentionMode:         retentionMode,
+		storage.RetentionPeriod:       formatRetentionPeriod(retentionPeriod),
 	}
 }
+
+func formatRetentionPeriod(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+
+	return d.String()
+}
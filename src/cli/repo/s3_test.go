@@ -49,3 +49,22 @@ func (suite *S3Suite) TestAddS3Commands() {
 		})
 	}
 }
+
+func (suite *S3Suite) TestAddS3Commands_retentionFlags() {
+	table := []struct {
+		use    string
+		expect bool
+	}{
+		{initCommand, true},
+		{connectCommand, false},
+	}
+	for _, test := range table {
+		suite.T().Run(test.use, func(t *testing.T) {
+			c := addS3Commands(&cobra.Command{Use: test.use})
+			require.NotNil(t, c)
+
+			assert.Equal(t, test.expect, c.Flags().Lookup(retentionModeFN) != nil)
+			assert.Equal(t, test.expect, c.Flags().Lookup(retentionPeriodFN) != nil)
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package repo
+
+import (
+	"path/filepath"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/alcionai/corso/src/cli/config"
+	"github.com/alcionai/corso/src/cli/options"
+	. "github.com/alcionai/corso/src/cli/print"
+	"github.com/alcionai/corso/src/cli/utils"
+	"github.com/alcionai/corso/src/pkg/account"
+	"github.com/alcionai/corso/src/pkg/credentials"
+	"github.com/alcionai/corso/src/pkg/repository"
+	"github.com/alcionai/corso/src/pkg/storage"
+)
+
+// sftp server info from flags
+var (
+	sftpHost           string
+	sftpPort           int
+	sftpUser           string
+	sftpKeyFile        string
+	sftpKnownHostsFile string
+	sftpPath           string
+)
+
+// called by repo.go to map subcommands to provider-specific handling.
+func addSFTPCommands(cmd *cobra.Command) *cobra.Command {
+	var (
+		c  *cobra.Command
+		fs *pflag.FlagSet
+	)
+
+	switch cmd.Use {
+	case initCommand:
+		c, fs = utils.AddCommand(cmd, sftpInitCmd())
+	case connectCommand:
+		c, fs = utils.AddCommand(cmd, sftpConnectCmd())
+	}
+
+	c.Use = c.Use + " " + sftpProviderCommandUseSuffix
+	c.SetUsageTemplate(cmd.UsageTemplate())
+
+	// Flags addition ordering should follow the order we want them to appear in help and docs:
+	// More generic and more frequently used flags take precedence.
+	fs.StringVar(&sftpHost, "host", "", "Hostname or address of the SFTP server. (required)")
+	cobra.CheckErr(c.MarkFlagRequired("host"))
+	fs.StringVar(&sftpUser, "user", "", "User to authenticate as on the SFTP server. (required)")
+	cobra.CheckErr(c.MarkFlagRequired("user"))
+	fs.StringVar(&sftpPath, "path", "", "Base path for the repo on the SFTP server. (required)")
+	cobra.CheckErr(c.MarkFlagRequired("path"))
+	fs.IntVar(&sftpPort, "port", 0, "Port of the SFTP server. Defaults to 22.")
+	fs.StringVar(&sftpKeyFile, "key-file", "", "Path to the SSH private key used to authenticate.")
+	fs.StringVar(
+		&sftpKnownHostsFile,
+		"known-hosts", "",
+		"Path to the known_hosts file used to verify the server. Defaults to ~/.ssh/known_hosts.")
+
+	fs.BoolVar(&succeedIfExists, "succeed-if-exists", false, "Exit with success if the repo has already been initialized.")
+	cobra.CheckErr(fs.MarkHidden("succeed-if-exists"))
+
+	if cmd.Use == initCommand {
+		addInitFlags(c)
+	}
+
+	return c
+}
+
+const (
+	sftpProviderCommand          = "sftp"
+	sftpProviderCommandUseSuffix = "--host <host> --user <user> --path <path>"
+)
+
+const (
+	sftpProviderCommandInitExamples = `# Create a new Corso repo in "/srv/corso" on the SFTP server "backup.example.com"
+corso repo init sftp --host backup.example.com --user corso --path /srv/corso \
+    --key-file ~/.ssh/id_ed25519
+
+# Create a new Corso repo on an SFTP server listening on a non-standard port
+corso repo init sftp --host 10.0.0.5 --port 2222 --user corso --path /srv/corso \
+    --key-file ~/.ssh/id_ed25519 --known-hosts /etc/corso/known_hosts`
+
+	sftpProviderCommandConnectExamples = `# Connect to a Corso repo in "/srv/corso" on the SFTP server "backup.example.com"
+corso repo connect sftp --host backup.example.com --user corso --path /srv/corso \
+    --key-file ~/.ssh/id_ed25519`
+)
+
+// the sftp password is only ever read from the environment.
+const sftpProviderCommandCredentialsHelp = `
+
+Authenticates using the private key in --key-file, or the password in $` + credentials.SFTPPassword + `.
+The server's host key must be present in the known_hosts file.`
+
+// ---------------------------------------------------------------------------------------------------------
+// Init
+// ---------------------------------------------------------------------------------------------------------
+
+// `corso repo init sftp [<flag>...]`
+func sftpInitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     sftpProviderCommand,
+		Short:   "Initialize an SFTP repository",
+		Long:    `Bootstraps a new SFTP repository and connects it to your m365 account.` + sftpProviderCommandCredentialsHelp,
+		RunE:    initSFTPCmd,
+		Args:    cobra.NoArgs,
+		Example: sftpProviderCommandInitExamples,
+	}
+}
+
+// initializes an sftp repo.
+func initSFTPCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if utils.HasNoFlagsAndShownHelp(cmd) {
+		return nil
+	}
+
+	if err := validateInitCompression(); err != nil {
+		return Only(ctx, err)
+	}
+
+	overrides, err := sftpOverrides()
+	if err != nil {
+		return Only(ctx, err)
+	}
+
+	s, a, err := config.GetStorageAndAccount(ctx, false, overrides)
+	if err != nil {
+		return Only(ctx, err)
+	}
+
+	sftpCfg, err := s.SFTPConfig()
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Retrieving sftp configuration"))
+	}
+
+	m365, err := a.M365Config()
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to parse m365 account config"))
+	}
+
+	r, err := repository.Initialize(ctx, a, s, options.Control())
+	if err != nil {
+		if succeedIfExists && errors.Is(err, repository.ErrorRepoAlreadyExists) {
+			return nil
+		}
+
+		return Only(ctx, errors.Wrap(err, "Failed to initialize a new SFTP repository"))
+	}
+
+	defer utils.CloseRepo(ctx, r)
+
+	Infof(ctx, "Initialized an SFTP repository at %s:%s.", sftpCfg.Host, sftpCfg.Path)
+
+	if err = config.WriteRepoConfig(ctx, s, m365); err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to write repository configuration"))
+	}
+
+	if err := setInitCompression(ctx, r); err != nil {
+		return Only(ctx, err)
+	}
+
+	return nil
+}
+
+// ---------------------------------------------------------------------------------------------------------
+// Connect
+// ---------------------------------------------------------------------------------------------------------
+
+// `corso repo connect sftp [<flag>...]`
+func sftpConnectCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     sftpProviderCommand,
+		Short:   "Connect to an SFTP repository",
+		Long:    `Ensures a connection to an existing SFTP repository.` + sftpProviderCommandCredentialsHelp,
+		RunE:    connectSFTPCmd,
+		Args:    cobra.NoArgs,
+		Example: sftpProviderCommandConnectExamples,
+	}
+}
+
+// connects to an existing sftp repo.
+func connectSFTPCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if utils.HasNoFlagsAndShownHelp(cmd) {
+		return nil
+	}
+
+	overrides, err := sftpOverrides()
+	if err != nil {
+		return Only(ctx, err)
+	}
+
+	s, a, err := config.GetStorageAndAccount(ctx, true, overrides)
+	if err != nil {
+		return Only(ctx, err)
+	}
+
+	sftpCfg, err := s.SFTPConfig()
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Retrieving sftp configuration"))
+	}
+
+	m365, err := a.M365Config()
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to parse m365 account config"))
+	}
+
+	r, err := repository.Connect(ctx, a, s, options.Control())
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to connect to the SFTP repository"))
+	}
+
+	defer utils.CloseRepo(ctx, r)
+
+	Infof(ctx, "Connected to SFTP repository at %s:%s.", sftpCfg.Host, sftpCfg.Path)
+
+	if err = config.WriteRepoConfig(ctx, s, m365); err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to write repository configuration"))
+	}
+
+	return nil
+}
+
+// sftpOverrides resolves the local --key-file and --known-hosts flags to
+// absolute paths, as required by the ssh client.  The --path flag refers to
+// the remote host and is passed through unchanged.
+func sftpOverrides() (map[string]string, error) {
+	keyFile, err := absPathOrEmpty(sftpKeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "resolving key file path")
+	}
+
+	knownHosts, err := absPathOrEmpty(sftpKnownHostsFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "resolving known_hosts path")
+	}
+
+	var port string
+	if sftpPort > 0 {
+		port = strconv.Itoa(sftpPort)
+	}
+
+	return map[string]string{
+		config.AccountProviderTypeKey: account.ProviderM365.String(),
+		config.StorageProviderTypeKey: storage.ProviderSFTP.String(),
+		storage.Host:                  sftpHost,
+		storage.Port:                  port,
+		storage.Username:              sftpUser,
+		storage.KeyFile:               keyFile,
+		storage.KnownHostsFile:        knownHosts,
+		storage.SFTPPath:              sftpPath,
+	}, nil
+}
+
+func absPathOrEmpty(p string) (string, error) {
+	if len(p) == 0 {
+		return "", nil
+	}
+
+	return filepath.Abs(p)
+}
@@ -0,0 +1,51 @@
+package repo
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/tester"
+)
+
+type SFTPSuite struct {
+	suite.Suite
+}
+
+func TestSFTPSuite(t *testing.T) {
+	suite.Run(t, new(SFTPSuite))
+}
+
+func (suite *SFTPSuite) TestAddSFTPCommands() {
+	expectUse := sftpProviderCommand + " " + sftpProviderCommandUseSuffix
+
+	table := []struct {
+		name        string
+		use         string
+		expectUse   string
+		expectShort string
+		expectRunE  func(*cobra.Command, []string) error
+	}{
+		{"init sftp", initCommand, expectUse, sftpInitCmd().Short, initSFTPCmd},
+		{"connect sftp", connectCommand, expectUse, sftpConnectCmd().Short, connectSFTPCmd},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: test.use}
+
+			c := addSFTPCommands(cmd)
+			require.NotNil(t, c)
+
+			cmds := cmd.Commands()
+			require.Len(t, cmds, 1)
+
+			child := cmds[0]
+			assert.Equal(t, test.expectUse, child.Use)
+			assert.Equal(t, test.expectShort, child.Short)
+			tester.AreSameFunc(t, test.expectRunE, child.RunE)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package repo
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/alcionai/corso/src/cli/config"
+	"github.com/alcionai/corso/src/cli/options"
+	. "github.com/alcionai/corso/src/cli/print"
+	"github.com/alcionai/corso/src/cli/utils"
+	"github.com/alcionai/corso/src/pkg/repository"
+)
+
+const statusCommand = "status"
+
+const statusExamples = `# Show the repository's details, backup counts, and storage use
+corso repo status
+
+# Show the same information as JSON
+corso repo status --json`
+
+// The repo status subcommand.
+// `corso repo status`
+func statusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   statusCommand,
+		Short: "Show details about the connected repository.",
+		Long: `Show the repository's ID, creation time, hash, encryption, and splitter algorithms, and
+storage provider configuration, with secrets redacted, along with the number of backups of
+each service and resource owner. Storage use
+compares the logical size of the backed up data with its size after deduplication and
+compression. Calculating storage use reads the repository's indexes, which may take a while
+for large repositories.`,
+		Example: statusExamples,
+		RunE:    handleStatusCmd,
+		Args:    cobra.NoArgs,
+	}
+}
+
+// adds the status command to the parent.
+func addStatusCommand(cmd *cobra.Command) *cobra.Command {
+	c, _ := utils.AddCommand(cmd, statusCmd())
+	return c
+}
+
+// Handler for calls to `corso repo status`.
+func handleStatusCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	s, acct, err := config.GetStorageAndAccount(ctx, true, nil)
+	if err != nil {
+		return Only(ctx, err)
+	}
+
+	r, err := repository.Connect(ctx, acct, s, options.Control())
+	if err != nil {
+		return Only(ctx, errors.Wrapf(err, "Failed to connect to the %s repository", s.Provider))
+	}
+
+	defer utils.CloseRepo(ctx, r)
+
+	status, err := r.Status(ctx)
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to retrieve the repository status"))
+	}
+
+	status.Print(ctx)
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package repo
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/tester"
+)
+
+type StatusSuite struct {
+	suite.Suite
+}
+
+func TestStatusSuite(t *testing.T) {
+	suite.Run(t, new(StatusSuite))
+}
+
+func (suite *StatusSuite) TestAddStatusCommand() {
+	t := suite.T()
+	cmd := &cobra.Command{Use: "repo"}
+
+	c := addStatusCommand(cmd)
+	require.NotNil(t, c)
+
+	cmds := cmd.Commands()
+	require.Len(t, cmds, 1)
+
+	child := cmds[0]
+	assert.Equal(t, statusCommand, child.Use)
+	assert.Equal(t, statusCmd().Short, child.Short)
+	tester.AreSameFunc(t, handleStatusCmd, child.RunE)
+}
@@ -0,0 +1,128 @@
+package repo
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/alcionai/corso/src/cli/config"
+	"github.com/alcionai/corso/src/cli/options"
+	. "github.com/alcionai/corso/src/cli/print"
+	"github.com/alcionai/corso/src/cli/utils"
+	"github.com/alcionai/corso/src/pkg/backup"
+	"github.com/alcionai/corso/src/pkg/credentials"
+	"github.com/alcionai/corso/src/pkg/repository"
+	"github.com/alcionai/corso/src/pkg/storage"
+)
+
+const syncCommand = "sync"
+
+// sync flags
+var syncTo string
+
+const toFN = "to"
+
+const syncExamples = `# Copy the backups in the connected repository into the repository in ~/offsite.toml
+corso repo sync --to ~/offsite.toml
+
+# Copy into a repository protected by a different passphrase
+CORSO_SYNC_PASSPHRASE=<passphrase> corso repo sync --to ~/offsite.toml`
+
+// The repo sync subcommand.
+// `corso repo sync --to <config file>`
+func syncCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   syncCommand,
+		Short: "Copy the backups in the connected repository into another repository.",
+		Long: `Copy every backup, along with its data and details, into the repository described by the
+--to config file. The other repository must already be initialized. Backups it already
+contains are skipped, so repeated syncs only copy backups made since the last sync. Its
+passphrase is read from $CORSO_SYNC_PASSPHRASE, or $CORSO_PASSPHRASE when that is unset.`,
+		Example: syncExamples,
+		RunE:    handleSyncCmd,
+		Args:    cobra.NoArgs,
+	}
+}
+
+// adds the sync command to the parent.
+func addSyncCommand(cmd *cobra.Command) *cobra.Command {
+	c, fs := utils.AddCommand(cmd, syncCmd())
+
+	fs.StringVar(&syncTo, toFN, "", "Config file of the repository to copy backups into.")
+	cobra.CheckErr(c.MarkFlagRequired(toFN))
+
+	return c
+}
+
+// Handler for calls to `corso repo sync`.
+func handleSyncCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	s, acct, err := config.GetStorageAndAccount(ctx, true, nil)
+	if err != nil {
+		return Only(ctx, err)
+	}
+
+	ds, dacct, err := config.GetStorageAndAccountFromFile(syncTo)
+	if err != nil {
+		return Only(ctx, errors.Wrapf(err, "Failed to read the repository config in %s", syncTo))
+	}
+
+	// the destination needs its own kopia config and cache, or connecting to
+	// it would clobber those of the connected repository.
+	kopiaDir, err := os.MkdirTemp("", "corso-sync-")
+	if err != nil {
+		return Only(ctx, errors.Wrap(err, "Failed to create a config directory for the destination repository"))
+	}
+
+	defer os.RemoveAll(kopiaDir)
+
+	if err := syncDestCommonConfig(&ds, kopiaDir); err != nil {
+		return Only(ctx, err)
+	}
+
+	r, err := repository.Connect(ctx, acct, s, options.Control())
+	if err != nil {
+		return Only(ctx, errors.Wrapf(err, "Failed to connect to the %s repository", s.Provider))
+	}
+
+	defer utils.CloseRepo(ctx, r)
+
+	dest, err := repository.Connect(ctx, dacct, ds, options.Control())
+	if err != nil {
+		return Only(ctx, errors.Wrapf(err, "Failed to connect to the destination %s repository", ds.Provider))
+	}
+
+	defer utils.CloseRepo(ctx, dest)
+
+	copied, skipped, err := r.SyncBackups(ctx, dest)
+	if len(copied) > 0 {
+		backup.PrintAll(ctx, copied)
+	}
+
+	if err != nil {
+		return Only(ctx, errors.Wrapf(err, "Failed after copying %d backups", len(copied)))
+	}
+
+	Infof(ctx, "Copied %d backups; %d were already present in the destination repository.", len(copied), skipped)
+
+	return nil
+}
+
+// syncDestCommonConfig points the destination storage at its own kopia config
+// directory, and applies the sync passphrase, if one was provided.
+func syncDestCommonConfig(s *storage.Storage, kopiaDir string) error {
+	cc, err := s.CommonConfig()
+	if err != nil {
+		return err
+	}
+
+	if p := os.Getenv(credentials.CorsoSyncPassphrase); len(p) > 0 {
+		cc.CorsoPassphrase = p
+	}
+
+	cc.KopiaCfgDir = kopiaDir
+
+	return s.SetCommonConfig(cc)
+}
@@ -0,0 +1,69 @@
+package repo
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/tester"
+	"github.com/alcionai/corso/src/pkg/credentials"
+	"github.com/alcionai/corso/src/pkg/storage"
+)
+
+type SyncSuite struct {
+	suite.Suite
+}
+
+func TestSyncSuite(t *testing.T) {
+	suite.Run(t, new(SyncSuite))
+}
+
+func (suite *SyncSuite) TestAddSyncCommand() {
+	t := suite.T()
+	cmd := &cobra.Command{Use: "repo"}
+
+	c := addSyncCommand(cmd)
+	require.NotNil(t, c)
+
+	cmds := cmd.Commands()
+	require.Len(t, cmds, 1)
+
+	child := cmds[0]
+	assert.Equal(t, syncCommand, child.Use)
+	assert.Equal(t, syncCmd().Short, child.Short)
+	tester.AreSameFunc(t, handleSyncCmd, child.RunE)
+	assert.NotNil(t, child.Flags().Lookup(toFN))
+}
+
+func (suite *SyncSuite) TestSyncDestCommonConfig() {
+	table := []struct {
+		name       string
+		passphrase string
+		expect     string
+	}{
+		{"source passphrase", "", "source"},
+		{"sync passphrase", "dest", "dest"},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			t.Setenv(credentials.CorsoSyncPassphrase, test.passphrase)
+
+			s, err := storage.NewStorage(
+				storage.ProviderFilesystem,
+				storage.FilesystemConfig{Path: t.TempDir()},
+				storage.CommonConfig{Corso: credentials.Corso{CorsoPassphrase: "source"}})
+			require.NoError(t, err)
+
+			dir := t.TempDir()
+			require.NoError(t, syncDestCommonConfig(&s, dir))
+
+			cc, err := s.CommonConfig()
+			require.NoError(t, err)
+			assert.Equal(t, test.expect, cc.CorsoPassphrase)
+			assert.Equal(t, dir, cc.KopiaCfgDir)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package repo
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/alcionai/corso/src/cli/config"
+	. "github.com/alcionai/corso/src/cli/print"
+	"github.com/alcionai/corso/src/cli/utils"
+	"github.com/alcionai/corso/src/internal/kopia"
+	"github.com/alcionai/corso/src/pkg/credentials"
+	"github.com/alcionai/corso/src/pkg/repository"
+)
+
+const updatePassphraseCommand = "update-passphrase"
+
+const updatePassphraseExamples = `# Change the repository passphrase
+CORSO_PASSPHRASE=<current passphrase> CORSO_NEW_PASSPHRASE=<new passphrase> \
+  corso repo update-passphrase`
+
+// The repo update-passphrase subcommand.
+// `corso repo update-passphrase`
+func updatePassphraseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   updatePassphraseCommand,
+		Short: "Change the passphrase of the connected repository.",
+		Long: `Re-encrypt the repository's keys with the passphrase in $CORSO_NEW_PASSPHRASE, and update the
+local connection to use it. Backup data is not rewritten. Once complete, $CORSO_PASSPHRASE must
+be set to the new passphrase, and other hosts must reconnect to the repository using it.
+Fails if another Corso operation is using the repository on this host.`,
+		Example: updatePassphraseExamples,
+		RunE:    handleUpdatePassphraseCmd,
+		Args:    cobra.NoArgs,
+	}
+}
+
+// adds the update-passphrase command to the parent.
+func addUpdatePassphraseCommand(cmd *cobra.Command) *cobra.Command {
+	c, _ := utils.AddCommand(cmd, updatePassphraseCmd())
+
+	return c
+}
+
+// Handler for calls to `corso repo update-passphrase`.
+func handleUpdatePassphraseCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	newPassphrase := os.Getenv(credentials.CorsoNewPassphrase)
+	if len(newPassphrase) == 0 {
+		return Only(ctx, errors.Errorf("%s must be set to the new passphrase", credentials.CorsoNewPassphrase))
+	}
+
+	s, _, err := config.GetStorageAndAccount(ctx, true, nil)
+	if err != nil {
+		return Only(ctx, err)
+	}
+
+	if err := repository.UpdatePassphrase(ctx, s, newPassphrase); err != nil {
+		if errors.Is(err, kopia.ErrRepoInUse) {
+			return Only(ctx, errors.New("The repository is in use by another Corso operation; try again once it completes"))
+		}
+
+		return Only(ctx, errors.Wrapf(err, "Failed to update the passphrase of the %s repository", s.Provider))
+	}
+
+	Infof(ctx, "Updated the repository passphrase. Set %s to the new passphrase before running Corso again.",
+		credentials.CorsoPassphrase)
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package repo
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/tester"
+)
+
+type UpdatePassphraseSuite struct {
+	suite.Suite
+}
+
+func TestUpdatePassphraseSuite(t *testing.T) {
+	suite.Run(t, new(UpdatePassphraseSuite))
+}
+
+func (suite *UpdatePassphraseSuite) TestAddUpdatePassphraseCommand() {
+	t := suite.T()
+	cmd := &cobra.Command{Use: "repo"}
+
+	c := addUpdatePassphraseCommand(cmd)
+	require.NotNil(t, c)
+
+	cmds := cmd.Commands()
+	require.Len(t, cmds, 1)
+
+	child := cmds[0]
+	assert.Equal(t, updatePassphraseCommand, child.Use)
+	assert.Equal(t, updatePassphraseCmd().Short, child.Short)
+	tester.AreSameFunc(t, handleUpdatePassphraseCmd, child.RunE)
+}
@@ -0,0 +1,146 @@
+package repo
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/alcionai/corso/src/cli/config"
+	"github.com/alcionai/corso/src/cli/options"
+	. "github.com/alcionai/corso/src/cli/print"
+	"github.com/alcionai/corso/src/cli/utils"
+	"github.com/alcionai/corso/src/internal/kopia"
+	"github.com/alcionai/corso/src/internal/model"
+	"github.com/alcionai/corso/src/internal/verify"
+	"github.com/alcionai/corso/src/pkg/backup"
+	"github.com/alcionai/corso/src/pkg/repository"
+)
+
+const verifyCommand = "verify"
+
+// verify flags
+var (
+	verifyBackupID  string
+	verifySamplePct float64
+)
+
+const (
+	sampleFN = "sample"
+
+	defaultSamplePct = 10
+)
+
+const verifyExamples = `# Verify every backup, reading 10% of the items in each
+corso repo verify
+
+# Verify a single backup, reading every item
+corso repo verify --backup 1234abcd-12ab-cd34-56de-1234abcd --sample 100
+
+# Only check that the backups' snapshots and details are readable
+corso repo verify --sample 0`
+
+// The repo verify subcommand.
+// `corso repo verify [--backup <backupId>] [--sample <pct>]`
+func verifyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   verifyCommand,
+		Short: "Verify the integrity of the backups in the connected repository.",
+		Long: `Check that each backup's snapshot and details can be read, and read a sample of each backup's
+items back out of the repository, validating their format and content hashes. Nothing is
+restored to M365.`,
+		Example: verifyExamples,
+		RunE:    handleVerifyCmd,
+		Args:    cobra.NoArgs,
+	}
+}
+
+// adds the verify command to the parent.
+func addVerifyCommand(cmd *cobra.Command) *cobra.Command {
+	c, fs := utils.AddCommand(cmd, verifyCmd())
+
+	fs.StringVar(&verifyBackupID, utils.BackupFN, "", "ID of the backup to verify; defaults to all backups.")
+	fs.Float64Var(
+		&verifySamplePct,
+		sampleFN, defaultSamplePct,
+		"Percentage of each backup's items to read back out of the repository, from 0 to 100.")
+
+	return c
+}
+
+// Handler for calls to `corso repo verify`.
+func handleVerifyCmd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if verifySamplePct < 0 || verifySamplePct > 100 {
+		return Only(ctx, errors.New("--sample must be between 0 and 100"))
+	}
+
+	s, acct, err := config.GetStorageAndAccount(ctx, true, nil)
+	if err != nil {
+		return Only(ctx, err)
+	}
+
+	r, err := repository.Connect(ctx, acct, s, options.Control())
+	if err != nil {
+		return Only(ctx, errors.Wrapf(err, "Failed to connect to the %s repository", s.Provider))
+	}
+
+	defer utils.CloseRepo(ctx, r)
+
+	ids := []model.StableID{model.StableID(verifyBackupID)}
+
+	if len(verifyBackupID) == 0 {
+		bs, err := r.BackupsByTag(ctx)
+		if err != nil {
+			return Only(ctx, errors.Wrap(err, "Failed to list backups in the repository"))
+		}
+
+		ids = backupIDs(bs)
+	}
+
+	var (
+		results = make([]*verify.Result, 0, len(ids))
+		failed  int
+	)
+
+	for _, id := range ids {
+		res, err := r.VerifyBackup(ctx, id, verifySamplePct)
+		if err != nil {
+			if errors.Is(err, kopia.ErrNotFound) {
+				return Only(ctx, errors.Errorf("No backup exists with the id %s", id))
+			}
+
+			return Only(ctx, errors.Wrapf(err, "Failed to verify backup %s", id))
+		}
+
+		if !res.Passed() {
+			failed++
+		}
+
+		results = append(results, res)
+	}
+
+	verify.PrintAll(ctx, results)
+
+	if failed == 0 {
+		return nil
+	}
+
+	if !JSONFormat() {
+		for _, res := range results {
+			for _, e := range res.Errs {
+				Infof(ctx, "%s: %v", res.BackupID, e)
+			}
+		}
+	}
+
+	return Only(ctx, errors.Errorf("%d of %d backups failed verification", failed, len(results)))
+}
+
+func backupIDs(bs []*backup.Backup) []model.StableID {
+	ids := make([]model.StableID, 0, len(bs))
+	for _, b := range bs {
+		ids = append(ids, b.ID)
+	}
+
+	return ids
+}
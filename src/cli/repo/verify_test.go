@@ -0,0 +1,42 @@
+package repo
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/cli/utils"
+	"github.com/alcionai/corso/src/internal/tester"
+)
+
+type VerifySuite struct {
+	suite.Suite
+}
+
+func TestVerifySuite(t *testing.T) {
+	suite.Run(t, new(VerifySuite))
+}
+
+func (suite *VerifySuite) TestAddVerifyCommand() {
+	t := suite.T()
+	cmd := &cobra.Command{Use: "repo"}
+
+	c := addVerifyCommand(cmd)
+	require.NotNil(t, c)
+
+	cmds := cmd.Commands()
+	require.Len(t, cmds, 1)
+
+	child := cmds[0]
+	assert.Equal(t, verifyCommand, child.Use)
+	assert.Equal(t, verifyCmd().Short, child.Short)
+	tester.AreSameFunc(t, handleVerifyCmd, child.RunE)
+
+	sample := child.Flags().Lookup(sampleFN)
+	require.NotNil(t, sample)
+	assert.Equal(t, "10", sample.DefValue)
+	assert.NotNil(t, child.Flags().Lookup(utils.BackupFN))
+}
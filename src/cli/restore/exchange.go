@@ -11,14 +11,14 @@ import (
 	"github.com/alcionai/corso/src/cli/utils"
 	"github.com/alcionai/corso/src/internal/common"
 	"github.com/alcionai/corso/src/internal/kopia"
-	"github.com/alcionai/corso/src/pkg/control"
 	"github.com/alcionai/corso/src/pkg/repository"
 )
 
 // exchange bucket info from flags
 var (
-	backupID string
-	user     []string
+	backupID        string
+	destinationUser string
+	user            []string
 
 	contact       []string
 	contactFolder []string
@@ -65,6 +65,10 @@ func addExchangeCommands(cmd *cobra.Command) *cobra.Command {
 			utils.UserFN, nil,
 			"Restore data by user ID; accepts '"+utils.Wildcard+"' to select all users.")
 
+		fs.StringVar(&destinationUser,
+			utils.DestinationUserFN, "",
+			"Restore data into this user's account instead of the account it was backed up from.")
+
 		// email flags
 		fs.StringSliceVar(&email,
 			utils.EmailFN, nil,
@@ -133,8 +137,12 @@ func addExchangeCommands(cmd *cobra.Command) *cobra.Command {
 			utils.ContactNameFN, "",
 			"Restore contacts whose contact name contains this value.")
 
+		// destination flags
+		addDestinationFlags(c, fs)
+
 		// others
 		options.AddOperationFlags(c)
+		options.AddRestoreFlags(c)
 	}
 
 	return c
@@ -156,7 +164,11 @@ corso restore exchange --backup 1234abcd-12ab-cd34-56de-1234abcd \
       --user bob@example.com --event-calendar Calendar
 
 # Restore contact with ID abdef0101 from a specific backup
-corso restore exchange --backup 1234abcd-12ab-cd34-56de-1234abcd --contact abdef0101`
+corso restore exchange --backup 1234abcd-12ab-cd34-56de-1234abcd --contact abdef0101
+
+# Restore Alice's entire mailbox from a specific backup into Bob's mailbox
+corso restore exchange --backup 1234abcd-12ab-cd34-56de-1234abcd \
+      --user alice@example.com --email '*' --destination-user bob@example.com`
 )
 
 // `corso restore exchange [<flag>...]`
@@ -216,7 +228,7 @@ func restoreExchangeCmd(cmd *cobra.Command, args []string) error {
 
 	defer utils.CloseRepo(ctx, r)
 
-	dest := control.DefaultRestoreDestination(common.SimpleDateTime)
+	dest := restoreDestination(common.SimpleDateTime, destinationUser)
 
 	sel := utils.IncludeExchangeRestoreDataSelectors(opts)
 	utils.FilterExchangeRestoreInfoSelectors(sel, opts)
@@ -235,6 +247,10 @@ func restoreExchangeCmd(cmd *cobra.Command, args []string) error {
 		return Only(ctx, errors.Wrap(err, "Failed to run Exchange restore"))
 	}
 
+	if ro.Results.ItemsSkipped > 0 {
+		Infof(ctx, "Skipped %d items that already exist in the restore destination\n", ro.Results.ItemsSkipped)
+	}
+
 	ds.PrintEntries(ctx)
 
 	return nil
@@ -11,7 +11,6 @@ import (
 	"github.com/alcionai/corso/src/cli/utils"
 	"github.com/alcionai/corso/src/internal/common"
 	"github.com/alcionai/corso/src/internal/kopia"
-	"github.com/alcionai/corso/src/pkg/control"
 	"github.com/alcionai/corso/src/pkg/repository"
 )
 
@@ -51,6 +50,10 @@ func addOneDriveCommands(cmd *cobra.Command) *cobra.Command {
 			utils.UserFN, nil,
 			"Restore data by user ID; accepts '"+utils.Wildcard+"' to select all users.")
 
+		fs.StringVar(&destinationUser,
+			utils.DestinationUserFN, "",
+			"Restore data into this user's account instead of the account it was backed up from.")
+
 		// onedrive hierarchy (path/name) flags
 
 		fs.StringSliceVar(
@@ -83,8 +86,14 @@ func addOneDriveCommands(cmd *cobra.Command) *cobra.Command {
 			utils.FileModifiedBeforeFN, "",
 			"Restore files modified before this datetime")
 
+		// destination flags
+		addDestinationFlags(c, fs)
+
 		// others
 		options.AddOperationFlags(c)
+		options.AddRestoreFlags(c)
+		options.AddPermissionsFlags(c)
+		options.AddVersionRestoreFlags(c)
 	}
 
 	return c
@@ -103,7 +112,18 @@ corso restore onedrive --backup 1234abcd-12ab-cd34-56de-1234abcd \
 
 # Restore all files from Bob's folder that were created before 2020 when captured in a specific backup
 corso restore onedrive --backup 1234abcd-12ab-cd34-56de-1234abcd 
-      --user bob@example.com --folder "Documents/Finance Reports" --file-created-before 2020-01-01T00:00:00`
+      --user bob@example.com --folder "Documents/Finance Reports" --file-created-before 2020-01-01T00:00:00
+
+# Restore Alice's files, granting the access they shared with Bob to Carol instead
+corso restore onedrive --backup 1234abcd-12ab-cd34-56de-1234abcd \
+      --user alice@example.com --map-principal bob@example.com=carol@example.com
+
+# Restore Alice's files without their permissions and sharing links
+corso restore onedrive --backup 1234abcd-12ab-cd34-56de-1234abcd --user alice@example.com --skip-permissions
+
+# Restore version 3.0 of Alice's file named "FY2021 Planning.xlsx", as listed by 'corso backup details onedrive'
+corso restore onedrive --backup 1234abcd-12ab-cd34-56de-1234abcd \
+      --user alice@example.com --file "FY2021 Planning.xlsx" --version 3.0`
 )
 
 // `corso restore onedrive [<flag>...]`
@@ -153,7 +173,7 @@ func restoreOneDriveCmd(cmd *cobra.Command, args []string) error {
 
 	defer utils.CloseRepo(ctx, r)
 
-	dest := control.DefaultRestoreDestination(common.SimpleDateTimeOneDrive)
+	dest := restoreDestination(common.SimpleDateTimeOneDrive, destinationUser)
 
 	sel := utils.IncludeOneDriveRestoreDataSelectors(opts)
 	utils.FilterOneDriveRestoreInfoSelectors(sel, opts)
@@ -172,6 +192,10 @@ func restoreOneDriveCmd(cmd *cobra.Command, args []string) error {
 		return Only(ctx, errors.Wrap(err, "Failed to run OneDrive restore"))
 	}
 
+	if ro.Results.ItemsSkipped > 0 {
+		Infof(ctx, "Skipped %d items that already exist in the restore destination\n", ro.Results.ItemsSkipped)
+	}
+
 	ds.PrintEntries(ctx)
 
 	return nil
@@ -2,6 +2,17 @@ package restore
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/alcionai/corso/src/cli/utils"
+	"github.com/alcionai/corso/src/internal/common"
+	"github.com/alcionai/corso/src/pkg/control"
+)
+
+// restore destination info from flags
+var (
+	destinationFolder string
+	originalLocation  bool
 )
 
 var restoreCommands = []func(cmd *cobra.Command) *cobra.Command{
@@ -39,3 +50,34 @@ func restoreCmd() *cobra.Command {
 func handleRestoreCmd(cmd *cobra.Command, args []string) error {
 	return cmd.Help()
 }
+
+// adds the flags that control where restored data is placed.
+func addDestinationFlags(c *cobra.Command, fs *pflag.FlagSet) {
+	fs.StringVar(
+		&destinationFolder,
+		utils.DestinationFolderFN, "",
+		"Restore data into this folder instead of a new, timestamped restore folder.")
+	fs.BoolVar(
+		&originalLocation,
+		utils.OriginalLocationFN, false,
+		"Restore data into the folders it was backed up from, recreating any that no longer exist.")
+
+	c.MarkFlagsMutuallyExclusive(utils.DestinationFolderFN, utils.OriginalLocationFN)
+}
+
+// restoreDestination produces the restore destination described by the
+// destination flags.  By default, data is restored into a new folder named
+// with the current time, using the provided format.
+func restoreDestination(timeFormat common.TimeFormat, ownerOverride string) control.RestoreDestination {
+	dest := control.DefaultRestoreDestination(timeFormat)
+	dest.ResourceOwnerOverride = ownerOverride
+
+	switch {
+	case originalLocation:
+		dest.ContainerName = ""
+	case len(destinationFolder) > 0:
+		dest.ContainerName = destinationFolder
+	}
+
+	return dest
+}
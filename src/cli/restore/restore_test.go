@@ -0,0 +1,66 @@
+package restore
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/common"
+)
+
+type RestoreSuite struct {
+	suite.Suite
+}
+
+func TestRestoreSuite(t *testing.T) {
+	suite.Run(t, new(RestoreSuite))
+}
+
+func (suite *RestoreSuite) TestRestoreDestination() {
+	table := []struct {
+		name            string
+		folder          string
+		inPlace         bool
+		owner           string
+		expectContainer func(t *testing.T, name string)
+	}{
+		{
+			name: "default",
+			expectContainer: func(t *testing.T, name string) {
+				assert.True(t, strings.HasPrefix(name, "Corso_Restore_"), name)
+			},
+		},
+		{
+			name:   "destination folder",
+			folder: "recovered",
+			owner:  "owner",
+			expectContainer: func(t *testing.T, name string) {
+				assert.Equal(t, "recovered", name)
+			},
+		},
+		{
+			name:    "original location",
+			inPlace: true,
+			expectContainer: func(t *testing.T, name string) {
+				assert.Empty(t, name)
+			},
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			destinationFolder = test.folder
+			originalLocation = test.inPlace
+
+			defer func() {
+				destinationFolder = ""
+				originalLocation = false
+			}()
+
+			dest := restoreDestination(common.SimpleDateTime, test.owner)
+			test.expectContainer(t, dest.ContainerName)
+			assert.Equal(t, test.owner, dest.ResourceOwnerOverride)
+		})
+	}
+}
@@ -11,7 +11,6 @@ import (
 	"github.com/alcionai/corso/src/cli/utils"
 	"github.com/alcionai/corso/src/internal/common"
 	"github.com/alcionai/corso/src/internal/kopia"
-	"github.com/alcionai/corso/src/pkg/control"
 	"github.com/alcionai/corso/src/pkg/repository"
 )
 
@@ -22,6 +21,8 @@ var (
 	libraryPaths []string
 	site         []string
 	weburl       []string
+
+	destinationSite string
 )
 
 // called by restore.go to map subcommands to provider-specific handling.
@@ -54,6 +55,10 @@ func addSharePointCommands(cmd *cobra.Command) *cobra.Command {
 			utils.WebURLFN, nil,
 			"Restore data by site webURL; accepts '"+utils.Wildcard+"' to select all sites.")
 
+		fs.StringVar(&destinationSite,
+			utils.DestinationSiteFN, "",
+			"Restore data into this site instead of the site it was backed up from.")
+
 		// sharepoint hierarchy (path/name) flags
 
 		fs.StringSliceVar(
@@ -83,8 +88,13 @@ func addSharePointCommands(cmd *cobra.Command) *cobra.Command {
 		// 	utils.FileCreatedAfterFN, "",
 		// 	"Restore files created after this datetime")
 
+		// destination flags
+		addDestinationFlags(c, fs)
+
 		// others
 		options.AddOperationFlags(c)
+		options.AddRestoreFlags(c)
+		options.AddPermissionsFlags(c)
 	}
 
 	return c
@@ -154,7 +164,7 @@ func restoreSharePointCmd(cmd *cobra.Command, args []string) error {
 
 	defer utils.CloseRepo(ctx, r)
 
-	dest := control.DefaultRestoreDestination(common.SimpleDateTime)
+	dest := restoreDestination(common.SimpleDateTime, destinationSite)
 
 	sel := utils.IncludeSharePointRestoreDataSelectors(opts)
 	utils.FilterSharePointRestoreInfoSelectors(sel, opts)
@@ -173,6 +183,10 @@ func restoreSharePointCmd(cmd *cobra.Command, args []string) error {
 		return Only(ctx, errors.Wrap(err, "Failed to run SharePoint restore"))
 	}
 
+	if ro.Results.ItemsSkipped > 0 {
+		Infof(ctx, "Skipped %d items that already exist in the restore destination\n", ro.Results.ItemsSkipped)
+	}
+
 	ds.PrintEntries(ctx)
 
 	return nil
@@ -15,10 +15,15 @@ import (
 
 // common flag names
 const (
-	BackupFN = "backup"
-	DataFN   = "data"
-	SiteFN   = "site"
-	UserFN   = "user"
+	BackupFN            = "backup"
+	DataFN              = "data"
+	DestinationFolderFN = "destination-folder"
+	DestinationSiteFN   = "destination-site"
+	DestinationUserFN   = "destination-user"
+	OriginalLocationFN  = "original-location"
+	OutputFN            = "output"
+	SiteFN              = "site"
+	UserFN              = "user"
 )
 
 const (
@@ -89,7 +89,7 @@ func generateAndRestoreItems(
 
 	Infof(ctx, "Generating %d %s items in %s\n", howMany, cat, Destination)
 
-	return gc.RestoreDataCollections(ctx, acct, sel, dest, dataColls)
+	return gc.RestoreDataCollections(ctx, acct, sel, dest, control.Defaults(), dataColls)
 }
 
 // ------------------------------------------------------------------------------------------
@@ -23,3 +23,9 @@ func NormalizePrefix(p string) string {
 
 	return tp
 }
+
+// NormalizeGCSBucket removes any gs:// url prefixing from a google
+// cloud storage bucket name, leaving only the bucket name.
+func NormalizeGCSBucket(b string) string {
+	return strings.TrimPrefix(b, "gs://")
+}
@@ -55,27 +55,18 @@ func (gc *GraphConnector) DataCollections(
 			return nil, err
 		}
 
-		for _, c := range colls {
-			// kopia doesn't stream Items() from deleted collections,
-			// and so they never end up calling the UpdateStatus closer.
-			// This is a brittle workaround, since changes in consumer
-			// behavior (such as calling Items()) could inadvertently
-			// break the process state, putting us into deadlock or
-			// panics.
-			if c.State() != data.DeletedState {
-				gc.incrementAwaitingMessages()
-			}
-		}
+		gc.awaitCollections(colls)
 
 		return colls, nil
 
 	case selectors.ServiceOneDrive:
-		return gc.OneDriveDataCollections(ctx, sels, ctrlOpts)
+		return gc.OneDriveDataCollections(ctx, sels, metadata, ctrlOpts)
 
 	case selectors.ServiceSharePoint:
 		colls, err := sharepoint.DataCollections(
 			ctx,
 			sels,
+			metadata,
 			gc.credentials.AzureTenantID,
 			gc.Service,
 			gc,
@@ -84,9 +75,7 @@ func (gc *GraphConnector) DataCollections(
 			return nil, err
 		}
 
-		for range colls {
-			gc.incrementAwaitingMessages()
-		}
+		gc.awaitCollections(colls)
 
 		return colls, nil
 
@@ -95,6 +84,22 @@ func (gc *GraphConnector) DataCollections(
 	}
 }
 
+// awaitCollections increments the count of status messages awaited from the
+// collections.
+func (gc *GraphConnector) awaitCollections(colls []data.Collection) {
+	for _, c := range colls {
+		// kopia doesn't stream Items() from deleted collections,
+		// and so they never end up calling the UpdateStatus closer.
+		// This is a brittle workaround, since changes in consumer
+		// behavior (such as calling Items()) could inadvertently
+		// break the process state, putting us into deadlock or
+		// panics.
+		if c.State() != data.DeletedState {
+			gc.incrementAwaitingMessages()
+		}
+	}
+}
+
 func verifyBackupInputs(sels selectors.Selector, userPNs, siteIDs []string) error {
 	var ids []string
 
@@ -141,10 +146,12 @@ func (fm odFolderMatcher) Matches(dir string) bool {
 }
 
 // OneDriveDataCollections returns a set of DataCollection which represents the OneDrive data
-// for the specified user
+// for the specified user.  metadata holds the collections of metadata saved by the previous
+// backup, which are used to back up the drives incrementally.
 func (gc *GraphConnector) OneDriveDataCollections(
 	ctx context.Context,
 	selector selectors.Selector,
+	metadata []data.Collection,
 	ctrlOpts control.Options,
 ) ([]data.Collection, error) {
 	odb, err := selector.ToOneDriveBackup()
@@ -158,6 +165,11 @@ func (gc *GraphConnector) OneDriveDataCollections(
 		errs        error
 	)
 
+	prev, err := onedrive.DeserializeMetadata(ctx, metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing previous backup metadata")
+	}
+
 	// for each scope that includes oneDrive items, get all
 	for _, scope := range odb.Scopes() {
 		logger.Ctx(ctx).With("user", user).Debug("Creating OneDrive collections")
@@ -170,7 +182,7 @@ func (gc *GraphConnector) OneDriveDataCollections(
 			gc.Service,
 			gc.UpdateStatus,
 			ctrlOpts,
-		).Get(ctx)
+		).Get(ctx, prev)
 		if err != nil {
 			return nil, support.WrapAndAppend(user, err, errs)
 		}
@@ -178,9 +190,7 @@ func (gc *GraphConnector) OneDriveDataCollections(
 		collections = append(collections, odcs...)
 	}
 
-	for range collections {
-		gc.incrementAwaitingMessages()
-	}
+	gc.awaitCollections(collections)
 
 	return collections, errs
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/alcionai/corso/src/internal/connector/exchange"
 	"github.com/alcionai/corso/src/internal/connector/sharepoint"
+	"github.com/alcionai/corso/src/internal/data"
 	"github.com/alcionai/corso/src/internal/tester"
 	"github.com/alcionai/corso/src/pkg/control"
 	"github.com/alcionai/corso/src/pkg/selectors"
@@ -243,21 +244,25 @@ func (suite *ConnectorDataCollectionIntegrationSuite) TestSharePointDataCollecti
 			collections, err := sharepoint.DataCollections(
 				ctx,
 				test.getSelector(),
+				nil,
 				connector.credentials.AzureTenantID,
 				connector.Service,
 				connector,
 				control.Options{})
 			require.NoError(t, err)
 
-			for range collections {
-				connector.incrementAwaitingMessages()
-			}
+			connector.awaitCollections(collections)
 
 			// we don't know an exact count of drives this will produce,
 			// but it should be more than one.
 			assert.Less(t, test.expected, len(collections))
 
 			for _, coll := range collections {
+				// deleted collections have no items to read.
+				if coll.State() == data.DeletedState {
+					continue
+				}
+
 				for object := range coll.Items() {
 					buf := &bytes.Buffer{}
 					_, err := buf.ReadFrom(object.ToReader())
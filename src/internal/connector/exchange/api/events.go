@@ -2,6 +2,8 @@ package api
 
 import (
 	"context"
+	"fmt"
+	"net/url"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/microsoft/kiota-abstractions-go/serialization"
@@ -133,29 +135,25 @@ func (c Events) EnumerateContainers(
 // item pager
 // ---------------------------------------------------------------------------
 
-type eventWrapper struct {
-	models.EventCollectionResponseable
-}
-
-func (ew eventWrapper) GetOdataDeltaLink() *string {
-	return nil
-}
+// betaEventsDeltaURL is the beta endpoint for the delta of the events within a
+// calendar.  The v1.0 API only supports event deltas within a calendarView.
+const betaEventsDeltaURL = "https://graph.microsoft.com/beta/users/%s/calendars/%s/events/delta?$select=id"
 
 var _ itemPager = &eventPager{}
 
+// eventPager pages through raw delta urls, which already hold any query
+// parameters, so it has no request options.
 type eventPager struct {
 	gs      graph.Servicer
-	builder *users.ItemCalendarsItemEventsRequestBuilder
-	options *users.ItemCalendarsItemEventsRequestBuilderGetRequestConfiguration
+	builder *users.ItemCalendarsItemEventsDeltaRequestBuilder
 }
 
 func (p *eventPager) getPage(ctx context.Context) (pageLinker, error) {
-	resp, err := p.builder.Get(ctx, p.options)
-	return eventWrapper{resp}, err
+	return p.builder.Get(ctx, nil)
 }
 
 func (p *eventPager) setNext(nextLink string) {
-	p.builder = users.NewItemCalendarsItemEventsRequestBuilder(nextLink, p.gs.Adapter())
+	p.builder = users.NewItemCalendarsItemEventsDeltaRequestBuilder(nextLink, p.gs.Adapter())
 }
 
 func (p *eventPager) valuesIn(pl pageLinker) ([]getIDAndAddtler, error) {
@@ -171,23 +169,48 @@ func (c Events) GetAddedAndRemovedItemIDs(
 		return nil, nil, DeltaUpdate{}, err
 	}
 
-	var errs *multierror.Error
+	return eventsAddedAndRemoved(ctx, service, user, calendarID, oldDelta)
+}
 
-	options, err := optionsForEventsByCalendar([]string{"id"})
-	if err != nil {
-		return nil, nil, DeltaUpdate{}, err
+func eventsAddedAndRemoved(
+	ctx context.Context,
+	service graph.Servicer,
+	user, calendarID, oldDelta string,
+) ([]string, []string, DeltaUpdate, error) {
+	var (
+		errs       *multierror.Error
+		resetDelta bool
+	)
+
+	if len(oldDelta) > 0 {
+		builder := users.NewItemCalendarsItemEventsDeltaRequestBuilder(oldDelta, service.Adapter())
+		pgr := &eventPager{service, builder}
+
+		added, removed, deltaURL, err := getItemsAddedAndRemovedFromContainer(ctx, pgr)
+		// note: happy path, not the error condition
+		if err == nil {
+			return added, removed, DeltaUpdate{deltaURL, false}, errs.ErrorOrNil()
+		}
+		// only return on error if it is NOT a delta issue.
+		// on bad deltas we retry the call with the regular builder
+		if graph.IsErrInvalidDelta(err) == nil {
+			return nil, nil, DeltaUpdate{}, err
+		}
+
+		resetDelta = true
+		errs = nil
 	}
 
-	builder := service.Client().UsersById(user).CalendarsById(calendarID).Events()
-	pgr := &eventPager{service, builder, options}
+	rawURL := fmt.Sprintf(betaEventsDeltaURL, url.PathEscape(user), url.PathEscape(calendarID))
+	builder := users.NewItemCalendarsItemEventsDeltaRequestBuilder(rawURL, service.Adapter())
+	pgr := &eventPager{service, builder}
 
-	added, _, _, err := getItemsAddedAndRemovedFromContainer(ctx, pgr)
+	added, removed, deltaURL, err := getItemsAddedAndRemovedFromContainer(ctx, pgr)
 	if err != nil {
 		return nil, nil, DeltaUpdate{}, err
 	}
 
-	// Events don't have a delta endpoint so just return an empty string.
-	return added, nil, DeltaUpdate{}, errs.ErrorOrNil()
+	return added, removed, DeltaUpdate{deltaURL, resetDelta}, errs.ErrorOrNil()
 }
 
 // ---------------------------------------------------------------------------
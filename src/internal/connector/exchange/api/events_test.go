@@ -0,0 +1,213 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/microsoft/kiota-abstractions-go/authentication"
+	msgraphsdk "github.com/microsoftgraph/msgraph-sdk-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/connector/graph"
+	"github.com/alcionai/corso/src/internal/tester"
+)
+
+const (
+	eventsDeltaPath = "/beta/users/user/calendars/calendar/events/delta"
+	eventsDeltaURL  = "https://graph.microsoft.com" + eventsDeltaPath
+
+	eventsFirstPage = `{
+		"value": [
+			{"id": "e1"},
+			{"id": "e2", "@removed": {"reason": "deleted"}}
+		],
+		"@odata.nextLink": "` + eventsDeltaURL + `?$skiptoken=next"
+	}`
+	eventsLastPage = `{
+		"value": [{"id": "e3"}],
+		"@odata.deltaLink": "` + eventsDeltaURL + `?$deltatoken=new"
+	}`
+	eventsInvalidDelta = `{"error": {"code": "SyncStateNotFound", "message": "sync state not found"}}`
+	eventsServerError  = `{"error": {"code": "generalException", "message": "server error"}}`
+)
+
+// redirectTransport sends all requests to the test server.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = rt.target.Scheme
+	req.URL.Host = rt.target.Host
+	req.Host = rt.target.Host
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+type EventsUnitSuite struct {
+	suite.Suite
+}
+
+func TestEventsUnitSuite(t *testing.T) {
+	suite.Run(t, new(EventsUnitSuite))
+}
+
+// mockEventsService produces a service whose requests are answered by the
+// provided responses, keyed by request query, and records the requested urls.
+func mockEventsService(
+	t *testing.T,
+	responses map[string]string,
+	statuses map[string]int,
+) (graph.Servicer, *[]string) {
+	var (
+		mu        sync.Mutex
+		requested = []string{}
+	)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path+"?"+r.URL.RawQuery)
+		mu.Unlock()
+
+		resp, ok := responses[r.URL.RawQuery]
+		if !ok || r.URL.Path != eventsDeltaPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if status, ok := statuses[r.URL.RawQuery]; ok {
+			w.WriteHeader(status)
+		}
+
+		_, err := w.Write([]byte(resp))
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(ts.Close)
+
+	target, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+
+	adpt, err := msgraphsdk.NewGraphRequestAdapterWithParseNodeFactoryAndSerializationWriterFactoryAndHttpClient(
+		&authentication.AnonymousAuthenticationProvider{},
+		nil,
+		nil,
+		&http.Client{Transport: redirectTransport{target}})
+	require.NoError(t, err)
+
+	return graph.NewService(adpt), &requested
+}
+
+func (suite *EventsUnitSuite) TestEventsAddedAndRemoved() {
+	var (
+		fullQuery = "$select=id"
+		nextQuery = "$skiptoken=next"
+		oldQuery  = "$deltatoken=old"
+		oldDelta  = eventsDeltaURL + "?" + oldQuery
+		newDelta  = eventsDeltaURL + "?$deltatoken=new"
+	)
+
+	table := []struct {
+		name            string
+		oldDelta        string
+		responses       map[string]string
+		statuses        map[string]int
+		expectErr       assert.ErrorAssertionFunc
+		expectAdded     []string
+		expectRemoved   []string
+		expectDelta     DeltaUpdate
+		expectRequested []string
+	}{
+		{
+			name: "no previous delta",
+			responses: map[string]string{
+				fullQuery: eventsFirstPage,
+				nextQuery: eventsLastPage,
+			},
+			expectErr:     assert.NoError,
+			expectAdded:   []string{"e1", "e3"},
+			expectRemoved: []string{"e2"},
+			expectDelta:   DeltaUpdate{URL: newDelta},
+			expectRequested: []string{
+				eventsDeltaPath + "?" + fullQuery,
+				eventsDeltaPath + "?" + nextQuery,
+			},
+		},
+		{
+			name:     "previous delta",
+			oldDelta: oldDelta,
+			responses: map[string]string{
+				oldQuery: eventsLastPage,
+			},
+			expectErr:     assert.NoError,
+			expectAdded:   []string{"e3"},
+			expectRemoved: []string{},
+			expectDelta:   DeltaUpdate{URL: newDelta},
+			expectRequested: []string{
+				eventsDeltaPath + "?" + oldQuery,
+			},
+		},
+		{
+			name:     "invalid previous delta",
+			oldDelta: oldDelta,
+			responses: map[string]string{
+				oldQuery:  eventsInvalidDelta,
+				fullQuery: eventsFirstPage,
+				nextQuery: eventsLastPage,
+			},
+			statuses: map[string]int{
+				oldQuery: http.StatusGone,
+			},
+			expectErr:     assert.NoError,
+			expectAdded:   []string{"e1", "e3"},
+			expectRemoved: []string{"e2"},
+			expectDelta:   DeltaUpdate{URL: newDelta, Reset: true},
+			expectRequested: []string{
+				eventsDeltaPath + "?" + oldQuery,
+				eventsDeltaPath + "?" + fullQuery,
+				eventsDeltaPath + "?" + nextQuery,
+			},
+		},
+		{
+			name:     "previous delta fails",
+			oldDelta: oldDelta,
+			responses: map[string]string{
+				oldQuery: eventsServerError,
+			},
+			statuses: map[string]int{
+				oldQuery: http.StatusBadRequest,
+			},
+			expectErr: assert.Error,
+			expectRequested: []string{
+				eventsDeltaPath + "?" + oldQuery,
+			},
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			ctx, flush := tester.NewContext()
+			defer flush()
+
+			service, requested := mockEventsService(t, test.responses, test.statuses)
+
+			added, removed, du, err := eventsAddedAndRemoved(ctx, service, "user", "calendar", test.oldDelta)
+			test.expectErr(t, err)
+			assert.Equal(t, test.expectRequested, *requested, "requested urls")
+
+			if err != nil {
+				return
+			}
+
+			assert.Equal(t, test.expectAdded, added, "added")
+			assert.Equal(t, test.expectRemoved, removed, "removed")
+			assert.Equal(t, test.expectDelta, du, "delta update")
+		})
+	}
+}
@@ -21,18 +21,6 @@ var (
 		"owner":             {},
 	}
 
-	fieldsForEvents = map[string]struct{}{
-		"calendar":          {},
-		"end":               {},
-		"id":                {},
-		"isOnlineMeeting":   {},
-		"isReminderOn":      {},
-		"responseStatus":    {},
-		"responseRequested": {},
-		"showAs":            {},
-		"subject":           {},
-	}
-
 	fieldsForFolders = map[string]struct{}{
 		"childFolderCount": {},
 		"displayName":      {},
@@ -213,26 +201,6 @@ func optionsForContactFoldersItemDelta(
 	return options, nil
 }
 
-// optionsForEvents ensures a valid option inputs for `exchange.Events` when selected from within a Calendar
-func optionsForEventsByCalendar(
-	moreOps []string,
-) (*users.ItemCalendarsItemEventsRequestBuilderGetRequestConfiguration, error) {
-	selecting, err := buildOptions(moreOps, fieldsForEvents)
-	if err != nil {
-		return nil, err
-	}
-
-	requestParameters := &users.ItemCalendarsItemEventsRequestBuilderGetQueryParameters{
-		Select: selecting,
-	}
-
-	options := &users.ItemCalendarsItemEventsRequestBuilderGetRequestConfiguration{
-		QueryParameters: requestParameters,
-	}
-
-	return options, nil
-}
-
 // optionsForContactChildFolders builds a contacts child folders request.
 func optionsForContactChildFolders(
 	moreOps []string,
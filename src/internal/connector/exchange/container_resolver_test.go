@@ -596,6 +596,7 @@ func (suite *FolderCacheIntegrationSuite) TestCreateContainerDestination() {
 			folderID, err := CreateContainerDestinaion(
 				ctx,
 				m365,
+				user,
 				test.pathFunc1(t),
 				folderName,
 				directoryCaches)
@@ -609,6 +610,7 @@ func (suite *FolderCacheIntegrationSuite) TestCreateContainerDestination() {
 			secondID, err := CreateContainerDestinaion(
 				ctx,
 				m365,
+				user,
 				test.pathFunc2(t),
 				folderName,
 				directoryCaches)
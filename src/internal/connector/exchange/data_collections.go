@@ -23,7 +23,7 @@ import (
 // store graph metadata such as delta tokens and folderID->path references.
 func MetadataFileNames(cat path.CategoryType) []string {
 	switch cat {
-	case path.EmailCategory, path.ContactsCategory:
+	case path.EmailCategory, path.ContactsCategory, path.EventsCategory:
 		return []string{graph.DeltaURLsFileName, graph.PreviousPathFileName}
 	default:
 		return []string{graph.PreviousPathFileName}
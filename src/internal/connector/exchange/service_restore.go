@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"reflect"
 	"runtime/trace"
+	"strings"
 
 	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/microsoftgraph/msgraph-sdk-go/users"
 	"github.com/pkg/errors"
 
 	"github.com/alcionai/corso/src/internal/common"
@@ -35,17 +37,13 @@ func RestoreExchangeObject(
 	service graph.Servicer,
 	destination, user string,
 ) (*details.ExchangeInfo, error) {
-	if policy != control.Copy {
-		return nil, fmt.Errorf("restore policy: %s not supported for RestoreExchangeObject", policy)
-	}
-
 	switch category {
 	case path.EmailCategory:
-		return RestoreMailMessage(ctx, bits, service, control.Copy, destination, user)
+		return RestoreMailMessage(ctx, bits, service, policy, destination, user)
 	case path.ContactsCategory:
-		return RestoreExchangeContact(ctx, bits, service, control.Copy, destination, user)
+		return RestoreExchangeContact(ctx, bits, service, policy, destination, user)
 	case path.EventsCategory:
-		return RestoreExchangeEvent(ctx, bits, service, control.Copy, destination, user)
+		return RestoreExchangeEvent(ctx, bits, service, policy, destination, user)
 	default:
 		return nil, fmt.Errorf("type: %s not supported for RestoreExchangeObject", category)
 	}
@@ -69,19 +67,31 @@ func RestoreExchangeContact(
 		return nil, errors.Wrap(err, "creating contact from bytes: RestoreExchangeContact")
 	}
 
-	response, err := service.Client().UsersById(user).ContactFoldersById(destination).Contacts().Post(ctx, contact, nil)
-	if err != nil {
-		name := *contact.GetGivenName()
+	err = restoreWithCollision(
+		ctx,
+		cp,
+		func() (string, error) { return existingContactID(ctx, service, user, destination, contact) },
+		func() error {
+			response, err := service.Client().UsersById(user).ContactFoldersById(destination).Contacts().Post(ctx, contact, nil)
+			if err != nil {
+				name := *contact.GetGivenName()
 
-		return nil, errors.Wrap(
-			err,
-			"uploading Contact during RestoreExchangeContact: "+name+" "+
-				support.ConnectorStackErrorTrace(err),
-		)
-	}
+				return errors.Wrap(
+					err,
+					"uploading Contact during RestoreExchangeContact: "+name+" "+
+						support.ConnectorStackErrorTrace(err),
+				)
+			}
 
-	if response == nil {
-		return nil, errors.New("msgraph contact post fail: REST response not received")
+			if response == nil {
+				return errors.New("msgraph contact post fail: REST response not received")
+			}
+
+			return nil
+		},
+		func(id string) error { return service.Client().UsersById(user).ContactsById(id).Delete(ctx, nil) })
+	if err != nil {
+		return nil, err
 	}
 
 	return ContactInfo(contact, int64(len(bits))), nil
@@ -109,7 +119,7 @@ func RestoreExchangeEvent(
 
 	var (
 		attached []models.Attachmentable
-		errs     error
+		created  bool
 	)
 
 	if *event.GetHasAttachments() {
@@ -118,42 +128,64 @@ func RestoreExchangeEvent(
 		transformedEvent.SetAttachments([]models.Attachmentable{})
 	}
 
-	response, err := service.Client().UsersById(user).CalendarsById(destination).Events().Post(ctx, transformedEvent, nil)
-	if err != nil {
-		return nil, errors.Wrap(err,
-			fmt.Sprintf(
-				"uploading event during RestoreExchangeEvent: %s",
-				support.ConnectorStackErrorTrace(err)),
-		)
-	}
+	err = restoreWithCollision(
+		ctx,
+		cp,
+		func() (string, error) { return existingEventID(ctx, service, user, destination, event) },
+		func() error {
+			response, err := service.Client().
+				UsersById(user).
+				CalendarsById(destination).
+				Events().
+				Post(ctx, transformedEvent, nil)
+			if err != nil {
+				return errors.Wrap(err,
+					fmt.Sprintf(
+						"uploading event during RestoreExchangeEvent: %s",
+						support.ConnectorStackErrorTrace(err)),
+				)
+			}
 
-	if response == nil {
-		return nil, errors.New("msgraph event post fail: REST response not received")
-	}
+			if response == nil {
+				return errors.New("msgraph event post fail: REST response not received")
+			}
 
-	uploader := &eventAttachmentUploader{
-		calendarID: destination,
-		userID:     user,
-		service:    service,
-		itemID:     *response.GetId(),
-	}
+			created = true
 
-	for _, attach := range attached {
-		if err := uploadAttachment(ctx, uploader, attach); err != nil {
-			errs = support.WrapAndAppend(
-				fmt.Sprintf(
-					"uploading attachment for message %s: %s",
-					*transformedEvent.GetId(), support.ConnectorStackErrorTrace(err),
-				),
-				err,
-				errs,
-			)
+			uploader := &eventAttachmentUploader{
+				calendarID: destination,
+				userID:     user,
+				service:    service,
+				itemID:     *response.GetId(),
+			}
 
-			break
-		}
+			var errs error
+
+			for _, attach := range attached {
+				if err := uploadAttachment(ctx, uploader, attach); err != nil {
+					errs = support.WrapAndAppend(
+						fmt.Sprintf(
+							"uploading attachment for message %s: %s",
+							*transformedEvent.GetId(), support.ConnectorStackErrorTrace(err),
+						),
+						err,
+						errs,
+					)
+
+					break
+				}
+			}
+
+			return errs
+		},
+		func(id string) error { return service.Client().UsersById(user).EventsById(id).Delete(ctx, nil) })
+	// An event whose attachments failed to upload is still restored, but it
+	// doesn't replace the existing event.
+	if err != nil && !created {
+		return nil, err
 	}
 
-	return EventInfo(event, int64(len(bits))), errs
+	return EventInfo(event, int64(len(bits))), err
 }
 
 // RestoreMailMessage utility function to place an exchange.Mail
@@ -202,20 +234,204 @@ func RestoreMailMessage(
 	svlep := []models.SingleValueLegacyExtendedPropertyable{sv1, sv2, sv3}
 	clone.SetSingleValueExtendedProperties(svlep)
 
-	// Switch workflow based on collision policy
-	switch cp {
-	default:
-		logger.Ctx(ctx).DPanicw("restoreMailMessage received unrecognized restore policy; defaulting to copy",
-			"policy", cp)
-		fallthrough
-	case control.Copy:
-		err := SendMailToBackStore(ctx, service, user, destination, clone)
+	err = restoreWithCollision(
+		ctx,
+		cp,
+		func() (string, error) { return existingMessageID(ctx, service, user, destination, originalMessage) },
+		func() error { return SendMailToBackStore(ctx, service, user, destination, clone) },
+		func(id string) error { return service.Client().UsersById(user).MessagesById(id).Delete(ctx, nil) })
+	if err != nil {
+		return nil, err
+	}
+
+	return MessageInfo(clone, int64(len(bits))), nil
+}
+
+// restoreWithCollision restores an item with create, applying the collision
+// policy.  Under the Skip and Replace policies, find looks up any item in the
+// destination that matches the item being restored.  If a match is found,
+// Skip returns graph.ErrItemAlreadyExists without creating the item, and
+// Replace removes the existing item once the restored item has been created,
+// so that a failed create never loses the existing copy.  Copy (the default)
+// always restores the item alongside any existing copies.
+func restoreWithCollision(
+	ctx context.Context,
+	cp control.CollisionPolicy,
+	find func() (string, error),
+	create func() error,
+	remove func(id string) error,
+) error {
+	var id string
+
+	if cp == control.Skip || cp == control.Replace {
+		var err error
+
+		id, err = find()
 		if err != nil {
-			return nil, err
+			return errors.Wrap(err, "looking up existing item: "+support.ConnectorStackErrorTrace(err))
+		}
+
+		if len(id) > 0 && cp == control.Skip {
+			return errors.WithStack(graph.ErrItemAlreadyExists)
 		}
 	}
 
-	return MessageInfo(clone, int64(len(bits))), nil
+	if err := create(); err != nil {
+		return err
+	}
+
+	if len(id) == 0 {
+		return nil
+	}
+
+	logger.Ctx(ctx).Debugw("replacing existing item", "item_id", id)
+
+	if err := remove(id); err != nil {
+		return errors.Wrap(err, "removing replaced item: "+support.ConnectorStackErrorTrace(err))
+	}
+
+	return nil
+}
+
+// existingMessageID returns the ID of the first message in the destination
+// folder that shares the message's internetMessageId.  Returns an empty
+// string if the message has no internetMessageId, or if no match exists.
+func existingMessageID(
+	ctx context.Context,
+	service graph.Servicer,
+	user, destination string,
+	msg models.Messageable,
+) (string, error) {
+	imid := msg.GetInternetMessageId()
+	if imid == nil || len(*imid) == 0 {
+		return "", nil
+	}
+
+	filter := odataEqualsFilter("internetMessageId", *imid)
+	config := &users.ItemMailFoldersItemMessagesRequestBuilderGetRequestConfiguration{
+		QueryParameters: &users.ItemMailFoldersItemMessagesRequestBuilderGetQueryParameters{
+			Filter: &filter,
+			Select: []string{"id"},
+		},
+	}
+
+	resp, err := service.Client().UsersById(user).MailFoldersById(destination).Messages().Get(ctx, config)
+	if err != nil {
+		return "", err
+	}
+
+	for _, m := range resp.GetValue() {
+		if m.GetId() != nil {
+			return *m.GetId(), nil
+		}
+	}
+
+	return "", nil
+}
+
+// existingEventID returns the ID of the first event in the destination
+// calendar that shares the event's iCalUId.  Returns an empty string if the
+// event has no iCalUId, or if no match exists.
+func existingEventID(
+	ctx context.Context,
+	service graph.Servicer,
+	user, destination string,
+	event models.Eventable,
+) (string, error) {
+	uid := event.GetICalUId()
+	if uid == nil || len(*uid) == 0 {
+		return "", nil
+	}
+
+	filter := odataEqualsFilter("iCalUId", *uid)
+	config := &users.ItemCalendarsItemEventsRequestBuilderGetRequestConfiguration{
+		QueryParameters: &users.ItemCalendarsItemEventsRequestBuilderGetQueryParameters{
+			Filter: &filter,
+			Select: []string{"id"},
+		},
+	}
+
+	resp, err := service.Client().UsersById(user).CalendarsById(destination).Events().Get(ctx, config)
+	if err != nil {
+		return "", err
+	}
+
+	for _, e := range resp.GetValue() {
+		if e.GetId() != nil {
+			return *e.GetId(), nil
+		}
+	}
+
+	return "", nil
+}
+
+// existingContactID returns the ID of the first contact in the destination
+// folder that shares both the contact's display name and its primary email
+// address.  Contacts carry no stable identifier across mailboxes, and a name
+// alone is too weak a match to replace another contact.  Returns an empty
+// string if the contact lacks either value, or if no match exists.
+func existingContactID(
+	ctx context.Context,
+	service graph.Servicer,
+	user, destination string,
+	contact models.Contactable,
+) (string, error) {
+	name := contact.GetDisplayName()
+	if name == nil || len(*name) == 0 || len(primaryEmail(contact)) == 0 {
+		return "", nil
+	}
+
+	filter := odataEqualsFilter("displayName", *name)
+	config := &users.ItemContactFoldersItemContactsRequestBuilderGetRequestConfiguration{
+		QueryParameters: &users.ItemContactFoldersItemContactsRequestBuilderGetQueryParameters{
+			Filter: &filter,
+			Select: []string{"id", "emailAddresses"},
+		},
+	}
+
+	resp, err := service.Client().UsersById(user).ContactFoldersById(destination).Contacts().Get(ctx, config)
+	if err != nil {
+		return "", err
+	}
+
+	return matchingContactID(contact, resp.GetValue()), nil
+}
+
+// matchingContactID returns the ID of the first candidate whose primary
+// email address matches the contact's, ignoring case.  Returns an empty
+// string if no candidate matches.
+func matchingContactID(contact models.Contactable, candidates []models.Contactable) string {
+	email := primaryEmail(contact)
+	if len(email) == 0 {
+		return ""
+	}
+
+	for _, c := range candidates {
+		if c.GetId() != nil && strings.EqualFold(email, primaryEmail(c)) {
+			return *c.GetId()
+		}
+	}
+
+	return ""
+}
+
+// primaryEmail returns the first email address of the contact, or an empty
+// string if it has none.
+func primaryEmail(contact models.Contactable) string {
+	for _, ea := range contact.GetEmailAddresses() {
+		if ea != nil && ea.GetAddress() != nil && len(*ea.GetAddress()) > 0 {
+			return *ea.GetAddress()
+		}
+	}
+
+	return ""
+}
+
+// odataEqualsFilter produces an OData `$filter` expression matching the
+// property against the value.  Single quotes within the value are escaped
+// by doubling them.
+func odataEqualsFilter(property, value string) string {
+	return fmt.Sprintf("%s eq '%s'", property, strings.ReplaceAll(value, "'", "''"))
 }
 
 // attachmentBytes is a helper to retrieve the attachment content from a models.Attachmentable
@@ -288,6 +504,7 @@ func RestoreExchangeDataCollections(
 	creds account.M365Config,
 	gs graph.Servicer,
 	dest control.RestoreDestination,
+	opts control.Options,
 	dcs []data.Collection,
 	deets *details.Builder,
 ) (*support.ConnectorOperationStatus, error) {
@@ -296,8 +513,7 @@ func RestoreExchangeDataCollections(
 		directoryCaches = make(map[string]map[path.CategoryType]graph.ContainerResolver)
 		metrics         support.CollectionMetrics
 		errs            error
-		// TODO policy to be updated from external source after completion of refactoring
-		policy = control.Copy
+		policy          = opts.Collision
 	)
 
 	errUpdater := func(id string, err error) {
@@ -305,7 +521,7 @@ func RestoreExchangeDataCollections(
 	}
 
 	for _, dc := range dcs {
-		userID := dc.FullPath().ResourceOwner()
+		userID := dest.RestoreOwner(dc.FullPath().ResourceOwner())
 
 		userCaches := directoryCaches[userID]
 		if userCaches == nil {
@@ -316,6 +532,7 @@ func RestoreExchangeDataCollections(
 		containerID, err := CreateContainerDestinaion(
 			ctx,
 			creds,
+			userID,
 			dc.FullPath(),
 			dest.ContainerName,
 			userCaches)
@@ -324,7 +541,7 @@ func RestoreExchangeDataCollections(
 			continue
 		}
 
-		temp, canceled := restoreCollection(ctx, gs, dc, containerID, policy, deets, errUpdater)
+		temp, canceled := restoreCollection(ctx, gs, dc, userID, containerID, policy, deets, errUpdater)
 
 		metrics.Combine(temp)
 
@@ -343,12 +560,13 @@ func RestoreExchangeDataCollections(
 	return status, errs
 }
 
-// restoreCollection handles restoration of an individual collection.
+// restoreCollection handles restoration of an individual collection into
+// the user's folder.
 func restoreCollection(
 	ctx context.Context,
 	gs graph.Servicer,
 	dc data.Collection,
-	folderID string,
+	user, folderID string,
 	policy control.CollisionPolicy,
 	deets *details.Builder,
 	errUpdater func(string, error),
@@ -362,7 +580,6 @@ func restoreCollection(
 		directory = dc.FullPath()
 		service   = directory.Service()
 		category  = directory.Category()
-		user      = directory.ResourceOwner()
 	)
 
 	colProgress, closer := observe.CollectionProgress(ctx, user, category.String(), directory.Folder())
@@ -394,6 +611,13 @@ func restoreCollection(
 			byteArray := buf.Bytes()
 
 			info, err := RestoreExchangeObject(ctx, byteArray, category, policy, gs, folderID, user)
+			if errors.Is(err, graph.ErrItemAlreadyExists) {
+				metrics.Skipped++
+				colProgress <- struct{}{}
+
+				continue
+			}
+
 			if err != nil {
 				//  More information to be here
 				errUpdater(
@@ -427,25 +651,31 @@ func restoreCollection(
 }
 
 // CreateContainerDestinaion builds the destination into the container
-// at the provided path.  As a precondition, the destination cannot
-// already exist.  If it does then an error is returned.  The provided
-// containerResolver is updated with the new destination.
-// @ returns the container ID of the new destination container.
+// at the provided path within the user's mailbox.  If the destination is
+// empty, the container's original path is used instead.  Any containers
+// along the path that do not already exist are created.  The provided
+// containerResolver is updated with the new containers.
+// @ returns the container ID of the destination container.
 func CreateContainerDestinaion(
 	ctx context.Context,
 	creds account.M365Config,
+	user string,
 	directory path.Path,
 	destination string,
 	caches map[path.CategoryType]graph.ContainerResolver,
 ) (string, error) {
 	var (
 		newCache       = false
-		user           = directory.ResourceOwner()
 		category       = directory.Category()
 		directoryCache = caches[category]
-		newPathFolders = append([]string{destination}, directory.Folders()...)
+		newPathFolders = directory.Folders()
 	)
 
+	// an empty destination restores items into their original location.
+	if len(destination) > 0 {
+		newPathFolders = append([]string{destination}, newPathFolders...)
+	}
+
 	// TODO(rkeepers): pass the api client into this func, rather than generating one.
 	ac, err := api.NewClient(creds)
 	if err != nil {
@@ -521,7 +751,8 @@ func CreateContainerDestinaion(
 }
 
 // establishMailRestoreLocation creates Mail folders in sequence
-// [root leaf1 leaf2] in a similar to a linked list.
+// [root leaf1 leaf2] in a similar to a linked list.  Folders which
+// already exist are re-used.
 // @param folders is the desired path from the root to the container
 // that the items will be restored into
 // @param isNewCache identifies if the cache is created and not populated
@@ -533,6 +764,12 @@ func establishMailRestoreLocation(
 	user string,
 	isNewCache bool,
 ) (string, error) {
+	if isNewCache {
+		if err := mfc.Populate(ctx, rootFolderAlias); err != nil {
+			return "", errors.Wrap(err, "populating folder cache")
+		}
+	}
+
 	// Process starts with the root folder in order to recreate
 	// the top-level folder with the same tactic
 	folderID := rootFolderAlias
@@ -555,17 +792,6 @@ func establishMailRestoreLocation(
 
 		folderID = *temp.GetId()
 
-		// Only populate the cache if we actually had to create it. Since we set
-		// newCache to false in this we'll only try to populate it once per function
-		// call even if we make a new cache.
-		if isNewCache {
-			if err := mfc.Populate(ctx, rootFolderAlias); err != nil {
-				return "", errors.Wrap(err, "populating folder cache")
-			}
-
-			isNewCache = false
-		}
-
 		// NOOP if the folder is already in the cache.
 		if err = mfc.AddToCache(ctx, temp); err != nil {
 			return "", errors.Wrap(err, "adding folder to cache")
@@ -578,7 +804,8 @@ func establishMailRestoreLocation(
 // establishContactsRestoreLocation creates Contact Folders in sequence
 // and updates the container resolver appropriately. Contact Folders are
 // displayed in a flat representation. Therefore, only the root can be populated and all content
-// must be restored into the root location.
+// must be restored into the root location.  Contacts restored into the
+// default contact folder are placed within it, rather than in a new folder.
 // @param folders is the list of intended folders from root to leaf (e.g. [root ...])
 // @param isNewCache bool representation of whether Populate function needs to be run
 func establishContactsRestoreLocation(
@@ -589,6 +816,21 @@ func establishContactsRestoreLocation(
 	user string,
 	isNewCache bool,
 ) (string, error) {
+	if isNewCache {
+		if err := cfc.Populate(ctx, DefaultContactFolder); err != nil {
+			return "", errors.Wrap(err, "populating contact cache")
+		}
+	}
+
+	if folders[0] == DefaultContactFolder {
+		f, err := ac.Contacts().GetContainerByID(ctx, user, DefaultContactFolder)
+		if err != nil {
+			return "", errors.Wrap(err, "fetching default contact folder: "+support.ConnectorStackErrorTrace(err))
+		}
+
+		return *f.GetId(), nil
+	}
+
 	cached, ok := cfc.PathInCache(folders[0])
 	if ok {
 		return cached, nil
@@ -599,21 +841,18 @@ func establishContactsRestoreLocation(
 		return "", errors.Wrap(err, support.ConnectorStackErrorTrace(err))
 	}
 
-	folderID := *temp.GetId()
-
-	if isNewCache {
-		if err := cfc.Populate(ctx, folderID, folders[0]); err != nil {
-			return "", errors.Wrap(err, "populating contact cache")
-		}
-
-		if err = cfc.AddToCache(ctx, temp); err != nil {
-			return "", errors.Wrap(err, "adding contact folder to cache")
-		}
+	if err = cfc.AddToCache(ctx, temp); err != nil {
+		return "", errors.Wrap(err, "adding contact folder to cache")
 	}
 
-	return folderID, nil
+	return *temp.GetId(), nil
 }
 
+// establishEventsRestoreLocation retrieves the calendar with the given name,
+// creating it if it does not already exist.  Calendars are flat, so only the
+// first of the folders is used.
+// @param folders is the list of intended folders from root to leaf (e.g. [root ...])
+// @param isNewCache bool representation of whether Populate function needs to be run
 func establishEventsRestoreLocation(
 	ctx context.Context,
 	ac api.Client,
@@ -622,6 +861,12 @@ func establishEventsRestoreLocation(
 	user string,
 	isNewCache bool,
 ) (string, error) {
+	if isNewCache {
+		if err := ecc.Populate(ctx, ""); err != nil {
+			return "", errors.Wrap(err, "populating event cache")
+		}
+	}
+
 	cached, ok := ecc.PathInCache(folders[0])
 	if ok {
 		return cached, nil
@@ -632,18 +877,10 @@ func establishEventsRestoreLocation(
 		return "", errors.Wrap(err, support.ConnectorStackErrorTrace(err))
 	}
 
-	folderID := *temp.GetId()
-
-	if isNewCache {
-		if err = ecc.Populate(ctx, folderID, folders[0]); err != nil {
-			return "", errors.Wrap(err, "populating event cache")
-		}
-
-		displayable := api.CalendarDisplayable{Calendarable: temp}
-		if err = ecc.AddToCache(ctx, displayable); err != nil {
-			return "", errors.Wrap(err, "adding new calendar to cache")
-		}
+	displayable := api.CalendarDisplayable{Calendarable: temp}
+	if err = ecc.AddToCache(ctx, displayable); err != nil {
+		return "", errors.Wrap(err, "adding new calendar to cache")
 	}
 
-	return folderID, nil
+	return *temp.GetId(), nil
 }
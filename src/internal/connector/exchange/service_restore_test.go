@@ -0,0 +1,202 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/connector/graph"
+	"github.com/alcionai/corso/src/internal/tester"
+	"github.com/alcionai/corso/src/pkg/control"
+)
+
+type ServiceRestoreUnitSuite struct {
+	suite.Suite
+}
+
+func TestServiceRestoreUnitSuite(t *testing.T) {
+	suite.Run(t, new(ServiceRestoreUnitSuite))
+}
+
+func (suite *ServiceRestoreUnitSuite) TestOdataEqualsFilter() {
+	table := []struct {
+		name   string
+		value  string
+		expect string
+	}{
+		{"plain", "<abc@example.com>", "internetMessageId eq '<abc@example.com>'"},
+		{"quoted", "o'brien's", "internetMessageId eq 'o''brien''s'"},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expect, odataEqualsFilter("internetMessageId", test.value))
+		})
+	}
+}
+
+func contactWith(id, email string) models.Contactable {
+	c := models.NewContact()
+	c.SetId(&id)
+
+	if len(email) > 0 {
+		ea := models.NewEmailAddress()
+		ea.SetAddress(&email)
+		c.SetEmailAddresses([]models.EmailAddressable{ea})
+	}
+
+	return c
+}
+
+func (suite *ServiceRestoreUnitSuite) TestMatchingContactID() {
+	candidates := []models.Contactable{
+		contactWith("no-email", ""),
+		contactWith("other", "other@example.com"),
+		contactWith("match", "Jane@Example.com"),
+	}
+
+	table := []struct {
+		name       string
+		contact    models.Contactable
+		candidates []models.Contactable
+		expect     string
+	}{
+		{
+			name:       "matching email",
+			contact:    contactWith("", "jane@example.com"),
+			candidates: candidates,
+			expect:     "match",
+		},
+		{
+			name:       "same name, different email",
+			contact:    contactWith("", "john@example.com"),
+			candidates: candidates,
+		},
+		{
+			name:       "no email",
+			contact:    contactWith("", ""),
+			candidates: candidates,
+		},
+		{
+			name:    "no candidates",
+			contact: contactWith("", "jane@example.com"),
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expect, matchingContactID(test.contact, test.candidates))
+		})
+	}
+}
+
+func (suite *ServiceRestoreUnitSuite) TestRestoreWithCollision() {
+	table := []struct {
+		name         string
+		policy       control.CollisionPolicy
+		existingID   string
+		findErr      error
+		createErr    error
+		expectFind   bool
+		expectCreate bool
+		expectRemove bool
+		expectErr    assert.ErrorAssertionFunc
+		expectSkip   bool
+	}{
+		{
+			name:         "copy",
+			policy:       control.Copy,
+			existingID:   "id",
+			expectCreate: true,
+			expectErr:    assert.NoError,
+		},
+		{
+			name:         "unknown",
+			policy:       control.Unknown,
+			existingID:   "id",
+			expectCreate: true,
+			expectErr:    assert.NoError,
+		},
+		{
+			name:         "skip, no collision",
+			policy:       control.Skip,
+			expectFind:   true,
+			expectCreate: true,
+			expectErr:    assert.NoError,
+		},
+		{
+			name:       "skip, collision",
+			policy:     control.Skip,
+			existingID: "id",
+			expectFind: true,
+			expectErr:  assert.Error,
+			expectSkip: true,
+		},
+		{
+			name:         "replace, no collision",
+			policy:       control.Replace,
+			expectFind:   true,
+			expectCreate: true,
+			expectErr:    assert.NoError,
+		},
+		{
+			name:         "replace, collision",
+			policy:       control.Replace,
+			existingID:   "id",
+			expectFind:   true,
+			expectCreate: true,
+			expectRemove: true,
+			expectErr:    assert.NoError,
+		},
+		{
+			name:         "replace, create failure",
+			policy:       control.Replace,
+			existingID:   "id",
+			createErr:    assert.AnError,
+			expectFind:   true,
+			expectCreate: true,
+			expectErr:    assert.Error,
+		},
+		{
+			name:       "lookup failure",
+			policy:     control.Skip,
+			findErr:    assert.AnError,
+			expectFind: true,
+			expectErr:  assert.Error,
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			ctx, flush := tester.NewContext()
+			defer flush()
+
+			var found, created, removed bool
+
+			err := restoreWithCollision(
+				ctx,
+				test.policy,
+				func() (string, error) {
+					found = true
+					return test.existingID, test.findErr
+				},
+				func() error {
+					created = true
+					return test.createErr
+				},
+				func(id string) error {
+					assert.True(t, created, "removed before creating the restored item")
+
+					removed = true
+					assert.Equal(t, test.existingID, id)
+
+					return nil
+				})
+			test.expectErr(t, err)
+			assert.Equal(t, test.expectSkip, errors.Is(err, graph.ErrItemAlreadyExists), "skipped")
+			assert.Equal(t, test.expectFind, found, "looked up existing item")
+			assert.Equal(t, test.expectCreate, created, "created restored item")
+			assert.Equal(t, test.expectRemove, removed, "removed existing item")
+		})
+	}
+}
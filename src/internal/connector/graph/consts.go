@@ -24,4 +24,19 @@ const (
 	// PreviousPathFileName is the name of the file containing previous path(s) for a
 	// given endpoint.
 	PreviousPathFileName = "previouspath"
+
+	// ItemParentsFileName is the name of the file containing the ID of the
+	// parent container of each item, for endpoints whose delta queries don't
+	// report the container that an item moved out of.
+	ItemParentsFileName = "itemparents"
+
+	// ItemKeysFileName is the name of the file containing the format of the
+	// keys that items were stored under, for endpoints whose item keys changed
+	// between releases.
+	ItemKeysFileName = "itemkeys"
+
+	// VersionsFileName is the name of the file containing whether the prior
+	// versions of items were backed up, for endpoints that keep version
+	// history.
+	VersionsFileName = "versions"
 )
@@ -18,10 +18,33 @@ const (
 	errCodeItemNotFound        = "ErrorItemNotFound"
 	errCodeEmailFolderNotFound = "ErrorSyncFolderNotFound"
 	errCodeResyncRequired      = "ResyncRequired"
+	// drive delta queries use a lowercase variant of the code.
+	errCodeDriveResyncRequired = "resyncRequired"
 	errCodeSyncFolderNotFound  = "ErrorSyncFolderNotFound"
 	errCodeSyncStateNotFound   = "SyncStateNotFound"
+	errCodeNameAlreadyExists   = "nameAlreadyExists"
 )
 
+// ErrItemAlreadyExists is returned when restoring an item that collides with
+// an existing item in the destination, and the collision policy calls for the
+// item to be skipped.
+var ErrItemAlreadyExists = errors.New("item already exists")
+
+// IsErrItemAlreadyExists returns an error wrapping ErrItemAlreadyExists if
+// graph refused to create an item because its name is already in use within
+// the parent folder.
+func IsErrItemAlreadyExists(err error) error {
+	if errors.Is(err, ErrItemAlreadyExists) {
+		return err
+	}
+
+	if hasErrorCode(err, errCodeNameAlreadyExists) {
+		return errors.Wrap(ErrItemAlreadyExists, err.Error())
+	}
+
+	return nil
+}
+
 // The folder or item was deleted between the time we identified
 // it and when we tried to fetch data for it.
 type ErrDeletedInFlight struct {
@@ -57,7 +80,7 @@ func IsErrInvalidDelta(err error) error {
 		return err
 	}
 
-	if hasErrorCode(err, errCodeSyncStateNotFound, errCodeResyncRequired) {
+	if hasErrorCode(err, errCodeSyncStateNotFound, errCodeResyncRequired, errCodeDriveResyncRequired) {
 		return ErrInvalidDelta{*common.EncapsulateError(err)}
 	}
 
@@ -0,0 +1,49 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/microsoftgraph/msgraph-sdk-go/models/odataerrors"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type GraphErrorsUnitSuite struct {
+	suite.Suite
+}
+
+func TestGraphErrorsUnitSuite(t *testing.T) {
+	suite.Run(t, new(GraphErrorsUnitSuite))
+}
+
+func odataErr(code string) *odataerrors.ODataError {
+	me := odataerrors.NewMainError()
+	me.SetCode(&code)
+
+	odErr := odataerrors.NewODataError()
+	odErr.SetError(me)
+
+	return odErr
+}
+
+func (suite *GraphErrorsUnitSuite) TestIsErrItemAlreadyExists() {
+	table := []struct {
+		name   string
+		err    error
+		expect bool
+	}{
+		{"nil", nil, false},
+		{"non-matching", assert.AnError, false},
+		{"name already exists", odataErr(errCodeNameAlreadyExists), true},
+		{"wrapped", errors.Wrap(odataErr(errCodeNameAlreadyExists), "creating item"), true},
+		{"other code", odataErr(errCodeItemNotFound), false},
+		{"already exists", errors.WithStack(ErrItemAlreadyExists), true},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			err := IsErrItemAlreadyExists(test.err)
+			assert.Equal(t, test.expect, errors.Is(err, ErrItemAlreadyExists))
+		})
+	}
+}
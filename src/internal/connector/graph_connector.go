@@ -255,6 +255,7 @@ func (gc *GraphConnector) RestoreDataCollections(
 	acct account.Account,
 	selector selectors.Selector,
 	dest control.RestoreDestination,
+	opts control.Options,
 	dcs []data.Collection,
 ) (*details.Details, error) {
 	ctx, end := D.Span(ctx, "connector:restore")
@@ -271,13 +272,20 @@ func (gc *GraphConnector) RestoreDataCollections(
 		return nil, errors.Wrap(err, "malformed azure credentials")
 	}
 
+	// restorers address the override by ID, so owners given by name are
+	// swapped for the ID they match.
+	dest.ResourceOwnerOverride, err = gc.resolveRestoreOwner(selector, dest.ResourceOwnerOverride)
+	if err != nil {
+		return nil, err
+	}
+
 	switch selector.Service {
 	case selectors.ServiceExchange:
-		status, err = exchange.RestoreExchangeDataCollections(ctx, creds, gc.Service, dest, dcs, deets)
+		status, err = exchange.RestoreExchangeDataCollections(ctx, creds, gc.Service, dest, opts, dcs, deets)
 	case selectors.ServiceOneDrive:
-		status, err = onedrive.RestoreCollections(ctx, gc.Service, dest, dcs, deets)
+		status, err = onedrive.RestoreCollections(ctx, gc.Service, dest, opts, dcs, deets)
 	case selectors.ServiceSharePoint:
-		status, err = sharepoint.RestoreCollections(ctx, gc.Service, dest, dcs, deets)
+		status, err = sharepoint.RestoreCollections(ctx, gc.Service, dest, opts, dcs, deets)
 	default:
 		err = errors.Errorf("restore data from service %s not supported", selector.Service.String())
 	}
@@ -288,6 +296,34 @@ func (gc *GraphConnector) RestoreDataCollections(
 	return deets.Details(), err
 }
 
+// resolveRestoreOwner ensures that the resource owner override, if one is
+// provided, identifies a user (or, for sharepoint, a site) in the tenant, and
+// returns the ID of that owner.  Owners may be identified by either ID or by
+// name (user principal name or site webURL).  The override is returned as-is
+// if the connector was not populated with the tenant's resource owners.
+func (gc *GraphConnector) resolveRestoreOwner(sel selectors.Selector, owner string) (string, error) {
+	if len(owner) == 0 {
+		return "", nil
+	}
+
+	owners, kind := gc.Users, "user"
+	if sel.Service == selectors.ServiceSharePoint {
+		owners, kind = gc.Sites, "site"
+	}
+
+	if len(owners) == 0 {
+		return owner, nil
+	}
+
+	for name, id := range owners {
+		if owner == name || owner == id {
+			return id, nil
+		}
+	}
+
+	return "", errors.Errorf("restore destination %s %q not found in tenant", kind, owner)
+}
+
 // AwaitStatus waits for all gc tasks to complete and then returns status
 func (gc *GraphConnector) AwaitStatus() *support.ConnectorOperationStatus {
 	defer func() {
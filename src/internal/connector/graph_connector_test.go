@@ -128,6 +128,42 @@ func (suite *GraphConnectorUnitSuite) TestUnionSiteIDsAndWebURLs() {
 	}
 }
 
+func (suite *GraphConnectorUnitSuite) TestResolveRestoreOwner() {
+	var (
+		gc = &GraphConnector{
+			Users: map[string]string{"user@example.com": "user-id"},
+			Sites: map[string]string{"www.foo.com/bar": "site-id"},
+		}
+		exchangeSel   = selectors.Selector{Service: selectors.ServiceExchange}
+		oneDriveSel   = selectors.Selector{Service: selectors.ServiceOneDrive}
+		sharePointSel = selectors.Selector{Service: selectors.ServiceSharePoint}
+	)
+
+	table := []struct {
+		name        string
+		sel         selectors.Selector
+		owner       string
+		expectOwner string
+		expectErr   assert.ErrorAssertionFunc
+	}{
+		{"no override", exchangeSel, "", "", assert.NoError},
+		{"user by name", exchangeSel, "user@example.com", "user-id", assert.NoError},
+		{"user by id", oneDriveSel, "user-id", "user-id", assert.NoError},
+		{"unknown user", exchangeSel, "nobody@example.com", "", assert.Error},
+		{"site as user", oneDriveSel, "site-id", "", assert.Error},
+		{"site by url", sharePointSel, "www.foo.com/bar", "site-id", assert.NoError},
+		{"site by id", sharePointSel, "site-id", "site-id", assert.NoError},
+		{"user as site", sharePointSel, "user-id", "", assert.Error},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			owner, err := gc.resolveRestoreOwner(test.sel, test.owner)
+			test.expectErr(t, err)
+			assert.Equal(t, test.expectOwner, owner)
+		})
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Integration tests
 // ---------------------------------------------------------------------------
@@ -225,7 +261,7 @@ func (suite *GraphConnectorIntegrationSuite) TestRestoreFailsBadService() {
 		}
 	)
 
-	deets, err := suite.connector.RestoreDataCollections(ctx, acct, sel, dest, nil)
+	deets, err := suite.connector.RestoreDataCollections(ctx, acct, sel, dest, control.Defaults(), nil)
 	assert.Error(t, err)
 	assert.NotNil(t, deets)
 
@@ -296,6 +332,7 @@ func (suite *GraphConnectorIntegrationSuite) TestEmptyCollections() {
 				suite.acct,
 				test.sel,
 				dest,
+				control.Defaults(),
 				test.col)
 			require.NoError(t, err)
 			assert.NotNil(t, deets)
@@ -386,6 +423,7 @@ func runRestoreBackupTest(
 		acct,
 		restoreSel,
 		dest,
+		control.Defaults(),
 		collections)
 	require.NoError(t, err)
 	assert.NotNil(t, deets)
@@ -870,7 +908,13 @@ func (suite *GraphConnectorIntegrationSuite) TestMultiFolderBackupDifferentNames
 				)
 
 				restoreGC := loadConnector(ctx, t, test.resource)
-				deets, err := restoreGC.RestoreDataCollections(ctx, suite.acct, restoreSel, dest, collections)
+				deets, err := restoreGC.RestoreDataCollections(
+					ctx,
+					suite.acct,
+					restoreSel,
+					dest,
+					control.Defaults(),
+					collections)
 				require.NoError(t, err)
 				require.NotNil(t, deets)
 
@@ -2,13 +2,16 @@
 package onedrive
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"io"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/pkg/errors"
 	"github.com/spatialcurrent/go-lazy/pkg/lazy"
 
 	"github.com/alcionai/corso/src/internal/connector/graph"
@@ -46,19 +49,35 @@ type Collection struct {
 	// data is used to share data streams with the collection consumer
 	data chan data.Stream
 	// folderPath indicates what level in the hierarchy this collection
-	// represents
+	// represents.  Nil if the folder was deleted.
 	folderPath path.Path
+	// prevPath is the folder's path in the previous backup, if it was
+	// included in that backup.
+	prevPath path.Path
 	// M365 IDs of file items within this collection
 	driveItems map[string]models.DriveItemable
+	// M365 IDs of file items removed from the folder since the previous
+	// backup, either deleted or moved to another folder.
+	removedItems map[string]struct{}
+	// the drive item of the folder, if it was enumerated by this backup.  Its
+	// permissions are backed up in the folder's metadata sidecar.
+	folder models.DriveItemable
 	// M365 ID of the drive this collection was created from
 	driveID       string
 	source        driveSource
 	service       graph.Servicer
 	statusUpdater support.StatusUpdater
 	itemReader    itemReaderFunc
-	ctrl          control.Options
-
-	// should only be true if the old delta token expired
+	permsReader   permissionsReaderFunc
+	// lists and reads prior versions of items, if version history is
+	// backed up.
+	versionsReader versionsReaderFunc
+	versionReader  versionReaderFunc
+	ctrl           control.Options
+	state          data.CollectionState
+
+	// true if the items in the previous backup of the folder must not be
+	// merged into this collection, as the collection holds all of them.
 	doNotMergeItems bool
 }
 
@@ -68,24 +87,34 @@ type itemReaderFunc func(
 	item models.DriveItemable,
 ) (itemInfo details.ItemInfo, itemData io.ReadCloser, err error)
 
-// NewCollection creates a Collection
+// NewCollection creates a Collection.  A nil folderPath marks the folder at
+// prevPath as deleted, and a nil prevPath marks the folder as new.
 func NewCollection(
 	folderPath path.Path,
+	prevPath path.Path,
 	driveID string,
 	service graph.Servicer,
 	statusUpdater support.StatusUpdater,
 	source driveSource,
 	ctrlOpts control.Options,
+	doNotMergeItems bool,
 ) *Collection {
 	c := &Collection{
-		folderPath:    folderPath,
-		driveItems:    map[string]models.DriveItemable{},
-		driveID:       driveID,
-		source:        source,
-		service:       service,
-		data:          make(chan data.Stream, collectionChannelBufferSize),
-		statusUpdater: statusUpdater,
-		ctrl:          ctrlOpts,
+		folderPath:      folderPath,
+		prevPath:        prevPath,
+		driveItems:      map[string]models.DriveItemable{},
+		removedItems:    map[string]struct{}{},
+		driveID:         driveID,
+		source:          source,
+		service:         service,
+		data:            make(chan data.Stream, collectionChannelBufferSize),
+		statusUpdater:   statusUpdater,
+		permsReader:     driveItemPermissions,
+		versionsReader:  driveItemVersions,
+		versionReader:   driveItemReader,
+		ctrl:            ctrlOpts,
+		state:           stateOf(prevPath, folderPath),
+		doNotMergeItems: doNotMergeItems,
 	}
 
 	// Allows tests to set a mock populator
@@ -99,12 +128,34 @@ func NewCollection(
 	return c
 }
 
+func stateOf(prev, curr path.Path) data.CollectionState {
+	if curr == nil || len(curr.String()) == 0 {
+		return data.DeletedState
+	}
+
+	if prev == nil || len(prev.String()) == 0 {
+		return data.NewState
+	}
+
+	if curr.String() != prev.String() {
+		return data.MovedState
+	}
+
+	return data.NotMovedState
+}
+
 // Adds an itemID to the collection
 // This will make it eligible to be populated
 func (oc *Collection) Add(item models.DriveItemable) {
 	oc.driveItems[*item.GetId()] = item
 }
 
+// Remove marks the item as removed from the folder, so that it isn't merged
+// into the collection from the previous backup.
+func (oc *Collection) Remove(itemID string) {
+	oc.removedItems[itemID] = struct{}{}
+}
+
 // Items() returns the channel containing M365 Exchange objects
 func (oc *Collection) Items() <-chan data.Stream {
 	go oc.populateItems(context.Background())
@@ -115,16 +166,12 @@ func (oc *Collection) FullPath() path.Path {
 	return oc.folderPath
 }
 
-// TODO(ashmrtn): Fill in with previous path once GraphConnector compares old
-// and new folder hierarchies.
 func (oc Collection) PreviousPath() path.Path {
-	return nil
+	return oc.prevPath
 }
 
-// TODO(ashmrtn): Fill in once GraphConnector compares old and new folder
-// hierarchies.
 func (oc Collection) State() data.CollectionState {
-	return data.NewState
+	return oc.state
 }
 
 func (oc Collection) DoNotMergeItems() bool {
@@ -198,6 +245,20 @@ func (oc *Collection) populateItems(ctx context.Context) {
 		m.Unlock()
 	}
 
+	// removed items and their sidecars are marked deleted, so that they're
+	// dropped from the folder instead of being merged in from the previous
+	// backup.
+	for id := range oc.removedItems {
+		oc.data <- &Item{id: id, deleted: true}
+		oc.data <- &metadataItem{id: id + MetaFileSuffix, deleted: true}
+	}
+
+	if oc.folder != nil {
+		if err := oc.streamFolderMetadata(ctx); err != nil {
+			errUpdater(*oc.folder.GetId(), err)
+		}
+	}
+
 	for _, item := range oc.driveItems {
 		if oc.ctrl.FailFast && errs != nil {
 			break
@@ -236,22 +297,53 @@ func (oc *Collection) populateItems(ctx context.Context) {
 				return
 			}
 
+			perms, err := oc.permsReader(ctx, oc.service, oc.driveID, item)
+			if err != nil {
+				errUpdater(*item.GetId(), err)
+				return
+			}
+
+			var versions []driveVersion
+
+			if oc.ctrl.Versions.Backup && oc.source == OneDriveSource {
+				versions, err = oc.versionsReader(ctx, oc.service, oc.driveID, item)
+				if err != nil {
+					// the item is still backed up, without its prior versions.
+					errUpdater(*item.GetId(), errors.Wrap(err, "backing up prior versions"))
+					versions = nil
+				}
+			}
+
 			var (
-				itemName string
-				itemSize int64
+				itemName    string
+				itemSize    int64
+				hasMetadata = len(perms) > 0
 			)
 
 			switch oc.source {
 			case SharePointSource:
 				itemInfo.SharePoint.ParentPath = parentPathString
+				itemInfo.SharePoint.HasMetadata = hasMetadata
 				itemName = itemInfo.SharePoint.ItemName
 				itemSize = itemInfo.SharePoint.Size
 			default:
 				itemInfo.OneDrive.ParentPath = parentPathString
+				itemInfo.OneDrive.HasMetadata = hasMetadata
+				itemInfo.OneDrive.Versions = versionDetails(versions)
 				itemName = itemInfo.OneDrive.ItemName
 				itemSize = itemInfo.OneDrive.Size
 			}
 
+			var metaData []byte
+
+			if hasMetadata {
+				metaData, err = json.Marshal(Metadata{Permissions: perms})
+				if err != nil {
+					errUpdater(*item.GetId(), errors.Wrap(err, "serializing item metadata"))
+					return
+				}
+			}
+
 			itemReader := lazy.NewLazyReadCloser(func() (io.ReadCloser, error) {
 				progReader, closer := observe.ItemProgress(ctx, itemData, observe.ItemBackupMsg, itemName, itemSize)
 				go closer()
@@ -263,11 +355,37 @@ func (oc *Collection) populateItems(ctx context.Context) {
 			// byteCount iteration
 			atomic.AddInt64(&byteCount, itemSize)
 
+			// items are stored under their graph ID, which stays the same
+			// when the item is renamed, while their name is kept in the info.
+			itemID := *item.GetId()
+
 			oc.data <- &Item{
-				id:   itemName,
+				id:   itemID,
 				data: itemReader,
 				info: itemInfo,
 			}
+
+			switch {
+			case hasMetadata:
+				oc.data <- &metadataItem{
+					id:   itemID + MetaFileSuffix,
+					data: io.NopCloser(bytes.NewReader(metaData)),
+				}
+			case oc.state != data.NewState:
+				// drop any sidecar the item had in the previous backup.
+				oc.data <- &metadataItem{id: itemID + MetaFileSuffix, deleted: true}
+			}
+
+			for _, v := range versions {
+				atomic.AddInt64(&byteCount, v.Size)
+
+				oc.data <- &versionItem{
+					id:      VersionFileName(itemID, v.ID),
+					data:    oc.lazyVersionReader(ctx, v.downloadURL),
+					modTime: v.Modified,
+				}
+			}
+
 			folderProgress <- struct{}{}
 		}(item)
 	}
@@ -277,6 +395,45 @@ func (oc *Collection) populateItems(ctx context.Context) {
 	oc.reportAsCompleted(ctx, int(itemsRead), byteCount, errs)
 }
 
+// streamFolderMetadata sends the metadata sidecar of the collection's folder.
+// Permissions inherited by the folder's items are granted on the folder, so
+// they are restored along with it.
+func (oc *Collection) streamFolderMetadata(ctx context.Context) error {
+	perms, err := oc.permsReader(ctx, oc.service, oc.driveID, oc.folder)
+	if err != nil {
+		return err
+	}
+
+	if len(perms) == 0 {
+		if oc.state != data.NewState {
+			// drop any sidecar the folder had in the previous backup.
+			oc.data <- &metadataItem{id: FolderMetaFileName, deleted: true}
+		}
+
+		return nil
+	}
+
+	metaData, err := json.Marshal(Metadata{Permissions: perms})
+	if err != nil {
+		return errors.Wrap(err, "serializing folder metadata")
+	}
+
+	oc.data <- &metadataItem{
+		id:   FolderMetaFileName,
+		data: io.NopCloser(bytes.NewReader(metaData)),
+	}
+
+	return nil
+}
+
+// lazyVersionReader defers downloading a prior version of an item until its
+// content is read.  Versions already held by the base backup are never read.
+func (oc *Collection) lazyVersionReader(ctx context.Context, url string) io.ReadCloser {
+	return lazy.NewLazyReadCloser(func() (io.ReadCloser, error) {
+		return oc.versionReader(ctx, url)
+	})
+}
+
 func (oc *Collection) reportAsCompleted(ctx context.Context, itemsRead int, byteCount int64, errs error) {
 	close(oc.data)
 
@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/alcionai/corso/src/internal/connector/graph"
 	"github.com/alcionai/corso/src/internal/connector/support"
 	"github.com/alcionai/corso/src/internal/data"
 	"github.com/alcionai/corso/src/pkg/backup/details"
@@ -141,11 +142,13 @@ func (suite *CollectionUnitTestSuite) TestCollection() {
 
 			coll := NewCollection(
 				folderPath,
+				nil,
 				"drive-id",
 				suite,
 				suite.testStatusUpdater(&wg, &collStatus),
 				test.source,
-				control.Options{})
+				control.Options{},
+				false)
 			require.NotNil(t, coll)
 			assert.Equal(t, folderPath, coll.FullPath())
 
@@ -158,6 +161,7 @@ func (suite *CollectionUnitTestSuite) TestCollection() {
 			}
 
 			coll.itemReader = test.itemReader
+			coll.permsReader = noPermissions
 
 			// Read items from the collection
 			wg.Add(1)
@@ -177,7 +181,8 @@ func (suite *CollectionUnitTestSuite) TestCollection() {
 			readItem := readItems[0]
 			readItemInfo := readItem.(data.StreamInfo)
 
-			assert.Equal(t, testItemName, readItem.UUID())
+			// items are keyed by their graph ID, and named by their info
+			assert.Equal(t, testItemID, readItem.UUID())
 
 			require.Implements(t, (*data.StreamModTime)(nil), readItem)
 			mt := readItem.(data.StreamModTime)
@@ -225,11 +230,13 @@ func (suite *CollectionUnitTestSuite) TestCollectionReadError() {
 
 			coll := NewCollection(
 				folderPath,
+				nil,
 				"fakeDriveID",
 				suite,
 				suite.testStatusUpdater(&wg, &collStatus),
 				test.source,
-				control.Options{})
+				control.Options{},
+				false)
 
 			mockItem := models.NewDriveItem()
 			mockItem.SetId(&testItemID)
@@ -240,6 +247,7 @@ func (suite *CollectionUnitTestSuite) TestCollectionReadError() {
 			coll.itemReader = func(context.Context, models.DriveItemable) (details.ItemInfo, io.ReadCloser, error) {
 				return details.ItemInfo{}, nil, readError
 			}
+			coll.permsReader = noPermissions
 
 			coll.Items()
 			wg.Wait()
@@ -250,3 +258,415 @@ func (suite *CollectionUnitTestSuite) TestCollectionReadError() {
 		})
 	}
 }
+
+func noPermissions(context.Context, graph.Servicer, string, models.DriveItemable) ([]UserPermission, error) {
+	return nil, nil
+}
+
+func (suite *CollectionUnitTestSuite) TestCollectionPermissions() {
+	var (
+		t            = suite.T()
+		testItemID   = "fakeItemID"
+		testItemName = "itemName"
+		perms        = []UserPermission{
+			{ID: "p1", Roles: []string{"write"}, Email: "user@example.com", EntityID: "user-id"},
+			{ID: "p2", Roles: []string{"read"}, LinkType: "view", LinkScope: "organization"},
+		}
+		wg         = sync.WaitGroup{}
+		collStatus = support.ConnectorOperationStatus{}
+		readItems  = map[string]data.Stream{}
+	)
+
+	folderPath, err := GetCanonicalPath("drive/driveID1/root:/dir1", "tenant", "owner", OneDriveSource)
+	require.NoError(t, err)
+
+	coll := NewCollection(
+		folderPath,
+		nil,
+		"drive-id",
+		suite,
+		suite.testStatusUpdater(&wg, &collStatus),
+		OneDriveSource,
+		control.Options{},
+		false)
+
+	mockItem := models.NewDriveItem()
+	mockItem.SetId(&testItemID)
+	coll.Add(mockItem)
+
+	coll.itemReader = func(context.Context, models.DriveItemable) (details.ItemInfo, io.ReadCloser, error) {
+		return details.ItemInfo{OneDrive: &details.OneDriveInfo{ItemName: testItemName}},
+			io.NopCloser(bytes.NewReader([]byte("testdata"))),
+			nil
+	}
+	coll.permsReader = func(context.Context, graph.Servicer, string, models.DriveItemable) ([]UserPermission, error) {
+		return perms, nil
+	}
+
+	wg.Add(1)
+
+	for item := range coll.Items() {
+		readItems[item.UUID()] = item
+	}
+
+	wg.Wait()
+
+	require.Len(t, readItems, 2)
+	assert.Equal(t, 1, collStatus.Successful)
+
+	item := readItems[testItemID]
+	require.NotNil(t, item)
+	require.Implements(t, (*data.StreamInfo)(nil), item)
+	assert.True(t, item.(data.StreamInfo).Info().OneDrive.HasMetadata)
+
+	meta := readItems[testItemID+MetaFileSuffix]
+	require.NotNil(t, meta)
+	_, ok := meta.(data.StreamInfo)
+	assert.False(t, ok, "metadata sidecar must not produce a details entry")
+
+	md, err := decodeMetadata(meta.ToReader())
+	require.NoError(t, err)
+	assert.Equal(t, perms, md.Permissions)
+}
+
+func (suite *CollectionUnitTestSuite) TestCollectionFolderPermissions() {
+	var (
+		folderID   = "folderID"
+		testItemID = "fakeItemID"
+		perms      = []UserPermission{
+			{ID: "p1", Roles: []string{"write"}, Email: "user@example.com", EntityID: "user-id"},
+		}
+	)
+
+	table := []struct {
+		name          string
+		prevPath      bool
+		folderPerms   []UserPermission
+		expectMeta    bool
+		expectDeleted bool
+	}{
+		{
+			name:        "new folder with permissions",
+			folderPerms: perms,
+			expectMeta:  true,
+		},
+		{
+			name: "new folder without permissions",
+		},
+		{
+			name:        "existing folder with permissions",
+			prevPath:    true,
+			folderPerms: perms,
+			expectMeta:  true,
+		},
+		{
+			name:          "existing folder without permissions",
+			prevPath:      true,
+			expectMeta:    true,
+			expectDeleted: true,
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			var (
+				wg         = sync.WaitGroup{}
+				collStatus = support.ConnectorOperationStatus{}
+				readItems  = map[string]data.Stream{}
+				prevPath   path.Path
+			)
+
+			folderPath, err := GetCanonicalPath("drive/driveID1/root:/dir1", "tenant", "owner", OneDriveSource)
+			require.NoError(t, err)
+
+			if test.prevPath {
+				prevPath = folderPath
+			}
+
+			coll := NewCollection(
+				folderPath,
+				prevPath,
+				"drive-id",
+				suite,
+				suite.testStatusUpdater(&wg, &collStatus),
+				OneDriveSource,
+				control.Options{},
+				false)
+
+			folder := models.NewDriveItem()
+			folder.SetId(&folderID)
+			coll.folder = folder
+
+			mockItem := models.NewDriveItem()
+			mockItem.SetId(&testItemID)
+			coll.Add(mockItem)
+
+			coll.itemReader = func(context.Context, models.DriveItemable) (details.ItemInfo, io.ReadCloser, error) {
+				return details.ItemInfo{OneDrive: &details.OneDriveInfo{ItemName: "itemName"}},
+					io.NopCloser(bytes.NewReader([]byte("testdata"))),
+					nil
+			}
+			coll.permsReader = func(
+				_ context.Context,
+				_ graph.Servicer,
+				_ string,
+				item models.DriveItemable,
+			) ([]UserPermission, error) {
+				if *item.GetId() == folderID {
+					return test.folderPerms, nil
+				}
+
+				return nil, nil
+			}
+
+			wg.Add(1)
+
+			for item := range coll.Items() {
+				readItems[item.UUID()] = item
+			}
+
+			wg.Wait()
+
+			assert.Equal(t, 1, collStatus.Successful)
+			assert.Contains(t, readItems, testItemID)
+
+			meta, ok := readItems[FolderMetaFileName]
+			require.Equal(t, test.expectMeta, ok, "folder metadata")
+
+			if !ok {
+				return
+			}
+
+			assert.Equal(t, test.expectDeleted, meta.Deleted(), "deleted folder metadata")
+
+			if test.expectDeleted {
+				return
+			}
+
+			md, err := decodeMetadata(meta.ToReader())
+			require.NoError(t, err)
+			assert.Equal(t, perms, md.Permissions)
+		})
+	}
+}
+
+func (suite *CollectionUnitTestSuite) TestCollectionRemovedItems() {
+	var (
+		t          = suite.T()
+		testItemID = "fakeItemID"
+		removedID  = "removedItemID"
+		wg         = sync.WaitGroup{}
+		collStatus = support.ConnectorOperationStatus{}
+		readItems  = map[string]data.Stream{}
+	)
+
+	folderPath, err := GetCanonicalPath("drive/driveID1/root:/dir1", "tenant", "owner", OneDriveSource)
+	require.NoError(t, err)
+
+	coll := NewCollection(
+		folderPath,
+		folderPath,
+		"drive-id",
+		suite,
+		suite.testStatusUpdater(&wg, &collStatus),
+		OneDriveSource,
+		control.Options{},
+		false)
+
+	mockItem := models.NewDriveItem()
+	mockItem.SetId(&testItemID)
+	coll.Add(mockItem)
+	coll.Remove(removedID)
+
+	coll.itemReader = func(context.Context, models.DriveItemable) (details.ItemInfo, io.ReadCloser, error) {
+		return details.ItemInfo{OneDrive: &details.OneDriveInfo{ItemName: "itemName"}},
+			io.NopCloser(bytes.NewReader([]byte("testdata"))),
+			nil
+	}
+	coll.permsReader = func(context.Context, graph.Servicer, string, models.DriveItemable) ([]UserPermission, error) {
+		return nil, nil
+	}
+
+	wg.Add(1)
+
+	for item := range coll.Items() {
+		readItems[item.UUID()] = item
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 1, collStatus.Successful)
+
+	expectDeleted := map[string]bool{
+		testItemID: false,
+		// the item no longer has permissions, so its old sidecar is dropped.
+		testItemID + MetaFileSuffix: true,
+		removedID:                   true,
+		removedID + MetaFileSuffix:  true,
+	}
+	require.Len(t, readItems, len(expectDeleted))
+
+	for id, deleted := range expectDeleted {
+		require.Contains(t, readItems, id)
+		assert.Equal(t, deleted, readItems[id].Deleted(), id)
+	}
+}
+
+func (suite *CollectionUnitTestSuite) TestCollectionVersions() {
+	var (
+		t            = suite.T()
+		testItemID   = "fakeItemID"
+		testItemName = "itemName"
+		now          = time.Now()
+		versions     = []driveVersion{
+			{
+				OneDriveVersion: details.OneDriveVersion{ID: "1.0", Modified: now.Add(-time.Hour), Size: 3},
+				downloadURL:     "https://version.url",
+			},
+		}
+		wg         = sync.WaitGroup{}
+		collStatus = support.ConnectorOperationStatus{}
+		readItems  = map[string]data.Stream{}
+	)
+
+	folderPath, err := GetCanonicalPath("drive/driveID1/root:/dir1", "tenant", "owner", OneDriveSource)
+	require.NoError(t, err)
+
+	coll := NewCollection(
+		folderPath,
+		nil,
+		"drive-id",
+		suite,
+		suite.testStatusUpdater(&wg, &collStatus),
+		OneDriveSource,
+		control.Options{Versions: control.VersionsConfig{Backup: true}},
+		false)
+
+	mockItem := models.NewDriveItem()
+	mockItem.SetId(&testItemID)
+	coll.Add(mockItem)
+
+	coll.itemReader = func(context.Context, models.DriveItemable) (details.ItemInfo, io.ReadCloser, error) {
+		return details.ItemInfo{OneDrive: &details.OneDriveInfo{ItemName: testItemName, Modified: now}},
+			io.NopCloser(bytes.NewReader([]byte("current"))),
+			nil
+	}
+	coll.permsReader = noPermissions
+	coll.versionsReader = func(
+		context.Context,
+		graph.Servicer,
+		string,
+		models.DriveItemable,
+	) ([]driveVersion, error) {
+		return versions, nil
+	}
+	coll.versionReader = func(_ context.Context, url string) (io.ReadCloser, error) {
+		assert.Equal(t, "https://version.url", url)
+
+		return io.NopCloser(bytes.NewReader([]byte("old"))), nil
+	}
+
+	wg.Add(1)
+
+	for item := range coll.Items() {
+		readItems[item.UUID()] = item
+	}
+
+	wg.Wait()
+
+	require.Len(t, readItems, 2)
+	assert.Equal(t, 1, collStatus.Successful)
+
+	item := readItems[testItemID]
+	require.NotNil(t, item)
+	assert.Equal(
+		t,
+		[]details.OneDriveVersion{versions[0].OneDriveVersion},
+		item.(data.StreamInfo).Info().OneDrive.Versions)
+
+	version := readItems[VersionFileName(testItemID, "1.0")]
+	require.NotNil(t, version)
+
+	_, ok := version.(data.StreamInfo)
+	assert.False(t, ok, "versions must not produce a details entry")
+
+	require.Implements(t, (*data.StreamImmutable)(nil), version)
+	assert.True(t, version.(data.StreamImmutable).Immutable())
+
+	require.Implements(t, (*data.StreamModTime)(nil), version)
+	assert.Equal(t, versions[0].Modified, version.(data.StreamModTime).ModTime())
+
+	readData, err := io.ReadAll(version.ToReader())
+	require.NoError(t, err)
+	assert.Equal(t, []byte("old"), readData)
+}
+
+func (suite *CollectionUnitTestSuite) TestCollectionVersionsReadError() {
+	var (
+		t            = suite.T()
+		testItemID   = "fakeItemID"
+		testItemName = "itemName"
+		perms        = []UserPermission{
+			{ID: "p1", Roles: []string{"write"}, Email: "user@example.com", EntityID: "user-id"},
+		}
+		wg         = sync.WaitGroup{}
+		collStatus = support.ConnectorOperationStatus{}
+		readItems  = map[string]data.Stream{}
+	)
+
+	folderPath, err := GetCanonicalPath("drive/driveID1/root:/dir1", "tenant", "owner", OneDriveSource)
+	require.NoError(t, err)
+
+	coll := NewCollection(
+		folderPath,
+		nil,
+		"drive-id",
+		suite,
+		suite.testStatusUpdater(&wg, &collStatus),
+		OneDriveSource,
+		control.Options{Versions: control.VersionsConfig{Backup: true}},
+		false)
+
+	mockItem := models.NewDriveItem()
+	mockItem.SetId(&testItemID)
+	coll.Add(mockItem)
+
+	coll.itemReader = func(context.Context, models.DriveItemable) (details.ItemInfo, io.ReadCloser, error) {
+		return details.ItemInfo{OneDrive: &details.OneDriveInfo{ItemName: testItemName}},
+			io.NopCloser(bytes.NewReader([]byte("current"))),
+			nil
+	}
+	coll.permsReader = func(context.Context, graph.Servicer, string, models.DriveItemable) ([]UserPermission, error) {
+		return perms, nil
+	}
+	coll.versionsReader = func(
+		context.Context,
+		graph.Servicer,
+		string,
+		models.DriveItemable,
+	) ([]driveVersion, error) {
+		return nil, errors.New("version has no download url")
+	}
+
+	wg.Add(1)
+
+	for item := range coll.Items() {
+		readItems[item.UUID()] = item
+	}
+
+	wg.Wait()
+
+	// the current item and its sidecar are still backed up.
+	require.Len(t, readItems, 2)
+	assert.Equal(t, 1, collStatus.Successful)
+	assert.Equal(t, 1, collStatus.ErrorCount)
+
+	item := readItems[testItemID]
+	require.NotNil(t, item)
+	assert.Empty(t, item.(data.StreamInfo).Info().OneDrive.Versions)
+
+	readData, err := io.ReadAll(item.ToReader())
+	require.NoError(t, err)
+	assert.Equal(t, []byte("current"), readData)
+
+	assert.NotNil(t, readItems[testItemID+MetaFileSuffix])
+}
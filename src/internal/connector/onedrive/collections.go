@@ -2,6 +2,7 @@ package onedrive
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -43,9 +44,28 @@ type Collections struct {
 	ctrl control.Options
 
 	// collectionMap allows lookup of the data.Collection
-	// for a OneDrive folder
+	// for a OneDrive folder.  Tombstones for deleted folders
+	// are keyed by the folder's ID.
 	CollectionMap map[string]data.Collection
 
+	// folder ID -> folder path lookups, keyed by drive ID.  Saved
+	// in the backup metadata, to resolve the previous path of the
+	// folders that change before the next backup.
+	folderPaths map[string]map[string]string
+
+	// file ID -> parent folder ID lookups, keyed by drive ID.  Saved in the
+	// backup metadata, as delta queries only report the folder that a file
+	// moved into, and not the one it moved out of.
+	itemParents map[string]map[string]string
+
+	// folder ID -> drive item of the folder, for the folders enumerated by
+	// this backup.
+	folderItems map[string]models.DriveItemable
+
+	// childrenReader lists the items within a folder.
+	// Allows tests to set a mock populator.
+	childrenReader childrenReaderFunc
+
 	// Track stats from drive enumeration. Represents the items backed up.
 	NumItems      int
 	NumFiles      int
@@ -62,49 +82,319 @@ func NewCollections(
 	ctrlOpts control.Options,
 ) *Collections {
 	return &Collections{
-		tenant:        tenant,
-		resourceOwner: resourceOwner,
-		source:        source,
-		matcher:       matcher,
-		CollectionMap: map[string]data.Collection{},
-		service:       service,
-		statusUpdater: statusUpdater,
-		ctrl:          ctrlOpts,
+		tenant:         tenant,
+		resourceOwner:  resourceOwner,
+		source:         source,
+		matcher:        matcher,
+		CollectionMap:  map[string]data.Collection{},
+		folderPaths:    map[string]map[string]string{},
+		itemParents:    map[string]map[string]string{},
+		folderItems:    map[string]models.DriveItemable{},
+		childrenReader: folderChildren,
+		service:        service,
+		statusUpdater:  statusUpdater,
+		ctrl:           ctrlOpts,
 	}
 }
 
-// Retrieves drive data as set of `data.Collections`
-func (c *Collections) Get(ctx context.Context) ([]data.Collection, error) {
+// childrenReaderFunc returns the items within the specified folder.
+type childrenReaderFunc func(
+	ctx context.Context,
+	service graph.Servicer,
+	driveID, folderID string,
+) ([]models.DriveItemable, error)
+
+// Retrieves drive data as set of `data.Collections`.  prev holds the delta
+// links, folder paths, and item parents saved by the previous backup, if any.
+// Drives with usable metadata, whose prior versions were backed up the same
+// way as they are now, are enumerated incrementally.
+func (c *Collections) Get(
+	ctx context.Context,
+	prev PrevMetadata,
+) ([]data.Collection, error) {
 	// Enumerate drives for the specified resourceOwner
 	drives, err := drives(ctx, c.service, c.resourceOwner, c.source)
 	if err != nil {
 		return nil, err
 	}
 
+	var (
+		// drive ID -> delta link for the next incremental backup
+		deltaURLs = map[string]string{}
+		// drive ID -> format of the keys the drive's items are stored under
+		itemKeys = map[string]string{}
+		// drive ID -> whether the prior versions of the drive's items are
+		// backed up
+		versions       = map[string]bool{}
+		backupVersions = c.ctrl.Versions.Backup && c.source == OneDriveSource
+	)
+
 	// Update the collection map with items from each drive
 	for _, d := range drives {
-		err = collectItems(ctx, c.service, *d.GetId(), c.UpdateCollections)
+		driveID := *d.GetId()
+
+		delta, err := c.collectDrive(
+			ctx,
+			driveID,
+			prev.delta(driveID, backupVersions),
+			prev.paths[driveID],
+			prev.parents[driveID])
 		if err != nil {
 			return nil, err
 		}
+
+		if len(delta) > 0 {
+			deltaURLs[driveID] = delta
+		}
+
+		itemKeys[driveID] = itemKeysByID
+		versions[driveID] = backupVersions
+	}
+
+	if err := c.addDeletedDrives(drives, prev.paths); err != nil {
+		return nil, err
 	}
 
 	observe.Message(ctx, fmt.Sprintf("Discovered %d items to backup", c.NumItems))
 
-	collections := make([]data.Collection, 0, len(c.CollectionMap))
+	collections := make([]data.Collection, 0, len(c.CollectionMap)+1)
 	for _, coll := range c.CollectionMap {
 		collections = append(collections, coll)
 	}
 
+	service, category := path.OneDriveService, path.FilesCategory
+	if c.source == SharePointSource {
+		service, category = path.SharePointService, path.LibrariesCategory
+	}
+
+	md, err := graph.MakeMetadataCollection(
+		c.tenant,
+		c.resourceOwner,
+		service,
+		category,
+		[]graph.MetadataCollectionEntry{
+			graph.NewMetadataEntry(graph.PreviousPathFileName, c.folderPaths),
+			graph.NewMetadataEntry(graph.DeltaURLsFileName, deltaURLs),
+			graph.NewMetadataEntry(graph.ItemParentsFileName, c.itemParents),
+			graph.NewMetadataEntry(graph.ItemKeysFileName, itemKeys),
+			graph.NewMetadataEntry(graph.VersionsFileName, versions),
+		},
+		c.statusUpdater)
+	if err != nil {
+		return nil, errors.Wrap(err, "making metadata collection")
+	}
+
+	if md != nil {
+		collections = append(collections, md)
+	}
+
 	return collections, nil
 }
 
+// collectDrive adds the collections for a single drive.  The drive is
+// enumerated incrementally if the previous delta link, folder paths, and item
+// parents are populated, and in full otherwise.  Returns the drive's new
+// delta link.
+func (c *Collections) collectDrive(
+	ctx context.Context,
+	driveID, prevDelta string,
+	oldPaths, oldParents map[string]string,
+) (string, error) {
+	if len(prevDelta) > 0 && len(oldPaths) > 0 && oldParents != nil {
+		var changed []models.DriveItemable
+
+		delta, err := collectItems(
+			ctx,
+			c.service,
+			driveID,
+			prevDelta,
+			func(_ context.Context, _ string, items []models.DriveItemable) error {
+				changed = append(changed, items...)
+				return nil
+			})
+		if err == nil {
+			return delta, c.UpdateIncrementalCollections(ctx, driveID, oldPaths, oldParents, changed)
+		}
+
+		if graph.IsErrInvalidDelta(err) == nil {
+			return "", err
+		}
+
+		logger.Ctx(ctx).Infow("invalid delta link, enumerating all drive items", "drive_id", driveID)
+	}
+
+	c.itemParents[driveID] = map[string]string{}
+
+	delta, err := collectItems(ctx, c.service, driveID, "", c.UpdateCollections)
+	if err != nil {
+		return "", err
+	}
+
+	// Every item in the drive is backed up by a full enumeration, so nothing
+	// from the drive gets merged in from the previous backup.
+	rootPath, err := c.driveRootPath(driveID, oldPaths)
+	if err != nil {
+		return "", err
+	}
+
+	c.CollectionMap[driveID] = NewCollection(
+		nil,
+		rootPath,
+		driveID,
+		c.service,
+		c.statusUpdater,
+		c.source,
+		c.ctrl,
+		false)
+
+	return delta, nil
+}
+
+// addDeletedDrives adds a tombstone for each drive in the previous backup
+// that no longer exists, so that the drive is removed from the backup along
+// with everything in it.  prevPaths holds the folder paths of the drives in
+// the previous backup.
+func (c *Collections) addDeletedDrives(
+	drives []models.Driveable,
+	prevPaths map[string]map[string]string,
+) error {
+	current := make(map[string]struct{}, len(drives))
+	for _, d := range drives {
+		current[*d.GetId()] = struct{}{}
+	}
+
+	for driveID, oldPaths := range prevPaths {
+		if _, ok := current[driveID]; ok || len(oldPaths) == 0 {
+			continue
+		}
+
+		rootPath, err := c.driveRootPath(driveID, oldPaths)
+		if err != nil {
+			return err
+		}
+
+		c.CollectionMap[driveID] = NewCollection(
+			nil,
+			rootPath,
+			driveID,
+			c.service,
+			c.statusUpdater,
+			c.source,
+			c.ctrl,
+			false)
+	}
+
+	return nil
+}
+
+// driveRootPath returns the path of the drive's root folder.  Every folder
+// in the drive is within the root, so its path is the shortest of the folder
+// paths.
+func (c *Collections) driveRootPath(driveID string, oldPaths map[string]string) (path.Path, error) {
+	var root string
+
+	for _, paths := range []map[string]string{c.folderPaths[driveID], oldPaths} {
+		for _, p := range paths {
+			if len(root) == 0 || len(p) < len(root) {
+				root = p
+			}
+		}
+
+		if len(root) > 0 {
+			return pathFromPrevString(root)
+		}
+	}
+
+	return GetCanonicalPath(fmt.Sprintf("/drives/%s/root:", driveID), c.tenant, c.resourceOwner, c.source)
+}
+
+// recordPaths records the paths of the item's parent and, if the item is a
+// folder, of the item itself.  Returns the item's path.
+func (c *Collections) recordPaths(driveID string, item models.DriveItemable) (path.Path, error) {
+	var (
+		paths     = c.folderPaths[driveID]
+		parentRef = item.GetParentReference()
+		parentID  string
+		parent    string
+	)
+
+	if paths == nil {
+		paths = map[string]string{}
+		c.folderPaths[driveID] = paths
+	}
+
+	if parentRef != nil && parentRef.GetId() != nil {
+		parentID = *parentRef.GetId()
+	}
+
+	if parentRef != nil && parentRef.GetPath() != nil {
+		pp, err := GetCanonicalPath(*parentRef.GetPath(), c.tenant, c.resourceOwner, c.source)
+		if err != nil {
+			return nil, err
+		}
+
+		parent = pp.String()
+
+		if len(parentID) > 0 {
+			paths[parentID] = parent
+		}
+	} else if len(parentID) > 0 {
+		parent = paths[parentID]
+	}
+
+	if len(parent) == 0 {
+		return nil, errors.Errorf("item does not have a parent reference. item name : %s", *item.GetName())
+	}
+
+	pp, err := pathFromPrevString(parent)
+	if err != nil {
+		return nil, err
+	}
+
+	itemPath, err := pp.Append(*item.GetName(), false)
+	if err != nil {
+		return nil, errors.Wrap(err, "building item path")
+	}
+
+	if item.GetFolder() != nil || item.GetPackage() != nil {
+		paths[*item.GetId()] = itemPath.String()
+	}
+
+	return itemPath, nil
+}
+
+// recordParent records the ID of the parent folder of the item, if the item
+// is a file.
+func (c *Collections) recordParent(driveID string, item models.DriveItemable) {
+	if item.GetFile() == nil || item.GetParentReference() == nil || item.GetParentReference().GetId() == nil {
+		return
+	}
+
+	parents := c.itemParents[driveID]
+	if parents == nil {
+		parents = map[string]string{}
+		c.itemParents[driveID] = parents
+	}
+
+	parents[*item.GetId()] = *item.GetParentReference().GetId()
+}
+
+// recordFolder records the drive item of the folder, and hands it to the
+// folder's collection, if the collection was already created.
+func (c *Collections) recordFolder(item models.DriveItemable, folderPath path.Path) {
+	c.folderItems[*item.GetId()] = item
+
+	if col, ok := c.CollectionMap[folderPath.String()].(*Collection); ok {
+		col.folder = item
+	}
+}
+
 // UpdateCollections initializes and adds the provided drive items to Collections
 // A new collection is created for every drive folder (or package)
 func (c *Collections) UpdateCollections(ctx context.Context, driveID string, items []models.DriveItemable) error {
 	for _, item := range items {
-		if item.GetRoot() != nil {
-			// Skip the root item
+		if item.GetRoot() != nil || item.GetDeleted() != nil {
+			// Skip the root item, and any deleted items
 			continue
 		}
 
@@ -112,6 +402,17 @@ func (c *Collections) UpdateCollections(ctx context.Context, driveID string, ite
 			return errors.Errorf("item does not have a parent reference. item name : %s", *item.GetName())
 		}
 
+		itemPath, err := c.recordPaths(driveID, item)
+		if err != nil {
+			return err
+		}
+
+		c.recordParent(driveID, item)
+
+		if item.GetFolder() != nil {
+			c.recordFolder(item, itemPath)
+		}
+
 		// Create a collection for the parent of this item
 		collectionPath, err := GetCanonicalPath(
 			*item.GetParentReference().GetPath(),
@@ -138,15 +439,22 @@ func (c *Collections) UpdateCollections(ctx context.Context, driveID string, ite
 		case item.GetFile() != nil:
 			col, found := c.CollectionMap[collectionPath.String()]
 			if !found {
-				col = NewCollection(
+				nc := NewCollection(
 					collectionPath,
+					nil,
 					driveID,
 					c.service,
 					c.statusUpdater,
 					c.source,
 					c.ctrl,
+					false,
 				)
 
+				if pid := item.GetParentReference().GetId(); pid != nil {
+					nc.folder = c.folderItems[*pid]
+				}
+
+				col = nc
 				c.CollectionMap[collectionPath.String()] = col
 				c.NumContainers++
 				c.NumItems++
@@ -165,6 +473,386 @@ func (c *Collections) UpdateCollections(ctx context.Context, driveID string, ite
 	return nil
 }
 
+// UpdateIncrementalCollections adds the collections for the folders changed
+// by the provided drive items, which are the changes reported by a delta query
+// since the previous backup.  oldPaths and oldParents hold the folder paths
+// and item parents saved by the previous backup.  Only the added and changed
+// files are backed up, and files that were deleted or moved out of a folder
+// are removed from it.  Everything else is merged in from the previous backup
+// by kopia.
+func (c *Collections) UpdateIncrementalCollections(
+	ctx context.Context,
+	driveID string,
+	oldPaths, oldParents map[string]string,
+	items []models.DriveItemable,
+) error {
+	newPaths := make(map[string]string, len(oldPaths))
+	for id, p := range oldPaths {
+		newPaths[id] = p
+	}
+
+	newParents := make(map[string]string, len(oldParents))
+	for id, p := range oldParents {
+		newParents[id] = p
+	}
+
+	c.folderPaths[driveID] = newPaths
+	c.itemParents[driveID] = newParents
+
+	var (
+		// folders that were added, moved, or deleted
+		changed = map[string]struct{}{}
+		deleted = map[string]struct{}{}
+		// folder ID -> files added to or changed within the folder
+		added = map[string][]models.DriveItemable{}
+		// folder ID -> IDs of files deleted or moved out of the folder
+		removed = map[string][]string{}
+	)
+
+	for _, item := range items {
+		if item.GetRoot() != nil {
+			continue
+		}
+
+		var (
+			id       = *item.GetId()
+			parentID string
+		)
+
+		if item.GetParentReference() != nil && item.GetParentReference().GetId() != nil {
+			parentID = *item.GetParentReference().GetId()
+		}
+
+		switch {
+		case item.GetDeleted() != nil:
+			op, ok := newPaths[id]
+			if !ok {
+				// a deleted file, which is removed from the folder it was in
+				// at the previous backup.
+				if prevParent, ok := newParents[id]; ok {
+					parentID = prevParent
+				}
+
+				if len(parentID) > 0 {
+					removed[parentID] = append(removed[parentID], id)
+				}
+
+				delete(newParents, id)
+
+				continue
+			}
+
+			// a deleted folder, along with everything in it
+			for fid, p := range newPaths {
+				if p == op || strings.HasPrefix(p, op+"/") {
+					delete(newPaths, fid)
+					deleted[fid] = struct{}{}
+					changed[fid] = struct{}{}
+				}
+			}
+
+		case item.GetFolder() != nil, item.GetPackage() != nil:
+			op, ok := newPaths[id]
+
+			np, err := c.recordPaths(driveID, item)
+			if err != nil {
+				return err
+			}
+
+			if item.GetFolder() != nil {
+				c.folderItems[id] = item
+			}
+
+			// the folder's own metadata, such as its permissions, may have
+			// changed even if it didn't move.
+			changed[id] = struct{}{}
+
+			if !ok || op == np.String() {
+				continue
+			}
+
+			// the folder moved, along with everything in it
+			for fid, p := range newPaths {
+				if strings.HasPrefix(p, op+"/") {
+					newPaths[fid] = np.String() + strings.TrimPrefix(p, op)
+					changed[fid] = struct{}{}
+				}
+			}
+
+		case item.GetFile() != nil:
+			if _, err := c.recordPaths(driveID, item); err != nil {
+				return err
+			}
+
+			if len(parentID) == 0 {
+				return errors.Errorf("item does not have a parent reference. item name : %s", *item.GetName())
+			}
+
+			// a file moved out of another folder is removed from that folder.
+			if prevParent, ok := newParents[id]; ok && prevParent != parentID {
+				removed[prevParent] = append(removed[prevParent], id)
+			}
+
+			newParents[id] = parentID
+			added[parentID] = append(added[parentID], item)
+
+		default:
+			return errors.Errorf("item type not supported. item name : %s", *item.GetName())
+		}
+	}
+
+	// files within deleted folders went along with them.
+	for id, parentID := range newParents {
+		if _, ok := deleted[parentID]; ok {
+			delete(newParents, id)
+		}
+	}
+
+	for id := range added {
+		changed[id] = struct{}{}
+	}
+
+	for id := range removed {
+		changed[id] = struct{}{}
+	}
+
+	for id := range changed {
+		err := c.addIncrementalCollection(ctx, driveID, id, oldPaths[id], newPaths[id], added[id], removed[id])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addIncrementalCollection adds the collection for a folder changed since the
+// previous backup, holding the folder's added and changed files, and marking
+// its removed files.
+func (c *Collections) addIncrementalCollection(
+	ctx context.Context,
+	driveID, folderID, oldPath, newPath string,
+	added []models.DriveItemable,
+	removed []string,
+) error {
+	prevPath, err := c.includedPath(ctx, oldPath)
+	if err != nil {
+		return err
+	}
+
+	currPath, err := c.includedPath(ctx, newPath)
+	if err != nil {
+		return err
+	}
+
+	if currPath == nil {
+		if prevPath != nil {
+			c.CollectionMap[folderID] = NewCollection(
+				nil,
+				prevPath,
+				driveID,
+				c.service,
+				c.statusUpdater,
+				c.source,
+				c.ctrl,
+				false)
+		}
+
+		return nil
+	}
+
+	col := NewCollection(
+		currPath,
+		prevPath,
+		driveID,
+		c.service,
+		c.statusUpdater,
+		c.source,
+		c.ctrl,
+		false)
+	col.folder = c.folderItems[folderID]
+
+	c.CollectionMap[currPath.String()] = col
+	c.NumContainers++
+	c.NumItems++
+
+	for _, id := range removed {
+		col.Remove(id)
+	}
+
+	files := added
+
+	// A folder that existed in the previous backup, but wasn't included in
+	// it, has nothing to merge.  Since its files are unchanged, they aren't
+	// reported by the delta query, and are listed instead.
+	if prevPath == nil && len(oldPath) > 0 {
+		files, err = c.childrenReader(ctx, c.service, driveID, folderID)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, item := range files {
+		if item.GetFile() == nil {
+			continue
+		}
+
+		col.Add(item)
+		c.NumFiles++
+		c.NumItems++
+	}
+
+	return nil
+}
+
+// includedPath parses the folder path, returning nil if the path is empty
+// or excluded by the folder selectors.
+func (c *Collections) includedPath(ctx context.Context, p string) (path.Path, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+
+	fp, err := pathFromPrevString(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if !includePath(ctx, c.matcher, fp) {
+		return nil, nil
+	}
+
+	return fp, nil
+}
+
+// itemKeysByID marks drives whose items are stored under their graph ID in
+// the item keys metadata.
+const itemKeysByID = "id"
+
+// PrevMetadata holds the delta links, folder paths, item parents, and
+// whether prior versions were backed up, saved by the previous backup of a
+// resource owner's drives, keyed by drive ID.
+type PrevMetadata struct {
+	deltas   map[string]string
+	paths    map[string]map[string]string
+	parents  map[string]map[string]string
+	versions map[string]bool
+}
+
+// delta returns the delta link to enumerate the drive from.  If the previous
+// backup didn't back up prior versions the same way, no delta is returned, so
+// that the drive is enumerated in full and every item's versions are
+// captured, not just those of items changed since.
+func (pm PrevMetadata) delta(driveID string, backupVersions bool) string {
+	if pm.versions[driveID] != backupVersions {
+		return ""
+	}
+
+	return pm.deltas[driveID]
+}
+
+// DeserializeMetadata parses the delta links, folder paths, item parents, and
+// versions mode from the metadata saved by the previous backup.  The delta and parents of
+// drives missing any of them, or whose items weren't stored under their graph
+// ID, are dropped, so that they're enumerated in full.  The paths of every
+// drive are kept, to find the drives that have since been deleted.  The
+// metadata collections are consumed, so they're parsed once per backup.
+func DeserializeMetadata(ctx context.Context, colls []data.Collection) (PrevMetadata, error) {
+	var (
+		prev = PrevMetadata{
+			deltas:   map[string]string{},
+			paths:    map[string]map[string]string{},
+			parents:  map[string]map[string]string{},
+			versions: map[string]bool{},
+		}
+		// drive ID -> format of the keys the drive's items were stored under
+		keys = map[string]string{}
+		// tracks the metadata we've loaded, to make sure we don't
+		// fetch overlapping copies.
+		found = map[string]struct{}{}
+	)
+
+	for _, coll := range colls {
+		items := coll.Items()
+
+		for breakLoop := false; !breakLoop; {
+			select {
+			case <-ctx.Done():
+				return PrevMetadata{}, errors.Wrap(ctx.Err(), "parsing collection metadata")
+
+			case item, ok := <-items:
+				if !ok {
+					breakLoop = true
+					break
+				}
+
+				if _, ok := found[item.UUID()]; ok {
+					return PrevMetadata{}, errors.Errorf("multiple versions of %s metadata", item.UUID())
+				}
+
+				var err error
+
+				switch item.UUID() {
+				case graph.PreviousPathFileName:
+					err = json.NewDecoder(item.ToReader()).Decode(&prev.paths)
+
+				case graph.DeltaURLsFileName:
+					err = json.NewDecoder(item.ToReader()).Decode(&prev.deltas)
+
+				case graph.ItemParentsFileName:
+					err = json.NewDecoder(item.ToReader()).Decode(&prev.parents)
+
+				case graph.ItemKeysFileName:
+					err = json.NewDecoder(item.ToReader()).Decode(&keys)
+
+				case graph.VersionsFileName:
+					err = json.NewDecoder(item.ToReader()).Decode(&prev.versions)
+
+				default:
+					continue
+				}
+
+				if err != nil {
+					return PrevMetadata{}, errors.Wrapf(err, "decoding %s metadata", item.UUID())
+				}
+
+				found[item.UUID()] = struct{}{}
+			}
+		}
+	}
+
+	// Remove any drives that are missing a delta, paths, or parents.  That
+	// metadata is considered incomplete, and needs to incur a complete
+	// enumeration of the drive.  Drives without files have empty parents.
+	//
+	// Drives backed up before items were keyed by their graph ID are removed
+	// as well.  Their items are stored under their names, so merging them
+	// would mix name and ID keys within a folder.
+	for id, d := range prev.deltas {
+		_, ok := prev.parents[id]
+
+		if len(d) == 0 || len(prev.paths[id]) == 0 || !ok || keys[id] != itemKeysByID {
+			delete(prev.deltas, id)
+		}
+	}
+
+	for id := range prev.parents {
+		if _, ok := prev.deltas[id]; !ok {
+			delete(prev.parents, id)
+		}
+	}
+
+	return prev, nil
+}
+
+func pathFromPrevString(ps string) (path.Path, error) {
+	p, err := path.FromDataLayerPath(ps, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing previous path string")
+	}
+
+	return p, nil
+}
+
 // GetCanonicalPath constructs the standard path for the given source.
 func GetCanonicalPath(p, tenant, resourceOwner string, source driveSource) (path.Path, error) {
 	var (
@@ -1,6 +1,7 @@
 package onedrive
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -9,8 +10,12 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/alcionai/corso/src/internal/connector/graph"
+	"github.com/alcionai/corso/src/internal/connector/support"
+	"github.com/alcionai/corso/src/internal/data"
 	"github.com/alcionai/corso/src/internal/tester"
 	"github.com/alcionai/corso/src/pkg/control"
+	"github.com/alcionai/corso/src/pkg/path"
 	"github.com/alcionai/corso/src/pkg/selectors"
 )
 
@@ -278,6 +283,502 @@ func (suite *OneDriveCollectionsSuite) TestUpdateCollections() {
 	}
 }
 
+func (suite *OneDriveCollectionsSuite) TestUpdateCollections_FolderItems() {
+	const (
+		tenant = "tenant"
+		user   = "user"
+	)
+
+	var (
+		folder     = driveItemWithParent("folder", "folder", "root", testBaseDrivePath, false)
+		file       = driveItemWithParent("file", "file", "folder", testBaseDrivePath+"/folder", true)
+		folderPath = expectedPathAsSlice(suite.T(), tenant, user, testBaseDrivePath+"/folder")[0]
+	)
+
+	table := []struct {
+		name  string
+		items []models.DriveItemable
+	}{
+		{
+			name:  "folder before file",
+			items: []models.DriveItemable{folder, file},
+		},
+		{
+			name:  "file before folder",
+			items: []models.DriveItemable{file, folder},
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			ctx, flush := tester.NewContext()
+			defer flush()
+
+			c := NewCollections(
+				tenant,
+				user,
+				OneDriveSource,
+				testFolderMatcher{(&selectors.OneDriveBackup{}).Folders(selectors.Any())[0]},
+				&MockGraphService{},
+				nil,
+				control.Options{})
+
+			err := c.UpdateCollections(ctx, "driveID", test.items)
+			require.NoError(t, err)
+			require.Contains(t, c.CollectionMap, folderPath)
+
+			assert.Equal(t, folder, c.CollectionMap[folderPath].(*Collection).folder)
+		})
+	}
+}
+
+func (suite *OneDriveCollectionsSuite) TestUpdateIncrementalCollections() {
+	anyFolder := (&selectors.OneDriveBackup{}).Folders(selectors.Any())[0]
+
+	const (
+		tenant  = "tenant"
+		user    = "user"
+		driveID = "driveID"
+	)
+
+	canonical := func(p string) string {
+		return expectedPathAsSlice(suite.T(), tenant, user, p)[0]
+	}
+
+	var (
+		rootPath    = canonical(testBaseDrivePath)
+		folderPath  = canonical(testBaseDrivePath + "/folder")
+		subPath     = canonical(testBaseDrivePath + "/folder/sub")
+		renamedPath = canonical(testBaseDrivePath + "/renamed")
+		renamedSub  = canonical(testBaseDrivePath + "/renamed/sub")
+		newPath     = canonical(testBaseDrivePath + "/new")
+		oldPaths    = map[string]string{
+			"root":   rootPath,
+			"folder": folderPath,
+			"sub":    subPath,
+		}
+		oldParents = map[string]string{
+			"file":    "folder",
+			"subfile": "sub",
+		}
+	)
+
+	type expectedCollection struct {
+		state      data.CollectionState
+		prevPath   string
+		numFiles   int
+		numRemoved int
+	}
+
+	table := []struct {
+		name            string
+		items           []models.DriveItemable
+		scope           selectors.OneDriveScope
+		expect          map[string]expectedCollection
+		expectedPaths   map[string]string
+		expectedParents map[string]string
+	}{
+		{
+			name: "changed file",
+			items: []models.DriveItemable{
+				driveItemWithParent("file", "file", "folder", testBaseDrivePath+"/folder", true),
+			},
+			scope: anyFolder,
+			expect: map[string]expectedCollection{
+				folderPath: {data.NotMovedState, folderPath, 1, 0},
+			},
+			expectedPaths:   oldPaths,
+			expectedParents: oldParents,
+		},
+		{
+			name: "deleted file",
+			items: []models.DriveItemable{
+				deletedItem("file", ""),
+			},
+			scope: anyFolder,
+			expect: map[string]expectedCollection{
+				folderPath: {data.NotMovedState, folderPath, 0, 1},
+			},
+			expectedPaths:   oldPaths,
+			expectedParents: map[string]string{"subfile": "sub"},
+		},
+		{
+			name: "file moved between folders",
+			items: []models.DriveItemable{
+				driveItemWithParent("file", "file", "sub", testBaseDrivePath+"/folder/sub", true),
+			},
+			scope: anyFolder,
+			expect: map[string]expectedCollection{
+				folderPath: {data.NotMovedState, folderPath, 0, 1},
+				subPath:    {data.NotMovedState, subPath, 1, 0},
+			},
+			expectedPaths: oldPaths,
+			expectedParents: map[string]string{
+				"file":    "sub",
+				"subfile": "sub",
+			},
+		},
+		{
+			name: "changed folder",
+			items: []models.DriveItemable{
+				driveItemWithParent("folder", "folder", "root", testBaseDrivePath, false),
+			},
+			scope: anyFolder,
+			expect: map[string]expectedCollection{
+				folderPath: {data.NotMovedState, folderPath, 0, 0},
+			},
+			expectedPaths:   oldPaths,
+			expectedParents: oldParents,
+		},
+		{
+			name: "renamed folder",
+			items: []models.DriveItemable{
+				driveItemWithParent("folder", "renamed", "root", testBaseDrivePath, false),
+			},
+			scope: anyFolder,
+			expect: map[string]expectedCollection{
+				renamedPath: {data.MovedState, folderPath, 0, 0},
+				renamedSub:  {data.MovedState, subPath, 0, 0},
+			},
+			expectedPaths: map[string]string{
+				"root":   rootPath,
+				"folder": renamedPath,
+				"sub":    renamedSub,
+			},
+			expectedParents: oldParents,
+		},
+		{
+			name: "deleted folder",
+			items: []models.DriveItemable{
+				deletedItem("folder", "root"),
+			},
+			scope: anyFolder,
+			expect: map[string]expectedCollection{
+				"folder": {data.DeletedState, folderPath, 0, 0},
+				"sub":    {data.DeletedState, subPath, 0, 0},
+			},
+			expectedPaths: map[string]string{
+				"root": rootPath,
+			},
+			expectedParents: map[string]string{},
+		},
+		{
+			name: "new folder with file",
+			items: []models.DriveItemable{
+				driveItemWithParent("new", "new", "root", testBaseDrivePath, false),
+				driveItemWithParent("newfile", "newfile", "new", testBaseDrivePath+"/new", true),
+			},
+			scope: anyFolder,
+			expect: map[string]expectedCollection{
+				newPath: {data.NewState, "", 1, 0},
+			},
+			expectedPaths: map[string]string{
+				"root":   rootPath,
+				"folder": folderPath,
+				"sub":    subPath,
+				"new":    newPath,
+			},
+			expectedParents: map[string]string{
+				"file":    "folder",
+				"subfile": "sub",
+				"newfile": "new",
+			},
+		},
+		{
+			name: "folder moved out of scope",
+			items: []models.DriveItemable{
+				driveItemWithParent("folder", "renamed", "root", testBaseDrivePath, false),
+			},
+			scope: (&selectors.OneDriveBackup{}).Folders([]string{"/folder"}, selectors.PrefixMatch())[0],
+			expect: map[string]expectedCollection{
+				"folder": {data.DeletedState, folderPath, 0, 0},
+				"sub":    {data.DeletedState, subPath, 0, 0},
+			},
+			expectedPaths: map[string]string{
+				"root":   rootPath,
+				"folder": renamedPath,
+				"sub":    renamedSub,
+			},
+			expectedParents: oldParents,
+		},
+		{
+			name: "folder moved into scope",
+			items: []models.DriveItemable{
+				driveItemWithParent("folder", "renamed", "root", testBaseDrivePath, false),
+			},
+			scope: (&selectors.OneDriveBackup{}).Folders([]string{"/renamed"}, selectors.PrefixMatch())[0],
+			expect: map[string]expectedCollection{
+				renamedPath: {data.NewState, "", 1, 0},
+				renamedSub:  {data.NewState, "", 1, 0},
+			},
+			expectedPaths: map[string]string{
+				"root":   rootPath,
+				"folder": renamedPath,
+				"sub":    renamedSub,
+			},
+			expectedParents: oldParents,
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			ctx, flush := tester.NewContext()
+			defer flush()
+
+			c := NewCollections(
+				tenant,
+				user,
+				OneDriveSource,
+				testFolderMatcher{test.scope},
+				&MockGraphService{},
+				nil,
+				control.Options{})
+
+			c.childrenReader = func(
+				context.Context,
+				graph.Servicer,
+				string, string,
+			) ([]models.DriveItemable, error) {
+				return []models.DriveItemable{
+					driveItemWithParent("child-file", "child-file", "", "", true),
+					driveItemWithParent("child", "child", "", "", false),
+				}, nil
+			}
+
+			err := c.UpdateIncrementalCollections(ctx, driveID, oldPaths, oldParents, test.items)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedPaths, c.folderPaths[driveID], "folder paths")
+			assert.Equal(t, test.expectedParents, c.itemParents[driveID], "item parents")
+			require.Len(t, c.CollectionMap, len(test.expect), "collections")
+
+			for k, expect := range test.expect {
+				require.Contains(t, c.CollectionMap, k)
+
+				col := c.CollectionMap[k].(*Collection)
+				assert.Equal(t, expect.state, col.State(), "state")
+				assert.False(t, col.DoNotMergeItems(), "do not merge")
+				assert.Len(t, col.driveItems, expect.numFiles, "files")
+				assert.Len(t, col.removedItems, expect.numRemoved, "removed files")
+
+				if len(expect.prevPath) == 0 {
+					assert.Nil(t, col.PreviousPath(), "previous path")
+				} else {
+					require.NotNil(t, col.PreviousPath(), "previous path")
+					assert.Equal(t, expect.prevPath, col.PreviousPath().String(), "previous path")
+				}
+
+				if expect.state == data.DeletedState {
+					assert.Nil(t, col.FullPath(), "current path")
+				} else {
+					assert.Equal(t, k, col.FullPath().String(), "current path")
+				}
+			}
+		})
+	}
+}
+
+func (suite *OneDriveCollectionsSuite) TestDeserializeMetadata() {
+	var (
+		paths = map[string]map[string]string{
+			"drive1": {"folder": "prev-path"},
+			"drive2": {"folder": "prev-path"},
+		}
+		parents = map[string]map[string]string{
+			"drive1": {"file": "folder"},
+			"drive2": {},
+		}
+		keys = map[string]string{
+			"drive1": itemKeysByID,
+			"drive2": itemKeysByID,
+		}
+		versions = map[string]bool{
+			"drive1": true,
+			"drive2": false,
+		}
+		empty = PrevMetadata{
+			deltas:   map[string]string{},
+			paths:    map[string]map[string]string{},
+			parents:  map[string]map[string]string{},
+			versions: map[string]bool{},
+		}
+		// drives that are enumerated in full keep their paths, so that they
+		// can be tombstoned if they've been deleted.
+		pathsOnly = PrevMetadata{
+			deltas:   map[string]string{},
+			paths:    paths,
+			parents:  map[string]map[string]string{},
+			versions: map[string]bool{},
+		}
+	)
+
+	table := []struct {
+		name    string
+		entries []graph.MetadataCollectionEntry
+		expect  PrevMetadata
+	}{
+		{
+			name: "delta urls, previous paths, and item parents",
+			entries: []graph.MetadataCollectionEntry{
+				graph.NewMetadataEntry(graph.DeltaURLsFileName, map[string]string{
+					"drive1": "delta-link",
+					"drive2": "delta-link",
+				}),
+				graph.NewMetadataEntry(graph.PreviousPathFileName, paths),
+				graph.NewMetadataEntry(graph.ItemParentsFileName, parents),
+				graph.NewMetadataEntry(graph.ItemKeysFileName, keys),
+			},
+			expect: PrevMetadata{
+				deltas:   map[string]string{"drive1": "delta-link", "drive2": "delta-link"},
+				paths:    paths,
+				parents:  parents,
+				versions: map[string]bool{},
+			},
+		},
+		{
+			name: "with versions",
+			entries: []graph.MetadataCollectionEntry{
+				graph.NewMetadataEntry(graph.DeltaURLsFileName, map[string]string{
+					"drive1": "delta-link",
+					"drive2": "delta-link",
+				}),
+				graph.NewMetadataEntry(graph.PreviousPathFileName, paths),
+				graph.NewMetadataEntry(graph.ItemParentsFileName, parents),
+				graph.NewMetadataEntry(graph.ItemKeysFileName, keys),
+				graph.NewMetadataEntry(graph.VersionsFileName, versions),
+			},
+			expect: PrevMetadata{
+				deltas:   map[string]string{"drive1": "delta-link", "drive2": "delta-link"},
+				paths:    paths,
+				parents:  parents,
+				versions: versions,
+			},
+		},
+		{
+			name: "items keyed by name",
+			entries: []graph.MetadataCollectionEntry{
+				graph.NewMetadataEntry(graph.DeltaURLsFileName, map[string]string{"drive1": "delta-link"}),
+				graph.NewMetadataEntry(graph.PreviousPathFileName, paths),
+				graph.NewMetadataEntry(graph.ItemParentsFileName, parents),
+			},
+			expect: pathsOnly,
+		},
+		{
+			name: "one drive keyed by name",
+			entries: []graph.MetadataCollectionEntry{
+				graph.NewMetadataEntry(graph.DeltaURLsFileName, map[string]string{
+					"drive1": "delta-link",
+					"drive2": "delta-link",
+				}),
+				graph.NewMetadataEntry(graph.PreviousPathFileName, paths),
+				graph.NewMetadataEntry(graph.ItemParentsFileName, parents),
+				graph.NewMetadataEntry(graph.ItemKeysFileName, map[string]string{"drive2": itemKeysByID}),
+			},
+			expect: PrevMetadata{
+				deltas:   map[string]string{"drive2": "delta-link"},
+				paths:    paths,
+				parents:  map[string]map[string]string{"drive2": parents["drive2"]},
+				versions: map[string]bool{},
+			},
+		},
+		{
+			name: "delta urls only",
+			entries: []graph.MetadataCollectionEntry{
+				graph.NewMetadataEntry(graph.DeltaURLsFileName, map[string]string{"drive1": "delta-link"}),
+			},
+			expect: empty,
+		},
+		{
+			name: "previous paths only",
+			entries: []graph.MetadataCollectionEntry{
+				graph.NewMetadataEntry(graph.PreviousPathFileName, paths),
+			},
+			expect: pathsOnly,
+		},
+		{
+			name: "no item parents",
+			entries: []graph.MetadataCollectionEntry{
+				graph.NewMetadataEntry(graph.DeltaURLsFileName, map[string]string{"drive1": "delta-link"}),
+				graph.NewMetadataEntry(graph.PreviousPathFileName, paths),
+			},
+			expect: pathsOnly,
+		},
+		{
+			name: "empty delta url",
+			entries: []graph.MetadataCollectionEntry{
+				graph.NewMetadataEntry(graph.DeltaURLsFileName, map[string]string{"drive1": ""}),
+				graph.NewMetadataEntry(graph.PreviousPathFileName, paths),
+				graph.NewMetadataEntry(graph.ItemParentsFileName, parents),
+				graph.NewMetadataEntry(graph.ItemKeysFileName, keys),
+			},
+			expect: pathsOnly,
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			ctx, flush := tester.NewContext()
+			defer flush()
+
+			coll, err := graph.MakeMetadataCollection(
+				"t", "u",
+				path.OneDriveService,
+				path.FilesCategory,
+				test.entries,
+				func(cos *support.ConnectorOperationStatus) {},
+			)
+			require.NoError(t, err)
+
+			prev, err := DeserializeMetadata(ctx, []data.Collection{coll})
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expect, prev)
+		})
+	}
+}
+
+func (suite *OneDriveCollectionsSuite) TestPrevMetadataDelta() {
+	prev := PrevMetadata{
+		deltas: map[string]string{
+			"drive1": "delta-link",
+			"drive2": "delta-link",
+		},
+		versions: map[string]bool{"drive1": true},
+	}
+
+	table := []struct {
+		name           string
+		driveID        string
+		backupVersions bool
+		expect         string
+	}{
+		{
+			name:           "versions backed up both times",
+			driveID:        "drive1",
+			backupVersions: true,
+			expect:         "delta-link",
+		},
+		{
+			name:           "versions no longer backed up",
+			driveID:        "drive1",
+			backupVersions: false,
+		},
+		{
+			name:           "versions not backed up either time",
+			driveID:        "drive2",
+			backupVersions: false,
+			expect:         "delta-link",
+		},
+		{
+			name:           "versions newly backed up",
+			driveID:        "drive2",
+			backupVersions: true,
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expect, prev.delta(test.driveID, test.backupVersions))
+		})
+	}
+}
+
 func driveItem(name string, path string, isFile, isFolder, isPackage bool) models.DriveItemable {
 	item := models.NewDriveItem()
 	item.SetName(&name)
@@ -298,3 +799,90 @@ func driveItem(name string, path string, isFile, isFolder, isPackage bool) model
 
 	return item
 }
+
+func driveItemWithParent(id, name, parentID, parentPath string, isFile bool) models.DriveItemable {
+	item := models.NewDriveItem()
+	item.SetName(&name)
+	item.SetId(&id)
+
+	parentReference := models.NewItemReference()
+
+	if len(parentID) > 0 {
+		parentReference.SetId(&parentID)
+	}
+
+	if len(parentPath) > 0 {
+		parentReference.SetPath(&parentPath)
+	}
+
+	item.SetParentReference(parentReference)
+
+	if isFile {
+		item.SetFile(models.NewFile())
+	} else {
+		item.SetFolder(models.NewFolder())
+	}
+
+	return item
+}
+
+func deletedItem(id, parentID string) models.DriveItemable {
+	item := models.NewDriveItem()
+	item.SetId(&id)
+	item.SetDeleted(models.NewDeleted())
+
+	parentReference := models.NewItemReference()
+	parentReference.SetId(&parentID)
+	item.SetParentReference(parentReference)
+
+	return item
+}
+
+func (suite *OneDriveCollectionsSuite) TestAddDeletedDrives() {
+	var (
+		t         = suite.T()
+		tenant    = "tenant"
+		user      = "user"
+		anyFolder = (&selectors.OneDriveBackup{}).Folders(selectors.Any())[0]
+	)
+
+	drive := func(id string) models.Driveable {
+		d := models.NewDrive()
+		d.SetId(&id)
+
+		return d
+	}
+
+	paths := func(driveID string) map[string]string {
+		return map[string]string{
+			"root":   expectedPathAsSlice(t, tenant, user, "drive/"+driveID+"/root:")[0],
+			"folder": expectedPathAsSlice(t, tenant, user, "drive/"+driveID+"/root:/folder")[0],
+		}
+	}
+
+	c := NewCollections(
+		tenant,
+		user,
+		OneDriveSource,
+		testFolderMatcher{anyFolder},
+		&MockGraphService{},
+		nil,
+		control.Options{})
+
+	err := c.addDeletedDrives(
+		[]models.Driveable{drive("kept")},
+		map[string]map[string]string{
+			"kept":    paths("kept"),
+			"deleted": paths("deleted"),
+			"empty":   {},
+		})
+	require.NoError(t, err)
+
+	require.Len(t, c.CollectionMap, 1)
+
+	col := c.CollectionMap["deleted"]
+	require.NotNil(t, col)
+	assert.Equal(t, data.DeletedState, col.State())
+	assert.Nil(t, col.FullPath())
+	assert.Equal(t, paths("deleted")["root"], col.PreviousPath().String())
+}
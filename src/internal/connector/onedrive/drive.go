@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	abstractions "github.com/microsoft/kiota-abstractions-go"
 	msgraphgocore "github.com/microsoftgraph/msgraph-sdk-go-core"
 	msdrive "github.com/microsoftgraph/msgraph-sdk-go/drive"
 	msdrives "github.com/microsoftgraph/msgraph-sdk-go/drives"
@@ -16,11 +17,14 @@ import (
 
 	"github.com/alcionai/corso/src/internal/connector/graph"
 	"github.com/alcionai/corso/src/internal/connector/support"
+	"github.com/alcionai/corso/src/pkg/control"
 	"github.com/alcionai/corso/src/pkg/logger"
+	"github.com/alcionai/corso/src/pkg/path"
 )
 
 var (
 	errFolderNotFound = errors.New("folder not found")
+	errItemNotFound   = errors.New("item not found")
 
 	// nolint:lll
 	// OneDrive associated SKUs located at:
@@ -164,13 +168,15 @@ func userDrives(ctx context.Context, service graph.Servicer, user string) ([]mod
 type itemCollector func(ctx context.Context, driveID string, driveItems []models.DriveItemable) error
 
 // collectItems will enumerate all items in the specified drive and hand them to the
-// provided `collector` method
+// provided `collector` method.  If prevDelta is populated, only the items changed
+// since that delta link was produced are enumerated.  Returns the delta link that
+// can be used to enumerate the changes made after this call.
 func collectItems(
 	ctx context.Context,
 	service graph.Servicer,
-	driveID string,
+	driveID, prevDelta string,
 	collector itemCollector,
-) error {
+) (string, error) {
 	// TODO: Specify a timestamp in the delta query
 	// https://docs.microsoft.com/en-us/graph/api/driveitem-delta?
 	// view=graph-rest-1.0&tabs=http#example-4-retrieving-delta-results-using-a-timestamp
@@ -180,6 +186,7 @@ func collectItems(
 		"content.downloadUrl",
 		"createdBy",
 		"createdDateTime",
+		"deleted",
 		"file",
 		"folder",
 		"id",
@@ -188,19 +195,37 @@ func collectItems(
 		"package",
 		"parentReference",
 		"root",
+		"shared",
 		"size",
 	}
+	// Items whose sharing changes are only reported if asked for, and the
+	// permission sidecars of the unreported items are carried over from the
+	// previous backup.  hierarchicalsharing limits the shared facet to the
+	// items that are shared directly, which are the only ones whose
+	// permissions are backed up.
+	headers := abstractions.NewRequestHeaders()
+	headers.Add("Prefer", "deltashowsharingchanges", "hierarchicalsharing")
+
 	requestConfig := &msdrives.ItemRootDeltaRequestBuilderGetRequestConfiguration{
+		Headers: headers,
 		QueryParameters: &msdrives.ItemRootDeltaRequestBuilderGetQueryParameters{
 			Top:    &pageCount,
 			Select: requestFields,
 		},
 	}
 
+	if len(prevDelta) > 0 {
+		builder = msdrives.NewItemRootDeltaRequestBuilder(prevDelta, service.Adapter())
+	}
+
 	for {
 		r, err := builder.Get(ctx, requestConfig)
 		if err != nil {
-			return errors.Wrapf(
+			if err := graph.IsErrInvalidDelta(err); err != nil {
+				return "", err
+			}
+
+			return "", errors.Wrapf(
 				err,
 				"failed to query drive items. details: %s",
 				support.ConnectorStackErrorTrace(err),
@@ -209,7 +234,12 @@ func collectItems(
 
 		err = collector(ctx, driveID, r.GetValue())
 		if err != nil {
-			return err
+			return "", err
+		}
+
+		// The delta link is only returned on the last page.
+		if dl := r.GetOdataDeltaLink(); dl != nil && len(*dl) > 0 {
+			return *dl, nil
 		}
 
 		// Check if there are more items
@@ -222,7 +252,42 @@ func collectItems(
 		builder = msdrives.NewItemRootDeltaRequestBuilder(*nextLink, service.Adapter())
 	}
 
-	return nil
+	return "", nil
+}
+
+// folderChildren returns the items directly within the specified folder.
+func folderChildren(
+	ctx context.Context,
+	service graph.Servicer,
+	driveID, folderID string,
+) ([]models.DriveItemable, error) {
+	var (
+		children []models.DriveItemable
+		builder  = service.Client().DrivesById(driveID).ItemsById(folderID).Children()
+	)
+
+	for {
+		r, err := builder.Get(ctx, nil)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err,
+				"failed to list children of folder %s. details: %s",
+				folderID,
+				support.ConnectorStackErrorTrace(err),
+			)
+		}
+
+		children = append(children, r.GetValue()...)
+
+		nextLink := r.GetOdataNextLink()
+		if nextLink == nil || len(*nextLink) == 0 {
+			break
+		}
+
+		builder = msdrives.NewItemItemsItemChildrenRequestBuilder(*nextLink, service.Adapter())
+	}
+
+	return children, nil
 }
 
 // getFolder will lookup the specified folder name under `parentFolderID`
@@ -231,12 +296,36 @@ func getFolder(
 	service graph.Servicer,
 	driveID, parentFolderID, folderName string,
 ) (models.DriveItemable, error) {
-	// The `Children().Get()` API doesn't yet support $filter, so using that to find a folder
+	foundItem, err := getItemByName(ctx, service, driveID, parentFolderID, folderName)
+	if err != nil {
+		if errors.Is(err, errItemNotFound) {
+			return nil, errors.WithStack(errFolderNotFound)
+		}
+
+		return nil, err
+	}
+
+	// Check if the item found is a folder, fail the call if not
+	if foundItem.GetFolder() == nil {
+		return nil, errors.WithStack(errFolderNotFound)
+	}
+
+	return foundItem, nil
+}
+
+// getItemByName looks up the item with the given name within the parent
+// folder.  Returns errItemNotFound if no such item exists.
+func getItemByName(
+	ctx context.Context,
+	service graph.Servicer,
+	driveID, parentFolderID, itemName string,
+) (models.DriveItemable, error) {
+	// The `Children().Get()` API doesn't yet support $filter, so using that to find an item
 	// will be sub-optimal.
 	// Instead, we leverage OneDrive path-based addressing -
 	// https://learn.microsoft.com/en-us/graph/onedrive-addressing-driveitems#path-based-addressing
 	// - which allows us to lookup an item by its path relative to the parent ID
-	rawURL := fmt.Sprintf(itemByPathRawURLFmt, driveID, parentFolderID, folderName)
+	rawURL := fmt.Sprintf(itemByPathRawURLFmt, driveID, parentFolderID, itemName)
 	builder := msdrive.NewItemsDriveItemItemRequestBuilder(rawURL, service.Adapter())
 
 	foundItem, err := builder.Get(ctx, nil)
@@ -246,22 +335,17 @@ func getFolder(
 			oDataError.GetError() != nil &&
 			oDataError.GetError().GetCode() != nil &&
 			*oDataError.GetError().GetCode() == itemNotFoundErrorCode {
-			return nil, errors.WithStack(errFolderNotFound)
+			return nil, errors.WithStack(errItemNotFound)
 		}
 
 		return nil, errors.Wrapf(err,
-			"failed to get folder %s/%s. details: %s",
+			"failed to get item %s/%s. details: %s",
 			parentFolderID,
-			folderName,
+			itemName,
 			support.ConnectorStackErrorTrace(err),
 		)
 	}
 
-	// Check if the item found is a folder, fail the call if not
-	if foundItem.GetFolder() == nil {
-		return nil, errors.WithStack(errFolderNotFound)
-	}
-
 	return foundItem, nil
 }
 
@@ -290,6 +374,56 @@ func createItem(
 	return newItem, nil
 }
 
+// defaultDriveID returns the ID of the default drive belonging to the
+// resource owner, which is a user for OneDrive, or a site for SharePoint.
+func defaultDriveID(
+	ctx context.Context,
+	service graph.Servicer,
+	srv path.ServiceType,
+	owner string,
+) (string, error) {
+	var (
+		d   models.Driveable
+		err error
+	)
+
+	switch srv {
+	case path.SharePointService:
+		d, err = service.Client().SitesById(owner).Drive().Get(ctx, nil)
+	default:
+		d, err = service.Client().UsersById(owner).Drive().Get(ctx, nil)
+	}
+
+	if err != nil {
+		return "", errors.Wrapf(
+			err,
+			"failed to get default drive for %s. details: %s",
+			owner,
+			support.ConnectorStackErrorTrace(err),
+		)
+	}
+
+	if d.GetId() == nil {
+		return "", errors.Errorf("default drive for %s has no ID", owner)
+	}
+
+	return *d.GetId(), nil
+}
+
+// conflictBehavior maps the restore collision policy to the graph
+// conflict behavior applied when creating an item whose name is already
+// in use within the parent folder.
+func conflictBehavior(cp control.CollisionPolicy) string {
+	switch cp {
+	case control.Replace:
+		return "replace"
+	case control.Skip:
+		return "fail"
+	default:
+		return "rename"
+	}
+}
+
 // newItem initializes a `models.DriveItemable` that can be used as input to `createItem`
 func newItem(name string, folder bool) models.DriveItemable {
 	itemToCreate := models.NewDriveItem()
@@ -329,10 +463,11 @@ func GetAllFolders(
 	folders := map[string]*Displayable{}
 
 	for _, d := range drives {
-		err = collectItems(
+		_, err = collectItems(
 			ctx,
 			gs,
 			*d.GetId(),
+			"",
 			func(innerCtx context.Context, driveID string, items []models.DriveItemable) error {
 				for _, item := range items {
 					// Skip the root item.
@@ -1,20 +1,90 @@
 package onedrive
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 
+	"github.com/microsoft/kiota-abstractions-go/authentication"
+	msgraphsdk "github.com/microsoftgraph/msgraph-sdk-go"
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/alcionai/corso/src/internal/common"
+	"github.com/alcionai/corso/src/internal/connector/graph"
+	"github.com/alcionai/corso/src/internal/data"
 	"github.com/alcionai/corso/src/internal/tester"
 	"github.com/alcionai/corso/src/pkg/control"
 	"github.com/alcionai/corso/src/pkg/logger"
 	"github.com/alcionai/corso/src/pkg/selectors"
 )
 
+// redirectTransport sends all requests to the test server.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = rt.target.Scheme
+	req.URL.Host = rt.target.Host
+	req.Host = rt.target.Host
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+type OneDriveUnitSuite struct {
+	suite.Suite
+}
+
+func TestOneDriveUnitSuite(t *testing.T) {
+	suite.Run(t, new(OneDriveUnitSuite))
+}
+
+func (suite *OneDriveUnitSuite) TestCollectItems_PrefersSharingChanges() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+
+	var prefer []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prefer = r.Header.Values("Prefer")
+
+		w.Header().Set("Content-Type", "application/json")
+
+		_, err := w.Write([]byte(`{"value": [], "@odata.deltaLink": "https://graph.microsoft.com/delta"}`))
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	target, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+
+	adpt, err := msgraphsdk.NewGraphRequestAdapterWithParseNodeFactoryAndSerializationWriterFactoryAndHttpClient(
+		&authentication.AnonymousAuthenticationProvider{},
+		nil,
+		nil,
+		&http.Client{Transport: redirectTransport{target}})
+	require.NoError(t, err)
+
+	delta, err := collectItems(
+		ctx,
+		graph.NewService(adpt),
+		"drive",
+		"",
+		func(context.Context, string, []models.DriveItemable) error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, "https://graph.microsoft.com/delta", delta)
+	assert.ElementsMatch(t, []string{"deltashowsharingchanges", "hierarchicalsharing"}, prefer)
+}
+
 type OneDriveSuite struct {
 	suite.Suite
 	userID string
@@ -59,7 +129,7 @@ func (suite *OneDriveSuite) TestCreateGetDeleteFolder() {
 		}
 	}()
 
-	folderID, err := CreateRestoreFolders(ctx, gs, driveID, folderElements)
+	folderID, err := CreateRestoreFolders(ctx, gs, driveID, folderElements, nil)
 	require.NoError(t, err)
 
 	folderIDs = append(folderIDs, folderID)
@@ -67,7 +137,7 @@ func (suite *OneDriveSuite) TestCreateGetDeleteFolder() {
 	folderName2 := "Corso_Folder_Test_" + common.FormatNow(common.SimpleTimeTesting)
 	folderElements = append(folderElements, folderName2)
 
-	folderID, err = CreateRestoreFolders(ctx, gs, driveID, folderElements)
+	folderID, err = CreateRestoreFolders(ctx, gs, driveID, folderElements, nil)
 	require.NoError(t, err)
 
 	folderIDs = append(folderIDs, folderID)
@@ -153,10 +223,16 @@ func (suite *OneDriveSuite) TestOneDriveNewCollections() {
 				service,
 				service.updateStatus,
 				control.Options{},
-			).Get(ctx)
+			).Get(ctx, PrevMetadata{})
 			assert.NoError(t, err)
 
 			for _, entry := range odcs {
+				// deleted collections only have a previous path.
+				if entry.State() == data.DeletedState {
+					assert.NotEmpty(t, entry.PreviousPath())
+					continue
+				}
+
 				assert.NotEmpty(t, entry.FullPath())
 			}
 		})
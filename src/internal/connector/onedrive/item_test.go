@@ -105,7 +105,7 @@ func (suite *ItemIntegrationSuite) TestItemReader_oneDrive() {
 
 		return nil
 	}
-	err := collectItems(ctx, suite, suite.userDriveID, itemCollector)
+	_, err := collectItems(ctx, suite, suite.userDriveID, "", itemCollector)
 	require.NoError(suite.T(), err)
 
 	// Test Requirement 2: Need a file
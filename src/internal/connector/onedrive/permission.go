@@ -0,0 +1,356 @@
+package onedrive
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	msdrives "github.com/microsoftgraph/msgraph-sdk-go/drives"
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/internal/connector/graph"
+	"github.com/alcionai/corso/src/internal/connector/support"
+	"github.com/alcionai/corso/src/internal/data"
+	"github.com/alcionai/corso/src/pkg/logger"
+)
+
+// MetaFileSuffix is appended to the key of a drive item to produce the name
+// of the sidecar item that holds the item's metadata.  Drive item IDs and
+// names can't contain a colon, so the sidecar never collides with a real item.
+const MetaFileSuffix = ":meta"
+
+// FolderMetaFileName is the name of the sidecar item that holds the metadata
+// of the folder it is stored in.
+const FolderMetaFileName = ":folder" + MetaFileSuffix
+
+const ownerRole = "owner"
+
+var _ data.Stream = &metadataItem{}
+
+// UserPermission is a single permission granted directly on a drive item.
+// Permissions are either granted to a user or group, identified by Email or
+// EntityID, or are sharing links, identified by LinkType.
+type UserPermission struct {
+	ID         string     `json:"id,omitempty"`
+	Roles      []string   `json:"roles,omitempty"`
+	Email      string     `json:"email,omitempty"`
+	EntityID   string     `json:"entityId,omitempty"`
+	LinkType   string     `json:"linkType,omitempty"`
+	LinkScope  string     `json:"linkScope,omitempty"`
+	Expiration *time.Time `json:"expiration,omitempty"`
+}
+
+// Metadata is the content of the sidecar item stored alongside each drive
+// item that has metadata worth preserving.
+type Metadata struct {
+	Permissions []UserPermission `json:"permissions,omitempty"`
+}
+
+// metadataItem is the sidecar stream for a drive item.  It purposefully does
+// not implement data.StreamInfo, so that it is not added to backup details.
+type metadataItem struct {
+	id   string
+	data io.ReadCloser
+
+	// true if the sidecar is removed, as its item was removed or no longer
+	// has metadata.
+	deleted bool
+}
+
+func (mi *metadataItem) UUID() string {
+	return mi.id
+}
+
+func (mi *metadataItem) ToReader() io.ReadCloser {
+	return mi.data
+}
+
+func (mi *metadataItem) Deleted() bool {
+	return mi.deleted
+}
+
+// IsMetaFile returns true if the item name belongs to a metadata sidecar.
+func IsMetaFile(name string) bool {
+	return strings.HasSuffix(name, MetaFileSuffix)
+}
+
+// permissionsReaderFunc returns the permissions granted directly on an item.
+type permissionsReaderFunc func(
+	ctx context.Context,
+	service graph.Servicer,
+	driveID string,
+	item models.DriveItemable,
+) ([]UserPermission, error)
+
+// driveItemPermissions retrieves the permissions of the item.  Permissions
+// inherited from a parent folder are skipped, since restoring the parent
+// recreates them.  Items without the shared facet have never been shared, so
+// their permissions aren't requested.
+func driveItemPermissions(
+	ctx context.Context,
+	service graph.Servicer,
+	driveID string,
+	item models.DriveItemable,
+) ([]UserPermission, error) {
+	if item.GetShared() == nil {
+		return nil, nil
+	}
+
+	var (
+		perms   []UserPermission
+		builder = service.Client().DrivesById(driveID).ItemsById(*item.GetId()).Permissions()
+	)
+
+	for {
+		r, err := builder.Get(ctx, nil)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err,
+				"failed to get permissions of item %s. details: %s",
+				*item.GetId(),
+				support.ConnectorStackErrorTrace(err),
+			)
+		}
+
+		for _, p := range r.GetValue() {
+			if p.GetInheritedFrom() != nil {
+				continue
+			}
+
+			perms = append(perms, toUserPermission(p))
+		}
+
+		nextLink := r.GetOdataNextLink()
+		if nextLink == nil || len(*nextLink) == 0 {
+			break
+		}
+
+		builder = msdrives.NewItemItemsItemPermissionsRequestBuilder(*nextLink, service.Adapter())
+	}
+
+	return perms, nil
+}
+
+func toUserPermission(p models.Permissionable) UserPermission {
+	up := UserPermission{
+		ID:         strValue(p.GetId()),
+		Roles:      p.GetRoles(),
+		Expiration: p.GetExpirationDateTime(),
+	}
+
+	if l := p.GetLink(); l != nil {
+		up.LinkType = strValue(l.GetType())
+		up.LinkScope = strValue(l.GetScope())
+
+		return up
+	}
+
+	var ident models.Identityable
+
+	if gt := p.GetGrantedToV2(); gt != nil {
+		switch {
+		case gt.GetUser() != nil:
+			ident = gt.GetUser()
+		case gt.GetGroup() != nil:
+			ident = gt.GetGroup()
+		case gt.GetSiteUser() != nil:
+			ident = gt.GetSiteUser()
+		}
+	}
+
+	if ident == nil && p.GetGrantedTo() != nil {
+		ident = p.GetGrantedTo().GetUser()
+	}
+
+	if ident != nil {
+		up.EntityID = strValue(ident.GetId())
+
+		if email, ok := ident.GetAdditionalData()["email"].(*string); ok {
+			up.Email = strValue(email)
+		}
+	}
+
+	if len(up.Email) == 0 && p.GetInvitation() != nil {
+		up.Email = strValue(p.GetInvitation().GetEmail())
+	}
+
+	return up
+}
+
+func strValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+// restorePermissions re-applies the permissions to the item.  The owner role
+// can't be granted, and is left to the restoring user.  Users and groups are
+// invited without sending them a notification, and sharing links are
+// recreated with their original type and scope.  principals maps the email
+// or ID of a backed up user onto the user that the permission is granted to
+// instead.
+func restorePermissions(
+	ctx context.Context,
+	service graph.Servicer,
+	driveID, itemID string,
+	perms []UserPermission,
+	principals map[string]string,
+) error {
+	var errs error
+
+	for _, p := range perms {
+		if isOwner(p) {
+			continue
+		}
+
+		var err error
+
+		if len(p.LinkType) > 0 {
+			err = createSharingLink(ctx, service, driveID, itemID, p)
+		} else {
+			err = invitePrincipal(ctx, service, driveID, itemID, p, principals)
+		}
+
+		if err != nil {
+			errs = support.WrapAndAppend(p.ID, err, errs)
+		}
+	}
+
+	return errs
+}
+
+func isOwner(p UserPermission) bool {
+	for _, r := range p.Roles {
+		if strings.EqualFold(r, ownerRole) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func createSharingLink(
+	ctx context.Context,
+	service graph.Servicer,
+	driveID, itemID string,
+	p UserPermission,
+) error {
+	body := msdrives.NewItemItemsItemCreateLinkPostRequestBody()
+	body.SetType(&p.LinkType)
+	body.SetExpirationDateTime(p.Expiration)
+
+	if len(p.LinkScope) > 0 {
+		body.SetScope(&p.LinkScope)
+	}
+
+	_, err := service.Client().DrivesById(driveID).ItemsById(itemID).CreateLink().Post(ctx, body, nil)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"failed to create %s sharing link. details: %s",
+			p.LinkType,
+			support.ConnectorStackErrorTrace(err),
+		)
+	}
+
+	return nil
+}
+
+func invitePrincipal(
+	ctx context.Context,
+	service graph.Servicer,
+	driveID, itemID string,
+	p UserPermission,
+	principals map[string]string,
+) error {
+	recipient := permissionRecipient(p, principals)
+	if recipient == nil {
+		logger.Ctx(ctx).Debugw("skipping permission without a grantee", "permission_id", p.ID)
+		return nil
+	}
+
+	var (
+		signIn = true
+		notify = false
+		body   = msdrives.NewItemItemsItemInvitePostRequestBody()
+	)
+
+	body.SetRecipients([]models.DriveRecipientable{recipient})
+	body.SetRoles(p.Roles)
+	body.SetRequireSignIn(&signIn)
+	body.SetSendInvitation(&notify)
+
+	if p.Expiration != nil {
+		exp := p.Expiration.UTC().Format(time.RFC3339)
+		body.SetExpirationDateTime(&exp)
+	}
+
+	_, err := service.Client().DrivesById(driveID).ItemsById(itemID).Invite().Post(ctx, body, nil)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"failed to grant %v on item %s. details: %s",
+			p.Roles,
+			itemID,
+			support.ConnectorStackErrorTrace(err),
+		)
+	}
+
+	return nil
+}
+
+// permissionRecipient produces the recipient of the permission, after
+// remapping the principal.  Mapped principals containing an @ are treated as
+// email addresses, and all others as object IDs.  Returns nil if the
+// permission has no grantee.
+func permissionRecipient(p UserPermission, principals map[string]string) models.DriveRecipientable {
+	email, id := p.Email, p.EntityID
+
+	for _, k := range []string{email, id} {
+		mapped, ok := principals[k]
+		if len(k) == 0 || !ok {
+			continue
+		}
+
+		email, id = "", ""
+
+		if strings.Contains(mapped, "@") {
+			email = mapped
+		} else {
+			id = mapped
+		}
+
+		break
+	}
+
+	dr := models.NewDriveRecipient()
+
+	switch {
+	case len(email) > 0:
+		dr.SetEmail(&email)
+	case len(id) > 0:
+		dr.SetObjectId(&id)
+	default:
+		return nil
+	}
+
+	return dr
+}
+
+// decodeMetadata reads the content of a metadata sidecar.
+func decodeMetadata(rc io.ReadCloser) (Metadata, error) {
+	defer rc.Close()
+
+	var meta Metadata
+
+	if err := json.NewDecoder(rc).Decode(&meta); err != nil {
+		return Metadata{}, errors.Wrap(err, "decoding item metadata")
+	}
+
+	return meta, nil
+}
@@ -0,0 +1,211 @@
+package onedrive
+
+import (
+	"testing"
+	"time"
+
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/tester"
+)
+
+type PermissionUnitSuite struct {
+	suite.Suite
+}
+
+func TestPermissionUnitSuite(t *testing.T) {
+	suite.Run(t, new(PermissionUnitSuite))
+}
+
+func identity(id, email string) models.Identityable {
+	ident := models.NewIdentity()
+	ident.SetId(&id)
+	ident.SetAdditionalData(map[string]any{"email": &email})
+
+	return ident
+}
+
+func (suite *PermissionUnitSuite) TestToUserPermission() {
+	var (
+		id     = "perm-id"
+		expiry = time.Now().UTC().Truncate(time.Second)
+	)
+
+	table := []struct {
+		name   string
+		perm   func() models.Permissionable
+		expect UserPermission
+	}{
+		{
+			name: "user",
+			perm: func() models.Permissionable {
+				gt := models.NewSharePointIdentitySet()
+				gt.SetUser(identity("user-id", "user@example.com"))
+
+				p := models.NewPermission()
+				p.SetId(&id)
+				p.SetRoles([]string{"write"})
+				p.SetGrantedToV2(gt)
+
+				return p
+			},
+			expect: UserPermission{
+				ID:       id,
+				Roles:    []string{"write"},
+				Email:    "user@example.com",
+				EntityID: "user-id",
+			},
+		},
+		{
+			name: "group",
+			perm: func() models.Permissionable {
+				gt := models.NewSharePointIdentitySet()
+				gt.SetGroup(identity("group-id", "group@example.com"))
+
+				p := models.NewPermission()
+				p.SetId(&id)
+				p.SetRoles([]string{"read"})
+				p.SetGrantedToV2(gt)
+
+				return p
+			},
+			expect: UserPermission{
+				ID:       id,
+				Roles:    []string{"read"},
+				Email:    "group@example.com",
+				EntityID: "group-id",
+			},
+		},
+		{
+			name: "legacy grantee",
+			perm: func() models.Permissionable {
+				gt := models.NewIdentitySet()
+				gt.SetUser(identity("user-id", "user@example.com"))
+
+				p := models.NewPermission()
+				p.SetId(&id)
+				p.SetRoles([]string{"read"})
+				p.SetGrantedTo(gt)
+
+				return p
+			},
+			expect: UserPermission{
+				ID:       id,
+				Roles:    []string{"read"},
+				Email:    "user@example.com",
+				EntityID: "user-id",
+			},
+		},
+		{
+			name: "sharing link",
+			perm: func() models.Permissionable {
+				var (
+					lt    = "edit"
+					scope = "organization"
+					l     = models.NewSharingLink()
+				)
+
+				l.SetType(&lt)
+				l.SetScope(&scope)
+
+				p := models.NewPermission()
+				p.SetId(&id)
+				p.SetRoles([]string{"write"})
+				p.SetLink(l)
+				p.SetExpirationDateTime(&expiry)
+
+				return p
+			},
+			expect: UserPermission{
+				ID:         id,
+				Roles:      []string{"write"},
+				LinkType:   "edit",
+				LinkScope:  "organization",
+				Expiration: &expiry,
+			},
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expect, toUserPermission(test.perm()))
+		})
+	}
+}
+
+func (suite *PermissionUnitSuite) TestPermissionRecipient() {
+	perm := UserPermission{Email: "old@example.com", EntityID: "old-id"}
+
+	table := []struct {
+		name        string
+		perm        UserPermission
+		principals  map[string]string
+		expectEmail string
+		expectID    string
+		expectNil   bool
+	}{
+		{
+			name:        "no mapping",
+			perm:        perm,
+			expectEmail: "old@example.com",
+		},
+		{
+			name:     "no email",
+			perm:     UserPermission{EntityID: "old-id"},
+			expectID: "old-id",
+		},
+		{
+			name:        "mapped by email",
+			perm:        perm,
+			principals:  map[string]string{"old@example.com": "new@example.com"},
+			expectEmail: "new@example.com",
+		},
+		{
+			name:       "mapped by id onto an id",
+			perm:       perm,
+			principals: map[string]string{"old-id": "new-id"},
+			expectID:   "new-id",
+		},
+		{
+			name:        "unrelated mapping",
+			perm:        perm,
+			principals:  map[string]string{"other@example.com": "new@example.com"},
+			expectEmail: "old@example.com",
+		},
+		{
+			name:      "no grantee",
+			perm:      UserPermission{Roles: []string{"read"}},
+			expectNil: true,
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			dr := permissionRecipient(test.perm, test.principals)
+			if test.expectNil {
+				assert.Nil(t, dr)
+				return
+			}
+
+			require.NotNil(t, dr)
+			assert.Equal(t, test.expectEmail, strValue(dr.GetEmail()))
+			assert.Equal(t, test.expectID, strValue(dr.GetObjectId()))
+		})
+	}
+}
+
+func (suite *PermissionUnitSuite) TestIsOwner() {
+	assert.True(suite.T(), isOwner(UserPermission{Roles: []string{"Owner"}}))
+	assert.False(suite.T(), isOwner(UserPermission{Roles: []string{"read", "write"}}))
+}
+
+func (suite *PermissionUnitSuite) TestDriveItemPermissions_NotShared() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	// items that were never shared are skipped without querying graph.
+	perms, err := driveItemPermissions(ctx, nil, "drive-id", models.NewDriveItem())
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), perms)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"runtime/trace"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -25,17 +26,22 @@ const (
 	copyBufferSize = 5 * 1024 * 1024
 )
 
+const conflictBehaviorKey = "@microsoft.graph.conflictBehavior"
+
 // RestoreCollections will restore the specified data collections into OneDrive
 func RestoreCollections(
 	ctx context.Context,
 	service graph.Servicer,
 	dest control.RestoreDestination,
+	opts control.Options,
 	dcs []data.Collection,
 	deets *details.Builder,
 ) (*support.ConnectorOperationStatus, error) {
 	var (
 		restoreMetrics support.CollectionMetrics
 		restoreErrors  error
+		// IDs of the folders created by the restore
+		createdFolders = map[string]struct{}{}
 	)
 
 	errUpdater := func(id string, err error) {
@@ -44,7 +50,16 @@ func RestoreCollections(
 
 	// Iterate through the data collections and restore the contents of each
 	for _, dc := range dcs {
-		temp, canceled := RestoreCollection(ctx, service, dc, OneDriveSource, dest.ContainerName, deets, errUpdater)
+		temp, canceled := RestoreCollection(
+			ctx,
+			service,
+			dc,
+			OneDriveSource,
+			dest,
+			opts,
+			deets,
+			createdFolders,
+			errUpdater)
 
 		restoreMetrics.Combine(temp)
 
@@ -63,7 +78,14 @@ func RestoreCollections(
 		nil
 }
 
-// RestoreCollection handles restoration of an individual collection.
+// RestoreCollection handles restoration of an individual collection.  Items
+// are restored into the drive of the collection's resource owner, unless the
+// destination overrides the owner, in which case they are restored into the
+// default drive of the overriding user or site.  Once all items are restored,
+// the permissions in their metadata sidecars are re-applied to them, unless
+// the options skip permissions.  The permissions of the collection's folder
+// are only applied if the folder is created by the restore, which is tracked
+// across collections by createdFolders.
 // returns:
 // - the collection's item and byte count metrics
 // - the context cancellation state (true if the context is cancelled)
@@ -72,8 +94,10 @@ func RestoreCollection(
 	service graph.Servicer,
 	dc data.Collection,
 	source driveSource,
-	restoreContainerName string,
+	dest control.RestoreDestination,
+	opts control.Options,
 	deets *details.Builder,
+	createdFolders map[string]struct{},
 	errUpdater func(string, error),
 ) (support.CollectionMetrics, bool) {
 	ctx, end := D.Span(ctx, "gc:oneDrive:restoreCollection", D.Label("path", dc.FullPath()))
@@ -83,6 +107,12 @@ func RestoreCollection(
 		metrics    = support.CollectionMetrics{}
 		copyBuffer = make([]byte, copyBufferSize)
 		directory  = dc.FullPath()
+		// item key -> ID of the restored item
+		restoredIDs = map[string]string{}
+		// item key -> metadata of the item
+		itemMeta = map[string]Metadata{}
+		// metadata of the collection's folder, if it has any
+		folderMeta *Metadata
 	)
 
 	drivePath, err := path.ToOneDrivePath(directory)
@@ -94,18 +124,35 @@ func RestoreCollection(
 	// Assemble folder hierarchy we're going to restore into (we recreate the folder hierarchy
 	// from the backup under this the restore folder instead of root)
 	// i.e. Restore into `<drive>/root:/<restoreContainerName>/<original folder path>`
+	// If no container name is provided, the items are restored in place, within
+	// their original folder path.
+	restoreFolderElements := []string{}
+	if len(dest.ContainerName) > 0 {
+		restoreFolderElements = append(restoreFolderElements, dest.ContainerName)
+	}
 
-	restoreFolderElements := []string{restoreContainerName}
 	restoreFolderElements = append(restoreFolderElements, drivePath.Folders...)
 
+	driveID := drivePath.DriveID
+	owner := dest.RestoreOwner(directory.ResourceOwner())
+
+	if owner != directory.ResourceOwner() {
+		driveID, err = defaultDriveID(ctx, service, directory.Service(), owner)
+		if err != nil {
+			errUpdater(directory.String(), err)
+			return metrics, false
+		}
+	}
+
 	trace.Log(ctx, "gc:oneDrive:restoreCollection", directory.String())
 	logger.Ctx(ctx).Infow(
 		"restoring to destination",
 		"origin", dc.FullPath().Folder(),
-		"destination", restoreFolderElements)
+		"destination", restoreFolderElements,
+		"resource_owner", owner)
 
 	// Create restore folders and get the folder ID of the folder the data stream will be restored in
-	restoreFolderID, err := CreateRestoreFolders(ctx, service, drivePath.DriveID, restoreFolderElements)
+	restoreFolderID, err := CreateRestoreFolders(ctx, service, driveID, restoreFolderElements, createdFolders)
 	if err != nil {
 		errUpdater(directory.String(), errors.Wrapf(err, "failed to create folders %v", restoreFolderElements))
 		return metrics, false
@@ -122,25 +169,68 @@ func RestoreCollection(
 
 		case itemData, ok := <-items:
 			if !ok {
+				if !opts.Permissions.Skip {
+					restoreFolderPermissions(ctx, service, driveID, restoreFolderID, folderMeta, createdFolders, opts, errUpdater)
+					restoreItemsPermissions(ctx, service, driveID, restoredIDs, itemMeta, opts, errUpdater)
+				}
+
 				return metrics, false
 			}
+
+			if itemData.UUID() == FolderMetaFileName {
+				meta, err := decodeMetadata(itemData.ToReader())
+				if err != nil {
+					errUpdater(itemData.UUID(), err)
+					continue
+				}
+
+				folderMeta = &meta
+
+				continue
+			}
+
+			if IsMetaFile(itemData.UUID()) {
+				meta, err := decodeMetadata(itemData.ToReader())
+				if err != nil {
+					errUpdater(itemData.UUID(), err)
+					continue
+				}
+
+				itemMeta[strings.TrimSuffix(itemData.UUID(), MetaFileSuffix)] = meta
+
+				continue
+			}
+
 			metrics.Objects++
 
 			metrics.TotalBytes += int64(len(copyBuffer))
 
-			itemInfo, err := restoreItem(ctx,
+			// prior versions are restored in place of their item.
+			itemKey, _, _ := splitVersionFileName(itemData.UUID())
+			itemName := ItemName(itemData, itemKey)
+
+			itemID, itemInfo, err := restoreItem(ctx,
 				service,
 				itemData,
-				drivePath.DriveID,
+				itemName,
+				driveID,
 				restoreFolderID,
 				copyBuffer,
-				source)
+				source,
+				opts.Collision)
+			if errors.Is(err, graph.ErrItemAlreadyExists) {
+				metrics.Skipped++
+				continue
+			}
+
 			if err != nil {
 				errUpdater(itemData.UUID(), err)
 				continue
 			}
 
-			itemPath, err := dc.FullPath().Append(itemData.UUID(), true)
+			restoredIDs[itemKey] = itemID
+
+			itemPath, err := dc.FullPath().Append(itemID, true)
 			if err != nil {
 				logger.Ctx(ctx).DPanicw("transforming item to full path", "error", err)
 				errUpdater(itemData.UUID(), err)
@@ -160,9 +250,88 @@ func RestoreCollection(
 	}
 }
 
+// ItemName produces the name of the drive item held by the stream.  Items are
+// stored under their graph ID, and their name is read from the item's info.
+// Backups made before items were keyed by ID stored the items under their
+// names, in which case the key is the name.
+func ItemName(itemData data.Stream, key string) string {
+	si, ok := itemData.(data.StreamInfo)
+	if !ok {
+		return key
+	}
+
+	info := si.Info()
+
+	switch {
+	case info.OneDrive != nil && len(info.OneDrive.ItemName) > 0:
+		return info.OneDrive.ItemName
+	case info.SharePoint != nil && len(info.SharePoint.ItemName) > 0:
+		return info.SharePoint.ItemName
+	}
+
+	return key
+}
+
+// restoreFolderPermissions applies the permissions from the folder's metadata
+// to the restored folder, if the folder was created by the restore.  Folders
+// that already existed keep their own permissions.
+func restoreFolderPermissions(
+	ctx context.Context,
+	service graph.Servicer,
+	driveID, folderID string,
+	meta *Metadata,
+	createdFolders map[string]struct{},
+	opts control.Options,
+	errUpdater func(string, error),
+) {
+	if meta == nil {
+		return
+	}
+
+	if _, ok := createdFolders[folderID]; !ok {
+		return
+	}
+
+	err := restorePermissions(ctx, service, driveID, folderID, meta.Permissions, opts.Permissions.PrincipalMap)
+	if err != nil {
+		errUpdater(folderID, errors.Wrap(err, "restoring folder permissions"))
+	}
+}
+
+// restoreItemsPermissions applies the permissions from each item's metadata
+// to the restored copy of the item.  Items that weren't restored, such as
+// those skipped due to a collision, are left untouched.
+func restoreItemsPermissions(
+	ctx context.Context,
+	service graph.Servicer,
+	driveID string,
+	restoredIDs map[string]string,
+	itemMeta map[string]Metadata,
+	opts control.Options,
+	errUpdater func(string, error),
+) {
+	for key, meta := range itemMeta {
+		itemID, ok := restoredIDs[key]
+		if !ok {
+			continue
+		}
+
+		err := restorePermissions(ctx, service, driveID, itemID, meta.Permissions, opts.Permissions.PrincipalMap)
+		if err != nil {
+			errUpdater(key, errors.Wrap(err, "restoring permissions"))
+		}
+	}
+}
+
 // createRestoreFolders creates the restore folder hieararchy in the specified drive and returns the folder ID
-// of the last folder entry in the hiearchy
-func CreateRestoreFolders(ctx context.Context, service graph.Servicer, driveID string, restoreFolders []string,
+// of the last folder entry in the hiearchy.  The IDs of the folders it creates are added to created, if it
+// isn't nil.
+func CreateRestoreFolders(
+	ctx context.Context,
+	service graph.Servicer,
+	driveID string,
+	restoreFolders []string,
+	created map[string]struct{},
 ) (string, error) {
 	driveRoot, err := service.Client().DrivesById(driveID).Root().Get(ctx, nil)
 	if err != nil {
@@ -204,42 +373,68 @@ func CreateRestoreFolders(ctx context.Context, service graph.Servicer, driveID s
 			"dest_id", *folderItem.GetId())
 
 		parentFolderID = *folderItem.GetId()
+
+		if created != nil {
+			created[parentFolderID] = struct{}{}
+		}
 	}
 
 	return parentFolderID, nil
 }
 
-// restoreItem will create a new item in the specified `parentFolderID` and upload the data.Stream
+// restoreItem will create a new item named `itemName` in the specified `parentFolderID` and upload
+// the data.Stream.  Returns the ID of the new item.  Items that collide with an existing item of the same name are
+// handled according to the collision policy.  Under the Skip policy, graph.ErrItemAlreadyExists
+// is returned.
 func restoreItem(
 	ctx context.Context,
 	service graph.Servicer,
 	itemData data.Stream,
-	driveID, parentFolderID string,
+	itemName, driveID, parentFolderID string,
 	copyBuffer []byte,
 	source driveSource,
-) (details.ItemInfo, error) {
+	policy control.CollisionPolicy,
+) (string, details.ItemInfo, error) {
 	ctx, end := D.Span(ctx, "gc:oneDrive:restoreItem", D.Label("item_uuid", itemData.UUID()))
 	defer end()
 
-	itemName := itemData.UUID()
 	trace.Log(ctx, "gc:oneDrive:restoreItem", itemName)
 
 	// Get the stream size (needed to create the upload session)
 	ss, ok := itemData.(data.StreamSize)
 	if !ok {
-		return details.ItemInfo{}, errors.Errorf("item %q does not implement DataStreamInfo", itemName)
+		return "", details.ItemInfo{}, errors.Errorf("item %q does not implement DataStreamInfo", itemName)
 	}
 
+	if policy == control.Skip {
+		_, err := getItemByName(ctx, service, driveID, parentFolderID, itemName)
+		if err == nil {
+			return "", details.ItemInfo{}, errors.WithStack(graph.ErrItemAlreadyExists)
+		}
+
+		if !errors.Is(err, errItemNotFound) {
+			return "", details.ItemInfo{}, errors.Wrapf(err, "failed to check for existing item %s", itemName)
+		}
+	}
+
+	item := newItem(itemName, false)
+	item.SetAdditionalData(map[string]any{conflictBehaviorKey: conflictBehavior(policy)})
+
 	// Create Item
-	newItem, err := createItem(ctx, service, driveID, parentFolderID, newItem(itemData.UUID(), false))
+	newItem, err := createItem(ctx, service, driveID, parentFolderID, item)
 	if err != nil {
-		return details.ItemInfo{}, errors.Wrapf(err, "failed to create item %s", itemName)
+		// another writer may have created the item since it was looked up.
+		if exists := graph.IsErrItemAlreadyExists(err); policy == control.Skip && exists != nil {
+			return "", details.ItemInfo{}, exists
+		}
+
+		return "", details.ItemInfo{}, errors.Wrapf(err, "failed to create item %s", itemName)
 	}
 
 	// Get a drive item writer
 	w, err := driveItemWriter(ctx, service, driveID, *newItem.GetId(), ss.Size())
 	if err != nil {
-		return details.ItemInfo{}, errors.Wrapf(err, "failed to create item upload session %s", itemName)
+		return "", details.ItemInfo{}, errors.Wrapf(err, "failed to create item upload session %s", itemName)
 	}
 
 	iReader := itemData.ToReader()
@@ -250,7 +445,7 @@ func restoreItem(
 	// Upload the stream data
 	written, err := io.CopyBuffer(w, progReader, copyBuffer)
 	if err != nil {
-		return details.ItemInfo{}, errors.Wrapf(err, "failed to upload data: item %s", itemName)
+		return "", details.ItemInfo{}, errors.Wrapf(err, "failed to upload data: item %s", itemName)
 	}
 
 	dii := details.ItemInfo{}
@@ -262,5 +457,5 @@ func restoreItem(
 		dii.OneDrive = oneDriveItemInfo(newItem, written)
 	}
 
-	return dii, nil
+	return *newItem.GetId(), dii, nil
 }
@@ -0,0 +1,62 @@
+package onedrive
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/data"
+	"github.com/alcionai/corso/src/pkg/backup/details"
+)
+
+type RestoreUnitSuite struct {
+	suite.Suite
+}
+
+func TestRestoreUnitSuite(t *testing.T) {
+	suite.Run(t, new(RestoreUnitSuite))
+}
+
+func (suite *RestoreUnitSuite) TestItemName() {
+	table := []struct {
+		name   string
+		item   data.Stream
+		expect string
+	}{
+		{
+			name:   "no info",
+			item:   &metadataItem{id: "itemID"},
+			expect: "itemID",
+		},
+		{
+			name: "onedrive",
+			item: &Item{
+				id:   "itemID",
+				info: details.ItemInfo{OneDrive: &details.OneDriveInfo{ItemName: "a.txt"}},
+			},
+			expect: "a.txt",
+		},
+		{
+			name: "sharepoint",
+			item: &Item{
+				id:   "itemID",
+				info: details.ItemInfo{SharePoint: &details.SharePointInfo{ItemName: "b.txt"}},
+			},
+			expect: "b.txt",
+		},
+		{
+			name: "info without a name",
+			item: &Item{
+				id:   "itemID",
+				info: details.ItemInfo{OneDrive: &details.OneDriveInfo{}},
+			},
+			expect: "itemID",
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expect, ItemName(test.item, test.item.UUID()))
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package onedrive
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	msdrives "github.com/microsoftgraph/msgraph-sdk-go/drives"
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/internal/connector/graph"
+	"github.com/alcionai/corso/src/internal/connector/support"
+	"github.com/alcionai/corso/src/internal/data"
+	"github.com/alcionai/corso/src/pkg/backup/details"
+)
+
+// versionInfix separates the key of a drive item from the ID of one of its
+// prior versions, in the name of the stream that holds the version.  Drive
+// item IDs and names can't contain a colon, so version streams never collide
+// with a real item.
+const versionInfix = ":version:"
+
+var (
+	_ data.Stream          = &versionItem{}
+	_ data.StreamModTime   = &versionItem{}
+	_ data.StreamImmutable = &versionItem{}
+)
+
+// VersionFileName produces the name of the stream that holds the given prior
+// version of the item.
+func VersionFileName(itemKey, versionID string) string {
+	return itemKey + versionInfix + versionID
+}
+
+// IsVersionFile returns true if the item name belongs to a version stream.
+func IsVersionFile(name string) bool {
+	_, _, ok := splitVersionFileName(name)
+	return ok
+}
+
+// splitVersionFileName returns the item key and version ID encoded in the
+// name of a version stream.  ok is false if the name doesn't belong to a
+// version stream.
+func splitVersionFileName(name string) (itemKey, versionID string, ok bool) {
+	idx := strings.LastIndex(name, versionInfix)
+	if idx < 0 {
+		return name, "", false
+	}
+
+	return name[:idx], name[idx+len(versionInfix):], true
+}
+
+// versionItem is the stream for a prior version of a drive item.  Versions
+// are listed in the details of their item, so the stream purposefully does
+// not implement data.StreamInfo.
+type versionItem struct {
+	id      string
+	data    io.ReadCloser
+	modTime time.Time
+}
+
+func (vi *versionItem) UUID() string {
+	return vi.id
+}
+
+func (vi *versionItem) ToReader() io.ReadCloser {
+	return vi.data
+}
+
+func (vi *versionItem) Deleted() bool {
+	return false
+}
+
+// Immutable is true because the content of a version never changes, so a
+// version already held by the base backup is not downloaded again.
+func (vi *versionItem) Immutable() bool {
+	return true
+}
+
+func (vi *versionItem) ModTime() time.Time {
+	return vi.modTime
+}
+
+// driveVersion is a prior version of a drive item along with the url its
+// content is downloaded from.
+type driveVersion struct {
+	details.OneDriveVersion
+	downloadURL string
+}
+
+// versionsReaderFunc returns the prior versions of an item.
+type versionsReaderFunc func(
+	ctx context.Context,
+	service graph.Servicer,
+	driveID string,
+	item models.DriveItemable,
+) ([]driveVersion, error)
+
+// versionReaderFunc returns a reader for the content at the download url of
+// a prior version of an item.
+type versionReaderFunc func(ctx context.Context, url string) (io.ReadCloser, error)
+
+// driveItemVersions retrieves the prior versions of the item.  Graph lists
+// the current version of the item along with the prior ones, so the most
+// recently modified version is dropped.
+func driveItemVersions(
+	ctx context.Context,
+	service graph.Servicer,
+	driveID string,
+	item models.DriveItemable,
+) ([]driveVersion, error) {
+	var (
+		versions []driveVersion
+		builder  = service.Client().DrivesById(driveID).ItemsById(*item.GetId()).Versions()
+	)
+
+	for {
+		r, err := builder.Get(ctx, nil)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err,
+				"failed to get versions of item %s. details: %s",
+				*item.GetId(),
+				support.ConnectorStackErrorTrace(err),
+			)
+		}
+
+		for _, v := range r.GetValue() {
+			url, ok := v.GetAdditionalData()[downloadURLKey].(*string)
+			if !ok || url == nil {
+				return nil, errors.Errorf(
+					"failed to get url for version %s of item %s",
+					strValue(v.GetId()),
+					*item.GetId())
+			}
+
+			versions = append(versions, driveVersion{
+				OneDriveVersion: toOneDriveVersion(v),
+				downloadURL:     *url,
+			})
+		}
+
+		nextLink := r.GetOdataNextLink()
+		if nextLink == nil || len(*nextLink) == 0 {
+			break
+		}
+
+		builder = msdrives.NewItemItemsItemVersionsRequestBuilder(*nextLink, service.Adapter())
+	}
+
+	return priorVersions(versions), nil
+}
+
+func toOneDriveVersion(v models.DriveItemVersionable) details.OneDriveVersion {
+	odv := details.OneDriveVersion{
+		ID: strValue(v.GetId()),
+	}
+
+	if v.GetLastModifiedDateTime() != nil {
+		odv.Modified = *v.GetLastModifiedDateTime()
+	}
+
+	if v.GetSize() != nil {
+		odv.Size = *v.GetSize()
+	}
+
+	if lmb := v.GetLastModifiedBy(); lmb != nil && lmb.GetUser() != nil {
+		if email, ok := lmb.GetUser().GetAdditionalData()["email"].(*string); ok {
+			odv.ModifiedBy = strValue(email)
+		}
+	}
+
+	return odv
+}
+
+// priorVersions drops the most recently modified version, which is the
+// current content of the item.
+func priorVersions(versions []driveVersion) []driveVersion {
+	if len(versions) == 0 {
+		return nil
+	}
+
+	latest := 0
+
+	for i, v := range versions {
+		if v.Modified.After(versions[latest].Modified) {
+			latest = i
+		}
+	}
+
+	prior := make([]driveVersion, 0, len(versions)-1)
+	prior = append(prior, versions[:latest]...)
+	prior = append(prior, versions[latest+1:]...)
+
+	return prior
+}
+
+// versionDetails returns the details of the versions.
+func versionDetails(versions []driveVersion) []details.OneDriveVersion {
+	if len(versions) == 0 {
+		return nil
+	}
+
+	dvs := make([]details.OneDriveVersion, 0, len(versions))
+
+	for _, v := range versions {
+		dvs = append(dvs, v.OneDriveVersion)
+	}
+
+	return dvs
+}
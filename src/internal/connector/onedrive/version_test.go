@@ -0,0 +1,103 @@
+package onedrive
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/pkg/backup/details"
+)
+
+type VersionUnitSuite struct {
+	suite.Suite
+}
+
+func TestVersionUnitSuite(t *testing.T) {
+	suite.Run(t, new(VersionUnitSuite))
+}
+
+func (suite *VersionUnitSuite) TestVersionFileName() {
+	table := []struct {
+		name          string
+		input         string
+		expectItem    string
+		expectVersion string
+		expectOK      bool
+	}{
+		{
+			name:          "version",
+			input:         VersionFileName("file.txt", "2.0"),
+			expectItem:    "file.txt",
+			expectVersion: "2.0",
+			expectOK:      true,
+		},
+		{
+			name:       "item",
+			input:      "file.txt",
+			expectItem: "file.txt",
+		},
+		{
+			name:       "metadata",
+			input:      "file.txt" + MetaFileSuffix,
+			expectItem: "file.txt" + MetaFileSuffix,
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			item, version, ok := splitVersionFileName(test.input)
+			assert.Equal(t, test.expectItem, item)
+			assert.Equal(t, test.expectVersion, version)
+			assert.Equal(t, test.expectOK, ok)
+		})
+	}
+}
+
+func (suite *VersionUnitSuite) TestPriorVersions() {
+	now := time.Now()
+
+	table := []struct {
+		name   string
+		input  []driveVersion
+		expect []driveVersion
+	}{
+		{
+			name: "none",
+		},
+		{
+			name:   "only the current version",
+			input:  []driveVersion{{OneDriveVersion: details.OneDriveVersion{ID: "1.0", Modified: now}}},
+			expect: []driveVersion{},
+		},
+		{
+			name: "newest first",
+			input: []driveVersion{
+				{OneDriveVersion: details.OneDriveVersion{ID: "3.0", Modified: now}},
+				{OneDriveVersion: details.OneDriveVersion{ID: "2.0", Modified: now.Add(-time.Hour)}},
+				{OneDriveVersion: details.OneDriveVersion{ID: "1.0", Modified: now.Add(-2 * time.Hour)}},
+			},
+			expect: []driveVersion{
+				{OneDriveVersion: details.OneDriveVersion{ID: "2.0", Modified: now.Add(-time.Hour)}},
+				{OneDriveVersion: details.OneDriveVersion{ID: "1.0", Modified: now.Add(-2 * time.Hour)}},
+			},
+		},
+		{
+			name: "out of order",
+			input: []driveVersion{
+				{OneDriveVersion: details.OneDriveVersion{ID: "1.0", Modified: now.Add(-2 * time.Hour)}},
+				{OneDriveVersion: details.OneDriveVersion{ID: "3.0", Modified: now}},
+				{OneDriveVersion: details.OneDriveVersion{ID: "2.0", Modified: now.Add(-time.Hour)}},
+			},
+			expect: []driveVersion{
+				{OneDriveVersion: details.OneDriveVersion{ID: "1.0", Modified: now.Add(-2 * time.Hour)}},
+				{OneDriveVersion: details.OneDriveVersion{ID: "2.0", Modified: now.Add(-time.Hour)}},
+			},
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expect, priorVersions(test.input))
+		})
+	}
+}
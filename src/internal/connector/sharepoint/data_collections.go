@@ -21,10 +21,12 @@ type statusUpdater interface {
 }
 
 // DataCollections returns a set of DataCollection which represents the SharePoint data
-// for the specified user
+// for the specified user.  metadata holds the collections of metadata saved by the
+// previous backup, which are used to back up the libraries incrementally.
 func DataCollections(
 	ctx context.Context,
 	selector selectors.Selector,
+	metadata []data.Collection,
 	tenantID string,
 	serv graph.Servicer,
 	su statusUpdater,
@@ -41,6 +43,12 @@ func DataCollections(
 		errs        error
 	)
 
+	// the metadata is shared by every library scope.
+	prev, err := onedrive.DeserializeMetadata(ctx, metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing previous backup metadata")
+	}
+
 	for _, scope := range b.Scopes() {
 		foldersComplete, closer := observe.MessageWithCompletion(ctx, observe.Bulletf(
 			"%s - %s",
@@ -70,6 +78,7 @@ func DataCollections(
 				tenantID,
 				site,
 				scope,
+				prev,
 				su,
 				ctrlOpts)
 			if err != nil {
@@ -127,6 +136,7 @@ func collectLibraries(
 	serv graph.Servicer,
 	tenantID, siteID string,
 	scope selectors.SharePointScope,
+	prev onedrive.PrevMetadata,
 	updater statusUpdater,
 	ctrlOpts control.Options,
 ) ([]data.Collection, error) {
@@ -146,7 +156,7 @@ func collectLibraries(
 		updater.UpdateStatus,
 		ctrlOpts)
 
-	odcs, err := colls.Get(ctx)
+	odcs, err := colls.Get(ctx, prev)
 	if err != nil {
 		return nil, support.WrapAndAppend(siteID, err, errs)
 	}
@@ -38,12 +38,15 @@ func RestoreCollections(
 	ctx context.Context,
 	service graph.Servicer,
 	dest control.RestoreDestination,
+	opts control.Options,
 	dcs []data.Collection,
 	deets *details.Builder,
 ) (*support.ConnectorOperationStatus, error) {
 	var (
 		restoreMetrics support.CollectionMetrics
 		restoreErrors  error
+		// IDs of the library folders created by the restore
+		createdFolders = map[string]struct{}{}
 	)
 
 	errUpdater := func(id string, err error) {
@@ -64,15 +67,17 @@ func RestoreCollections(
 				service,
 				dc,
 				onedrive.OneDriveSource,
-				dest.ContainerName,
+				dest,
+				opts,
 				deets,
+				createdFolders,
 				errUpdater)
 		case path.ListsCategory:
 			metrics, canceled = RestoreCollection(
 				ctx,
 				service,
 				dc,
-				dest.ContainerName,
+				dest,
 				deets,
 				errUpdater,
 			)
@@ -111,11 +116,12 @@ func createRestoreFolders(ctx context.Context, service graph.Servicer, siteID st
 		)
 	}
 
-	return onedrive.CreateRestoreFolders(ctx, service, *mainDrive.GetId(), restoreFolders)
+	return onedrive.CreateRestoreFolders(ctx, service, *mainDrive.GetId(), restoreFolders, nil)
 }
 
 // restoreListItem utility function restores a List to the siteID.
-// The name is changed to to Corso_Restore_{timeStame}_name
+// The name is changed to to Corso_Restore_{timeStame}_name.  If no destName
+// is provided, the list is restored under its original name.
 // API Reference: https://learn.microsoft.com/en-us/graph/api/list-create?view=graph-rest-1.0&tabs=http
 // Restored List can be verified within the Site contents.
 func restoreListItem(
@@ -146,7 +152,12 @@ func restoreListItem(
 		listName = *oldList.GetDisplayName()
 	}
 
-	newName := fmt.Sprintf("%s_%s", destName, listName)
+	// lists restored to their original location keep their original name.
+	newName := listName
+	if len(destName) > 0 {
+		newName = fmt.Sprintf("%s_%s", destName, listName)
+	}
+
 	newList := support.ToListable(oldList, newName)
 
 	contents := make([]models.ListItemable, 0)
@@ -197,11 +208,14 @@ func restoreListItem(
 	return dii, nil
 }
 
+// RestoreCollection restores the lists within the collection into the site
+// of the collection's resource owner, or into the overriding site, if the
+// destination specifies one.
 func RestoreCollection(
 	ctx context.Context,
 	service graph.Servicer,
 	dc data.Collection,
-	restoreContainerName string,
+	dest control.RestoreDestination,
 	deets *details.Builder,
 	errUpdater func(string, error),
 ) (support.CollectionMetrics, bool) {
@@ -214,7 +228,7 @@ func RestoreCollection(
 	)
 
 	trace.Log(ctx, "gc:sharepoint:restoreCollection", directory.String())
-	siteID := directory.ResourceOwner()
+	siteID := dest.RestoreOwner(directory.ResourceOwner())
 
 	// Restore items from the collection
 	items := dc.Items()
@@ -236,7 +250,7 @@ func RestoreCollection(
 				service,
 				itemData,
 				siteID,
-				restoreContainerName,
+				dest.ContainerName,
 			)
 			if err != nil {
 				errUpdater(itemData.UUID(), err)
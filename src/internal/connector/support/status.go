@@ -20,6 +20,7 @@ type ConnectorOperationStatus struct {
 	ObjectCount       int
 	FolderCount       int
 	Successful        int
+	Skipped           int
 	ErrorCount        int
 	incomplete        bool
 	incompleteReason  string
@@ -27,14 +28,18 @@ type ConnectorOperationStatus struct {
 	bytes             int64
 }
 
+// CollectionMetrics tracks the per-item results of a collection's
+// processing.  Skipped counts items which were intentionally not
+// processed, such as restores that collided with an existing item.
 type CollectionMetrics struct {
-	Objects, Successes int
-	TotalBytes         int64
+	Objects, Successes, Skipped int
+	TotalBytes                  int64
 }
 
 func (cm *CollectionMetrics) Combine(additional CollectionMetrics) {
 	cm.Objects += additional.Objects
 	cm.Successes += additional.Successes
+	cm.Skipped += additional.Skipped
 	cm.TotalBytes += additional.TotalBytes
 }
 
@@ -69,6 +74,7 @@ func CreateStatus(
 		ObjectCount:       cm.Objects,
 		FolderCount:       folders,
 		Successful:        cm.Successes,
+		Skipped:           cm.Skipped,
 		ErrorCount:        numErr,
 		incomplete:        hasErrors,
 		incompleteReason:  reason,
@@ -76,11 +82,12 @@ func CreateStatus(
 		additionalDetails: details,
 	}
 
-	if status.ObjectCount != status.ErrorCount+status.Successful {
+	if status.ObjectCount != status.ErrorCount+status.Successful+status.Skipped {
 		logger.Ctx(ctx).Errorw(
-			"status object count does not match errors + successes",
+			"status object count does not match errors + successes + skips",
 			"objects", cm.Objects,
 			"successes", cm.Successes,
+			"skipped", cm.Skipped,
 			"numErrors", numErr,
 			"errors", err)
 	}
@@ -114,6 +121,7 @@ func MergeStatus(one, two ConnectorOperationStatus) ConnectorOperationStatus {
 		ObjectCount:       one.ObjectCount + two.ObjectCount,
 		FolderCount:       one.FolderCount + two.FolderCount,
 		Successful:        one.Successful + two.Successful,
+		Skipped:           one.Skipped + two.Skipped,
 		ErrorCount:        one.ErrorCount + two.ErrorCount,
 		bytes:             one.bytes + two.bytes,
 		incomplete:        hasErrors,
@@ -142,6 +150,10 @@ func (cos *ConnectorOperationStatus) String() string {
 		cos.FolderCount,
 	)
 
+	if cos.Skipped > 0 {
+		message += fmt.Sprintf(" Skipped %d existing objects.", cos.Skipped)
+	}
+
 	if cos.incomplete {
 		message += " " + cos.incompleteReason
 	}
@@ -59,7 +59,7 @@ func (suite *GCStatusTestSuite) TestCreateStatus() {
 				ctx,
 				test.params.operationType,
 				test.params.folders,
-				CollectionMetrics{test.params.objects, test.params.success, 0},
+				CollectionMetrics{test.params.objects, test.params.success, 0, 0},
 				test.params.err,
 				"",
 			)
@@ -84,6 +84,7 @@ func (suite *GCStatusTestSuite) TestCreateStatus_InvalidStatus() {
 				params.objects,
 				params.success,
 				0,
+				0,
 			},
 			params.err,
 			"",
@@ -91,6 +92,36 @@ func (suite *GCStatusTestSuite) TestCreateStatus_InvalidStatus() {
 	})
 }
 
+func (suite *GCStatusTestSuite) TestCreateStatus_Skipped() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+
+	one := CreateStatus(
+		ctx,
+		Restore,
+		1,
+		CollectionMetrics{Objects: 5, Successes: 3, Skipped: 2},
+		nil,
+		"")
+	assert.Equal(t, 2, one.Skipped)
+	assert.False(t, one.incomplete)
+	assert.Contains(t, one.String(), "Skipped 2 existing objects")
+
+	two := CreateStatus(
+		ctx,
+		Restore,
+		1,
+		CollectionMetrics{Objects: 2, Skipped: 2},
+		nil,
+		"")
+
+	merged := MergeStatus(*one, *two)
+	assert.Equal(t, 4, merged.Skipped)
+	assert.Equal(t, 3, merged.Successful)
+}
+
 func (suite *GCStatusTestSuite) TestMergeStatus() {
 	ctx, flush := tester.NewContext()
 	defer flush()
@@ -104,7 +135,7 @@ func (suite *GCStatusTestSuite) TestMergeStatus() {
 	}{
 		{
 			name:         "Test:  Status + unknown",
-			one:          *CreateStatus(ctx, Backup, 1, CollectionMetrics{1, 1, 0}, nil, ""),
+			one:          *CreateStatus(ctx, Backup, 1, CollectionMetrics{1, 1, 0, 0}, nil, ""),
 			two:          ConnectorOperationStatus{},
 			expected:     statusParams{Backup, 1, 1, 1, nil},
 			isIncomplete: assert.False,
@@ -112,20 +143,20 @@ func (suite *GCStatusTestSuite) TestMergeStatus() {
 		{
 			name:         "Test: unknown + Status",
 			one:          ConnectorOperationStatus{},
-			two:          *CreateStatus(ctx, Backup, 1, CollectionMetrics{1, 1, 0}, nil, ""),
+			two:          *CreateStatus(ctx, Backup, 1, CollectionMetrics{1, 1, 0, 0}, nil, ""),
 			expected:     statusParams{Backup, 1, 1, 1, nil},
 			isIncomplete: assert.False,
 		},
 		{
 			name:         "Test: Successful + Successful",
-			one:          *CreateStatus(ctx, Backup, 1, CollectionMetrics{1, 1, 0}, nil, ""),
-			two:          *CreateStatus(ctx, Backup, 3, CollectionMetrics{3, 3, 0}, nil, ""),
+			one:          *CreateStatus(ctx, Backup, 1, CollectionMetrics{1, 1, 0, 0}, nil, ""),
+			two:          *CreateStatus(ctx, Backup, 3, CollectionMetrics{3, 3, 0, 0}, nil, ""),
 			expected:     statusParams{Backup, 4, 4, 4, nil},
 			isIncomplete: assert.False,
 		},
 		{
 			name: "Test: Successful + Unsuccessful",
-			one:  *CreateStatus(ctx, Backup, 13, CollectionMetrics{17, 17, 0}, nil, ""),
+			one:  *CreateStatus(ctx, Backup, 13, CollectionMetrics{17, 17, 0, 0}, nil, ""),
 			two: *CreateStatus(
 				ctx,
 				Backup,
@@ -134,6 +165,7 @@ func (suite *GCStatusTestSuite) TestMergeStatus() {
 					12,
 					9,
 					0,
+					0,
 				},
 				WrapAndAppend("tres", errors.New("three"), WrapAndAppend("arc376", errors.New("one"), errors.New("two"))),
 				"",
@@ -0,0 +1,144 @@
+// Package contentline writes the "content lines" shared by the vCard
+// (RFC 6350) and iCalendar (RFC 5545) formats:
+//
+//	name *(";" param) ":" value CRLF
+//
+// Lines longer than 75 octets are folded onto continuation lines.
+package contentline
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	crlf = "\r\n"
+
+	// maxLineLength is the number of octets, excluding the line break, a
+	// single line is allowed to hold before it must be folded.
+	maxLineLength = 75
+)
+
+// Param is a property parameter.  Multiple values are joined with commas.
+type Param struct {
+	Name   string
+	Values []string
+}
+
+// Writer accumulates content lines.
+type Writer struct {
+	buf bytes.Buffer
+}
+
+// Write adds a property line.  The value is written as-is; text values
+// should be passed through Escape, and structured values through Join.
+// Properties with empty values are omitted.
+func (w *Writer) Write(name, value string, params ...Param) {
+	if len(value) == 0 {
+		return
+	}
+
+	w.Line(name, value, params...)
+}
+
+// Line adds a property line, even if the value is empty.
+func (w *Writer) Line(name, value string, params ...Param) {
+	var sb strings.Builder
+
+	sb.WriteString(name)
+
+	for _, p := range params {
+		if len(p.Values) == 0 {
+			continue
+		}
+
+		vs := make([]string, 0, len(p.Values))
+		for _, v := range p.Values {
+			vs = append(vs, paramValue(v))
+		}
+
+		sb.WriteString(";" + p.Name + "=" + strings.Join(vs, ","))
+	}
+
+	sb.WriteString(":" + value)
+
+	w.buf.WriteString(fold(sb.String()))
+}
+
+// Bytes returns the lines written so far.
+func (w *Writer) Bytes() []byte {
+	return w.buf.Bytes()
+}
+
+// Escape escapes a text value.
+func Escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// Join escapes each component of a structured value, such as a name or an
+// address, and joins them with semicolons.
+func Join(components ...string) string {
+	var (
+		escaped   = make([]string, 0, len(components))
+		populated bool
+	)
+
+	for _, c := range components {
+		escaped = append(escaped, Escape(c))
+		populated = populated || len(c) > 0
+	}
+
+	// a structured value with no populated components is empty.
+	if !populated {
+		return ""
+	}
+
+	return strings.Join(escaped, ";")
+}
+
+// paramValue quotes parameter values which contain characters that are
+// otherwise delimiters.  Double quotes aren't allowed in parameter values
+// and are dropped.
+func paramValue(v string) string {
+	v = strings.ReplaceAll(v, `"`, "")
+
+	if strings.ContainsAny(v, ":;,") {
+		return `"` + v + `"`
+	}
+
+	return v
+}
+
+// fold splits the line into chunks of at most maxLineLength octets, never
+// splitting a multi-byte character, and terminates it with CRLF.
+func fold(line string) string {
+	var (
+		sb    strings.Builder
+		limit = maxLineLength
+	)
+
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		sb.WriteString(line[:cut] + crlf + " ")
+		line = line[cut:]
+
+		// continuation lines lose one octet to the leading space.
+		limit = maxLineLength - 1
+	}
+
+	sb.WriteString(line + crlf)
+
+	return sb.String()
+}
@@ -0,0 +1,91 @@
+package contentline
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ContentLineUnitSuite struct {
+	suite.Suite
+}
+
+func TestContentLineUnitSuite(t *testing.T) {
+	suite.Run(t, new(ContentLineUnitSuite))
+}
+
+func (suite *ContentLineUnitSuite) TestWrite() {
+	table := []struct {
+		name   string
+		prop   string
+		value  string
+		params []Param
+		expect string
+	}{
+		{
+			name:   "simple",
+			prop:   "SUMMARY",
+			value:  "lunch",
+			expect: "SUMMARY:lunch\r\n",
+		},
+		{
+			name:   "empty value omitted",
+			prop:   "SUMMARY",
+			expect: "",
+		},
+		{
+			name:  "params",
+			prop:  "ATTENDEE",
+			value: "mailto:a@b.c",
+			params: []Param{
+				{Name: "CN", Values: []string{`Last, "First"`}},
+				{Name: "TYPE", Values: []string{"work", "voice"}},
+				{Name: "EMPTY"},
+			},
+			expect: `ATTENDEE;CN="Last, First";TYPE=work,voice:mailto:a@b.c` + "\r\n",
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			w := &Writer{}
+			w.Write(test.prop, test.value, test.params...)
+			assert.Equal(t, test.expect, string(w.Bytes()))
+		})
+	}
+}
+
+func (suite *ContentLineUnitSuite) TestFold() {
+	t := suite.T()
+
+	// multi-byte characters must not be split across lines.
+	w := &Writer{}
+	w.Write("NOTE", strings.Repeat("é", 100))
+
+	lines := strings.Split(strings.TrimSuffix(string(w.Bytes()), "\r\n"), "\r\n")
+	assert.Greater(t, len(lines), 1)
+
+	var unfolded strings.Builder
+
+	for i, line := range lines {
+		assert.LessOrEqual(t, len(line), maxLineLength)
+
+		if i > 0 {
+			assert.True(t, strings.HasPrefix(line, " "))
+			line = line[1:]
+		}
+
+		unfolded.WriteString(line)
+	}
+
+	assert.Equal(t, "NOTE:"+strings.Repeat("é", 100), unfolded.String())
+}
+
+func (suite *ContentLineUnitSuite) TestEscapeAndJoin() {
+	t := suite.T()
+
+	assert.Equal(t, `a\\b\;c\,d\ne`, Escape("a\\b;c,d\r\ne"))
+	assert.Equal(t, `Doe;Jane\, Jr.;;;`, Join("Doe", "Jane, Jr.", "", "", ""))
+	assert.Empty(t, Join("", "", ""))
+}
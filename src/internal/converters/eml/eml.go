@@ -0,0 +1,486 @@
+// Package eml converts exchange messages, as they are stored in a backup,
+// into RFC 5322 internet messages which can be read by any mail client.
+package eml
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/internal/connector/support"
+)
+
+const (
+	crlf = "\r\n"
+
+	// base64 encoded content is wrapped at this line length, as required
+	// by RFC 2045.
+	base64LineLength = 76
+
+	defaultAttachmentType = "application/octet-stream"
+)
+
+// threadingHeaders are the internet message headers that get carried over
+// from the original message, when graph provided them, so that mail clients
+// are able to reconstruct conversations.
+var threadingHeaders = map[string]struct{}{
+	"In-Reply-To": {},
+	"References":  {},
+}
+
+// FromJSON converts the json-serialized graph message produced during
+// backup into an .eml file.
+func FromJSON(body []byte) ([]byte, error) {
+	msg, err := support.CreateMessageFromBytes(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "deserializing message")
+	}
+
+	return ToEml(msg)
+}
+
+// ToEml converts the message, along with its file and message attachments,
+// into an RFC 5322 internet message.  Reference attachments are links to
+// content stored elsewhere, and are not included.
+func ToEml(msg models.Messageable) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := writeMessage(&buf, msg); err != nil {
+		return nil, errors.Wrapf(err, "converting message %s", ptrVal(msg.GetId()))
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeMessage(w io.Writer, msg models.Messageable) error {
+	var (
+		hdrs     = messageHeaders(msg)
+		body     bytes.Buffer
+		attached = attachmentParts(msg.GetAttachments())
+	)
+
+	if len(attached) == 0 {
+		ct, err := writeBody(&body, msg.GetBody())
+		if err != nil {
+			return err
+		}
+
+		hdrs = append(hdrs,
+			header{"Content-Type", ct},
+			header{"Content-Transfer-Encoding", "quoted-printable"})
+
+		return writeEntity(w, hdrs, body.Bytes())
+	}
+
+	mixed := multipart.NewWriter(&body)
+
+	if err := writeMixedParts(mixed, msg.GetBody(), attached); err != nil {
+		return err
+	}
+
+	if err := mixed.Close(); err != nil {
+		return errors.Wrap(err, "closing multipart message")
+	}
+
+	hdrs = append(hdrs, header{"Content-Type", multipartType("mixed", mixed.Boundary())})
+
+	return writeEntity(w, hdrs, body.Bytes())
+}
+
+// writeMixedParts writes the message body, followed by each attachment, into
+// the multipart/mixed writer.  Inline attachments are grouped with an html
+// body into a multipart/related part so that clients can resolve their cid:
+// references.
+func writeMixedParts(
+	mixed *multipart.Writer,
+	itemBody models.ItemBodyable,
+	attached []part,
+) error {
+	var inline, regular []part
+
+	for _, p := range attached {
+		if p.inline && isHTML(itemBody) {
+			inline = append(inline, p)
+			continue
+		}
+
+		regular = append(regular, p)
+	}
+
+	if len(inline) == 0 {
+		if err := writeBodyPart(mixed, itemBody); err != nil {
+			return err
+		}
+	} else {
+		var (
+			buf     bytes.Buffer
+			related = multipart.NewWriter(&buf)
+		)
+
+		if err := writeBodyPart(related, itemBody); err != nil {
+			return err
+		}
+
+		for _, p := range inline {
+			if err := p.write(related); err != nil {
+				return err
+			}
+		}
+
+		if err := related.Close(); err != nil {
+			return errors.Wrap(err, "closing related parts")
+		}
+
+		pw, err := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type": {multipartType("related", related.Boundary())},
+		})
+		if err != nil {
+			return errors.Wrap(err, "creating related part")
+		}
+
+		if _, err := pw.Write(buf.Bytes()); err != nil {
+			return errors.Wrap(err, "writing related part")
+		}
+	}
+
+	for _, p := range regular {
+		if err := p.write(mixed); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// headers
+// ---------------------------------------------------------------------------
+
+// header is a single header field.  Headers are kept in a slice, instead of
+// a textproto.MIMEHeader, so that they are written in a stable order.
+type header struct {
+	key, value string
+}
+
+func messageHeaders(msg models.Messageable) []header {
+	hdrs := []header{{"MIME-Version", "1.0"}}
+
+	if t := messageDate(msg); t != nil {
+		hdrs = append(hdrs, header{"Date", t.Format(time.RFC1123Z)})
+	}
+
+	if id := ptrVal(msg.GetInternetMessageId()); len(id) > 0 {
+		hdrs = append(hdrs, header{"Message-ID", id})
+	}
+
+	for _, ih := range msg.GetInternetMessageHeaders() {
+		key := textproto.CanonicalMIMEHeaderKey(ptrVal(ih.GetName()))
+		if _, ok := threadingHeaders[key]; ok {
+			hdrs = append(hdrs, header{key, ptrVal(ih.GetValue())})
+		}
+	}
+
+	if from := addressList(msg.GetFrom()); len(from) > 0 {
+		hdrs = append(hdrs, header{"From", from})
+	}
+
+	if sender := addressList(msg.GetSender()); len(sender) > 0 && sender != addressList(msg.GetFrom()) {
+		hdrs = append(hdrs, header{"Sender", sender})
+	}
+
+	recipients := []struct {
+		key  string
+		rcps []models.Recipientable
+	}{
+		{"Reply-To", msg.GetReplyTo()},
+		{"To", msg.GetToRecipients()},
+		{"Cc", msg.GetCcRecipients()},
+		{"Bcc", msg.GetBccRecipients()},
+	}
+
+	for _, r := range recipients {
+		if al := addressList(r.rcps...); len(al) > 0 {
+			hdrs = append(hdrs, header{r.key, al})
+		}
+	}
+
+	hdrs = append(hdrs, header{"Subject", mime.QEncoding.Encode("utf-8", ptrVal(msg.GetSubject()))})
+
+	if imp := msg.GetImportance(); imp != nil {
+		switch *imp {
+		case models.HIGH_IMPORTANCE:
+			hdrs = append(hdrs, header{"Importance", "high"}, header{"X-Priority", "1"})
+		case models.LOW_IMPORTANCE:
+			hdrs = append(hdrs, header{"Importance", "low"}, header{"X-Priority", "5"})
+		}
+	}
+
+	return hdrs
+}
+
+// messageDate prefers the time the message was sent, falling back to when
+// it was received or created for messages, such as drafts, that were never
+// sent.
+func messageDate(msg models.Messageable) *time.Time {
+	for _, t := range []*time.Time{
+		msg.GetSentDateTime(),
+		msg.GetReceivedDateTime(),
+		msg.GetCreatedDateTime(),
+	} {
+		if t != nil {
+			return t
+		}
+	}
+
+	return nil
+}
+
+// addressList formats the recipients as a folded RFC 5322 address list.
+func addressList(rcps ...models.Recipientable) string {
+	addrs := []string{}
+
+	for _, r := range rcps {
+		if r == nil || r.GetEmailAddress() == nil {
+			continue
+		}
+
+		ea := r.GetEmailAddress()
+		if len(ptrVal(ea.GetAddress())) == 0 {
+			continue
+		}
+
+		addr := mail.Address{
+			Name:    ptrVal(ea.GetName()),
+			Address: ptrVal(ea.GetAddress()),
+		}
+
+		// graph commonly reports the address as the name when no
+		// display name is known.
+		if addr.Name == addr.Address {
+			addr.Name = ""
+		}
+
+		addrs = append(addrs, addr.String())
+	}
+
+	return strings.Join(addrs, ","+crlf+" ")
+}
+
+func writeEntity(w io.Writer, hdrs []header, body []byte) error {
+	var buf bytes.Buffer
+
+	for _, h := range hdrs {
+		buf.WriteString(h.key + ": " + h.value + crlf)
+	}
+
+	buf.WriteString(crlf)
+	buf.Write(body)
+
+	_, err := w.Write(buf.Bytes())
+
+	return errors.Wrap(err, "writing message")
+}
+
+func multipartType(subtype, boundary string) string {
+	return mime.FormatMediaType("multipart/"+subtype, map[string]string{"boundary": boundary})
+}
+
+// ---------------------------------------------------------------------------
+// body
+// ---------------------------------------------------------------------------
+
+func isHTML(body models.ItemBodyable) bool {
+	return body != nil &&
+		body.GetContentType() != nil &&
+		*body.GetContentType() == models.HTML_BODYTYPE
+}
+
+// writeBody writes the quoted-printable encoded message body, and returns
+// its content type.
+func writeBody(w io.Writer, body models.ItemBodyable) (string, error) {
+	ct := "text/plain"
+	if isHTML(body) {
+		ct = "text/html"
+	}
+
+	var content string
+	if body != nil {
+		content = ptrVal(body.GetContent())
+	}
+
+	qp := quotedprintable.NewWriter(w)
+
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return "", errors.Wrap(err, "encoding message body")
+	}
+
+	if err := qp.Close(); err != nil {
+		return "", errors.Wrap(err, "encoding message body")
+	}
+
+	return mime.FormatMediaType(ct, map[string]string{"charset": "utf-8"}), nil
+}
+
+func writeBodyPart(mw *multipart.Writer, body models.ItemBodyable) error {
+	var buf bytes.Buffer
+
+	ct, err := writeBody(&buf, body)
+	if err != nil {
+		return err
+	}
+
+	pw, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {ct},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return errors.Wrap(err, "creating body part")
+	}
+
+	_, err = pw.Write(buf.Bytes())
+
+	return errors.Wrap(err, "writing body part")
+}
+
+// ---------------------------------------------------------------------------
+// attachments
+// ---------------------------------------------------------------------------
+
+// part is a single attachment, ready to be written into a multipart message.
+type part struct {
+	header textproto.MIMEHeader
+	body   []byte
+	inline bool
+}
+
+func (p part) write(mw *multipart.Writer) error {
+	pw, err := mw.CreatePart(p.header)
+	if err != nil {
+		return errors.Wrap(err, "creating attachment part")
+	}
+
+	_, err = pw.Write(p.body)
+
+	return errors.Wrap(err, "writing attachment part")
+}
+
+// attachmentParts produces the mime parts for each attachment which holds
+// its own content.
+func attachmentParts(atts []models.Attachmentable) []part {
+	parts := []part{}
+
+	for _, att := range atts {
+		switch a := att.(type) {
+		case models.FileAttachmentable:
+			parts = append(parts, fileAttachmentPart(a))
+
+		case models.ItemAttachmentable:
+			if p, ok := itemAttachmentPart(a); ok {
+				parts = append(parts, p)
+			}
+		}
+	}
+
+	return parts
+}
+
+func fileAttachmentPart(att models.FileAttachmentable) part {
+	var (
+		name   = ptrVal(att.GetName())
+		ct     = ptrVal(att.GetContentType())
+		cid    = ptrVal(att.GetContentId())
+		inline = ptrVal(att.GetIsInline()) && len(cid) > 0
+		disp   = "attachment"
+	)
+
+	if len(ct) == 0 {
+		ct = defaultAttachmentType
+	}
+
+	if inline {
+		disp = "inline"
+	}
+
+	hdr := textproto.MIMEHeader{
+		"Content-Type":              {ct},
+		"Content-Disposition":       {disposition(disp, name)},
+		"Content-Transfer-Encoding": {"base64"},
+	}
+
+	if len(cid) > 0 {
+		hdr.Set("Content-ID", "<"+strings.Trim(cid, "<>")+">")
+	}
+
+	return part{
+		header: hdr,
+		body:   wrappedBase64(att.GetContentBytes()),
+		inline: inline,
+	}
+}
+
+// itemAttachmentPart embeds attached messages as message/rfc822 parts.
+// Other attached outlook items, and items which were not retrieved during
+// backup, have no mail representation and are skipped.
+func itemAttachmentPart(att models.ItemAttachmentable) (part, bool) {
+	msg, ok := att.GetItem().(models.Messageable)
+	if !ok || msg == nil {
+		return part{}, false
+	}
+
+	var buf bytes.Buffer
+
+	if err := writeMessage(&buf, msg); err != nil {
+		return part{}, false
+	}
+
+	return part{
+		header: textproto.MIMEHeader{
+			"Content-Type":        {"message/rfc822"},
+			"Content-Disposition": {disposition("attachment", ptrVal(att.GetName())+".eml")},
+		},
+		body: buf.Bytes(),
+	}, true
+}
+
+func disposition(disp, filename string) string {
+	if len(filename) == 0 {
+		return disp
+	}
+
+	return mime.FormatMediaType(disp, map[string]string{"filename": filename})
+}
+
+func wrappedBase64(bs []byte) []byte {
+	var (
+		enc = base64.StdEncoding.EncodeToString(bs)
+		buf bytes.Buffer
+	)
+
+	for len(enc) > base64LineLength {
+		buf.WriteString(enc[:base64LineLength] + crlf)
+		enc = enc[base64LineLength:]
+	}
+
+	buf.WriteString(enc)
+
+	return buf.Bytes()
+}
+
+func ptrVal[T any](t *T) T {
+	var v T
+	if t != nil {
+		v = *t
+	}
+
+	return v
+}
@@ -0,0 +1,188 @@
+package eml
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/connector/mockconnector"
+)
+
+type EMLUnitSuite struct {
+	suite.Suite
+}
+
+func TestEMLUnitSuite(t *testing.T) {
+	suite.Run(t, new(EMLUnitSuite))
+}
+
+func (suite *EMLUnitSuite) TestFromJSON() {
+	t := suite.T()
+
+	bs, err := FromJSON(mockconnector.GetMockMessageBytes("eml"))
+	require.NoError(t, err)
+
+	msg, err := mail.ReadMessage(bytes.NewReader(bs))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(subject, "TPS Report eml"), subject)
+
+	from, err := msg.Header.AddressList("From")
+	require.NoError(t, err)
+	require.Len(t, from, 1)
+	assert.Equal(t, "foobar@8qzvrj.onmicrosoft.com", from[0].Address)
+
+	to, err := msg.Header.AddressList("To")
+	require.NoError(t, err)
+	require.Len(t, to, 1)
+	assert.Equal(t, "LidiaH@8qzvrj.onmicrosoft.com", to[0].Address)
+
+	date, err := msg.Header.Date()
+	require.NoError(t, err)
+	assert.True(t, date.Equal(time.Date(2022, 9, 26, 23, 15, 46, 0, time.UTC)), date)
+
+	mt, _, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "text/html", mt)
+	assert.Equal(t, "quoted-printable", msg.Header.Get("Content-Transfer-Encoding"))
+}
+
+func (suite *EMLUnitSuite) TestFromJSON_attachment() {
+	t := suite.T()
+
+	bs, err := FromJSON(mockconnector.GetMockMessageWithDirectAttachment("eml"))
+	require.NoError(t, err)
+
+	msg, err := mail.ReadMessage(bytes.NewReader(bs))
+	require.NoError(t, err)
+	assert.NotEmpty(t, msg.Header.Get("Message-ID"))
+
+	parts := readParts(t, msg.Header.Get("Content-Type"), msg.Body, "multipart/mixed")
+	require.Len(t, parts, 2)
+
+	assert.Contains(t, parts[0].header.Get("Content-Type"), "text/html")
+
+	disp, params, err := mime.ParseMediaType(parts[1].header.Get("Content-Disposition"))
+	require.NoError(t, err)
+	assert.Equal(t, "attachment", disp)
+	assert.Equal(t, "database.db", params["filename"])
+	assert.Equal(t, "base64", parts[1].header.Get("Content-Transfer-Encoding"))
+
+	content, err := io.ReadAll(parts[1].reader())
+	require.NoError(t, err)
+
+	for _, line := range strings.Split(string(content), crlf) {
+		assert.LessOrEqual(t, len(line), base64LineLength)
+	}
+}
+
+func (suite *EMLUnitSuite) TestToEml_inlineAndItemAttachments() {
+	t := suite.T()
+
+	inner := models.NewMessage()
+	inner.SetSubject(ptr("forwarded"))
+
+	img := models.NewFileAttachment()
+	img.SetName(ptr("logo.png"))
+	img.SetContentType(ptr("image/png"))
+	img.SetContentId(ptr("logo"))
+	img.SetIsInline(ptr(true))
+	img.SetContentBytes([]byte("png"))
+
+	item := models.NewItemAttachment()
+	item.SetName(ptr("forwarded"))
+	item.SetItem(inner)
+
+	ref := models.NewReferenceAttachment()
+	ref.SetName(ptr("link"))
+
+	body := models.NewItemBody()
+	body.SetContent(ptr(`<img src="cid:logo">`))
+	body.SetContentType(ptr(models.HTML_BODYTYPE))
+
+	msg := models.NewMessage()
+	msg.SetSubject(ptr("héllo"))
+	msg.SetBody(body)
+	msg.SetAttachments([]models.Attachmentable{img, item, ref})
+
+	bs, err := ToEml(msg)
+	require.NoError(t, err)
+
+	m, err := mail.ReadMessage(bytes.NewReader(bs))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "héllo", subject)
+
+	// the reference attachment is dropped.
+	mixed := readParts(t, m.Header.Get("Content-Type"), m.Body, "multipart/mixed")
+	require.Len(t, mixed, 2)
+
+	related := readParts(t, mixed[0].header.Get("Content-Type"), mixed[0].reader(), "multipart/related")
+	require.Len(t, related, 2)
+	assert.Contains(t, related[0].header.Get("Content-Type"), "text/html")
+	assert.Equal(t, "<logo>", related[1].header.Get("Content-ID"))
+	assert.True(t, strings.HasPrefix(related[1].header.Get("Content-Disposition"), "inline"))
+
+	assert.Equal(t, "message/rfc822", mixed[1].header.Get("Content-Type"))
+
+	embedded, err := mail.ReadMessage(mixed[1].reader())
+	require.NoError(t, err)
+	assert.Equal(t, "forwarded", embedded.Header.Get("Subject"))
+}
+
+type testPart struct {
+	header textproto.MIMEHeader
+	body   []byte
+}
+
+func (tp testPart) reader() io.Reader {
+	return bytes.NewReader(tp.body)
+}
+
+// readParts reads the raw, undecoded contents of every part in the
+// multipart entity.
+func readParts(t *testing.T, contentType string, r io.Reader, expectType string) []testPart {
+	mt, params, err := mime.ParseMediaType(contentType)
+	require.NoError(t, err)
+	require.Equal(t, expectType, mt)
+
+	var (
+		mr    = multipart.NewReader(r, params["boundary"])
+		parts = []testPart{}
+	)
+
+	for {
+		p, err := mr.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+
+		require.NoError(t, err)
+
+		bs, err := io.ReadAll(p)
+		require.NoError(t, err)
+
+		parts = append(parts, testPart{p.Header, bs})
+	}
+
+	return parts
+}
+
+func ptr[T any](t T) *T {
+	return &t
+}
@@ -0,0 +1,105 @@
+package eml
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/internal/connector/support"
+)
+
+// mboxSender is used in the separator line of messages that have no
+// sender address, such as drafts.
+const mboxSender = "MAILER-DAEMON"
+
+// MboxWriter aggregates messages into a single mbox file.  Messages are
+// written in the mboxrd format: any body line beginning with zero or more
+// '>' characters followed by "From " is quoted with an additional '>', so
+// that readers can unambiguously locate message boundaries.
+type MboxWriter struct {
+	w io.Writer
+}
+
+// NewMboxWriter produces an MboxWriter that appends messages to w.
+func NewMboxWriter(w io.Writer) *MboxWriter {
+	return &MboxWriter{w: w}
+}
+
+// WriteJSON converts the json-serialized graph message produced during
+// backup, and appends it to the mbox.
+func (mw *MboxWriter) WriteJSON(body []byte) (int64, error) {
+	msg, err := support.CreateMessageFromBytes(body)
+	if err != nil {
+		return 0, errors.Wrap(err, "deserializing message")
+	}
+
+	return mw.Write(msg)
+}
+
+// Write appends the message to the mbox.  Returns the number of bytes
+// written.
+func (mw *MboxWriter) Write(msg models.Messageable) (int64, error) {
+	eml, err := ToEml(msg)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		buf  bytes.Buffer
+		date = time.Unix(0, 0)
+	)
+
+	if t := messageDate(msg); t != nil {
+		date = *t
+	}
+
+	buf.WriteString("From " + envelopeSender(msg) + " " + date.UTC().Format(time.ANSIC) + "\n")
+
+	scanner := bufio.NewScanner(bytes.NewReader(eml))
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), len(eml)+1)
+
+	for scanner.Scan() {
+		line := bytes.TrimSuffix(scanner.Bytes(), []byte("\r"))
+
+		if isFromLine(line) {
+			buf.WriteByte('>')
+		}
+
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, errors.Wrap(err, "reading message")
+	}
+
+	// messages are separated by an empty line.
+	buf.WriteByte('\n')
+
+	n, err := mw.w.Write(buf.Bytes())
+
+	return int64(n), errors.Wrap(err, "writing mbox message")
+}
+
+// isFromLine reports whether the line matches /^>*From /.
+func isFromLine(line []byte) bool {
+	return bytes.HasPrefix(bytes.TrimLeft(line, ">"), []byte("From "))
+}
+
+func envelopeSender(msg models.Messageable) string {
+	for _, r := range []models.Recipientable{msg.GetSender(), msg.GetFrom()} {
+		if r == nil || r.GetEmailAddress() == nil {
+			continue
+		}
+
+		if addr := ptrVal(r.GetEmailAddress().GetAddress()); len(addr) > 0 {
+			return addr
+		}
+	}
+
+	return mboxSender
+}
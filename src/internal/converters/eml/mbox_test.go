@@ -0,0 +1,66 @@
+package eml
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/connector/mockconnector"
+)
+
+type MboxUnitSuite struct {
+	suite.Suite
+}
+
+func TestMboxUnitSuite(t *testing.T) {
+	suite.Run(t, new(MboxUnitSuite))
+}
+
+func (suite *MboxUnitSuite) TestWrite() {
+	t := suite.T()
+
+	body := models.NewItemBody()
+	body.SetContent(ptr("first line\nFrom here on\n>From there on"))
+	body.SetContentType(ptr(models.TEXT_BODYTYPE))
+
+	draft := models.NewMessage()
+	draft.SetSubject(ptr("draft"))
+	draft.SetBody(body)
+
+	var (
+		buf bytes.Buffer
+		mw  = NewMboxWriter(&buf)
+	)
+
+	n, err := mw.WriteJSON(mockconnector.GetMockMessageBytes("mbox"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+
+	_, err = mw.Write(draft)
+	require.NoError(t, err)
+
+	var (
+		out        = buf.String()
+		separators = []string{}
+	)
+
+	assert.NotContains(t, out, "\r\n")
+
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "From ") {
+			separators = append(separators, line)
+		}
+	}
+
+	require.Len(t, separators, 2)
+	assert.Equal(t, "From foobar@8qzvrj.onmicrosoft.com Mon Sep 26 23:15:46 2022", separators[0])
+	assert.True(t, strings.HasPrefix(separators[1], "From "+mboxSender+" "), separators[1])
+
+	assert.Contains(t, out, "\n>From here on\n")
+	assert.Contains(t, out, "\n>>From there on\n")
+}
@@ -0,0 +1,504 @@
+// Package ics converts exchange events, as they are stored in a backup,
+// into iCalendar (RFC 5545) files.
+package ics
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/internal/connector/support"
+	cl "github.com/alcionai/corso/src/internal/converters/contentline"
+)
+
+const (
+	prodID = "-//Alcion//Corso//EN"
+
+	dateFormat     = "20060102"
+	localFormat    = "20060102T150405"
+	utcFormat      = "20060102T150405Z"
+	graphTimestamp = "2006-01-02T15:04:05.9999999"
+
+	utcZone = "UTC"
+)
+
+var (
+	htmlTags       = regexp.MustCompile(`(?s)<(head|style|script)[^>]*>.*?</(head|style|script)>|<[^>]*>`)
+	blankLines     = regexp.MustCompile(`\n\s*\n+`)
+	htmlLineBreaks = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>`)
+)
+
+// FromJSON converts the json-serialized graph event produced during backup
+// into an iCalendar.
+func FromJSON(body []byte) ([]byte, error) {
+	event, err := support.CreateEventFromBytes(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "deserializing event")
+	}
+
+	return ToICal(event)
+}
+
+// ToICal converts the event, including its attendees, recurrence, and file
+// attachments, into an iCalendar holding a single VEVENT.  Item and
+// reference attachments have no iCalendar representation, and are not
+// included.
+func ToICal(event models.Eventable) ([]byte, error) {
+	w := &cl.Writer{}
+
+	w.Line("BEGIN", "VCALENDAR")
+	w.Line("VERSION", "2.0")
+	w.Line("PRODID", prodID)
+	w.Line("CALSCALE", "GREGORIAN")
+
+	if err := writeEvent(w, event); err != nil {
+		return nil, errors.Wrapf(err, "converting event %s", ptrVal(event.GetId()))
+	}
+
+	w.Line("END", "VCALENDAR")
+
+	return w.Bytes(), nil
+}
+
+func writeEvent(w *cl.Writer, event models.Eventable) error {
+	w.Line("BEGIN", "VEVENT")
+
+	uid := ptrVal(event.GetICalUId())
+	if len(uid) == 0 {
+		uid = ptrVal(event.GetId())
+	}
+
+	w.Line("UID", cl.Escape(uid))
+
+	stamp := time.Now()
+	if lm := event.GetLastModifiedDateTime(); lm != nil {
+		stamp = *lm
+	}
+
+	w.Line("DTSTAMP", stamp.UTC().Format(utcFormat))
+
+	if c := event.GetCreatedDateTime(); c != nil {
+		w.Write("CREATED", c.UTC().Format(utcFormat))
+	}
+
+	if lm := event.GetLastModifiedDateTime(); lm != nil {
+		w.Write("LAST-MODIFIED", lm.UTC().Format(utcFormat))
+	}
+
+	allDay := ptrVal(event.GetIsAllDay())
+
+	for _, dt := range []struct {
+		name string
+		dttz models.DateTimeTimeZoneable
+	}{
+		{"DTSTART", event.GetStart()},
+		{"DTEND", event.GetEnd()},
+	} {
+		value, params, err := dateTime(dt.dttz, allDay)
+		if err != nil {
+			return errors.Wrap(err, strings.ToLower(dt.name))
+		}
+
+		w.Write(dt.name, value, params...)
+	}
+
+	if rrule := recurrenceRule(event.GetRecurrence(), allDay); len(rrule) > 0 {
+		w.Write("RRULE", rrule)
+	}
+
+	w.Write("SUMMARY", cl.Escape(ptrVal(event.GetSubject())))
+	writeDescription(w, event.GetBody())
+
+	if loc := event.GetLocation(); loc != nil {
+		w.Write("LOCATION", cl.Escape(ptrVal(loc.GetDisplayName())))
+	}
+
+	if om := event.GetOnlineMeeting(); om != nil && len(ptrVal(om.GetJoinUrl())) > 0 {
+		w.Write("URL", ptrVal(om.GetJoinUrl()))
+	} else {
+		w.Write("URL", ptrVal(event.GetOnlineMeetingUrl()))
+	}
+
+	if org := event.GetOrganizer(); org != nil && org.GetEmailAddress() != nil {
+		ea := org.GetEmailAddress()
+		w.Write("ORGANIZER", mailto(ea), commonName(ea)...)
+	}
+
+	for _, att := range event.GetAttendees() {
+		writeAttendee(w, att)
+	}
+
+	w.Line("STATUS", status(event))
+	w.Write("TRANSP", transparency(event.GetShowAs()))
+	w.Write("CLASS", classification(event.GetSensitivity()))
+	w.Write("PRIORITY", priority(event.GetImportance()))
+
+	if cats := event.GetCategories(); len(cats) > 0 {
+		escaped := make([]string, 0, len(cats))
+		for _, c := range cats {
+			escaped = append(escaped, cl.Escape(c))
+		}
+
+		w.Write("CATEGORIES", strings.Join(escaped, ","))
+	}
+
+	for _, att := range event.GetAttachments() {
+		if fa, ok := att.(models.FileAttachmentable); ok {
+			writeAttachment(w, fa)
+		}
+	}
+
+	if ptrVal(event.GetIsReminderOn()) {
+		w.Line("BEGIN", "VALARM")
+		w.Line("ACTION", "DISPLAY")
+		w.Line("DESCRIPTION", "Reminder")
+		w.Line("TRIGGER", fmt.Sprintf("-PT%dM", ptrVal(event.GetReminderMinutesBeforeStart())))
+		w.Line("END", "VALARM")
+	}
+
+	w.Line("END", "VEVENT")
+
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// dates
+// ---------------------------------------------------------------------------
+
+// dateTime formats the graph dateTimeTimeZone.  All-day events are written
+// as dates.  Times in UTC, or in a time zone recognized by the local tz
+// database, are written in UTC.  Other time zones (ex: Windows zone names,
+// which graph uses unless told otherwise) are written as local times
+// referencing the zone by TZID.
+func dateTime(dttz models.DateTimeTimeZoneable, allDay bool) (string, []cl.Param, error) {
+	if dttz == nil || len(ptrVal(dttz.GetDateTime())) == 0 {
+		return "", nil, nil
+	}
+
+	t, err := time.Parse(graphTimestamp, strings.TrimSuffix(ptrVal(dttz.GetDateTime()), "Z"))
+	if err != nil {
+		return "", nil, errors.Wrap(err, "parsing date time")
+	}
+
+	if allDay {
+		return t.Format(dateFormat), []cl.Param{{Name: "VALUE", Values: []string{"DATE"}}}, nil
+	}
+
+	tz := ptrVal(dttz.GetTimeZone())
+	if len(tz) == 0 || tz == utcZone {
+		return t.Format(utcFormat), nil, nil
+	}
+
+	if loc, err := time.LoadLocation(tz); err == nil {
+		local := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+		return local.UTC().Format(utcFormat), nil, nil
+	}
+
+	return t.Format(localFormat), []cl.Param{{Name: "TZID", Values: []string{tz}}}, nil
+}
+
+// ---------------------------------------------------------------------------
+// recurrence
+// ---------------------------------------------------------------------------
+
+var (
+	weekdays = map[models.DayOfWeek]string{
+		models.SUNDAY_DAYOFWEEK:    "SU",
+		models.MONDAY_DAYOFWEEK:    "MO",
+		models.TUESDAY_DAYOFWEEK:   "TU",
+		models.WEDNESDAY_DAYOFWEEK: "WE",
+		models.THURSDAY_DAYOFWEEK:  "TH",
+		models.FRIDAY_DAYOFWEEK:    "FR",
+		models.SATURDAY_DAYOFWEEK:  "SA",
+	}
+
+	weekIndexes = map[models.WeekIndex]string{
+		models.FIRST_WEEKINDEX:  "1",
+		models.SECOND_WEEKINDEX: "2",
+		models.THIRD_WEEKINDEX:  "3",
+		models.FOURTH_WEEKINDEX: "4",
+		models.LAST_WEEKINDEX:   "-1",
+	}
+)
+
+// recurrenceRule translates the graph recurrence pattern and range into an
+// RRULE value.  Returns an empty string if the event doesn't recur.
+func recurrenceRule(rec models.PatternedRecurrenceable, allDay bool) string {
+	if rec == nil || rec.GetPattern() == nil || rec.GetPattern().GetType() == nil {
+		return ""
+	}
+
+	var (
+		pattern = rec.GetPattern()
+		parts   = []string{}
+		byDay   = func() {
+			days := []string{}
+
+			for _, d := range pattern.GetDaysOfWeek() {
+				days = append(days, weekdays[d])
+			}
+
+			if len(days) > 0 {
+				parts = append(parts, "BYDAY="+strings.Join(days, ","))
+			}
+		}
+		bySetPos = func() {
+			if idx := pattern.GetIndex(); idx != nil {
+				parts = append(parts, "BYSETPOS="+weekIndexes[*idx])
+			}
+		}
+		byMonthDay = func() {
+			parts = append(parts, "BYMONTHDAY="+strconv.Itoa(int(ptrVal(pattern.GetDayOfMonth()))))
+		}
+		byMonth = func() {
+			parts = append(parts, "BYMONTH="+strconv.Itoa(int(ptrVal(pattern.GetMonth()))))
+		}
+	)
+
+	switch *pattern.GetType() {
+	case models.DAILY_RECURRENCEPATTERNTYPE:
+		parts = append(parts, "FREQ=DAILY")
+
+	case models.WEEKLY_RECURRENCEPATTERNTYPE:
+		parts = append(parts, "FREQ=WEEKLY")
+		byDay()
+
+		if fdw := pattern.GetFirstDayOfWeek(); fdw != nil {
+			parts = append(parts, "WKST="+weekdays[*fdw])
+		}
+
+	case models.ABSOLUTEMONTHLY_RECURRENCEPATTERNTYPE:
+		parts = append(parts, "FREQ=MONTHLY")
+		byMonthDay()
+
+	case models.RELATIVEMONTHLY_RECURRENCEPATTERNTYPE:
+		parts = append(parts, "FREQ=MONTHLY")
+		byDay()
+		bySetPos()
+
+	case models.ABSOLUTEYEARLY_RECURRENCEPATTERNTYPE:
+		parts = append(parts, "FREQ=YEARLY")
+		byMonth()
+		byMonthDay()
+
+	case models.RELATIVEYEARLY_RECURRENCEPATTERNTYPE:
+		parts = append(parts, "FREQ=YEARLY")
+		byMonth()
+		byDay()
+		bySetPos()
+
+	default:
+		return ""
+	}
+
+	if interval := ptrVal(pattern.GetInterval()); interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(int(interval)))
+	}
+
+	if rng := rec.GetRange(); rng != nil && rng.GetType() != nil {
+		switch *rng.GetType() {
+		case models.ENDDATE_RECURRENCERANGETYPE:
+			if end := rng.GetEndDate(); end != nil {
+				until := strings.ReplaceAll(end.String(), "-", "")
+
+				// UNTIL must match the value type of DTSTART.  The range
+				// end date is inclusive, so timed events recur until the
+				// end of that day.
+				if !allDay {
+					until += "T235959Z"
+				}
+
+				parts = append(parts, "UNTIL="+until)
+			}
+
+		case models.NUMBERED_RECURRENCERANGETYPE:
+			parts = append(parts, "COUNT="+strconv.Itoa(int(ptrVal(rng.GetNumberOfOccurrences()))))
+		}
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// ---------------------------------------------------------------------------
+// people
+// ---------------------------------------------------------------------------
+
+func mailto(ea models.EmailAddressable) string {
+	addr := ptrVal(ea.GetAddress())
+	if len(addr) == 0 {
+		return ""
+	}
+
+	return "mailto:" + addr
+}
+
+func commonName(ea models.EmailAddressable) []cl.Param {
+	if name := ptrVal(ea.GetName()); len(name) > 0 {
+		return []cl.Param{{Name: "CN", Values: []string{name}}}
+	}
+
+	return nil
+}
+
+var participationStatus = map[models.ResponseType]string{
+	models.ACCEPTED_RESPONSETYPE:            "ACCEPTED",
+	models.DECLINED_RESPONSETYPE:            "DECLINED",
+	models.TENTATIVELYACCEPTED_RESPONSETYPE: "TENTATIVE",
+	models.ORGANIZER_RESPONSETYPE:           "ACCEPTED",
+}
+
+func writeAttendee(w *cl.Writer, att models.Attendeeable) {
+	if att == nil || att.GetEmailAddress() == nil {
+		return
+	}
+
+	var (
+		ea       = att.GetEmailAddress()
+		params   = commonName(ea)
+		role     = "REQ-PARTICIPANT"
+		cuType   = "INDIVIDUAL"
+		partStat = "NEEDS-ACTION"
+	)
+
+	if t := att.GetType(); t != nil {
+		switch *t {
+		case models.OPTIONAL_ATTENDEETYPE:
+			role = "OPT-PARTICIPANT"
+		case models.RESOURCE_ATTENDEETYPE:
+			role = "NON-PARTICIPANT"
+			cuType = "RESOURCE"
+		}
+	}
+
+	if st := att.GetStatus(); st != nil && st.GetResponse() != nil {
+		if ps, ok := participationStatus[*st.GetResponse()]; ok {
+			partStat = ps
+		}
+	}
+
+	params = append(params,
+		cl.Param{Name: "CUTYPE", Values: []string{cuType}},
+		cl.Param{Name: "ROLE", Values: []string{role}},
+		cl.Param{Name: "PARTSTAT", Values: []string{partStat}})
+
+	w.Write("ATTENDEE", mailto(ea), params...)
+}
+
+// ---------------------------------------------------------------------------
+// properties
+// ---------------------------------------------------------------------------
+
+// writeDescription writes the event body.  Html bodies are written as both
+// a plain text DESCRIPTION, for clients which only understand text, and as
+// an X-ALT-DESC that preserves the original formatting.
+func writeDescription(w *cl.Writer, body models.ItemBodyable) {
+	if body == nil {
+		return
+	}
+
+	content := ptrVal(body.GetContent())
+
+	if body.GetContentType() == nil || *body.GetContentType() != models.HTML_BODYTYPE {
+		w.Write("DESCRIPTION", cl.Escape(content))
+		return
+	}
+
+	w.Write("DESCRIPTION", cl.Escape(htmlToText(content)))
+	w.Write("X-ALT-DESC", cl.Escape(content), cl.Param{Name: "FMTTYPE", Values: []string{"text/html"}})
+}
+
+func htmlToText(s string) string {
+	s = htmlLineBreaks.ReplaceAllString(s, "\n")
+	s = htmlTags.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	s = blankLines.ReplaceAllString(s, "\n\n")
+
+	return strings.TrimSpace(s)
+}
+
+func status(event models.Eventable) string {
+	if ptrVal(event.GetIsCancelled()) {
+		return "CANCELLED"
+	}
+
+	return "CONFIRMED"
+}
+
+func transparency(showAs *models.FreeBusyStatus) string {
+	if showAs == nil {
+		return ""
+	}
+
+	if *showAs == models.FREE_FREEBUSYSTATUS {
+		return "TRANSPARENT"
+	}
+
+	return "OPAQUE"
+}
+
+func classification(sens *models.Sensitivity) string {
+	if sens == nil {
+		return ""
+	}
+
+	switch *sens {
+	case models.PRIVATE_SENSITIVITY, models.PERSONAL_SENSITIVITY:
+		return "PRIVATE"
+	case models.CONFIDENTIAL_SENSITIVITY:
+		return "CONFIDENTIAL"
+	default:
+		return "PUBLIC"
+	}
+}
+
+func priority(imp *models.Importance) string {
+	if imp == nil {
+		return ""
+	}
+
+	switch *imp {
+	case models.HIGH_IMPORTANCE:
+		return "1"
+	case models.LOW_IMPORTANCE:
+		return "9"
+	default:
+		return "5"
+	}
+}
+
+// writeAttachment inlines the file attachment as a base64 encoded binary
+// ATTACH property.
+func writeAttachment(w *cl.Writer, att models.FileAttachmentable) {
+	ct := ptrVal(att.GetContentType())
+	if len(ct) == 0 {
+		ct = "application/octet-stream"
+	}
+
+	params := []cl.Param{
+		{Name: "FMTTYPE", Values: []string{ct}},
+		{Name: "ENCODING", Values: []string{"BASE64"}},
+		{Name: "VALUE", Values: []string{"BINARY"}},
+	}
+
+	if name := ptrVal(att.GetName()); len(name) > 0 {
+		params = append(params, cl.Param{Name: "X-FILENAME", Values: []string{name}})
+	}
+
+	w.Line("ATTACH", base64.StdEncoding.EncodeToString(att.GetContentBytes()), params...)
+}
+
+func ptrVal[T any](t *T) T {
+	var v T
+	if t != nil {
+		v = *t
+	}
+
+	return v
+}
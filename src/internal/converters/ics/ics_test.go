@@ -0,0 +1,186 @@
+package ics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/microsoft/kiota-abstractions-go/serialization"
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/connector/mockconnector"
+)
+
+type ICSUnitSuite struct {
+	suite.Suite
+}
+
+func TestICSUnitSuite(t *testing.T) {
+	suite.Run(t, new(ICSUnitSuite))
+}
+
+// unfold reverses line folding, so that assertions can match whole lines.
+func unfold(bs []byte) string {
+	return strings.ReplaceAll(string(bs), "\r\n ", "")
+}
+
+func (suite *ICSUnitSuite) TestFromJSON() {
+	table := []struct {
+		name   string
+		event  []byte
+		expect []string
+	}{
+		{
+			name: "basic",
+			event: mockconnector.GetMockEventWith(
+				"organizer@example.com", "lunch", "body", "preview",
+				"2022-10-19T20:00:00Z", "2022-10-19T20:30:00Z", false),
+			expect: []string{
+				"BEGIN:VEVENT",
+				"DTSTART:20221019T200000Z",
+				"DTEND:20221019T203000Z",
+				"SUMMARY:lunch",
+				"DESCRIPTION:body",
+				"X-ALT-DESC;FMTTYPE=text/html:",
+				"ORGANIZER;CN=Anu Pierson:mailto:organizer@example.com",
+				"BEGIN:VALARM",
+			},
+		},
+		{
+			name:   "attachment",
+			event:  mockconnector.GetMockEventWithAttachment("attached"),
+			expect: []string{"ATTACH;FMTTYPE="},
+		},
+		{
+			name:   "attendees",
+			event:  mockconnector.GetMockEventWithAttendeesBytes("attendees"),
+			expect: []string{"ATTENDEE;CN=George Martinez;CUTYPE=INDIVIDUAL;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION:"},
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			bs, err := FromJSON(test.event)
+			require.NoError(t, err)
+
+			cal := unfold(bs)
+			assert.True(t, strings.HasPrefix(cal, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"), cal)
+			assert.True(t, strings.HasSuffix(cal, "END:VEVENT\r\nEND:VCALENDAR\r\n"), cal)
+
+			for _, e := range test.expect {
+				assert.Contains(t, cal, "\r\n"+e)
+			}
+		})
+	}
+}
+
+func (suite *ICSUnitSuite) TestDateTime() {
+	table := []struct {
+		name         string
+		dt, tz       string
+		allDay       bool
+		expect       string
+		expectParams int
+	}{
+		{"utc", "2022-10-19T20:00:00.0000000", "UTC", false, "20221019T200000Z", 0},
+		{"iana zone", "2022-10-19T20:00:00.0000000", "America/New_York", false, "20221020T000000Z", 0},
+		{"windows zone", "2022-10-19T20:00:00.0000000", "Pacific Standard Time", false, "20221019T200000", 1},
+		{"all day", "2022-10-19T00:00:00.0000000", "UTC", true, "20221019", 1},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			dttz := models.NewDateTimeTimeZone()
+			dttz.SetDateTime(ptr(test.dt))
+			dttz.SetTimeZone(ptr(test.tz))
+
+			value, params, err := dateTime(dttz, test.allDay)
+			require.NoError(t, err)
+			assert.Equal(t, test.expect, value)
+			assert.Len(t, params, test.expectParams)
+		})
+	}
+}
+
+func (suite *ICSUnitSuite) TestRecurrenceRule() {
+	weekly := func() *models.RecurrencePattern {
+		p := models.NewRecurrencePattern()
+		p.SetType(ptr(models.WEEKLY_RECURRENCEPATTERNTYPE))
+		p.SetInterval(ptr(int32(2)))
+		p.SetDaysOfWeek([]models.DayOfWeek{models.MONDAY_DAYOFWEEK, models.WEDNESDAY_DAYOFWEEK})
+		p.SetFirstDayOfWeek(ptr(models.SUNDAY_DAYOFWEEK))
+
+		return p
+	}
+
+	relativeYearly := func() *models.RecurrencePattern {
+		p := models.NewRecurrencePattern()
+		p.SetType(ptr(models.RELATIVEYEARLY_RECURRENCEPATTERNTYPE))
+		p.SetInterval(ptr(int32(1)))
+		p.SetMonth(ptr(int32(11)))
+		p.SetDaysOfWeek([]models.DayOfWeek{models.THURSDAY_DAYOFWEEK})
+		p.SetIndex(ptr(models.FOURTH_WEEKINDEX))
+
+		return p
+	}
+
+	endDate := func() *models.RecurrenceRange {
+		r := models.NewRecurrenceRange()
+		r.SetType(ptr(models.ENDDATE_RECURRENCERANGETYPE))
+		r.SetEndDate(serialization.NewDateOnly(time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)))
+
+		return r
+	}
+
+	numbered := func() *models.RecurrenceRange {
+		r := models.NewRecurrenceRange()
+		r.SetType(ptr(models.NUMBERED_RECURRENCERANGETYPE))
+		r.SetNumberOfOccurrences(ptr(int32(10)))
+
+		return r
+	}
+
+	table := []struct {
+		name    string
+		pattern *models.RecurrencePattern
+		rng     *models.RecurrenceRange
+		allDay  bool
+		expect  string
+	}{
+		{
+			name:    "weekly until",
+			pattern: weekly(),
+			rng:     endDate(),
+			expect:  "FREQ=WEEKLY;BYDAY=MO,WE;WKST=SU;INTERVAL=2;UNTIL=20230301T235959Z",
+		},
+		{
+			name:    "weekly until, all day",
+			pattern: weekly(),
+			rng:     endDate(),
+			allDay:  true,
+			expect:  "FREQ=WEEKLY;BYDAY=MO,WE;WKST=SU;INTERVAL=2;UNTIL=20230301",
+		},
+		{
+			name:    "relative yearly count",
+			pattern: relativeYearly(),
+			rng:     numbered(),
+			expect:  "FREQ=YEARLY;BYMONTH=11;BYDAY=TH;BYSETPOS=4;COUNT=10",
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			rec := models.NewPatternedRecurrence()
+			rec.SetPattern(test.pattern)
+			rec.SetRange(test.rng)
+
+			assert.Equal(t, test.expect, recurrenceRule(rec, test.allDay))
+		})
+	}
+
+	assert.Empty(suite.T(), recurrenceRule(nil, false))
+}
+
+func ptr[T any](t T) *T {
+	return &t
+}
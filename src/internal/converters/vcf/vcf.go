@@ -0,0 +1,184 @@
+// Package vcf converts exchange contacts, as they are stored in a backup,
+// into vCard 4.0 (RFC 6350) files.
+package vcf
+
+import (
+	"strings"
+
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/internal/connector/support"
+	cl "github.com/alcionai/corso/src/internal/converters/contentline"
+)
+
+const (
+	dateFormat      = "20060102"
+	timestampFormat = "20060102T150405Z"
+)
+
+// FromJSON converts the json-serialized graph contact produced during
+// backup into a vCard.
+func FromJSON(body []byte) ([]byte, error) {
+	contact, err := support.CreateContactFromBytes(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "deserializing contact")
+	}
+
+	return ToVCard(contact), nil
+}
+
+// ToVCard converts the contact into a vCard 4.0.
+func ToVCard(contact models.Contactable) []byte {
+	w := &cl.Writer{}
+
+	w.Line("BEGIN", "VCARD")
+	w.Line("VERSION", "4.0")
+
+	if id := ptrVal(contact.GetId()); len(id) > 0 {
+		w.Write("UID", cl.Escape(id))
+	}
+
+	// FN is the only required property.
+	w.Line("FN", cl.Escape(formattedName(contact)))
+
+	w.Write("N", cl.Join(
+		ptrVal(contact.GetSurname()),
+		ptrVal(contact.GetGivenName()),
+		ptrVal(contact.GetMiddleName()),
+		ptrVal(contact.GetTitle()),
+		ptrVal(contact.GetGeneration())))
+	w.Write("NICKNAME", cl.Escape(ptrVal(contact.GetNickName())))
+
+	if bday := contact.GetBirthday(); bday != nil {
+		w.Write("BDAY", bday.Format(dateFormat), cl.Param{Name: "VALUE", Values: []string{"date"}})
+	}
+
+	for _, ea := range contact.GetEmailAddresses() {
+		if ea == nil {
+			continue
+		}
+
+		w.Write("EMAIL", cl.Escape(ptrVal(ea.GetAddress())))
+	}
+
+	phones := []struct {
+		numbers []string
+		types   []string
+	}{
+		{[]string{ptrVal(contact.GetMobilePhone())}, []string{"cell", "voice"}},
+		{contact.GetBusinessPhones(), []string{"work", "voice"}},
+		{contact.GetHomePhones(), []string{"home", "voice"}},
+	}
+
+	for _, ph := range phones {
+		for _, num := range ph.numbers {
+			w.Write("TEL", cl.Escape(num), cl.Param{Name: "TYPE", Values: ph.types})
+		}
+	}
+
+	addresses := []struct {
+		addr models.PhysicalAddressable
+		typ  string
+	}{
+		{contact.GetHomeAddress(), "home"},
+		{contact.GetBusinessAddress(), "work"},
+		{contact.GetOtherAddress(), ""},
+	}
+
+	for _, a := range addresses {
+		var params []cl.Param
+		if len(a.typ) > 0 {
+			params = append(params, cl.Param{Name: "TYPE", Values: []string{a.typ}})
+		}
+
+		w.Write("ADR", address(a.addr), params...)
+	}
+
+	for _, im := range contact.GetImAddresses() {
+		w.Write("IMPP", cl.Escape(im))
+	}
+
+	w.Write("TITLE", cl.Escape(ptrVal(contact.GetJobTitle())))
+	w.Write("ROLE", cl.Escape(ptrVal(contact.GetProfession())))
+	w.Write("ORG", cl.Join(ptrVal(contact.GetCompanyName()), ptrVal(contact.GetDepartment())))
+	w.Write("URL", ptrVal(contact.GetBusinessHomePage()))
+
+	if spouse := ptrVal(contact.GetSpouseName()); len(spouse) > 0 {
+		w.Write("RELATED", cl.Escape(spouse),
+			cl.Param{Name: "TYPE", Values: []string{"spouse"}},
+			cl.Param{Name: "VALUE", Values: []string{"text"}})
+	}
+
+	for _, child := range contact.GetChildren() {
+		w.Write("RELATED", cl.Escape(child),
+			cl.Param{Name: "TYPE", Values: []string{"child"}},
+			cl.Param{Name: "VALUE", Values: []string{"text"}})
+	}
+
+	if cats := contact.GetCategories(); len(cats) > 0 {
+		escaped := make([]string, 0, len(cats))
+		for _, c := range cats {
+			escaped = append(escaped, cl.Escape(c))
+		}
+
+		w.Write("CATEGORIES", strings.Join(escaped, ","))
+	}
+
+	w.Write("NOTE", cl.Escape(ptrVal(contact.GetPersonalNotes())))
+
+	if rev := contact.GetLastModifiedDateTime(); rev != nil {
+		w.Write("REV", rev.UTC().Format(timestampFormat))
+	}
+
+	w.Line("END", "VCARD")
+
+	return w.Bytes()
+}
+
+// formattedName falls back through the contact's names, since graph doesn't
+// require a display name.
+func formattedName(contact models.Contactable) string {
+	if dn := ptrVal(contact.GetDisplayName()); len(dn) > 0 {
+		return dn
+	}
+
+	name := strings.TrimSpace(ptrVal(contact.GetGivenName()) + " " + ptrVal(contact.GetSurname()))
+	if len(name) > 0 {
+		return name
+	}
+
+	for _, ea := range contact.GetEmailAddresses() {
+		if ea != nil && len(ptrVal(ea.GetAddress())) > 0 {
+			return ptrVal(ea.GetAddress())
+		}
+	}
+
+	return ptrVal(contact.GetCompanyName())
+}
+
+// address produces the structured ADR value:
+// po box; extended address; street; locality; region; postal code; country
+func address(addr models.PhysicalAddressable) string {
+	if addr == nil {
+		return ""
+	}
+
+	return cl.Join(
+		"",
+		"",
+		ptrVal(addr.GetStreet()),
+		ptrVal(addr.GetCity()),
+		ptrVal(addr.GetState()),
+		ptrVal(addr.GetPostalCode()),
+		ptrVal(addr.GetCountryOrRegion()))
+}
+
+func ptrVal[T any](t *T) T {
+	var v T
+	if t != nil {
+		v = *t
+	}
+
+	return v
+}
@@ -0,0 +1,83 @@
+package vcf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/connector/mockconnector"
+)
+
+type VCFUnitSuite struct {
+	suite.Suite
+}
+
+func TestVCFUnitSuite(t *testing.T) {
+	suite.Run(t, new(VCFUnitSuite))
+}
+
+func (suite *VCFUnitSuite) TestFromJSON() {
+	t := suite.T()
+
+	bs, err := FromJSON(mockconnector.GetMockContactBytes("vcf"))
+	require.NoError(t, err)
+
+	card := string(bs)
+	assert.True(t, strings.HasPrefix(card, "BEGIN:VCARD\r\nVERSION:4.0\r\n"), card)
+	assert.True(t, strings.HasSuffix(card, "END:VCARD\r\n"), card)
+	assert.Contains(t, card, "\r\nFN:")
+	assert.Contains(t, card, "\r\nN:")
+	assert.Contains(t, card, "vcf")
+}
+
+func (suite *VCFUnitSuite) TestToVCard() {
+	t := suite.T()
+
+	email := models.NewEmailAddress()
+	email.SetAddress(ptr("jane@example.com"))
+
+	home := models.NewPhysicalAddress()
+	home.SetStreet(ptr("1 Main St"))
+	home.SetCity(ptr("Springfield"))
+	home.SetPostalCode(ptr("12345"))
+
+	contact := models.NewContact()
+	contact.SetId(ptr("id"))
+	contact.SetGivenName(ptr("Jane"))
+	contact.SetSurname(ptr("Doe"))
+	contact.SetEmailAddresses([]models.EmailAddressable{email})
+	contact.SetMobilePhone(ptr("555-0100"))
+	contact.SetBusinessPhones([]string{"555-0101"})
+	contact.SetHomeAddress(home)
+	contact.SetCompanyName(ptr("Acme, Inc."))
+	contact.SetPersonalNotes(ptr("line one\nline two"))
+
+	card := string(ToVCard(contact))
+
+	expect := []string{
+		"UID:id",
+		"FN:Jane Doe",
+		"N:Doe;Jane;;;",
+		"EMAIL:jane@example.com",
+		"TEL;TYPE=cell,voice:555-0100",
+		"TEL;TYPE=work,voice:555-0101",
+		"ADR;TYPE=home:;;1 Main St;Springfield;;12345;",
+		`ORG:Acme\, Inc.;`,
+		`NOTE:line one\nline two`,
+	}
+
+	for _, e := range expect {
+		assert.Contains(t, card, "\r\n"+e+"\r\n")
+	}
+
+	assert.NotContains(t, card, "ADR;TYPE=work")
+	assert.NotContains(t, card, "BDAY")
+}
+
+func ptr[T any](t T) *T {
+	return &t
+}
@@ -87,6 +87,13 @@ type StreamModTime interface {
 	ModTime() time.Time
 }
 
+// StreamImmutable is used to mark streams whose data never changes once
+// written. If the base snapshot of an incremental backup already holds an
+// immutable stream, the copy in the base is kept and the stream isn't read.
+type StreamImmutable interface {
+	Immutable() bool
+}
+
 // ------------------------------------------------------------------------------------------------
 // functionality
 // ------------------------------------------------------------------------------------------------
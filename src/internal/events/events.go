@@ -30,6 +30,8 @@ const (
 	BackupEnd    = "Backup End"
 	RestoreStart = "Restore Start"
 	RestoreEnd   = "Restore End"
+	ExportStart  = "Export Start"
+	ExportEnd    = "Export End"
 
 	// Event Data Keys
 	BackupCreateTime = "backup_creation_time"
@@ -38,7 +40,9 @@ const (
 	DataStored       = "data_stored"
 	Duration         = "duration"
 	EndTime          = "end_time"
+	ExportID         = "export_id"
 	ItemsRead        = "items_read"
+	ItemsSkipped     = "items_skipped"
 	ItemsWritten     = "items_written"
 	Resources        = "resources"
 	RestoreID        = "restore_id"
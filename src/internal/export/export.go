@@ -0,0 +1,310 @@
+// Package export writes the contents of restored data collections to the
+// local filesystem, as an alternative to restoring them into M365.
+package export
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/internal/connector/onedrive"
+	"github.com/alcionai/corso/src/internal/converters/eml"
+	"github.com/alcionai/corso/src/internal/converters/ics"
+	"github.com/alcionai/corso/src/internal/converters/vcf"
+	"github.com/alcionai/corso/src/internal/data"
+	"github.com/alcionai/corso/src/pkg/control"
+	"github.com/alcionai/corso/src/pkg/logger"
+	"github.com/alcionai/corso/src/pkg/path"
+)
+
+const (
+	dirPerms  = 0o700
+	filePerms = 0o600
+
+	// jsonExt is appended to the names of items which are stored as
+	// serialized graph api objects.
+	jsonExt = ".json"
+	emlExt  = ".eml"
+	mboxExt = ".mbox"
+	vcfExt  = ".vcf"
+	icsExt  = ".ics"
+)
+
+// convertFunc transforms the serialized graph object retrieved during
+// backup into another file format.
+type convertFunc func([]byte) ([]byte, error)
+
+// Stats describes the data written by an export.
+type Stats struct {
+	ItemsWritten int
+	BytesWritten int64
+}
+
+// Collections writes every item in the collections to a file under the
+// root directory.  The collection's hierarchy is preserved, producing the
+// layout:
+//
+//	<root>/<service>/<resource owner>/<category>/<folders...>/<item>
+//
+// Drive items (OneDrive files, SharePoint library items) are written under
+// their original names.  Mail is written in the cfg.MailFormat, either as
+// one .eml file per message, or as one .mbox file per folder, written
+// alongside the folder's directory.  Contacts and events are written in the
+// cfg.ContactFormat and cfg.EventFormat, as .vcf and .ics files.  All other
+// items are written as the json-serialized graph objects retrieved during
+// backup.
+// Items that fail to export are skipped and their errors are returned
+// together once all collections have been processed.
+func Collections(
+	ctx context.Context,
+	root string,
+	cfg control.ExportConfig,
+	dcs []data.Collection,
+) (Stats, error) {
+	var (
+		stats      Stats
+		errs       *multierror.Error
+		errUpdater = func(err error) {
+			errs = multierror.Append(errs, err)
+		}
+	)
+
+	for _, dc := range dcs {
+		var (
+			fp       = dc.FullPath()
+			canceled bool
+		)
+
+		dir, err := collectionDir(root, fp)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		logger.Ctx(ctx).Debugw("exporting collection", "path", fp, "dir", dir)
+
+		if fp.Category() == path.EmailCategory && cfg.MailFormat == control.MboxFormat {
+			canceled, err = exportMbox(ctx, dir+mboxExt, dc, &stats, errUpdater)
+		} else {
+			canceled, err = exportFiles(ctx, dir, cfg, dc, &stats, errUpdater)
+		}
+
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		if canceled {
+			errs = multierror.Append(errs, errors.Wrap(ctx.Err(), "context canceled"))
+			break
+		}
+	}
+
+	return stats, errs.ErrorOrNil()
+}
+
+// exportFiles writes each item in the collection into its own file within
+// the directory.  Returns true if the context was canceled before all items
+// were exported.
+func exportFiles(
+	ctx context.Context,
+	dir string,
+	cfg control.ExportConfig,
+	dc data.Collection,
+	stats *Stats,
+	errUpdater func(error),
+) (bool, error) {
+	if err := os.MkdirAll(dir, dirPerms); err != nil {
+		return false, errors.Wrapf(err, "creating directory %s", dir)
+	}
+
+	ext, convert := itemFormat(cfg, dc.FullPath().Category())
+
+	canceled := exportItems(ctx, dc, stats, errUpdater, func(item data.Stream) (int64, error) {
+		return writeItem(filepath.Join(dir, safeName(onedrive.ItemName(item, item.UUID()))+ext), item, convert)
+	})
+
+	return canceled, nil
+}
+
+// exportMbox appends every message in the collection to a single mbox file.
+// Returns true if the context was canceled before all items were exported.
+func exportMbox(
+	ctx context.Context,
+	fp string,
+	dc data.Collection,
+	stats *Stats,
+	errUpdater func(error),
+) (bool, error) {
+	if err := os.MkdirAll(filepath.Dir(fp), dirPerms); err != nil {
+		return false, errors.Wrapf(err, "creating directory %s", filepath.Dir(fp))
+	}
+
+	f, err := os.OpenFile(fp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, filePerms)
+	if err != nil {
+		return false, errors.Wrapf(err, "creating mbox %s", fp)
+	}
+
+	mw := eml.NewMboxWriter(f)
+
+	canceled := exportItems(ctx, dc, stats, errUpdater, func(item data.Stream) (int64, error) {
+		bs, err := readItem(item)
+		if err != nil {
+			return 0, err
+		}
+
+		return mw.WriteJSON(bs)
+	})
+
+	return canceled, errors.Wrapf(f.Close(), "closing mbox %s", fp)
+}
+
+// exportItems hands each item in the collection to the write func.
+// Returns true if the context was canceled before all items were exported.
+func exportItems(
+	ctx context.Context,
+	dc data.Collection,
+	stats *Stats,
+	errUpdater func(error),
+	write func(data.Stream) (int64, error),
+) bool {
+	items := dc.Items()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true
+
+		case item, ok := <-items:
+			if !ok {
+				return false
+			}
+
+			n, err := write(item)
+			if err != nil {
+				errUpdater(errors.Wrapf(err, "exporting item %s", item.UUID()))
+				continue
+			}
+
+			stats.ItemsWritten++
+			stats.BytesWritten += n
+		}
+	}
+}
+
+func readItem(item data.Stream) ([]byte, error) {
+	rc := item.ToReader()
+	defer rc.Close()
+
+	bs, err := io.ReadAll(rc)
+
+	return bs, errors.Wrap(err, "reading item")
+}
+
+// writeItem writes the item into a new file at fp, first transforming its
+// contents with the convert func, if one is provided.
+func writeItem(fp string, item data.Stream, convert convertFunc) (int64, error) {
+	var r io.Reader
+
+	if convert == nil {
+		rc := item.ToReader()
+		defer rc.Close()
+
+		r = rc
+	} else {
+		bs, err := readItem(item)
+		if err != nil {
+			return 0, err
+		}
+
+		bs, err = convert(bs)
+		if err != nil {
+			return 0, err
+		}
+
+		r = bytes.NewReader(bs)
+	}
+
+	f, err := os.OpenFile(fp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, filePerms)
+	if err != nil {
+		return 0, errors.Wrap(err, "creating file")
+	}
+
+	n, err := io.Copy(f, r)
+	if err != nil {
+		f.Close() //nolint:errcheck
+		return 0, errors.Wrap(err, "writing file")
+	}
+
+	return n, errors.Wrap(f.Close(), "closing file")
+}
+
+// collectionDir produces the directory that holds the collection's items.
+func collectionDir(root string, p path.Path) (string, error) {
+	folders := p.Folders()
+
+	switch p.Category() {
+	case path.FilesCategory, path.LibrariesCategory:
+		// drive collections are prefixed with `drives/<driveID>/root:`, which
+		// has no meaning outside of the drive.
+		dp, err := path.ToOneDrivePath(p)
+		if err != nil {
+			return "", errors.Wrapf(err, "exporting collection %s", p)
+		}
+
+		folders = dp.Folders
+	}
+
+	elems := []string{
+		root,
+		p.Service().String(),
+		safeName(p.ResourceOwner()),
+		p.Category().String(),
+	}
+
+	for _, f := range folders {
+		elems = append(elems, safeName(f))
+	}
+
+	return filepath.Join(elems...), nil
+}
+
+// itemFormat produces the file extension used for items in the category,
+// along with the conversion, if any, that gets applied to their contents.
+func itemFormat(cfg control.ExportConfig, cat path.CategoryType) (string, convertFunc) {
+	switch cat {
+	case path.FilesCategory, path.LibrariesCategory:
+		return "", nil
+	case path.EmailCategory:
+		if cfg.MailFormat == control.EMLFormat {
+			return emlExt, eml.FromJSON
+		}
+	case path.ContactsCategory:
+		if cfg.ContactFormat == control.VCFFormat {
+			return vcfExt, vcf.FromJSON
+		}
+	case path.EventsCategory:
+		if cfg.EventFormat == control.ICSFormat {
+			return icsExt, ics.FromJSON
+		}
+	}
+
+	return jsonExt, nil
+}
+
+// safeName ensures that the name can be used as a single element within
+// a local filesystem path.
+func safeName(name string) string {
+	switch name {
+	case "", ".", "..":
+		return "_" + name
+	}
+
+	return strings.NewReplacer("/", "_", string(filepath.Separator), "_").Replace(name)
+}
@@ -0,0 +1,179 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/connector/mockconnector"
+	"github.com/alcionai/corso/src/internal/data"
+	"github.com/alcionai/corso/src/internal/tester"
+	"github.com/alcionai/corso/src/pkg/control"
+	"github.com/alcionai/corso/src/pkg/path"
+)
+
+type ExportUnitSuite struct {
+	suite.Suite
+}
+
+func TestExportUnitSuite(t *testing.T) {
+	suite.Run(t, new(ExportUnitSuite))
+}
+
+func (suite *ExportUnitSuite) TestCollections() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+	root := t.TempDir()
+
+	mailPath, err := path.Builder{}.
+		Append("Inbox", "Archive").
+		ToDataLayerExchangePathForCategory("tenant", "user", path.EmailCategory, false)
+	require.NoError(t, err)
+
+	drivePath, err := path.Builder{}.
+		Append("drives", "driveID", "root:", "docs").
+		ToDataLayerOneDrivePath("tenant", "user", false)
+	require.NoError(t, err)
+
+	mail := mockconnector.NewMockExchangeCollection(mailPath, 2)
+	files := mockconnector.NewMockExchangeCollection(drivePath, 1)
+	files.Names[0] = "report.docx"
+
+	stats, err := Collections(ctx, root, control.ExportConfig{MailFormat: control.JSONFormat}, []data.Collection{mail, files})
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.ItemsWritten)
+
+	var expectBytes int64
+	for _, d := range append(mail.Data, files.Data...) {
+		expectBytes += int64(len(d))
+	}
+
+	assert.Equal(t, expectBytes, stats.BytesWritten)
+
+	for i, name := range mail.Names {
+		bs, err := os.ReadFile(filepath.Join(root, "exchange", "user", "email", "Inbox", "Archive", name+".json"))
+		require.NoError(t, err)
+		assert.Equal(t, mail.Data[i], bs)
+	}
+
+	bs, err := os.ReadFile(filepath.Join(root, "onedrive", "user", "files", "docs", "report.docx"))
+	require.NoError(t, err)
+	assert.Equal(t, files.Data[0], bs)
+}
+
+func (suite *ExportUnitSuite) TestCollections_mailFormats() {
+	mailPath, err := path.Builder{}.
+		Append("Inbox").
+		ToDataLayerExchangePathForCategory("tenant", "user", path.EmailCategory, false)
+	require.NoError(suite.T(), err)
+
+	mailFile := func(root string, elems ...string) string {
+		return filepath.Join(append([]string{root, "exchange", "user", "email"}, elems...)...)
+	}
+
+	table := []struct {
+		name   string
+		format control.ExportFormat
+		expect func(t *testing.T, root string, mail *mockconnector.MockExchangeDataCollection)
+	}{
+		{
+			name:   "eml",
+			format: control.EMLFormat,
+			expect: func(t *testing.T, root string, mail *mockconnector.MockExchangeDataCollection) {
+				for _, name := range mail.Names {
+					bs, err := os.ReadFile(mailFile(root, "Inbox", name+emlExt))
+					require.NoError(t, err)
+					assert.Contains(t, string(bs), "MIME-Version: 1.0")
+				}
+			},
+		},
+		{
+			name:   "mbox",
+			format: control.MboxFormat,
+			expect: func(t *testing.T, root string, mail *mockconnector.MockExchangeDataCollection) {
+				bs, err := os.ReadFile(mailFile(root, "Inbox"+mboxExt))
+				require.NoError(t, err)
+				assert.Equal(t, len(mail.Names), strings.Count(string(bs), "\nFrom ")+1)
+
+				_, err = os.Stat(mailFile(root, "Inbox"))
+				assert.True(t, os.IsNotExist(err), "no per-item directory")
+			},
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			ctx, flush := tester.NewContext()
+			defer flush()
+
+			root := t.TempDir()
+			mail := mockconnector.NewMockExchangeCollection(mailPath, 3)
+
+			stats, err := Collections(ctx, root, control.ExportConfig{MailFormat: test.format}, []data.Collection{mail})
+			require.NoError(t, err)
+			assert.Equal(t, 3, stats.ItemsWritten)
+
+			test.expect(t, root, mail)
+		})
+	}
+}
+
+func (suite *ExportUnitSuite) TestCollections_contactsAndEvents() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+	root := t.TempDir()
+
+	contactPath, err := path.Builder{}.
+		Append("Contacts").
+		ToDataLayerExchangePathForCategory("tenant", "user", path.ContactsCategory, false)
+	require.NoError(t, err)
+
+	eventPath, err := path.Builder{}.
+		Append("Calendar").
+		ToDataLayerExchangePathForCategory("tenant", "user", path.EventsCategory, false)
+	require.NoError(t, err)
+
+	contacts := mockconnector.NewMockExchangeCollection(contactPath, 1)
+	contacts.Data[0] = mockconnector.GetMockContactBytes("export")
+
+	events := mockconnector.NewMockExchangeCollection(eventPath, 1)
+	events.Data[0] = mockconnector.GetDefaultMockEventBytes("export")
+
+	stats, err := Collections(ctx, root, control.Defaults().Export, []data.Collection{contacts, events})
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.ItemsWritten)
+
+	bs, err := os.ReadFile(filepath.Join(root, "exchange", "user", "contacts", "Contacts", contacts.Names[0]+vcfExt))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(bs), "BEGIN:VCARD"))
+
+	bs, err = os.ReadFile(filepath.Join(root, "exchange", "user", "events", "Calendar", events.Names[0]+icsExt))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(bs), "BEGIN:VCALENDAR"))
+}
+
+func (suite *ExportUnitSuite) TestSafeName() {
+	table := []struct {
+		name   string
+		expect string
+	}{
+		{"file.txt", "file.txt"},
+		{"a/b", "a_b"},
+		{"..", "_.."},
+		{".", "_."},
+		{"", "_"},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expect, safeName(test.name))
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package kopia
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	azureblob "github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/kopia/kopia/repo/blob"
+	"github.com/kopia/kopia/repo/blob/retrying"
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/pkg/storage"
+)
+
+const (
+	azureStorageType = "corsoAzureBlob"
+	// matches the domain used by kopia's azure provider.
+	defaultAzureDomain = "blob.core.windows.net"
+	// blob metadata key holding the kopia-provided mod time.  Azure requires
+	// metadata keys to be valid C# identifiers, and may return them lower-cased.
+	azureModTimeKey = "Kopiamtime"
+)
+
+// kopia's own azure provider is built against an azblob release that is not
+// compatible with the azcore version used by the rest of corso.  azureStorage
+// provides the same blob.Storage behavior using the current azblob sdk, and
+// additionally allows a full endpoint url so that emulators (ex: Azurite) can
+// be targeted.
+type azureStorage struct {
+	opts      azureOptions
+	container *container.Client
+}
+
+// azureOptions is the json-serializable form of the azure configuration that
+// kopia persists in its repository config.  It is used to re-create the
+// storage when the repository is opened.
+type azureOptions struct {
+	Container   string `json:"container"`
+	Prefix      string `json:"prefix,omitempty"`
+	AccountName string `json:"accountName"`
+	AccountKey  string `json:"accountKey,omitempty" kopia:"sensitive"`
+	SASToken    string `json:"sasToken,omitempty" kopia:"sensitive"`
+	Endpoint    string `json:"endpoint,omitempty"`
+}
+
+func init() {
+	blob.AddSupportedStorage(azureStorageType, azureOptions{}, newAzureStorage)
+}
+
+func azureBlobStorage(ctx context.Context, s storage.Storage) (blob.Storage, error) {
+	cfg, err := s.AzureConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := azureOptions{
+		Container:   cfg.Container,
+		Prefix:      cfg.Prefix,
+		AccountName: cfg.AccountName,
+		AccountKey:  cfg.AccountKey,
+		SASToken:    cfg.SASToken,
+		Endpoint:    cfg.Endpoint,
+	}
+
+	return newAzureStorage(ctx, &opts, false)
+}
+
+func newAzureStorage(ctx context.Context, opts *azureOptions, _ bool) (blob.Storage, error) {
+	cc, err := azureContainerClient(*opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating azure container client")
+	}
+
+	raw := &azureStorage{
+		opts:      *opts,
+		container: cc,
+	}
+
+	// verify the connection is functional by listing blobs in the container,
+	// which fails if the container does not exist.  The prefix used won't
+	// exist, so no objects are iterated.
+	nonExistentPrefix := blob.ID(fmt.Sprintf("corso-azure-storage-initializing-%d", time.Now().UnixNano()))
+
+	err = raw.ListBlobs(ctx, nonExistentPrefix, func(blob.Metadata) error { return nil })
+	if err != nil {
+		return nil, errors.Wrap(err, "listing blobs in azure container")
+	}
+
+	return retrying.NewWrapper(raw), nil
+}
+
+// azureServiceURL returns the endpoint of the blob service.  If no endpoint
+// is configured, the public azure domain for the account is used.
+func azureServiceURL(opts azureOptions) string {
+	if len(opts.Endpoint) > 0 {
+		return strings.TrimSuffix(opts.Endpoint, "/")
+	}
+
+	return fmt.Sprintf("https://%s.%s", opts.AccountName, defaultAzureDomain)
+}
+
+func azureContainerClient(opts azureOptions) (*container.Client, error) {
+	containerURL := azureServiceURL(opts) + "/" + opts.Container
+
+	if len(opts.SASToken) > 0 {
+		return container.NewClientWithNoCredential(
+			containerURL+"?"+strings.TrimPrefix(opts.SASToken, "?"),
+			nil)
+	}
+
+	cred, err := container.NewSharedKeyCredential(opts.AccountName, opts.AccountKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating azure shared key credential")
+	}
+
+	return container.NewClientWithSharedKeyCredential(containerURL, cred, nil)
+}
+
+func (az *azureStorage) objectName(b blob.ID) string {
+	return az.opts.Prefix + string(b)
+}
+
+func (az *azureStorage) GetCapacity(ctx context.Context) (blob.Capacity, error) {
+	return blob.Capacity{}, blob.ErrNotAVolume
+}
+
+func (az *azureStorage) GetBlob(
+	ctx context.Context,
+	b blob.ID,
+	offset, length int64,
+	output blob.OutputBuffer,
+) error {
+	if offset < 0 {
+		return errors.Wrap(blob.ErrInvalidRange, "invalid offset")
+	}
+
+	opts := &azureblob.DownloadStreamOptions{}
+
+	switch {
+	case length > 0:
+		opts.Range = azureblob.HTTPRange{Offset: offset, Count: length}
+	case length == 0:
+		// only verify the existence of the range.
+		opts.Range = azureblob.HTTPRange{Offset: offset, Count: 1}
+	}
+
+	resp, err := az.container.NewBlobClient(az.objectName(b)).DownloadStream(ctx, opts)
+	if err != nil {
+		return translateAzureError(err)
+	}
+
+	defer resp.Body.Close()
+
+	if length == 0 {
+		return nil
+	}
+
+	if _, err := io.Copy(output, resp.Body); err != nil {
+		return translateAzureError(err)
+	}
+
+	return blob.EnsureLengthExactly(output.Length(), length)
+}
+
+func (az *azureStorage) GetMetadata(ctx context.Context, b blob.ID) (blob.Metadata, error) {
+	props, err := az.container.NewBlobClient(az.objectName(b)).GetProperties(ctx, nil)
+	if err != nil {
+		return blob.Metadata{}, errors.Wrap(translateAzureError(err), "getting blob properties")
+	}
+
+	bm := blob.Metadata{
+		BlobID:    b,
+		Length:    derefInt64(props.ContentLength),
+		Timestamp: derefTime(props.LastModified),
+	}
+
+	for k, v := range props.Metadata {
+		if t, ok := azureModTime(k, v); ok {
+			bm.Timestamp = t
+		}
+	}
+
+	return bm, nil
+}
+
+func (az *azureStorage) PutBlob(
+	ctx context.Context,
+	b blob.ID,
+	data blob.Bytes,
+	opts blob.PutOptions,
+) error {
+	switch {
+	case opts.HasRetentionOptions():
+		return errors.Wrap(blob.ErrUnsupportedPutBlobOption, "blob-retention")
+	case opts.DoNotRecreate:
+		return errors.Wrap(blob.ErrUnsupportedPutBlobOption, "do-not-recreate")
+	}
+
+	var md map[string]string
+	if !opts.SetModTime.IsZero() {
+		md = map[string]string{
+			azureModTimeKey: strconv.FormatInt(opts.SetModTime.UnixNano(), 10),
+		}
+	}
+
+	resp, err := az.container.
+		NewBlockBlobClient(az.objectName(b)).
+		Upload(ctx, data.Reader(), &blockblob.UploadOptions{Metadata: md})
+	if err != nil {
+		return translateAzureError(err)
+	}
+
+	if opts.GetModTime != nil && resp.LastModified != nil {
+		*opts.GetModTime = *resp.LastModified
+	}
+
+	return nil
+}
+
+func (az *azureStorage) DeleteBlob(ctx context.Context, b blob.ID) error {
+	_, err := az.container.NewBlobClient(az.objectName(b)).Delete(ctx, nil)
+
+	err = translateAzureError(err)
+	// don't return an error if the blob is already deleted.
+	if errors.Is(err, blob.ErrBlobNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+func (az *azureStorage) ListBlobs(
+	ctx context.Context,
+	prefix blob.ID,
+	callback func(blob.Metadata) error,
+) error {
+	fullPrefix := az.objectName(prefix)
+
+	pager := az.container.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{
+		Prefix:  &fullPrefix,
+		Include: container.ListBlobsInclude{Metadata: true},
+	})
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return translateAzureError(err)
+		}
+
+		if page.Segment == nil {
+			continue
+		}
+
+		for _, it := range page.Segment.BlobItems {
+			if it == nil || it.Name == nil || it.Properties == nil {
+				continue
+			}
+
+			bm := blob.Metadata{
+				BlobID:    blob.ID(strings.TrimPrefix(*it.Name, az.opts.Prefix)),
+				Length:    derefInt64(it.Properties.ContentLength),
+				Timestamp: derefTime(it.Properties.LastModified),
+			}
+
+			for k, v := range it.Metadata {
+				if v == nil {
+					continue
+				}
+
+				if t, ok := azureModTime(k, *v); ok {
+					bm.Timestamp = t
+				}
+			}
+
+			if err := callback(bm); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (az *azureStorage) ConnectionInfo() blob.ConnectionInfo {
+	return blob.ConnectionInfo{
+		Type:   azureStorageType,
+		Config: &az.opts,
+	}
+}
+
+func (az *azureStorage) DisplayName() string {
+	return "Azure: " + az.opts.Container
+}
+
+func (az *azureStorage) Close(ctx context.Context) error {
+	return nil
+}
+
+func (az *azureStorage) FlushCaches(ctx context.Context) error {
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// helpers
+// ---------------------------------------------------------------------------
+
+func translateAzureError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch {
+	case bloberror.HasCode(err, bloberror.BlobNotFound):
+		return blob.ErrBlobNotFound
+	case bloberror.HasCode(err, bloberror.InvalidRange):
+		return blob.ErrInvalidRange
+	}
+
+	return err
+}
+
+// azureModTime parses the kopia mod time out of the blob metadata entry, if
+// the key matches.
+func azureModTime(k, v string) (time.Time, bool) {
+	if !strings.EqualFold(k, azureModTimeKey) {
+		return time.Time{}, false
+	}
+
+	nanos, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(0, nanos), true
+}
+
+func derefInt64(i *int64) int64 {
+	if i == nil {
+		return 0
+	}
+
+	return *i
+}
+
+func derefTime(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+
+	return *t
+}
@@ -0,0 +1,115 @@
+package kopia
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/tester"
+	"github.com/alcionai/corso/src/pkg/control"
+)
+
+// ---------------
+// unit tests
+// ---------------
+type AzureUnitSuite struct {
+	suite.Suite
+}
+
+func TestAzureUnitSuite(t *testing.T) {
+	suite.Run(t, new(AzureUnitSuite))
+}
+
+func (suite *AzureUnitSuite) TestAzureServiceURL() {
+	table := []struct {
+		name   string
+		opts   azureOptions
+		expect string
+	}{
+		{
+			name:   "default domain",
+			opts:   azureOptions{AccountName: "acct"},
+			expect: "https://acct.blob.core.windows.net",
+		},
+		{
+			name: "custom endpoint",
+			opts: azureOptions{
+				AccountName: "devstoreaccount1",
+				Endpoint:    "http://127.0.0.1:10000/devstoreaccount1/",
+			},
+			expect: "http://127.0.0.1:10000/devstoreaccount1",
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expect, azureServiceURL(test.opts))
+		})
+	}
+}
+
+func (suite *AzureUnitSuite) TestAzureModTime() {
+	now := time.Now()
+	nanos := strconv.FormatInt(now.UnixNano(), 10)
+
+	table := []struct {
+		name   string
+		k, v   string
+		expect assert.BoolAssertionFunc
+	}{
+		{"matching key", azureModTimeKey, nanos, assert.True},
+		{"lower-cased key", "kopiamtime", nanos, assert.True},
+		{"other key", "fnords", nanos, assert.False},
+		{"bad value", azureModTimeKey, "smarf", assert.False},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			result, ok := azureModTime(test.k, test.v)
+			test.expect(t, ok)
+
+			if ok {
+				assert.Equal(t, now.UnixNano(), result.UnixNano())
+			}
+		})
+	}
+}
+
+// ---------------
+// integration tests
+// ---------------
+type AzureIntegrationSuite struct {
+	suite.Suite
+}
+
+func TestAzureIntegrationSuite(t *testing.T) {
+	tester.RunOnAny(
+		t,
+		tester.CorsoAzureStorageTests)
+
+	suite.Run(t, new(AzureIntegrationSuite))
+}
+
+func (suite *AzureIntegrationSuite) SetupSuite() {
+	tester.MustGetEnvSets(suite.T(), tester.AzureStorageEnvs)
+}
+
+func (suite *AzureIntegrationSuite) TestInitializeAndConnect() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+
+	k := NewConn(tester.NewPrefixedAzureStorage(t))
+	require.NoError(t, k.Initialize(ctx, control.RepoFormat{}))
+	require.NoError(t, k.Close(ctx))
+
+	err := k.Initialize(ctx, control.RepoFormat{})
+	assert.Error(t, err)
+	assert.True(t, IsRepoAlreadyExistsError(err))
+
+	require.NoError(t, k.Connect(ctx))
+	assert.NoError(t, k.Close(ctx))
+}
@@ -0,0 +1,175 @@
+package kopia
+
+import (
+	"context"
+	"sort"
+
+	"github.com/kopia/kopia/repo/compression"
+	"github.com/kopia/kopia/snapshot"
+	"github.com/kopia/kopia/snapshot/policy"
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/pkg/path"
+)
+
+// compressionServices are the services whose data can be given its own
+// compressor.
+var compressionServices = []path.ServiceType{
+	path.ExchangeService,
+	path.OneDriveService,
+	path.SharePointService,
+}
+
+// CompressionPolicy describes the compressors used when writing backup data.
+// Services without an entry in Services use the Default compressor.
+type CompressionPolicy struct {
+	Default  string
+	Services map[path.ServiceType]string
+}
+
+// Compressors lists the names of the compressors that can be set on the
+// repository, including "none" to store data uncompressed.
+func Compressors() []string {
+	cs := make([]string, 0, len(compression.ByName)+1)
+	cs = append(cs, string(noCompressor))
+
+	for c := range compression.ByName {
+		cs = append(cs, string(c))
+	}
+
+	sort.Strings(cs)
+
+	return cs
+}
+
+// ValidateCompressor returns an error if the compressor is not one of the
+// Compressors.
+func ValidateCompressor(compressor string) error {
+	return checkCompressor(compression.Name(compressor))
+}
+
+// compressionSourceInfo is the kopia source that holds the compression
+// override for the service's data.  Backups snapshot a tree rooted at the
+// tenant, so the override is stored on the service directory beneath it.
+func compressionSourceInfo(tenant string, service path.ServiceType) snapshot.SourceInfo {
+	return serviceSourceInfo(encodeAsPath(tenant), service)
+}
+
+func serviceSourceInfo(rootPath string, service path.ServiceType) snapshot.SourceInfo {
+	return snapshot.SourceInfo{
+		Host:     corsoHost,
+		UserName: corsoUser,
+		Path:     rootPath + "/" + encodeAsPath(service.String()),
+	}
+}
+
+// policyTree builds the policy tree used when uploading a snapshot of the
+// source.  kopia uses a directory's own policy in place of its parent's
+// instead of merging the two, so each service's compressor override is
+// applied to a copy of the source's effective policy rather than handed to
+// kopia as-is.
+func (w Wrapper) policyTree(
+	ctx context.Context,
+	si snapshot.SourceInfo,
+	override *policy.Policy,
+) (*policy.Tree, error) {
+	root, _, _, err := policy.GetEffectivePolicyWithOverride(ctx, w.c, si, override)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting effective policy")
+	}
+
+	defined := map[string]*policy.Policy{".": root}
+
+	for _, s := range compressionServices {
+		p, err := w.c.getPolicyOrEmpty(ctx, serviceSourceInfo(si.Path, s))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(p.CompressionPolicy.CompressorName) == 0 {
+			continue
+		}
+
+		sp := *root
+		sp.CompressionPolicy.CompressorName = p.CompressionPolicy.CompressorName
+		defined["./"+encodeAsPath(s.String())] = &sp
+	}
+
+	return policy.BuildTree(defined, policy.DefaultPolicy), nil
+}
+
+// SetCompression sets the compressor used for the service's data in the
+// tenant's backups.  An UnknownService sets the repository-wide default.
+// An empty compressor removes the service's override, so that its data
+// falls back to the default.  Only data written after the change is
+// affected.
+func (w Wrapper) SetCompression(
+	ctx context.Context,
+	tenant string,
+	service path.ServiceType,
+	compressor string,
+) error {
+	if w.c == nil {
+		return errNotConnected
+	}
+
+	if service == path.UnknownService {
+		return w.c.Compression(ctx, compressor)
+	}
+
+	if len(compressor) > 0 {
+		if err := ValidateCompressor(compressor); err != nil {
+			return err
+		}
+	}
+
+	si := compressionSourceInfo(tenant, service)
+
+	p, err := w.c.getPolicyOrEmpty(ctx, si)
+	if err != nil {
+		return err
+	}
+
+	if compression.Name(compressor) == p.CompressionPolicy.CompressorName {
+		return nil
+	}
+
+	p.CompressionPolicy.CompressorName = compression.Name(compressor)
+
+	return errors.Wrapf(
+		w.c.writePolicy(ctx, "UpdateServiceCompressionPolicy", si, p),
+		"updating %s compression policy",
+		service,
+	)
+}
+
+// Compression returns the repository's default compressor, along with any
+// per-service overrides for the tenant's backups.
+func (w Wrapper) Compression(ctx context.Context, tenant string) (*CompressionPolicy, error) {
+	if w.c == nil {
+		return nil, errNotConnected
+	}
+
+	p, err := w.c.getGlobalPolicyOrEmpty(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cp := &CompressionPolicy{
+		Default:  string(p.CompressionPolicy.CompressorName),
+		Services: map[path.ServiceType]string{},
+	}
+
+	for _, s := range compressionServices {
+		p, err := w.c.getPolicyOrEmpty(ctx, compressionSourceInfo(tenant, s))
+		if err != nil {
+			return nil, err
+		}
+
+		if c := p.CompressionPolicy.CompressorName; len(c) > 0 {
+			cp.Services[s] = string(c)
+		}
+	}
+
+	return cp, nil
+}
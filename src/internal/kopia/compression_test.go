@@ -0,0 +1,110 @@
+package kopia
+
+import (
+	"testing"
+
+	"github.com/kopia/kopia/snapshot"
+	"github.com/kopia/kopia/snapshot/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/tester"
+	"github.com/alcionai/corso/src/pkg/credentials"
+	"github.com/alcionai/corso/src/pkg/path"
+)
+
+type CompressionUnitSuite struct {
+	suite.Suite
+}
+
+func TestCompressionUnitSuite(t *testing.T) {
+	suite.Run(t, new(CompressionUnitSuite))
+}
+
+func (suite *CompressionUnitSuite) TestValidateCompressor() {
+	t := suite.T()
+
+	assert.NoError(t, ValidateCompressor("zstd"))
+	assert.NoError(t, ValidateCompressor("none"))
+	assert.Error(t, ValidateCompressor("not-a-compressor"))
+	assert.Error(t, ValidateCompressor(""))
+
+	cs := Compressors()
+	assert.Contains(t, cs, "none")
+	assert.Contains(t, cs, defaultCompressor)
+	assert.IsIncreasing(t, cs)
+}
+
+func (suite *CompressionUnitSuite) TestSetCompression() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+	t.Setenv(credentials.CorsoPassphrase, "filesystem-test-passphrase")
+
+	assert.Error(t, Wrapper{}.SetCompression(ctx, testTenant, path.ExchangeService, "zstd"))
+
+	w := newFilesystemWrapper(t, ctx)
+	defer w.Close(ctx)
+
+	cp, err := w.Compression(ctx, testTenant)
+	require.NoError(t, err)
+	assert.Equal(t, defaultCompressor, cp.Default)
+	assert.Empty(t, cp.Services)
+
+	assert.Error(t, w.SetCompression(ctx, testTenant, path.ExchangeService, "not-a-compressor"))
+	assert.Error(t, w.SetCompression(ctx, testTenant, path.UnknownService, "not-a-compressor"))
+
+	require.NoError(t, w.SetCompression(ctx, testTenant, path.UnknownService, "pgzip"))
+	require.NoError(t, w.SetCompression(ctx, testTenant, path.ExchangeService, "zstd"))
+	require.NoError(t, w.SetCompression(ctx, testTenant, path.OneDriveService, "none"))
+	require.NoError(t, w.SetCompression(ctx, testTenant, path.SharePointService, "s2-better"))
+	require.NoError(t, w.SetCompression(ctx, testTenant, path.SharePointService, ""))
+
+	cp, err = w.Compression(ctx, testTenant)
+	require.NoError(t, err)
+	assert.Equal(t, "pgzip", cp.Default)
+	assert.Equal(
+		t,
+		map[path.ServiceType]string{
+			path.ExchangeService: "zstd",
+			path.OneDriveService: "none",
+		},
+		cp.Services)
+
+	// overrides are scoped to the tenant.
+	cp, err = w.Compression(ctx, "other-tenant")
+	require.NoError(t, err)
+	assert.Empty(t, cp.Services)
+
+	// backups of the tenant pick up the overrides for each service directory,
+	// without losing the rest of the backup's policy.
+	si := snapshot.SourceInfo{
+		Host:     corsoHost,
+		UserName: corsoUser,
+		Path:     encodeAsPath(testTenant),
+	}
+
+	trueVal := policy.OptionalBool(true)
+	override := &policy.Policy{
+		ErrorHandlingPolicy: policy.ErrorHandlingPolicy{IgnoreFileErrors: &trueVal},
+	}
+
+	tree, err := w.policyTree(ctx, si, override)
+	require.NoError(t, err)
+
+	table := []struct {
+		service path.ServiceType
+		expect  string
+	}{
+		{path.ExchangeService, "zstd"},
+		{path.OneDriveService, "none"},
+		{path.SharePointService, "pgzip"},
+	}
+	for _, test := range table {
+		p := tree.Child(encodeAsPath(test.service.String())).EffectivePolicy()
+		assert.Equal(t, test.expect, string(p.CompressionPolicy.CompressorName), test.service.String())
+		assert.True(t, p.ErrorHandlingPolicy.IgnoreFileErrors.OrDefault(false), test.service.String())
+	}
+}
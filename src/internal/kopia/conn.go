@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gofrs/flock"
 	"github.com/kopia/kopia/fs"
 	"github.com/kopia/kopia/repo"
 	"github.com/kopia/kopia/repo/blob"
@@ -18,6 +19,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/alcionai/corso/src/internal/common"
+	"github.com/alcionai/corso/src/pkg/control"
 	"github.com/alcionai/corso/src/pkg/storage"
 )
 
@@ -25,6 +27,11 @@ const (
 	defaultKopiaConfigDir  = "/tmp/"
 	defaultKopiaConfigFile = "repository.config"
 	defaultCompressor      = "s2-default"
+	// appended to the kopia config file to produce the lock file that every
+	// connection to the repository from this host holds.
+	connLockSuffix = ".corsolock"
+	// kopia stores content uncompressed when the policy names this compressor.
+	noCompressor = compression.Name("none")
 	// Interval of 0 disables scheduling.
 	defaultSchedulingInterval = time.Second * 0
 )
@@ -76,6 +83,8 @@ type conn struct {
 	repo.Repository
 	mu       sync.Mutex
 	refCount int
+	// shared lock held while the connection is open.
+	lock *flock.Flock
 }
 
 func NewConn(s storage.Storage) *conn {
@@ -84,8 +93,14 @@ func NewConn(s storage.Storage) *conn {
 	}
 }
 
-func (w *conn) Initialize(ctx context.Context) error {
-	bst, err := blobStoreByProvider(ctx, w.storage)
+// Initialize creates a new repository in the storage, using the format's
+// algorithms, and connects to it.
+func (w *conn) Initialize(ctx context.Context, f control.RepoFormat) error {
+	if err := ValidateRepoFormat(f); err != nil {
+		return errors.Wrap(err, errInit.Error())
+	}
+
+	bst, err := blobStoreByProvider(ctx, w.storage, true)
 	if err != nil {
 		return errors.Wrap(err, errInit.Error())
 	}
@@ -96,8 +111,19 @@ func (w *conn) Initialize(ctx context.Context) error {
 		return err
 	}
 
-	// todo - issue #75: nil here should be a storage.NewRepoOptions()
-	if err = repo.Initialize(ctx, bst, nil, cfg.CorsoPassphrase); err != nil {
+	opts := newRepoOptions(f)
+
+	ol, err := storageObjectLock(ctx, w.storage)
+	if err != nil {
+		return errors.Wrap(err, errInit.Error())
+	}
+
+	if ol.Enabled() {
+		opts.RetentionMode = blob.RetentionMode(ol.Mode)
+		opts.RetentionPeriod = ol.Period
+	}
+
+	if err = repo.Initialize(ctx, bst, opts, cfg.CorsoPassphrase); err != nil {
 		if errors.Is(err, repo.ErrAlreadyInitialized) {
 			return RepoAlreadyExistsError(err)
 		}
@@ -115,7 +141,7 @@ func (w *conn) Initialize(ctx context.Context) error {
 }
 
 func (w *conn) Connect(ctx context.Context) error {
-	bst, err := blobStoreByProvider(ctx, w.storage)
+	bst, err := blobStoreByProvider(ctx, w.storage, false)
 	if err != nil {
 		return errors.Wrap(err, errInit.Error())
 	}
@@ -172,10 +198,20 @@ func (w *conn) commonConnect(
 	return w.setDefaultConfigValues(ctx)
 }
 
-func blobStoreByProvider(ctx context.Context, s storage.Storage) (blob.Storage, error) {
+// blobStoreByProvider produces the blob storage for the storage's provider.
+// isCreate is true when the storage is opened to initialize a repository.
+func blobStoreByProvider(ctx context.Context, s storage.Storage, isCreate bool) (blob.Storage, error) {
 	switch s.Provider {
 	case storage.ProviderS3:
 		return s3BlobStorage(ctx, s)
+	case storage.ProviderFilesystem:
+		return filesystemBlobStorage(ctx, s)
+	case storage.ProviderAzure:
+		return azureBlobStorage(ctx, s)
+	case storage.ProviderGCS:
+		return gcsBlobStorage(ctx, s)
+	case storage.ProviderSFTP:
+		return sftpBlobStorage(ctx, s, isCreate)
 	default:
 		return nil, errors.New("storage provider details are required")
 	}
@@ -204,22 +240,50 @@ func (w *conn) close(ctx context.Context) error {
 	err := w.Repository.Close(ctx)
 	w.Repository = nil
 
+	w.unlock()
+
 	return errors.Wrap(err, "closing repository connection")
 }
 
+// unlock releases the connection's shared lock on the repository, if held.
+func (w *conn) unlock() {
+	if w.lock == nil {
+		return
+	}
+
+	w.lock.Unlock() //nolint:errcheck
+	w.lock = nil
+}
+
 func (w *conn) open(ctx context.Context, configPath, password string) error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
 	w.refCount++
 
+	// every connection holds a shared lock on the repository, so that
+	// operations which require exclusive use of the repository can tell
+	// whether others are running.
+	lock := flock.New(configPath + connLockSuffix)
+
+	locked, err := lock.TryRLock()
+	if err != nil {
+		return errors.Wrap(err, "acquiring repository lock")
+	}
+
+	if !locked {
+		return errors.WithStack(ErrRepoInUse)
+	}
+
 	// TODO(ashmrtnz): issue #75: nil here should be storage.ConnectionOptions().
 	rep, err := repo.Open(ctx, configPath, password, nil)
 	if err != nil {
+		lock.Unlock() //nolint:errcheck
 		return errors.Wrap(err, "opening repository connection")
 	}
 
 	w.Repository = rep
+	w.lock = lock
 
 	return nil
 }
@@ -243,9 +307,15 @@ func (w *conn) setDefaultConfigValues(ctx context.Context) error {
 		return errors.Wrap(err, defaultConfigErrTmpl)
 	}
 
-	changed, err := updateCompressionOnPolicy(defaultCompressor, p)
-	if err != nil {
-		return errors.Wrap(err, defaultConfigErrTmpl)
+	var changed bool
+
+	// Only fill in a missing compressor, so that a compressor chosen at init
+	// or set later on isn't reverted on every connect.
+	if len(p.CompressionPolicy.CompressorName) == 0 {
+		changed, err = updateCompressionOnPolicy(defaultCompressor, p)
+		if err != nil {
+			return errors.Wrap(err, defaultConfigErrTmpl)
+		}
 	}
 
 	if updateRetentionOnPolicy(defaultRetention, p) {
@@ -383,6 +453,10 @@ func (w *conn) writePolicy(
 }
 
 func checkCompressor(compressor compression.Name) error {
+	if compressor == noCompressor {
+		return nil
+	}
+
 	for c := range compression.ByName {
 		if c == compressor {
 			return nil
@@ -3,9 +3,11 @@ package kopia
 import (
 	"context"
 	"math"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/gofrs/flock"
 	"github.com/kopia/kopia/snapshot"
 	"github.com/kopia/kopia/snapshot/policy"
 	"github.com/stretchr/testify/assert"
@@ -13,6 +15,8 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"github.com/alcionai/corso/src/internal/tester"
+	"github.com/alcionai/corso/src/pkg/control"
+	"github.com/alcionai/corso/src/pkg/credentials"
 	"github.com/alcionai/corso/src/pkg/storage"
 )
 
@@ -22,7 +26,7 @@ func openKopiaRepo(t *testing.T, ctx context.Context) (*conn, error) {
 	st := tester.NewPrefixedS3Storage(t)
 
 	k := NewConn(st)
-	if err := k.Initialize(ctx); err != nil {
+	if err := k.Initialize(ctx, control.RepoFormat{}); err != nil {
 		return nil, err
 	}
 
@@ -51,6 +55,68 @@ func (suite *WrapperUnitSuite) TestCloseWithoutOpenDoesNotCrash() {
 	})
 }
 
+func (suite *WrapperUnitSuite) TestFilesystemInitializeAndConnect() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+	t.Setenv(credentials.CorsoPassphrase, "filesystem-test-passphrase")
+
+	st := tester.NewFilesystemStorage(t)
+
+	k := NewConn(st)
+	require.NoError(t, k.Initialize(ctx, control.RepoFormat{}))
+	require.NoError(t, k.Close(ctx))
+
+	err := k.Initialize(ctx, control.RepoFormat{})
+	assert.Error(t, err)
+	assert.True(t, IsRepoAlreadyExistsError(err))
+
+	require.NoError(t, k.Connect(ctx))
+	assert.NoError(t, k.Close(ctx))
+}
+
+func (suite *WrapperUnitSuite) TestFilesystemInitializeWithFormat() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+	t.Setenv(credentials.CorsoPassphrase, "filesystem-test-passphrase")
+
+	k := NewConn(tester.NewFilesystemStorage(t))
+	assert.Error(t, k.Initialize(ctx, control.RepoFormat{Hash: "md5"}))
+
+	f := control.RepoFormat{
+		Hash:       "HMAC-SHA256-128",
+		Encryption: "CHACHA20-POLY1305-HMAC-SHA256",
+		Splitter:   "DYNAMIC-1M-BUZHASH",
+	}
+
+	require.NoError(t, k.Initialize(ctx, f))
+	require.NoError(t, k.Close(ctx))
+
+	require.NoError(t, k.Connect(ctx))
+
+	defer func() {
+		assert.NoError(t, k.Close(ctx))
+	}()
+
+	got, err := k.Format()
+	require.NoError(t, err)
+	assert.Equal(t, f, got)
+}
+
+func (suite *WrapperUnitSuite) TestFilesystemConnectWithoutInitErrors() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+	t.Setenv(credentials.CorsoPassphrase, "filesystem-test-passphrase")
+
+	k := NewConn(tester.NewFilesystemStorage(t))
+	assert.Error(t, k.Connect(ctx))
+}
+
 // ---------------
 // integration tests that use kopia
 // ---------------
@@ -79,11 +145,11 @@ func (suite *WrapperIntegrationSuite) TestRepoExistsError() {
 
 	st := tester.NewPrefixedS3Storage(t)
 	k := NewConn(st)
-	require.NoError(t, k.Initialize(ctx))
+	require.NoError(t, k.Initialize(ctx, control.RepoFormat{}))
 
 	require.NoError(t, k.Close(ctx))
 
-	err := k.Initialize(ctx)
+	err := k.Initialize(ctx, control.RepoFormat{})
 	assert.Error(t, err)
 	assert.True(t, IsRepoAlreadyExistsError(err))
 }
@@ -98,7 +164,7 @@ func (suite *WrapperIntegrationSuite) TestBadProviderErrors() {
 	st.Provider = storage.ProviderUnknown
 
 	k := NewConn(st)
-	assert.Error(t, k.Initialize(ctx))
+	assert.Error(t, k.Initialize(ctx, control.RepoFormat{}))
 }
 
 func (suite *WrapperIntegrationSuite) TestConnectWithoutInitErrors() {
@@ -247,17 +313,6 @@ func (suite *WrapperIntegrationSuite) TestConfigDefaultsSetOnInitAndConnect() {
 		checkFunc func(*testing.T, *policy.Policy)
 		mutator   func(context.Context, *policy.Policy) error
 	}{
-		{
-			name: "Compression",
-			checkFunc: func(t *testing.T, p *policy.Policy) {
-				t.Helper()
-				require.Equal(t, defaultCompressor, string(p.CompressionPolicy.CompressorName))
-			},
-			mutator: func(innerCtx context.Context, p *policy.Policy) error {
-				_, res := updateCompressionOnPolicy("pgzip", p)
-				return res
-			},
-		},
 		{
 			name: "Retention",
 			checkFunc: func(t *testing.T, p *policy.Policy) {
@@ -326,6 +381,36 @@ func (suite *WrapperIntegrationSuite) TestConfigDefaultsSetOnInitAndConnect() {
 	}
 }
 
+func (suite *WrapperUnitSuite) TestFilesystemCompressionSetOnInitAndKeptOnConnect() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+	t.Setenv(credentials.CorsoPassphrase, "filesystem-test-passphrase")
+
+	k := NewConn(tester.NewFilesystemStorage(t))
+	require.NoError(t, k.Initialize(ctx, control.RepoFormat{}))
+
+	p, err := k.getGlobalPolicyOrEmpty(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, defaultCompressor, string(p.CompressionPolicy.CompressorName))
+
+	require.NoError(t, k.Compression(ctx, "zstd"))
+	require.NoError(t, k.Close(ctx))
+
+	require.NoError(t, k.Connect(ctx))
+
+	defer func() {
+		assert.NoError(t, k.Close(ctx))
+	}()
+
+	p, err = k.getGlobalPolicyOrEmpty(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "zstd", string(p.CompressionPolicy.CompressorName))
+
+	assert.NoError(t, k.Compression(ctx, string(noCompressor)))
+}
+
 func (suite *WrapperIntegrationSuite) TestInitAndConnWithTempDirectory() {
 	ctx, flush := tester.NewContext()
 	defer flush()
@@ -340,3 +425,74 @@ func (suite *WrapperIntegrationSuite) TestInitAndConnWithTempDirectory() {
 	require.NoError(t, k.Connect(ctx))
 	assert.NoError(t, k.Close(ctx))
 }
+
+func (suite *WrapperUnitSuite) TestFilesystemUpdatePassword() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+	t.Setenv(credentials.CorsoPassphrase, "filesystem-test-passphrase")
+
+	st := tester.NewFilesystemStorage(t)
+
+	k := NewConn(st)
+	require.NoError(t, k.Initialize(ctx, control.RepoFormat{}))
+
+	assert.Error(t, k.UpdatePassword(ctx, ""))
+
+	// refuses to run while other components hold the connection.
+	w, err := NewWrapper(k)
+	require.NoError(t, err)
+	assert.ErrorIs(t, k.UpdatePassword(ctx, "rotated-passphrase"), ErrRepoInUse)
+	require.NoError(t, w.Close(ctx))
+
+	// refuses to run while another connection, such as that of another
+	// process, holds the repository.
+	other := NewConn(st)
+	require.NoError(t, other.Connect(ctx))
+	assert.ErrorIs(t, k.UpdatePassword(ctx, "rotated-passphrase"), ErrRepoInUse)
+	require.NoError(t, other.Close(ctx))
+
+	cfg, err := st.CommonConfig()
+	require.NoError(t, err)
+
+	lock := flock.New(filepath.Join(cfg.KopiaCfgDir, defaultKopiaConfigFile) + connLockSuffix)
+
+	// the refused connection took back its shared lock.
+	locked, err := lock.TryLock()
+	require.NoError(t, err)
+	assert.False(t, locked, "connection lost its shared lock")
+
+	// the exclusive lock keeps other connections out while the passphrase
+	// is changing.
+	require.NoError(t, k.Close(ctx))
+
+	locked, err = lock.TryLock()
+	require.NoError(t, err)
+	require.True(t, locked)
+	assert.ErrorIs(t, NewConn(st).Connect(ctx), ErrRepoInUse)
+	require.NoError(t, lock.Unlock())
+
+	require.NoError(t, k.Connect(ctx))
+	require.NoError(t, k.UpdatePassword(ctx, "rotated-passphrase"))
+	require.NoError(t, k.Close(ctx))
+
+	// the old passphrase no longer opens the repository.
+	k = NewConn(st)
+	assert.Error(t, k.Connect(ctx))
+
+	fsCfg, err := st.FilesystemConfig()
+	require.NoError(t, err)
+
+	common, err := st.CommonConfig()
+	require.NoError(t, err)
+
+	common.CorsoPassphrase = "rotated-passphrase"
+
+	st, err = storage.NewStorage(storage.ProviderFilesystem, fsCfg, common)
+	require.NoError(t, err)
+
+	k = NewConn(st)
+	require.NoError(t, k.Connect(ctx))
+	assert.NoError(t, k.Close(ctx))
+}
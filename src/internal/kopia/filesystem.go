@@ -0,0 +1,25 @@
+package kopia
+
+import (
+	"context"
+
+	"github.com/kopia/kopia/repo/blob"
+	"github.com/kopia/kopia/repo/blob/filesystem"
+
+	"github.com/alcionai/corso/src/pkg/storage"
+)
+
+func filesystemBlobStorage(ctx context.Context, s storage.Storage) (blob.Storage, error) {
+	cfg, err := s.FilesystemConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := filesystem.Options{
+		Path: cfg.Path,
+	}
+
+	// isCreate ensures the root directory exists.  Connecting to a directory
+	// that holds no repository still fails when kopia reads the format blob.
+	return filesystem.New(ctx, &opts, true)
+}
@@ -0,0 +1,100 @@
+package kopia
+
+import (
+	"github.com/kopia/kopia/repo"
+	"github.com/kopia/kopia/repo/encryption"
+	"github.com/kopia/kopia/repo/format"
+	"github.com/kopia/kopia/repo/hashing"
+	"github.com/kopia/kopia/repo/splitter"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+
+	"github.com/alcionai/corso/src/pkg/control"
+)
+
+// HashAlgorithms lists the hash algorithms a new repository can use.
+func HashAlgorithms() []string {
+	return hashing.SupportedAlgorithms()
+}
+
+// EncryptionAlgorithms lists the encryption algorithms a new repository can
+// use.
+func EncryptionAlgorithms() []string {
+	return encryption.SupportedAlgorithms(false)
+}
+
+// SplitterAlgorithms lists the splitters a new repository can use.
+func SplitterAlgorithms() []string {
+	return splitter.SupportedAlgorithms()
+}
+
+// DefaultRepoFormat is the format used for any values left empty when
+// initializing a repository.
+func DefaultRepoFormat() control.RepoFormat {
+	return control.RepoFormat{
+		Hash:       hashing.DefaultAlgorithm,
+		Encryption: encryption.DefaultAlgorithm,
+		Splitter:   splitter.DefaultAlgorithm,
+	}
+}
+
+// ValidateRepoFormat returns an error if any of the format's algorithms
+// are unsupported.  Empty values are valid, and use the defaults.
+func ValidateRepoFormat(f control.RepoFormat) error {
+	checks := []struct {
+		kind      string
+		name      string
+		supported []string
+	}{
+		{"hash", f.Hash, HashAlgorithms()},
+		{"encryption", f.Encryption, EncryptionAlgorithms()},
+		{"splitter", f.Splitter, SplitterAlgorithms()},
+	}
+
+	for _, c := range checks {
+		if len(c.name) > 0 && !slices.Contains(c.supported, c.name) {
+			return errors.Errorf("unknown %s algorithm %s", c.kind, c.name)
+		}
+	}
+
+	return nil
+}
+
+func newRepoOptions(f control.RepoFormat) *repo.NewRepositoryOptions {
+	return &repo.NewRepositoryOptions{
+		BlockFormat: format.ContentFormat{
+			Hash:       f.Hash,
+			Encryption: f.Encryption,
+		},
+		ObjectFormat: format.ObjectFormat{
+			Splitter: f.Splitter,
+		},
+	}
+}
+
+// Format returns the algorithms the connected repository uses to hash,
+// encrypt, and split its content.
+func (w *conn) Format() (control.RepoFormat, error) {
+	dr, ok := w.Repository.(repo.DirectRepository)
+	if !ok {
+		return control.RepoFormat{}, errors.New("repository format is not available")
+	}
+
+	fm := dr.FormatManager()
+
+	return control.RepoFormat{
+		Hash:       fm.GetHashFunction(),
+		Encryption: fm.GetEncryptionAlgorithm(),
+		Splitter:   dr.ObjectFormat().Splitter,
+	}, nil
+}
+
+// Format returns the algorithms the repository uses to hash, encrypt, and
+// split its content.
+func (w Wrapper) Format() (control.RepoFormat, error) {
+	if w.c == nil {
+		return control.RepoFormat{}, errNotConnected
+	}
+
+	return w.c.Format()
+}
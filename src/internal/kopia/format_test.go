@@ -0,0 +1,38 @@
+package kopia
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/pkg/control"
+)
+
+type FormatUnitSuite struct {
+	suite.Suite
+}
+
+func TestFormatUnitSuite(t *testing.T) {
+	suite.Run(t, new(FormatUnitSuite))
+}
+
+func (suite *FormatUnitSuite) TestValidateRepoFormat() {
+	table := []struct {
+		name      string
+		format    control.RepoFormat
+		expectErr assert.ErrorAssertionFunc
+	}{
+		{"empty", control.RepoFormat{}, assert.NoError},
+		{"defaults", DefaultRepoFormat(), assert.NoError},
+		{"chacha", control.RepoFormat{Encryption: "CHACHA20-POLY1305-HMAC-SHA256"}, assert.NoError},
+		{"bad hash", control.RepoFormat{Hash: "md5"}, assert.Error},
+		{"bad encryption", control.RepoFormat{Encryption: "rot13"}, assert.Error},
+		{"bad splitter", control.RepoFormat{Splitter: "halves"}, assert.Error},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			test.expectErr(t, ValidateRepoFormat(test.format))
+		})
+	}
+}
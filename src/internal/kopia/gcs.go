@@ -0,0 +1,196 @@
+package kopia
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/kopia/kopia/repo/blob"
+	"github.com/kopia/kopia/repo/blob/gcs"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+
+	"github.com/alcionai/corso/src/pkg/storage"
+)
+
+const (
+	gcsStorageType = "corsoGCS"
+	// host of the gcs json and xml apis, used by kopia's gcs provider.
+	gcsStorageHost = "storage.googleapis.com"
+	// token endpoint of google service accounts.
+	gcsTokenURL = "https://oauth2.googleapis.com/token"
+	// bits in the key of the placeholder emulator service account.
+	emulatorKeyBits = 2048
+)
+
+// gcsOptions extends the options of kopia's gcs provider with an endpoint
+// override, so that emulators (ex: fake-gcs-server) can be targeted.  Kopia
+// persists it in its repository config when an endpoint is configured, and
+// uses it to re-create the storage when the repository is opened.
+type gcsOptions struct {
+	gcs.Options
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+func init() {
+	blob.AddSupportedStorage(gcsStorageType, gcsOptions{}, newGCSStorage)
+}
+
+func gcsBlobStorage(ctx context.Context, s storage.Storage) (blob.Storage, error) {
+	cfg, err := s.GCSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := gcsOptions{
+		Options: gcs.Options{
+			BucketName:                    cfg.Bucket,
+			Prefix:                        cfg.Prefix,
+			ServiceAccountCredentialsFile: cfg.CredentialsFile,
+		},
+		Endpoint: cfg.Endpoint,
+	}
+
+	if len(cfg.CredentialsJSON) > 0 {
+		opts.ServiceAccountCredentialJSON = json.RawMessage(cfg.CredentialsJSON)
+	}
+
+	return newGCSStorage(ctx, &opts, false)
+}
+
+// newGCSStorage produces kopia's gcs storage.  Without an endpoint override
+// the storage is used as-is.  Otherwise, all requests made by the storage are
+// redirected to the endpoint.
+func newGCSStorage(ctx context.Context, opts *gcsOptions, isCreate bool) (blob.Storage, error) {
+	if len(opts.Endpoint) == 0 {
+		return gcs.New(ctx, &opts.Options, isCreate)
+	}
+
+	endpoint, err := url.Parse(strings.TrimSuffix(opts.Endpoint, "/"))
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing gcs endpoint")
+	}
+
+	var (
+		kopts     = opts.Options
+		anonymous = len(kopts.ServiceAccountCredentialJSON) == 0 && len(kopts.ServiceAccountCredentialsFile) == 0
+	)
+
+	// kopia always authenticates through a token source.  Emulators require
+	// no authentication, so a placeholder account is handed to kopia instead,
+	// and its token requests are answered by the transport.
+	if anonymous {
+		kopts.ServiceAccountCredentialJSON, err = emulatorCredentials()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	hc := &http.Client{
+		Transport: &gcsEndpointTransport{
+			endpoint:  endpoint,
+			anonymous: anonymous,
+			base:      http.DefaultTransport,
+		},
+	}
+
+	// kopia's gcs client builds its http client on top of the one in the ctx.
+	st, err := gcs.New(context.WithValue(ctx, oauth2.HTTPClient, hc), &kopts, isCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gcsEndpointStorage{Storage: st, opts: *opts}, nil
+}
+
+// gcsEndpointStorage is kopia's gcs storage, reporting the corso options so
+// that the endpoint override is persisted in the repository config.
+type gcsEndpointStorage struct {
+	blob.Storage
+	opts gcsOptions
+}
+
+func (s *gcsEndpointStorage) ConnectionInfo() blob.ConnectionInfo {
+	return blob.ConnectionInfo{
+		Type:   gcsStorageType,
+		Config: &s.opts,
+	}
+}
+
+// gcsEndpointTransport redirects requests for the gcs apis to the endpoint.
+// If anonymous, token requests are answered with a placeholder token, and no
+// credentials are sent to the endpoint.
+type gcsEndpointTransport struct {
+	endpoint  *url.URL
+	anonymous bool
+	base      http.RoundTripper
+}
+
+func (t *gcsEndpointTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.anonymous && req.URL.Host+req.URL.Path == strings.TrimPrefix(gcsTokenURL, "https://") {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body: io.NopCloser(strings.NewReader(
+				`{"access_token":"emulator","token_type":"Bearer","expires_in":3600}`)),
+			Request: req,
+		}, nil
+	}
+
+	if req.URL.Host != gcsStorageHost {
+		return t.base.RoundTrip(req)
+	}
+
+	r := req.Clone(req.Context())
+	r.Host = ""
+	r.URL.Scheme = t.endpoint.Scheme
+	r.URL.Host = t.endpoint.Host
+	r.URL.Path = t.endpoint.Path + r.URL.Path
+
+	if len(r.URL.RawPath) > 0 {
+		r.URL.RawPath = t.endpoint.EscapedPath() + r.URL.RawPath
+	}
+
+	if t.anonymous {
+		r.Header.Del("Authorization")
+	}
+
+	return t.base.RoundTrip(r)
+}
+
+// emulatorCredentials produces a placeholder service account.  Its key is
+// only used to sign token requests, which never leave the transport.
+func emulatorCredentials() (json.RawMessage, error) {
+	key, err := rsa.GenerateKey(rand.Reader, emulatorKeyBits)
+	if err != nil {
+		return nil, errors.Wrap(err, "generating emulator credentials")
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "generating emulator credentials")
+	}
+
+	creds, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "emulator@localhost",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    gcsTokenURL,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "generating emulator credentials")
+	}
+
+	return creds, nil
+}
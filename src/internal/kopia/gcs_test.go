@@ -0,0 +1,179 @@
+package kopia
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/kopia/kopia/repo/blob/gcs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/tester"
+	"github.com/alcionai/corso/src/pkg/control"
+)
+
+// ---------------
+// unit tests
+// ---------------
+type GCSUnitSuite struct {
+	suite.Suite
+}
+
+func TestGCSUnitSuite(t *testing.T) {
+	suite.Run(t, new(GCSUnitSuite))
+}
+
+func (suite *GCSUnitSuite) TestNewGCSStorage_Endpoint() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	var (
+		t     = suite.T()
+		paths []string
+		auths []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		auths = append(auths, r.Header.Get("Authorization"))
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"kind":"storage#objects"}`)
+	}))
+	defer srv.Close()
+
+	opts := gcsOptions{
+		Options:  gcs.Options{BucketName: "bkt", Prefix: "pfx/"},
+		Endpoint: srv.URL + "/",
+	}
+
+	st, err := newGCSStorage(ctx, &opts, false)
+	require.NoError(t, err)
+
+	defer st.Close(ctx)
+
+	require.NotEmpty(t, paths, "requests reached the endpoint")
+	assert.Equal(t, "/storage/v1/b/bkt/o", paths[0])
+	assert.Empty(t, auths[0], "no credentials sent to the emulator")
+
+	ci := st.ConnectionInfo()
+	assert.Equal(t, gcsStorageType, ci.Type)
+	assert.Equal(t, &opts, ci.Config, "placeholder credentials are not persisted")
+}
+
+func (suite *GCSUnitSuite) TestGCSEndpointTransport() {
+	var (
+		t        = suite.T()
+		endpoint = &url.URL{Scheme: "http", Host: "127.0.0.1:4443", Path: "/gcs"}
+		got      *http.Request
+		base     = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			got = r
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: r}, nil
+		})
+	)
+
+	table := []struct {
+		name       string
+		anonymous  bool
+		url        string
+		expectURL  string
+		expectAuth string
+	}{
+		{
+			name:       "storage request",
+			url:        "https://storage.googleapis.com/storage/v1/b/bkt/o",
+			expectURL:  "http://127.0.0.1:4443/gcs/storage/v1/b/bkt/o",
+			expectAuth: "Bearer token",
+		},
+		{
+			name:      "anonymous storage request",
+			anonymous: true,
+			url:       "https://storage.googleapis.com/bkt/obj",
+			expectURL: "http://127.0.0.1:4443/gcs/bkt/obj",
+		},
+		{
+			name:       "other host",
+			url:        "https://oauth2.googleapis.com/token",
+			expectURL:  "https://oauth2.googleapis.com/token",
+			expectAuth: "Bearer token",
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			got = nil
+
+			req, err := http.NewRequest(http.MethodGet, test.url, nil)
+			require.NoError(t, err)
+
+			req.Header.Set("Authorization", "Bearer token")
+
+			tr := &gcsEndpointTransport{endpoint: endpoint, anonymous: test.anonymous, base: base}
+
+			_, err = tr.RoundTrip(req)
+			require.NoError(t, err)
+			require.NotNil(t, got)
+			assert.Equal(t, test.expectURL, got.URL.String())
+			assert.Equal(t, test.expectAuth, got.Header.Get("Authorization"))
+		})
+	}
+
+	// anonymous token requests never reach the base transport.
+	got = nil
+
+	req, err := http.NewRequest(http.MethodPost, gcsTokenURL, nil)
+	require.NoError(t, err)
+
+	tr := &gcsEndpointTransport{endpoint: endpoint, anonymous: true, base: base}
+
+	resp, err := tr.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// ---------------
+// integration tests
+// ---------------
+type GCSIntegrationSuite struct {
+	suite.Suite
+}
+
+func TestGCSIntegrationSuite(t *testing.T) {
+	tester.RunOnAny(
+		t,
+		tester.CorsoGCSStorageTests)
+
+	suite.Run(t, new(GCSIntegrationSuite))
+}
+
+func (suite *GCSIntegrationSuite) SetupSuite() {
+	tester.MustGetEnvSets(suite.T(), tester.GCSStorageEnvs)
+}
+
+func (suite *GCSIntegrationSuite) TestInitializeAndConnect() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+
+	k := NewConn(tester.NewPrefixedGCSStorage(t))
+	require.NoError(t, k.Initialize(ctx, control.RepoFormat{}))
+	require.NoError(t, k.Close(ctx))
+
+	err := k.Initialize(ctx, control.RepoFormat{})
+	assert.Error(t, err)
+	assert.True(t, IsRepoAlreadyExistsError(err))
+
+	require.NoError(t, k.Connect(ctx))
+	assert.NoError(t, k.Close(ctx))
+}
@@ -0,0 +1,199 @@
+package kopia
+
+import (
+	"context"
+
+	"github.com/kopia/kopia/repo"
+	"github.com/kopia/kopia/repo/blob"
+	"github.com/kopia/kopia/repo/maintenance"
+	"github.com/kopia/kopia/snapshot/snapshotgc"
+	"github.com/pkg/errors"
+)
+
+// MaintenanceMode selects the set of maintenance tasks to run.
+type MaintenanceMode string
+
+const (
+	// QuickMaintenance compacts indexes and rewrites short packs, without
+	// scanning snapshots for unreferenced content.
+	QuickMaintenance MaintenanceMode = MaintenanceMode(maintenance.ModeQuick)
+	// FullMaintenance additionally garbage collects the content of deleted
+	// snapshots, and removes the blobs that held it.
+	FullMaintenance MaintenanceMode = MaintenanceMode(maintenance.ModeFull)
+)
+
+var (
+	ErrMaintenanceNotOwned = errors.New("repository maintenance is owned by another host")
+	ErrMaintenanceRunning  = errors.New("repository maintenance is already running")
+)
+
+// MaintenanceStats summarizes a maintenance run.
+type MaintenanceStats struct {
+	Mode  MaintenanceMode
+	Owner string
+
+	// UnusedContentCount and UnusedContentBytes count the content that
+	// garbage collection found to be unreferenced by any snapshot.  Only
+	// populated by full maintenance.
+	UnusedContentCount uint32
+	UnusedContentBytes int64
+
+	// StorageBytesBefore and StorageBytesAfter are the total size of the
+	// blobs in the storage provider before and after the run.
+	StorageBytesBefore int64
+	StorageBytesAfter  int64
+
+	// LocksExtended is the number of blobs whose object lock was renewed.
+	// Only populated when the repository uses object lock.
+	LocksExtended int
+}
+
+// ReclaimedBytes is the amount of storage freed by the run.
+func (ms MaintenanceStats) ReclaimedBytes() int64 {
+	if ms.StorageBytesAfter > ms.StorageBytesBefore {
+		return 0
+	}
+
+	return ms.StorageBytesBefore - ms.StorageBytesAfter
+}
+
+// Maintenance runs kopia maintenance on the repository.  Maintenance is owned
+// by a single user@host; the first host to run it takes ownership, and other
+// hosts are refused unless force is set, in which case they take ownership
+// instead.  Content is only removed once it is old enough that concurrent
+// backups can't still be referencing it, so space from recently deleted
+// backups may take several runs to be reclaimed.  When the repository uses
+// object lock, each run also renews the lock on the blobs that remain.
+func (w Wrapper) Maintenance(
+	ctx context.Context,
+	mode MaintenanceMode,
+	force bool,
+) (*MaintenanceStats, error) {
+	if w.c == nil {
+		return nil, errNotConnected
+	}
+
+	if mode != QuickMaintenance && mode != FullMaintenance {
+		return nil, errors.Errorf("unknown maintenance mode %q", mode)
+	}
+
+	dr, ok := w.c.Repository.(repo.DirectRepository)
+	if !ok {
+		return nil, errors.New("repository does not support maintenance")
+	}
+
+	ms := &MaintenanceStats{Mode: mode}
+
+	before, err := storageBytes(ctx, dr)
+	if err != nil {
+		return nil, err
+	}
+
+	ms.StorageBytesBefore = before
+
+	err = repo.DirectWriteSession(
+		ctx,
+		dr,
+		repo.WriteSessionOptions{Purpose: "CorsoMaintenance"},
+		func(innerCtx context.Context, dw repo.DirectRepositoryWriter) error {
+			owner, err := claimMaintenance(innerCtx, dw, force)
+			if err != nil {
+				return err
+			}
+
+			ms.Owner = owner
+
+			var ran bool
+
+			err = maintenance.RunExclusive(
+				innerCtx,
+				dw,
+				maintenance.Mode(mode),
+				false,
+				func(ctx context.Context, rp maintenance.RunParameters) error {
+					ran = true
+
+					if rp.Mode == maintenance.ModeFull {
+						st, err := snapshotgc.Run(ctx, dw, true, maintenance.SafetyFull, rp.MaintenanceStartTime)
+						if err != nil {
+							return errors.Wrap(err, "collecting unreferenced content")
+						}
+
+						ms.UnusedContentCount = st.UnusedCount
+						ms.UnusedContentBytes = st.UnusedBytes
+					}
+
+					return maintenance.Run(ctx, rp, maintenance.SafetyFull)
+				})
+			if err != nil {
+				return err
+			}
+
+			// RunExclusive no-ops if another local process holds the lock.
+			if !ran {
+				return ErrMaintenanceRunning
+			}
+
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "running repository maintenance")
+	}
+
+	ms.LocksExtended, err = w.c.extendObjectLocks(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "extending object locks")
+	}
+
+	after, err := storageBytes(ctx, dr)
+	if err != nil {
+		return nil, err
+	}
+
+	ms.StorageBytesAfter = after
+
+	return ms, nil
+}
+
+// claimMaintenance ensures the current user@host owns maintenance on the
+// repository, taking ownership if it's unowned or if force is set.  Returns
+// the owner.
+func claimMaintenance(
+	ctx context.Context,
+	dw repo.DirectRepositoryWriter,
+	force bool,
+) (string, error) {
+	p, err := maintenance.GetParams(ctx, dw)
+	if err != nil {
+		return "", errors.Wrap(err, "getting maintenance params")
+	}
+
+	self := dw.ClientOptions().UsernameAtHost()
+
+	if p.Owner == self {
+		return self, nil
+	}
+
+	if len(p.Owner) > 0 && !force {
+		return "", errors.Wrapf(ErrMaintenanceNotOwned, "owner %s", p.Owner)
+	}
+
+	p.Owner = self
+
+	if err := maintenance.SetParams(ctx, dw, p); err != nil {
+		return "", errors.Wrap(err, "taking maintenance ownership")
+	}
+
+	return self, nil
+}
+
+// storageBytes sums the size of every blob in the storage provider.
+func storageBytes(ctx context.Context, dr repo.DirectRepository) (int64, error) {
+	bms, err := blob.ListAllBlobs(ctx, dr.BlobReader(), "")
+	if err != nil {
+		return 0, errors.Wrap(err, "listing repository blobs")
+	}
+
+	return blob.TotalLength(bms), nil
+}
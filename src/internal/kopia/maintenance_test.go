@@ -0,0 +1,100 @@
+package kopia
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kopia/kopia/repo"
+	"github.com/kopia/kopia/repo/maintenance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/tester"
+	"github.com/alcionai/corso/src/pkg/control"
+	"github.com/alcionai/corso/src/pkg/credentials"
+)
+
+type MaintenanceUnitSuite struct {
+	suite.Suite
+}
+
+func TestMaintenanceUnitSuite(t *testing.T) {
+	suite.Run(t, new(MaintenanceUnitSuite))
+}
+
+func (suite *MaintenanceUnitSuite) TestReclaimedBytes() {
+	table := []struct {
+		name          string
+		before, after int64
+		expect        int64
+	}{
+		{"shrunk", 100, 40, 60},
+		{"unchanged", 100, 100, 0},
+		{"grew", 100, 120, 0},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			ms := MaintenanceStats{StorageBytesBefore: test.before, StorageBytesAfter: test.after}
+			assert.Equal(t, test.expect, ms.ReclaimedBytes())
+		})
+	}
+}
+
+func (suite *MaintenanceUnitSuite) TestMaintenance() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+	t.Setenv(credentials.CorsoPassphrase, "filesystem-test-passphrase")
+
+	k := NewConn(tester.NewFilesystemStorage(t))
+	require.NoError(t, k.Initialize(ctx, control.RepoFormat{}))
+
+	w, err := NewWrapper(k)
+	require.NoError(t, err)
+	require.NoError(t, k.Close(ctx))
+
+	defer w.Close(ctx)
+
+	_, err = w.Maintenance(ctx, MaintenanceMode("partial"), false)
+	assert.Error(t, err)
+
+	for _, mode := range []MaintenanceMode{QuickMaintenance, FullMaintenance} {
+		ms, err := w.Maintenance(ctx, mode, false)
+		require.NoError(t, err, mode)
+		assert.Equal(t, mode, ms.Mode)
+		assert.NotEmpty(t, ms.Owner)
+		assert.Positive(t, ms.StorageBytesBefore)
+	}
+
+	// hand ownership to another host.
+	setMaintenanceOwner(t, ctx, w, "someone@elsewhere")
+
+	_, err = w.Maintenance(ctx, QuickMaintenance, false)
+	assert.ErrorIs(t, err, ErrMaintenanceNotOwned)
+
+	ms, err := w.Maintenance(ctx, QuickMaintenance, true)
+	require.NoError(t, err)
+	assert.NotEqual(t, "someone@elsewhere", ms.Owner)
+}
+
+//revive:disable:context-as-argument
+func setMaintenanceOwner(t *testing.T, ctx context.Context, w *Wrapper, owner string) {
+	//revive:enable:context-as-argument
+	err := repo.WriteSession(
+		ctx,
+		w.c.Repository,
+		repo.WriteSessionOptions{Purpose: "test"},
+		func(innerCtx context.Context, rw repo.RepositoryWriter) error {
+			p, err := maintenance.GetParams(innerCtx, rw)
+			if err != nil {
+				return err
+			}
+
+			p.Owner = owner
+
+			return maintenance.SetParams(innerCtx, rw, p)
+		})
+	require.NoError(t, err)
+}
@@ -3,6 +3,7 @@ package kopia
 import (
 	"context"
 	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/kopia/kopia/repo"
@@ -257,42 +258,73 @@ func (ms *ModelStore) GetIDsForType(
 	return res, nil
 }
 
-// getModelStoreID gets the ModelStoreID of the model with the given
-// StableID. Returns an error if the given StableID is empty or more than
-// one model has the same StableID.
-func (ms *ModelStore) getModelStoreID(
+// getModelMetadata gets the store metadata of the model with the given
+// StableID. Returns an error if the given StableID is empty or more than one
+// model has the same StableID.
+func (ms *ModelStore) getModelMetadata(
 	ctx context.Context,
 	s model.Schema,
 	id model.StableID,
-) (manifest.ID, error) {
+) (*manifest.EntryMetadata, error) {
 	if !s.Valid() {
-		return "", errors.WithStack(errUnrecognizedSchema)
+		return nil, errors.WithStack(errUnrecognizedSchema)
 	}
 
 	if len(id) == 0 {
-		return "", errors.WithStack(errNoStableID)
+		return nil, errors.WithStack(errNoStableID)
 	}
 
 	tags := map[string]string{stableIDKey: string(id)}
 
 	metadata, err := ms.c.FindManifests(ctx, tags)
 	if err != nil {
-		return "", errors.Wrap(err, "getting ModelStoreID")
+		return nil, errors.Wrap(err, "getting model metadata")
 	}
 
 	if len(metadata) == 0 {
-		return "", errors.Wrap(ErrNotFound, "getting ModelStoreID")
+		return nil, errors.Wrap(ErrNotFound, "getting model metadata")
 	}
 
 	if len(metadata) != 1 {
-		return "", errors.New("multiple models with same StableID")
+		return nil, errors.New("multiple models with same StableID")
 	}
 
 	if metadata[0].Labels[manifest.TypeLabelKey] != s.String() {
-		return "", errors.WithStack(errModelTypeMismatch)
+		return nil, errors.WithStack(errModelTypeMismatch)
+	}
+
+	return metadata[0], nil
+}
+
+// getModelStoreID gets the ModelStoreID of the model with the given
+// StableID. Returns an error if the given StableID is empty or more than
+// one model has the same StableID.
+func (ms *ModelStore) getModelStoreID(
+	ctx context.Context,
+	s model.Schema,
+	id model.StableID,
+) (manifest.ID, error) {
+	m, err := ms.getModelMetadata(ctx, s, id)
+	if err != nil {
+		return "", err
+	}
+
+	return m.ID, nil
+}
+
+// ModTime returns the time at which the model with the given StableID was
+// last written to the store.
+func (ms *ModelStore) ModTime(
+	ctx context.Context,
+	s model.Schema,
+	id model.StableID,
+) (time.Time, error) {
+	m, err := ms.getModelMetadata(ctx, s, id)
+	if err != nil {
+		return time.Time{}, err
 	}
 
-	return metadata[0].ID, nil
+	return m.ModTime, nil
 }
 
 // Get deserializes the model with the given StableID into data.
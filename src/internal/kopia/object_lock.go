@@ -0,0 +1,97 @@
+package kopia
+
+import (
+	"context"
+	"time"
+
+	"github.com/kopia/kopia/repo"
+	"github.com/kopia/kopia/repo/blob"
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/pkg/storage"
+)
+
+// ObjectLock describes the retention that the storage provider applies to
+// the repository's blobs.  Locked blobs can't be deleted or overwritten
+// until the period has passed.
+type ObjectLock struct {
+	Mode   string
+	Period time.Duration
+}
+
+// Enabled is true if the repository's blobs are locked.
+func (ol ObjectLock) Enabled() bool {
+	return ol.Period > 0
+}
+
+// storageObjectLock returns the object lock that a new repository in the
+// storage should use.  Only S3 storage supports object lock.
+func storageObjectLock(ctx context.Context, s storage.Storage) (ObjectLock, error) {
+	if s.Provider != storage.ProviderS3 {
+		return ObjectLock{}, nil
+	}
+
+	cfg, err := s.S3Config()
+	if err != nil {
+		return ObjectLock{}, err
+	}
+
+	if !cfg.ObjectLock() {
+		return ObjectLock{}, nil
+	}
+
+	if err := checkS3ObjectLock(ctx, cfg); err != nil {
+		return ObjectLock{}, err
+	}
+
+	return ObjectLock{Mode: cfg.RetentionMode, Period: cfg.RetentionPeriod}, nil
+}
+
+// ObjectLock returns the retention applied to the repository's blobs.
+func (w *conn) ObjectLock() (ObjectLock, error) {
+	dr, ok := w.Repository.(repo.DirectRepository)
+	if !ok {
+		return ObjectLock{}, errors.New("repository object lock is not available")
+	}
+
+	bc, err := dr.FormatManager().BlobCfgBlob()
+	if err != nil {
+		return ObjectLock{}, errors.Wrap(err, "reading blob storage config")
+	}
+
+	if !bc.IsRetentionEnabled() {
+		return ObjectLock{}, nil
+	}
+
+	return ObjectLock{Mode: string(bc.RetentionMode), Period: bc.RetentionPeriod}, nil
+}
+
+// ObjectLock returns the retention applied to the repository's blobs.
+func (w Wrapper) ObjectLock() (ObjectLock, error) {
+	if w.c == nil {
+		return ObjectLock{}, errNotConnected
+	}
+
+	return w.c.ObjectLock()
+}
+
+// extendObjectLocks renews the lock on every blob still in the repository,
+// so that the data of live backups stays locked for as long as they're kept.
+// Returns the number of blobs extended.
+func (w *conn) extendObjectLocks(ctx context.Context) (int, error) {
+	ol, err := w.ObjectLock()
+	if err != nil || !ol.Enabled() {
+		return 0, err
+	}
+
+	if w.storage.Provider != storage.ProviderS3 {
+		return 0, errors.Errorf("%s storage does not support object lock", w.storage.Provider)
+	}
+
+	cfg, err := w.storage.S3Config()
+	if err != nil {
+		return 0, err
+	}
+
+	return extendS3ObjectLocks(ctx, cfg, blob.RetentionMode(ol.Mode), ol.Period)
+}
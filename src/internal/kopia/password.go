@@ -0,0 +1,122 @@
+package kopia
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/hashicorp/go-multierror"
+	"github.com/kopia/kopia/repo"
+	"github.com/pkg/errors"
+)
+
+var ErrRepoInUse = errors.New("repository is in use by another operation")
+
+// relockRetryDelay is how often a connection retries taking back its shared
+// lock while another operation holds the repository exclusively.
+const relockRetryDelay = 100 * time.Millisecond
+
+// UpdatePassword changes the passphrase protecting the repository, then
+// reconnects using the new passphrase so that the local kopia config is
+// rewritten.  Refuses to run if any other Wrapper or ModelStore holds the
+// connection, or if any other connection to the repository is open on this
+// host, including those of other processes.
+func (w *conn) UpdatePassword(ctx context.Context, password string) error {
+	if len(password) == 0 {
+		return errors.New("a new passphrase is required")
+	}
+
+	w.mu.Lock()
+	inUse := w.refCount != 1
+	w.mu.Unlock()
+
+	if inUse {
+		return errors.WithStack(ErrRepoInUse)
+	}
+
+	dr, ok := w.Repository.(repo.DirectRepository)
+	if !ok {
+		return errors.New("repository does not support passphrase changes")
+	}
+
+	// Trade this connection's shared lock for an exclusive one, which is only
+	// granted while no other connection holds the repository.  The shared
+	// lock is taken back if the passphrase doesn't change, since the
+	// connection stays open.
+	configPath := dr.ConfigFilename()
+
+	w.unlock()
+
+	lock := flock.New(configPath + connLockSuffix)
+
+	locked, err := lock.TryLock()
+	if err != nil {
+		return w.relock(ctx, configPath, errors.Wrap(err, "acquiring repository lock"))
+	}
+
+	if !locked {
+		return w.relock(ctx, configPath, errors.WithStack(ErrRepoInUse))
+	}
+
+	err = repo.DirectWriteSession(
+		ctx,
+		dr,
+		repo.WriteSessionOptions{Purpose: "CorsoUpdatePassword"},
+		func(innerCtx context.Context, dw repo.DirectRepositoryWriter) error {
+			return dw.FormatManager().ChangePassword(innerCtx, password)
+		},
+	)
+
+	// the reconnect below takes a shared lock of its own.
+	lock.Unlock() //nolint:errcheck
+
+	if err != nil {
+		return w.relock(ctx, configPath, errors.Wrap(err, "changing repository passphrase"))
+	}
+
+	// The open connection still holds the old passphrase, so replace it.
+	if err := w.Close(ctx); err != nil {
+		return err
+	}
+
+	bst, err := blobStoreByProvider(ctx, w.storage, false)
+	if err != nil {
+		return errors.Wrap(err, errConnect.Error())
+	}
+	defer bst.Close(ctx)
+
+	cfg, err := w.storage.CommonConfig()
+	if err != nil {
+		return err
+	}
+
+	return w.commonConnect(
+		ctx,
+		cfg.KopiaCfgDir,
+		bst,
+		password,
+		defaultCompressor,
+	)
+}
+
+// relock takes back the shared lock of a connection that gave it up for an
+// exclusive one.  Waits for any other exclusive holder to finish.  err is
+// returned along with any failure to take the lock.
+func (w *conn) relock(ctx context.Context, configPath string, err error) error {
+	lock := flock.New(configPath + connLockSuffix)
+
+	locked, lerr := lock.TryRLockContext(ctx, relockRetryDelay)
+	if lerr == nil && !locked {
+		lerr = errors.WithStack(ErrRepoInUse)
+	}
+
+	if lerr != nil {
+		return multierror.Append(err, errors.Wrap(lerr, "reacquiring repository lock")).ErrorOrNil()
+	}
+
+	w.mu.Lock()
+	w.lock = lock
+	w.mu.Unlock()
+
+	return err
+}
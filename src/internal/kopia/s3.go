@@ -2,31 +2,54 @@ package kopia
 
 import (
 	"context"
+	"crypto/tls"
+	"net/http"
+	"time"
 
 	"github.com/kopia/kopia/repo/blob"
 	"github.com/kopia/kopia/repo/blob/s3"
+	"github.com/kopia/kopia/repo/content"
+	"github.com/kopia/kopia/repo/content/indexblob"
+	"github.com/kopia/kopia/repo/format"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/pkg/errors"
 
 	"github.com/alcionai/corso/src/pkg/storage"
 )
 
 const (
 	defaultS3Endpoint = "s3.amazonaws.com" // matches kopia's default value
+	// kopia's epoch index blob prefix, which isn't exported.
+	epochIndexBlobPrefix = "x"
 )
 
+// lockedBlobPrefixes are the prefixes of the blobs that kopia writes with
+// a retention period when object lock is enabled.
+var lockedBlobPrefixes = func() []string {
+	ps := []string{
+		indexblob.V0IndexBlobPrefix,
+		epochIndexBlobPrefix,
+		format.KopiaRepositoryBlobID,
+		format.KopiaBlobCfgBlobID,
+	}
+
+	for _, p := range content.PackBlobIDPrefixes {
+		ps = append(ps, string(p))
+	}
+
+	return ps
+}()
+
 func s3BlobStorage(ctx context.Context, s storage.Storage) (blob.Storage, error) {
 	cfg, err := s.S3Config()
 	if err != nil {
 		return nil, err
 	}
 
-	endpoint := defaultS3Endpoint
-	if len(cfg.Endpoint) > 0 {
-		endpoint = cfg.Endpoint
-	}
-
 	opts := s3.Options{
 		BucketName:     cfg.Bucket,
-		Endpoint:       endpoint,
+		Endpoint:       s3Endpoint(cfg),
 		Prefix:         cfg.Prefix,
 		DoNotUseTLS:    cfg.DoNotUseTLS,
 		DoNotVerifyTLS: cfg.DoNotVerifyTLS,
@@ -34,3 +57,104 @@ func s3BlobStorage(ctx context.Context, s storage.Storage) (blob.Storage, error)
 
 	return s3.New(ctx, &opts, false)
 }
+
+func s3Endpoint(cfg storage.S3Config) string {
+	if len(cfg.Endpoint) > 0 {
+		return cfg.Endpoint
+	}
+
+	return defaultS3Endpoint
+}
+
+// s3Client builds a client for the bucket, using the same credentials as
+// kopia's s3 storage.
+func s3Client(cfg storage.S3Config) (*minio.Client, error) {
+	creds := credentials.NewChainCredentials([]credentials.Provider{
+		&credentials.EnvAWS{},
+		&credentials.IAM{Client: &http.Client{Transport: http.DefaultTransport}},
+	})
+
+	opts := &minio.Options{
+		Creds:  creds,
+		Secure: !cfg.DoNotUseTLS,
+	}
+
+	if cfg.DoNotVerifyTLS {
+		//nolint:gosec
+		opts.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+
+	cli, err := minio.New(s3Endpoint(cfg), opts)
+
+	return cli, errors.Wrap(err, "creating s3 client")
+}
+
+// checkS3ObjectLock returns an error if the bucket doesn't have object lock
+// enabled, as kopia can't write locked blobs to it.
+func checkS3ObjectLock(ctx context.Context, cfg storage.S3Config) error {
+	cli, err := s3Client(cfg)
+	if err != nil {
+		return err
+	}
+
+	enabled, _, _, _, err := cli.GetObjectLockConfig(ctx, cfg.Bucket)
+	if err != nil {
+		return errors.Wrapf(err, "bucket %s must have object lock enabled", cfg.Bucket)
+	}
+
+	if enabled != "Enabled" {
+		return errors.Errorf("bucket %s must have object lock enabled", cfg.Bucket)
+	}
+
+	return nil
+}
+
+// extendS3ObjectLocks pushes the retain-until date of every locked blob in
+// the repository out to the full retention period from now, so that blobs
+// which are still in use stay locked beyond the period they were written
+// with.  Blobs deleted by maintenance aren't listed, and so are left to
+// expire.  Nothing else extends the locks, so blobs that new backups share
+// with old ones are only kept locked if maintenance runs at least once per
+// retention period.  Returns the number of blobs extended.
+func extendS3ObjectLocks(
+	ctx context.Context,
+	cfg storage.S3Config,
+	mode blob.RetentionMode,
+	period time.Duration,
+) (int, error) {
+	cli, err := s3Client(cfg)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		extended int
+		rm       = minio.RetentionMode(mode)
+		until    = time.Now().Add(period).UTC()
+	)
+
+	for _, p := range lockedBlobPrefixes {
+		objs := cli.ListObjects(ctx, cfg.Bucket, minio.ListObjectsOptions{
+			Prefix:    cfg.Prefix + p,
+			Recursive: true,
+		})
+
+		for o := range objs {
+			if o.Err != nil {
+				return extended, errors.Wrap(o.Err, "listing locked blobs")
+			}
+
+			err := cli.PutObjectRetention(ctx, cfg.Bucket, o.Key, minio.PutObjectRetentionOptions{
+				Mode:            &rm,
+				RetainUntilDate: &until,
+			})
+			if err != nil {
+				return extended, errors.Wrapf(err, "extending lock on blob %s", o.Key)
+			}
+
+			extended++
+		}
+	}
+
+	return extended, nil
+}
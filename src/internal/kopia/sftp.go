@@ -0,0 +1,36 @@
+package kopia
+
+import (
+	"context"
+
+	"github.com/kopia/kopia/repo/blob"
+	"github.com/kopia/kopia/repo/blob/sftp"
+
+	"github.com/alcionai/corso/src/pkg/storage"
+)
+
+func sftpBlobStorage(ctx context.Context, s storage.Storage, isCreate bool) (blob.Storage, error) {
+	cfg, err := s.SFTPConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg = cfg.Normalize()
+
+	opts := sftp.Options{
+		Path:           cfg.Path,
+		Host:           cfg.Host,
+		Port:           cfg.Port,
+		Username:       cfg.Username,
+		Password:       cfg.Password,
+		Keyfile:        cfg.KeyFile,
+		KnownHostsFile: cfg.KnownHostsFile,
+	}
+
+	// isCreate is only set by repo init, where it lets kopia write the shard
+	// layout of the new repository.  Regardless of isCreate, kopia makes the
+	// base path on the remote host when it's missing, so connecting with a
+	// mistyped path leaves an empty directory on the server before failing
+	// to find a repository in it.
+	return sftp.New(ctx, &opts, isCreate)
+}
@@ -0,0 +1,46 @@
+package kopia
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/tester"
+	"github.com/alcionai/corso/src/pkg/control"
+)
+
+type SFTPIntegrationSuite struct {
+	suite.Suite
+}
+
+func TestSFTPIntegrationSuite(t *testing.T) {
+	tester.RunOnAny(
+		t,
+		tester.CorsoSFTPStorageTests)
+
+	suite.Run(t, new(SFTPIntegrationSuite))
+}
+
+func (suite *SFTPIntegrationSuite) SetupSuite() {
+	tester.MustGetEnvSets(suite.T(), tester.SFTPStorageEnvs)
+}
+
+func (suite *SFTPIntegrationSuite) TestInitializeAndConnect() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+
+	k := NewConn(tester.NewPrefixedSFTPStorage(t))
+	require.NoError(t, k.Initialize(ctx, control.RepoFormat{}))
+	require.NoError(t, k.Close(ctx))
+
+	err := k.Initialize(ctx, control.RepoFormat{})
+	assert.Error(t, err)
+	assert.True(t, IsRepoAlreadyExistsError(err))
+
+	require.NoError(t, k.Connect(ctx))
+	assert.NoError(t, k.Close(ctx))
+}
@@ -0,0 +1,106 @@
+package kopia
+
+import (
+	"context"
+
+	"github.com/kopia/kopia/repo"
+	"github.com/kopia/kopia/repo/content"
+	"github.com/kopia/kopia/snapshot"
+	"github.com/pkg/errors"
+)
+
+// StorageStats summarizes the space used by the repository.
+type StorageStats struct {
+	// SnapshotCount is the number of snapshots in the repository, and
+	// LogicalBytes is the total size of the files they contain, before
+	// deduplication and compression.
+	SnapshotCount int
+	LogicalBytes  int64
+
+	// ContentCount and ContentBytes count the unique content that the
+	// snapshots are built from, after deduplication and before compression.
+	// PackedBytes is the size of that content once compressed and encrypted.
+	ContentCount int64
+	ContentBytes int64
+	PackedBytes  int64
+
+	// StorageBytes is the size of every blob in the storage provider,
+	// including indexes and content that is awaiting maintenance.
+	StorageBytes int64
+}
+
+// DedupeRatio is the ratio of the logical size of the snapshots to the size
+// of their unique content.
+func (ss StorageStats) DedupeRatio() float64 {
+	return ratio(ss.LogicalBytes, ss.ContentBytes)
+}
+
+// CompressionRatio is the ratio of the size of the unique content to its
+// size once compressed.
+func (ss StorageStats) CompressionRatio() float64 {
+	return ratio(ss.ContentBytes, ss.PackedBytes)
+}
+
+func ratio(a, b int64) float64 {
+	if b == 0 {
+		return 0
+	}
+
+	return float64(a) / float64(b)
+}
+
+// StorageStats totals the logical size of every snapshot in the repository,
+// along with the size of the content and blobs that hold them.
+func (w Wrapper) StorageStats(ctx context.Context) (*StorageStats, error) {
+	if w.c == nil {
+		return nil, errors.WithStack(errNotConnected)
+	}
+
+	dr, ok := w.c.Repository.(repo.DirectRepository)
+	if !ok {
+		return nil, errors.New("repository does not support storage stats")
+	}
+
+	ids, err := snapshot.ListSnapshotManifests(ctx, w.c, nil, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing snapshots")
+	}
+
+	mans, err := snapshot.LoadSnapshots(ctx, w.c, ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading snapshots")
+	}
+
+	ss := &StorageStats{SnapshotCount: len(mans)}
+
+	for _, man := range mans {
+		if man.RootEntry != nil && man.RootEntry.DirSummary != nil {
+			ss.LogicalBytes += man.RootEntry.DirSummary.TotalFileSize
+			continue
+		}
+
+		ss.LogicalBytes += man.Stats.TotalFileSize
+	}
+
+	err = dr.ContentReader().IterateContents(
+		ctx,
+		content.IterateOptions{},
+		func(ci content.Info) error {
+			ss.ContentCount++
+			ss.ContentBytes += int64(ci.GetOriginalLength())
+			ss.PackedBytes += int64(ci.GetPackedLength())
+
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "iterating repository content")
+	}
+
+	ss.StorageBytes, err = storageBytes(ctx, dr)
+	if err != nil {
+		return nil, err
+	}
+
+	return ss, nil
+}
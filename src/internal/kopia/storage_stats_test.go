@@ -0,0 +1,91 @@
+package kopia
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/connector/mockconnector"
+	"github.com/alcionai/corso/src/internal/data"
+	"github.com/alcionai/corso/src/internal/tester"
+	"github.com/alcionai/corso/src/pkg/credentials"
+	"github.com/alcionai/corso/src/pkg/path"
+)
+
+type StorageStatsUnitSuite struct {
+	suite.Suite
+}
+
+func TestStorageStatsUnitSuite(t *testing.T) {
+	suite.Run(t, new(StorageStatsUnitSuite))
+}
+
+func (suite *StorageStatsUnitSuite) TestRatios() {
+	table := []struct {
+		name        string
+		ss          StorageStats
+		dedupe      float64
+		compression float64
+	}{
+		{"empty", StorageStats{}, 0, 0},
+		{"deduped", StorageStats{LogicalBytes: 300, ContentBytes: 100, PackedBytes: 100}, 3, 1},
+		{"compressed", StorageStats{LogicalBytes: 100, ContentBytes: 100, PackedBytes: 25}, 1, 4},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			assert.InDelta(t, test.dedupe, test.ss.DedupeRatio(), 0.001)
+			assert.InDelta(t, test.compression, test.ss.CompressionRatio(), 0.001)
+		})
+	}
+}
+
+func (suite *StorageStatsUnitSuite) TestStorageStats() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+	t.Setenv(credentials.CorsoPassphrase, "filesystem-test-passphrase")
+
+	w := newFilesystemWrapper(t, ctx)
+	defer w.Close(ctx)
+
+	_, err := (&Wrapper{}).StorageStats(ctx)
+	assert.Error(t, err)
+
+	ss, err := w.StorageStats(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, ss.SnapshotCount)
+	assert.Zero(t, ss.LogicalBytes)
+
+	p, err := path.Builder{}.Append(testInboxDir).ToDataLayerExchangePathForCategory(
+		testTenant,
+		testUser,
+		path.EmailCategory,
+		false)
+	require.NoError(t, err)
+
+	// identical items are only stored once.
+	collection := &kopiaDataCollection{path: p}
+	for _, name := range []string{testFileName, testFileName2} {
+		collection.streams = append(collection.streams, &mockconnector.MockExchangeData{
+			ID:     name,
+			Reader: io.NopCloser(bytes.NewReader(testFileData)),
+		})
+	}
+
+	_, _, _, err = w.BackupCollections(ctx, nil, []data.Collection{collection}, nil, false)
+	require.NoError(t, err)
+
+	ss, err = w.StorageStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, ss.SnapshotCount)
+	assert.Positive(t, ss.LogicalBytes)
+	assert.Positive(t, ss.ContentCount)
+	assert.Positive(t, ss.ContentBytes)
+	assert.Positive(t, ss.PackedBytes)
+	assert.GreaterOrEqual(t, ss.StorageBytes, ss.PackedBytes)
+}
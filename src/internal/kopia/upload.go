@@ -232,6 +232,7 @@ func collectionEntries(
 	ctx context.Context,
 	cb func(context.Context, fs.Entry) error,
 	streamedEnts data.Collection,
+	baseDir fs.Directory,
 	progress *corsoProgress,
 ) (map[string]struct{}, *multierror.Error) {
 	if streamedEnts == nil {
@@ -245,6 +246,9 @@ func collectionEntries(
 		seen  = map[string]struct{}{}
 		items = streamedEnts.Items()
 		log   = logger.Ctx(ctx)
+		// Names of the entries in the base directory. Only populated once an
+		// immutable item is seen.
+		baseNames map[string]struct{}
 	)
 
 	for {
@@ -292,6 +296,23 @@ func collectionEntries(
 				continue
 			}
 
+			// Immutable items already in the base snapshot are left for the base
+			// entries to merge, so their data is never read.
+			if im, ok := e.(data.StreamImmutable); ok && im.Immutable() && baseDir != nil {
+				if baseNames == nil {
+					baseNames, err = baseEntryNames(ctx, baseDir)
+					if err != nil {
+						errs = multierror.Append(errs, err)
+						return seen, errs
+					}
+				}
+
+				if _, ok := baseNames[encodedName]; ok {
+					delete(seen, encodedName)
+					continue
+				}
+			}
+
 			// Not all items implement StreamInfo. For example, the metadata files
 			// do not because they don't contain information directly backed up or
 			// used for restore. If progress does not contain information about a
@@ -331,6 +352,28 @@ func collectionEntries(
 	}
 }
 
+// baseEntryNames returns the names of the items in the base directory.
+func baseEntryNames(ctx context.Context, dir fs.Directory) (map[string]struct{}, error) {
+	names := map[string]struct{}{}
+
+	err := dir.IterateEntries(ctx, func(innerCtx context.Context, entry fs.Entry) error {
+		if err := innerCtx.Err(); err != nil {
+			return err
+		}
+
+		if _, ok := entry.(fs.Directory); !ok {
+			names[entry.Name()] = struct{}{}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing items in base snapshot directory")
+	}
+
+	return names, nil
+}
+
 func streamBaseEntries(
 	ctx context.Context,
 	cb func(context.Context, fs.Entry) error,
@@ -427,7 +470,7 @@ func getStreamItemFunc(
 			}
 		}
 
-		seen, errs := collectionEntries(ctx, cb, streamedEnts, progress)
+		seen, errs := collectionEntries(ctx, cb, streamedEnts, baseDir, progress)
 
 		if err := streamBaseEntries(
 			ctx,
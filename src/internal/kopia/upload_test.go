@@ -1238,6 +1238,147 @@ func (suite *HierarchyBuilderUnitSuite) TestBuildDirectoryTreeSingleSubtree() {
 	}
 }
 
+// immutableCollection marks all the streams of the collection as immutable
+// and records which of them have been read.
+type immutableCollection struct {
+	data.Collection
+	read map[string]bool
+}
+
+func (ic *immutableCollection) Items() <-chan data.Stream {
+	res := make(chan data.Stream)
+
+	go func() {
+		defer close(res)
+
+		for s := range ic.Collection.Items() {
+			res <- &immutableStream{Stream: s, read: ic.read}
+		}
+	}()
+
+	return res
+}
+
+type immutableStream struct {
+	data.Stream
+	read map[string]bool
+}
+
+func (is *immutableStream) ToReader() io.ReadCloser {
+	is.read[is.UUID()] = true
+	return is.Stream.ToReader()
+}
+
+func (is *immutableStream) Immutable() bool {
+	return true
+}
+
+func (suite *HierarchyBuilderUnitSuite) TestBuildDirectoryTreeImmutableItems() {
+	dirPath := makePath(
+		suite.T(),
+		[]string{testTenant, service, testUser, category, testInboxDir},
+		false,
+	)
+
+	getBaseSnapshot := func() fs.Entry {
+		return baseWithChildren(
+			[]string{
+				testTenant,
+				service,
+				testUser,
+				category,
+			},
+			[]fs.Entry{
+				virtualfs.NewStaticDirectory(
+					encodeElements(testInboxDir)[0],
+					[]fs.Entry{
+						virtualfs.StreamingFileWithModTimeFromReader(
+							encodeElements(testFileName)[0],
+							time.Time{},
+							bytes.NewReader(testFileData),
+						),
+					},
+				),
+			},
+		)
+	}
+
+	table := []struct {
+		name       string
+		state      data.CollectionState
+		expectRead bool
+		expectData []byte
+	}{
+		{
+			name:  "KeepsBaseItem",
+			state: data.NotMovedState,
+		},
+		{
+			name:       "NewCollectionReadsItem",
+			state:      data.NewState,
+			expectRead: true,
+			expectData: testFileData2,
+		},
+	}
+
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			ctx, flush := tester.NewContext()
+			defer flush()
+
+			mc := mockconnector.NewMockExchangeCollection(dirPath, 1)
+			mc.Names[0] = testFileName
+			mc.Data[0] = testFileData2
+			mc.ColState = test.state
+
+			ic := &immutableCollection{Collection: mc, read: map[string]bool{}}
+
+			progress := &corsoProgress{pending: map[string]*itemDetails{}}
+			msw := &mockSnapshotWalker{
+				snapshotRoot: getBaseSnapshot(),
+			}
+
+			dirTree, err := inflateDirTree(
+				ctx,
+				msw,
+				[]IncrementalBase{
+					mockIncrementalBase("", testTenant, testUser, path.ExchangeService, path.EmailCategory),
+				},
+				[]data.Collection{ic},
+				progress,
+			)
+			require.NoError(t, err)
+
+			expectTree(
+				t,
+				ctx,
+				expectedTreeWithChildren(
+					[]string{
+						testTenant,
+						service,
+						testUser,
+						category,
+					},
+					[]*expectedNode{
+						{
+							name: testInboxDir,
+							children: []*expectedNode{
+								{
+									name:     testFileName,
+									children: []*expectedNode{},
+									data:     test.expectData,
+								},
+							},
+						},
+					},
+				),
+				dirTree)
+
+			assert.Equal(t, test.expectRead, ic.read[testFileName], "item read")
+		})
+	}
+}
+
 func (suite *HierarchyBuilderUnitSuite) TestBuildDirectoryTreeMultipleSubdirectories() {
 	const (
 		personalDir = "personal"
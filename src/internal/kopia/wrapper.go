@@ -12,6 +12,7 @@ import (
 	"github.com/kopia/kopia/snapshot/policy"
 	"github.com/kopia/kopia/snapshot/snapshotfs"
 	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
 
 	"github.com/alcionai/corso/src/internal/data"
 	D "github.com/alcionai/corso/src/internal/diagnostics"
@@ -208,7 +209,7 @@ func (w Wrapper) makeSnapshotWithRoot(
 					IgnoreDirectoryErrors: &trueVal,
 				},
 			}
-			policyTree, err := policy.TreeForSourceWithOverride(innerCtx, w.c, si, errPolicy)
+			policyTree, err := w.policyTree(innerCtx, si, errPolicy)
 			if err != nil {
 				err = errors.Wrap(err, "get policy tree")
 				logger.Ctx(innerCtx).Errorw("kopia backup", err)
@@ -273,6 +274,126 @@ func (w Wrapper) getSnapshotRoot(
 	return rootDirEntry, errors.Wrap(err, "getting root directory")
 }
 
+// VerifySnapshot ensures the snapshot's manifest can be loaded, and that all
+// of the content making up its root directory is present in the repository.
+func (w Wrapper) VerifySnapshot(ctx context.Context, snapshotID string) error {
+	if w.c == nil {
+		return errNotConnected
+	}
+
+	man, err := snapshot.LoadSnapshot(ctx, w.c, manifest.ID(snapshotID))
+	if err != nil {
+		return errors.Wrap(err, "getting snapshot handle")
+	}
+
+	if _, err := w.c.VerifyObject(ctx, man.RootObjectID()); err != nil {
+		return errors.Wrap(err, "verifying snapshot root")
+	}
+
+	return nil
+}
+
+// CopySnapshot copies the snapshot with the given ID out of src and into this
+// repository, keeping the snapshot's source, tags, and timestamps so that it
+// can be used as a base for later backups.  Content that already exists in
+// this repository isn't uploaded again.  Returns the ID of the new snapshot.
+func (w Wrapper) CopySnapshot(ctx context.Context, src *Wrapper, snapshotID string) (string, error) {
+	if w.c == nil || src == nil || src.c == nil {
+		return "", errors.WithStack(errNotConnected)
+	}
+
+	ctx, end := D.Span(ctx, "kopia:copySnapshot")
+	defer end()
+
+	man, err := snapshot.LoadSnapshot(ctx, src.c, manifest.ID(snapshotID))
+	if err != nil {
+		return "", errors.Wrap(err, "getting snapshot handle")
+	}
+
+	root, err := snapshotfs.SnapshotRoot(src.c, man)
+	if err != nil {
+		return "", errors.Wrap(err, "getting root directory")
+	}
+
+	// the most recent copy from the same source lets the uploader skip
+	// reading files that haven't changed since.
+	prev, err := latestCompleteSnapshot(ctx, w.c, man.Source)
+	if err != nil {
+		return "", err
+	}
+
+	var copyID manifest.ID
+
+	err = repo.WriteSession(
+		ctx,
+		w.c,
+		repo.WriteSessionOptions{Purpose: "KopiaWrapperCopySnapshot"},
+		func(innerCtx context.Context, rw repo.RepositoryWriter) error {
+			policyTree, err := w.policyTree(innerCtx, man.Source, nil)
+			if err != nil {
+				return errors.Wrap(err, "get policy tree")
+			}
+
+			cp, err := snapshotfs.NewUploader(rw).Upload(innerCtx, root, policyTree, man.Source, prev...)
+			if err != nil {
+				return errors.Wrap(err, "uploading data")
+			}
+
+			if cp.IncompleteReason != "" || cp.Stats.ErrorCount > 0 {
+				return errors.Errorf(
+					"incomplete copy: %d errors, reason %q",
+					cp.Stats.ErrorCount,
+					cp.IncompleteReason)
+			}
+
+			cp.Description = man.Description
+			cp.StartTime = man.StartTime
+			cp.EndTime = man.EndTime
+			cp.Tags = maps.Clone(man.Tags)
+
+			copyID, err = snapshot.SaveSnapshot(innerCtx, rw, cp)
+
+			return errors.Wrap(err, "saving snapshot")
+		},
+	)
+	if err != nil {
+		return "", errors.Wrap(err, "kopia copy snapshot")
+	}
+
+	return string(copyID), nil
+}
+
+// latestCompleteSnapshot returns the most recent complete snapshot of the
+// source, if the repository contains one.
+func latestCompleteSnapshot(
+	ctx context.Context,
+	rep repo.Repository,
+	si snapshot.SourceInfo,
+) ([]*snapshot.Manifest, error) {
+	mans, err := snapshot.ListSnapshots(ctx, rep, si)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing snapshots")
+	}
+
+	var latest *snapshot.Manifest
+
+	for _, m := range mans {
+		if m.IncompleteReason != "" {
+			continue
+		}
+
+		if latest == nil || m.StartTime > latest.StartTime {
+			latest = m
+		}
+	}
+
+	if latest == nil {
+		return nil, nil
+	}
+
+	return []*snapshot.Manifest{latest}, nil
+}
+
 // getItemStream looks up the item at the given path starting from snapshotRoot.
 // If the item is a file in kopia then it returns a data.Stream of the item. If
 // the item does not exist in kopia or is not a file an error is returned. The
@@ -19,6 +19,8 @@ import (
 	"github.com/alcionai/corso/src/internal/connector/mockconnector"
 	"github.com/alcionai/corso/src/internal/data"
 	"github.com/alcionai/corso/src/internal/tester"
+	"github.com/alcionai/corso/src/pkg/control"
+	"github.com/alcionai/corso/src/pkg/credentials"
 	"github.com/alcionai/corso/src/pkg/logger"
 	"github.com/alcionai/corso/src/pkg/path"
 )
@@ -134,6 +136,97 @@ func (suite *KopiaUnitSuite) TestCloseWithoutInitDoesNotPanic() {
 	})
 }
 
+//revive:disable:context-as-argument
+func newFilesystemWrapper(t *testing.T, ctx context.Context) *Wrapper {
+	//revive:enable:context-as-argument
+	k := NewConn(tester.NewFilesystemStorage(t))
+	require.NoError(t, k.Initialize(ctx, control.RepoFormat{}))
+
+	w, err := NewWrapper(k)
+	require.NoError(t, err)
+	require.NoError(t, k.Close(ctx))
+
+	return w
+}
+
+func (suite *KopiaUnitSuite) TestCopySnapshot() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+	t.Setenv(credentials.CorsoPassphrase, "filesystem-test-passphrase")
+
+	src := newFilesystemWrapper(t, ctx)
+	defer src.Close(ctx)
+
+	dest := newFilesystemWrapper(t, ctx)
+	defer dest.Close(ctx)
+
+	_, err := dest.CopySnapshot(ctx, &Wrapper{}, "id")
+	assert.Error(t, err)
+
+	collection := &kopiaDataCollection{path: suite.testPath}
+	expected := map[string][]byte{}
+
+	for _, item := range []struct {
+		name string
+		data []byte
+	}{
+		{testFileName, testFileData},
+		{testFileName2, testFileData2},
+	} {
+		collection.streams = append(collection.streams, &mockconnector.MockExchangeData{
+			ID:     item.name,
+			Reader: io.NopCloser(bytes.NewReader(item.data)),
+		})
+
+		p, err := suite.testPath.Append(item.name, true)
+		require.NoError(t, err)
+
+		expected[p.String()] = item.data
+	}
+
+	stats, _, _, err := src.BackupCollections(
+		ctx,
+		nil,
+		[]data.Collection{collection},
+		map[string]string{TagBackupID: "bid"},
+		false,
+	)
+	require.NoError(t, err)
+
+	_, err = dest.CopySnapshot(ctx, src, "not-a-snapshot")
+	assert.Error(t, err)
+
+	srcMan, err := snapshot.LoadSnapshot(ctx, src.c, manifest.ID(stats.SnapshotID))
+	require.NoError(t, err)
+
+	// a second copy of the same snapshot reuses the content of the first.
+	for i := 0; i < 2; i++ {
+		id, err := dest.CopySnapshot(ctx, src, stats.SnapshotID)
+		require.NoError(t, err)
+
+		man, err := snapshot.LoadSnapshot(ctx, dest.c, manifest.ID(id))
+		require.NoError(t, err)
+		assert.Equal(t, srcMan.Source, man.Source)
+		assert.Equal(t, srcMan.Tags, man.Tags)
+		assert.Equal(t, srcMan.StartTime, man.StartTime)
+
+		paths := make([]path.Path, 0, len(expected))
+
+		for p := range expected {
+			pth, err := path.FromDataLayerPath(p, true)
+			require.NoError(t, err)
+
+			paths = append(paths, pth)
+		}
+
+		cols, err := dest.RestoreMultipleItems(ctx, id, paths, nil)
+		require.NoError(t, err)
+		testForFiles(t, expected, cols)
+	}
+}
+
 // ---------------
 // integration tests that use kopia
 // ---------------
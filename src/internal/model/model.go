@@ -24,6 +24,7 @@ const (
 	BackupSchema
 	BackupDetailsSchema
 	RepositorySchema
+	RetentionPolicySchema
 )
 
 // common tags for filtering
@@ -33,7 +34,7 @@ const (
 
 // Valid returns true if the ModelType value fits within the iota range.
 func (mt Schema) Valid() bool {
-	return mt > 0 && mt < RepositorySchema+1
+	return mt > 0 && mt < RetentionPolicySchema+1
 }
 
 type Model interface {
@@ -29,7 +29,8 @@ func (suite *ModelUnitSuite) TestValid() {
 		{model.BackupSchema, assert.True},
 		{model.BackupDetailsSchema, assert.True},
 		{model.RepositorySchema, assert.True},
-		{model.RepositorySchema + 1, assert.False},
+		{model.RetentionPolicySchema, assert.True},
+		{model.RetentionPolicySchema + 1, assert.False},
 		{model.Schema(-1), assert.False},
 		{model.Schema(100), assert.False},
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/alcionai/corso/src/internal/common"
 	"github.com/alcionai/corso/src/internal/connector"
 	"github.com/alcionai/corso/src/internal/connector/graph"
+	"github.com/alcionai/corso/src/internal/connector/onedrive"
 	"github.com/alcionai/corso/src/internal/connector/support"
 	"github.com/alcionai/corso/src/internal/data"
 	D "github.com/alcionai/corso/src/internal/diagnostics"
@@ -218,8 +219,11 @@ func (op *BackupOperation) Run(ctx context.Context) (err error) {
 // checker to see if conditions are correct for incremental backup behavior such as
 // retrieving metadata like delta tokens and previous paths.
 func useIncrementalBackup(sel selectors.Selector, opts control.Options) bool {
-	// Delta-based incrementals currently only supported for Exchange
-	if sel.Service != selectors.ServiceExchange {
+	// Delta-based incrementals are supported for Exchange, OneDrive,
+	// and SharePoint libraries.
+	switch sel.Service {
+	case selectors.ServiceExchange, selectors.ServiceOneDrive, selectors.ServiceSharePoint:
+	default:
 		return false
 	}
 
@@ -642,17 +646,37 @@ func mergeDetails(
 		}
 	}
 
-	if addedEntries != len(shortRefsFromPrevBackup) {
+	if expected := countDetailsEntries(shortRefsFromPrevBackup); addedEntries != expected {
 		return errors.Errorf(
 			"incomplete migration of backup details: found %v of %v expected items",
 			addedEntries,
-			len(shortRefsFromPrevBackup),
+			expected,
 		)
 	}
 
 	return nil
 }
 
+// countDetailsEntries returns the number of merged items that have an entry in
+// the base backup details.  Drive item sidecars and versions are stored
+// alongside their item, but aren't listed in the details themselves.
+func countDetailsEntries(refs map[string]path.Path) int {
+	var count int
+
+	for _, p := range refs {
+		switch p.Service() {
+		case path.OneDriveService, path.SharePointService:
+			if onedrive.IsMetaFile(p.Item()) || onedrive.IsVersionFile(p.Item()) {
+				continue
+			}
+		}
+
+		count++
+	}
+
+	return count
+}
+
 // writes the results metrics to the operation results.
 // later stored in the manifest using createBackupModels.
 func (op *BackupOperation) persistResults(
@@ -61,7 +61,7 @@ func prepNewTestBackupOp(
 	st := tester.NewPrefixedS3Storage(t)
 
 	k := kopia.NewConn(st)
-	require.NoError(t, k.Initialize(ctx))
+	require.NoError(t, k.Initialize(ctx, control.RepoFormat{}))
 
 	// kopiaRef comes with a count of 1 and Wrapper bumps it again so safe
 	// to close here.
@@ -337,7 +337,7 @@ func generateContainerOfItems(
 		dest,
 		collections)
 
-	deets, err := gc.RestoreDataCollections(ctx, acct, sel, dest, dataColls)
+	deets, err := gc.RestoreDataCollections(ctx, acct, sel, dest, control.Defaults(), dataColls)
 	require.NoError(t, err)
 
 	return deets
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/alcionai/corso/src/internal/connector/onedrive"
 	"github.com/alcionai/corso/src/internal/connector/support"
 	"github.com/alcionai/corso/src/internal/data"
 	evmock "github.com/alcionai/corso/src/internal/events/mock"
@@ -36,6 +37,7 @@ import (
 
 type mockRestorer struct {
 	gotPaths []path.Path
+	err      error
 }
 
 func (mr *mockRestorer) RestoreMultipleItems(
@@ -46,7 +48,7 @@ func (mr *mockRestorer) RestoreMultipleItems(
 ) ([]data.Collection, error) {
 	mr.gotPaths = append(mr.gotPaths, paths...)
 
-	return nil, nil
+	return nil, mr.err
 }
 
 func (mr mockRestorer) checkPaths(t *testing.T, expected []path.Path) {
@@ -825,6 +827,36 @@ func (suite *BackupOpSuite) TestBackupOperation_MergeBackupDetails_AddsItems() {
 			},
 			true,
 		)
+		metaPath1 = makePath(
+			suite.T(),
+			[]string{
+				tenant,
+				path.OneDriveService.String(),
+				ro,
+				path.FilesCategory.String(),
+				"drives",
+				"drive-id",
+				"root:",
+				"work",
+				"item1" + onedrive.MetaFileSuffix,
+			},
+			true,
+		)
+		versionPath1 = makePath(
+			suite.T(),
+			[]string{
+				tenant,
+				path.OneDriveService.String(),
+				ro,
+				path.FilesCategory.String(),
+				"drives",
+				"drive-id",
+				"root:",
+				"work",
+				onedrive.VersionFileName("item1", "1.0"),
+			},
+			true,
+		)
 		itemPath3 = makePath(
 			suite.T(),
 			[]string{
@@ -1103,6 +1135,38 @@ func (suite *BackupOpSuite) TestBackupOperation_MergeBackupDetails_AddsItems() {
 				makeDetailsEntry(suite.T(), itemPath1, 42, false),
 			},
 		},
+		{
+			name: "ItemMergedWithSidecarAndVersion",
+			inputShortRefsFromPrevBackup: map[string]path.Path{
+				itemPath1.ShortRef():    itemPath1,
+				metaPath1.ShortRef():    metaPath1,
+				versionPath1.ShortRef(): versionPath1,
+			},
+			inputMans: []*kopia.ManifestEntry{
+				{
+					Manifest: makeManifest(suite.T(), backup1.ID, ""),
+					Reasons: []kopia.Reason{
+						pathReason1,
+					},
+				},
+			},
+			populatedModels: map[model.StableID]backup.Backup{
+				backup1.ID: backup1,
+			},
+			populatedDetails: map[string]*details.Details{
+				backup1.DetailsID: {
+					DetailsModel: details.DetailsModel{
+						Entries: []details.DetailsEntry{
+							*makeDetailsEntry(suite.T(), itemPath1, 42, false),
+						},
+					},
+				},
+			},
+			errCheck: assert.NoError,
+			expectedEntries: []*details.DetailsEntry{
+				makeDetailsEntry(suite.T(), itemPath1, 42, false),
+			},
+		},
 		{
 			name: "ItemMergedExtraItemsInBase",
 			inputShortRefsFromPrevBackup: map[string]path.Path{
@@ -0,0 +1,258 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/internal/common"
+	"github.com/alcionai/corso/src/internal/data"
+	D "github.com/alcionai/corso/src/internal/diagnostics"
+	"github.com/alcionai/corso/src/internal/events"
+	"github.com/alcionai/corso/src/internal/export"
+	"github.com/alcionai/corso/src/internal/kopia"
+	"github.com/alcionai/corso/src/internal/model"
+	"github.com/alcionai/corso/src/internal/observe"
+	"github.com/alcionai/corso/src/internal/stats"
+	"github.com/alcionai/corso/src/internal/streamstore"
+	"github.com/alcionai/corso/src/pkg/account"
+	"github.com/alcionai/corso/src/pkg/backup/details"
+	"github.com/alcionai/corso/src/pkg/control"
+	"github.com/alcionai/corso/src/pkg/selectors"
+	"github.com/alcionai/corso/src/pkg/store"
+)
+
+// ExportOperation wraps an operation with export-specific props.
+// Exports retrieve the same data as a restore, but write it to the
+// local filesystem instead of M365.
+type ExportOperation struct {
+	operation
+
+	BackupID  model.StableID     `json:"backupID"`
+	Results   ExportResults      `json:"results"`
+	Selectors selectors.Selector `json:"selectors"`
+	Directory string             `json:"directory"`
+	Version   string             `json:"version"`
+
+	account account.Account
+}
+
+// ExportResults aggregate the details of the results of the operation.
+type ExportResults struct {
+	stats.Errs
+	stats.ReadWrites
+	stats.StartAndEndTime
+}
+
+// NewExportOperation constructs and validates an export operation.
+func NewExportOperation(
+	ctx context.Context,
+	opts control.Options,
+	kw *kopia.Wrapper,
+	sw *store.Wrapper,
+	acct account.Account,
+	backupID model.StableID,
+	sel selectors.Selector,
+	dir string,
+	bus events.Eventer,
+) (ExportOperation, error) {
+	op := ExportOperation{
+		operation: newOperation(opts, bus, kw, sw),
+		BackupID:  backupID,
+		Selectors: sel,
+		Directory: dir,
+		Version:   "v0",
+		account:   acct,
+	}
+	if err := op.validate(); err != nil {
+		return ExportOperation{}, err
+	}
+
+	return op, nil
+}
+
+func (op ExportOperation) validate() error {
+	if len(op.Directory) == 0 {
+		return errors.New("missing export directory")
+	}
+
+	return op.operation.validate()
+}
+
+// aggregates stats from the export.Run().
+type exportStats struct {
+	cs                []data.Collection
+	written           export.Stats
+	bytesRead         *stats.ByteCounter
+	resourceCount     int
+	started           bool
+	readErr, writeErr error
+
+	// a transient value only used to pair up start-end events.
+	exportID string
+}
+
+// Run begins a synchronous export operation.  The returned details
+// contain the entries that were selected for export.
+func (op *ExportOperation) Run(ctx context.Context) (exportDetails *details.Details, err error) {
+	ctx, end := D.Span(ctx, "operations:export:run")
+	defer end()
+
+	var (
+		opStats = exportStats{
+			bytesRead: &stats.ByteCounter{},
+			exportID:  uuid.NewString(),
+		}
+		startTime = time.Now()
+	)
+
+	defer func() {
+		// wait for the progress display to clean up
+		observe.Complete()
+
+		err = op.persistResults(ctx, startTime, &opStats)
+		if err != nil {
+			return
+		}
+	}()
+
+	detailsStore := streamstore.New(op.kopia, op.account.ID(), op.Selectors.PathService())
+
+	bup, deets, err := getBackupAndDetailsFromID(
+		ctx,
+		op.BackupID,
+		op.store,
+		detailsStore,
+	)
+	if err != nil {
+		err = errors.Wrap(err, "export")
+		opStats.readErr = err
+
+		return nil, err
+	}
+
+	op.bus.Event(
+		ctx,
+		events.ExportStart,
+		map[string]any{
+			events.StartTime:        startTime,
+			events.BackupID:         op.BackupID,
+			events.BackupCreateTime: bup.CreationTime,
+			events.ExportID:         opStats.exportID,
+		},
+	)
+
+	fds, err := op.Selectors.Reduce(ctx, deets)
+	if err != nil {
+		opStats.readErr = err
+		return nil, err
+	}
+
+	paths, err := detailsPaths(fds)
+	if err != nil {
+		opStats.readErr = err
+		return nil, err
+	}
+
+	infos, err := driveItemInfos(fds, "")
+	if err != nil {
+		opStats.readErr = err
+		return nil, err
+	}
+
+	observe.Message(ctx, fmt.Sprintf("Discovered %d items in backup %s to export", len(paths), op.BackupID))
+
+	kopiaComplete, closer := observe.MessageWithCompletion(ctx, "Enumerating items in repository")
+	defer closer()
+	defer close(kopiaComplete)
+
+	dcs, err := op.kopia.RestoreMultipleItems(ctx, bup.SnapshotID, paths, opStats.bytesRead)
+	if err != nil {
+		err = errors.Wrap(err, "retrieving service data")
+		opStats.readErr = err
+
+		return nil, err
+	}
+	kopiaComplete <- struct{}{}
+
+	dcs = withItemInfo(dcs, infos)
+
+	opStats.cs = dcs
+	opStats.resourceCount = len(data.ResourceOwnerSet(dcs))
+
+	exportComplete, closer := observe.MessageWithCompletion(ctx, "Exporting data to "+op.Directory)
+	defer closer()
+	defer close(exportComplete)
+
+	opStats.started = true
+
+	opStats.written, err = export.Collections(ctx, op.Directory, op.Options.Export, dcs)
+	if err != nil {
+		err = errors.Wrap(err, "exporting service data")
+		opStats.writeErr = err
+
+		return nil, err
+	}
+	exportComplete <- struct{}{}
+
+	return fds, nil
+}
+
+// persists details and statistics about the export operation.
+func (op *ExportOperation) persistResults(
+	ctx context.Context,
+	started time.Time,
+	opStats *exportStats,
+) error {
+	op.Results.StartedAt = started
+	op.Results.CompletedAt = time.Now()
+
+	op.Status = Completed
+
+	if !opStats.started {
+		op.Status = Failed
+
+		return multierror.Append(
+			errors.New("errors prevented the operation from processing"),
+			opStats.readErr,
+			opStats.writeErr)
+	}
+
+	if opStats.readErr == nil && opStats.writeErr == nil && opStats.written.ItemsWritten == 0 {
+		op.Status = NoData
+	}
+
+	op.Results.ReadErrors = opStats.readErr
+	op.Results.WriteErrors = opStats.writeErr
+
+	op.Results.BytesRead = opStats.bytesRead.NumBytes
+	op.Results.ItemsRead = len(opStats.cs) // TODO: file count, not collection count
+	op.Results.ItemsWritten = opStats.written.ItemsWritten
+	op.Results.ResourceOwners = opStats.resourceCount
+
+	dur := op.Results.CompletedAt.Sub(op.Results.StartedAt)
+
+	op.bus.Event(
+		ctx,
+		events.ExportEnd,
+		map[string]any{
+			events.BackupID:      op.BackupID,
+			events.DataRetrieved: op.Results.BytesRead,
+			events.Duration:      dur,
+			events.EndTime:       common.FormatTime(op.Results.CompletedAt),
+			events.ExportID:      opStats.exportID,
+			events.ItemsRead:     op.Results.ItemsRead,
+			events.ItemsWritten:  op.Results.ItemsWritten,
+			events.Resources:     op.Results.ResourceOwners,
+			events.Service:       op.Selectors.Service.String(),
+			events.StartTime:     common.FormatTime(op.Results.StartedAt),
+			events.Status:        op.Status.String(),
+		},
+	)
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package operations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/connector/exchange"
+	"github.com/alcionai/corso/src/internal/data"
+	evmock "github.com/alcionai/corso/src/internal/events/mock"
+	"github.com/alcionai/corso/src/internal/export"
+	"github.com/alcionai/corso/src/internal/kopia"
+	"github.com/alcionai/corso/src/internal/stats"
+	"github.com/alcionai/corso/src/internal/tester"
+	"github.com/alcionai/corso/src/pkg/account"
+	"github.com/alcionai/corso/src/pkg/control"
+	"github.com/alcionai/corso/src/pkg/selectors"
+	"github.com/alcionai/corso/src/pkg/store"
+)
+
+// ---------------------------------------------------------------------------
+// unit
+// ---------------------------------------------------------------------------
+
+type ExportOpSuite struct {
+	suite.Suite
+}
+
+func TestExportOpSuite(t *testing.T) {
+	suite.Run(t, new(ExportOpSuite))
+}
+
+func (suite *ExportOpSuite) TestNewExportOperation() {
+	var (
+		kw   = &kopia.Wrapper{}
+		sw   = &store.Wrapper{}
+		acct = account.Account{}
+	)
+
+	table := []struct {
+		name     string
+		kw       *kopia.Wrapper
+		sw       *store.Wrapper
+		dir      string
+		errCheck assert.ErrorAssertionFunc
+	}{
+		{"good", kw, sw, "/tmp/export", assert.NoError},
+		{"missing directory", kw, sw, "", assert.Error},
+		{"missing kopia", nil, sw, "/tmp/export", assert.Error},
+		{"missing modelstore", kw, nil, "/tmp/export", assert.Error},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			ctx, flush := tester.NewContext()
+			defer flush()
+
+			_, err := NewExportOperation(
+				ctx,
+				control.Options{},
+				test.kw,
+				test.sw,
+				acct,
+				"backup-id",
+				selectors.Selector{DiscreteOwner: "test"},
+				test.dir,
+				evmock.NewBus())
+			test.errCheck(t, err)
+		})
+	}
+}
+
+func (suite *ExportOpSuite) TestExportOperation_PersistResults() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	var (
+		kw   = &kopia.Wrapper{}
+		sw   = &store.Wrapper{}
+		acct = account.Account{}
+		now  = time.Now()
+	)
+
+	table := []struct {
+		expectStatus opStatus
+		expectErr    assert.ErrorAssertionFunc
+		stats        exportStats
+	}{
+		{
+			expectStatus: Completed,
+			expectErr:    assert.NoError,
+			stats: exportStats{
+				started:       true,
+				resourceCount: 1,
+				bytesRead: &stats.ByteCounter{
+					NumBytes: 42,
+				},
+				cs:      []data.Collection{&exchange.Collection{}},
+				written: export.Stats{ItemsWritten: 1, BytesWritten: 42},
+			},
+		},
+		{
+			expectStatus: Failed,
+			expectErr:    assert.Error,
+			stats: exportStats{
+				started:   false,
+				bytesRead: &stats.ByteCounter{},
+			},
+		},
+		{
+			expectStatus: NoData,
+			expectErr:    assert.NoError,
+			stats: exportStats{
+				started:   true,
+				bytesRead: &stats.ByteCounter{},
+				cs:        []data.Collection{},
+			},
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.expectStatus.String(), func(t *testing.T) {
+			op, err := NewExportOperation(
+				ctx,
+				control.Options{},
+				kw,
+				sw,
+				acct,
+				"foo",
+				selectors.Selector{DiscreteOwner: "test"},
+				"/tmp/export",
+				evmock.NewBus())
+			require.NoError(t, err)
+			test.expectErr(t, op.persistResults(ctx, now, &test.stats))
+
+			assert.Equal(t, test.expectStatus.String(), op.Status.String(), "status")
+			assert.Equal(t, len(test.stats.cs), op.Results.ItemsRead, "items read")
+			assert.Equal(t, test.stats.written.ItemsWritten, op.Results.ItemsWritten, "items written")
+			assert.Equal(t, test.stats.bytesRead.NumBytes, op.Results.BytesRead, "bytes read")
+			assert.Equal(t, test.stats.resourceCount, op.Results.ResourceOwners, "resource owners")
+			assert.Equal(t, now, op.Results.StartedAt, "started at")
+			assert.Less(t, now, op.Results.CompletedAt, "completed at")
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/alcionai/corso/src/internal/common"
+	"github.com/alcionai/corso/src/internal/connector/onedrive"
 	"github.com/alcionai/corso/src/internal/connector/support"
 	"github.com/alcionai/corso/src/internal/data"
 	D "github.com/alcionai/corso/src/internal/diagnostics"
@@ -46,6 +47,9 @@ type RestoreResults struct {
 	stats.Errs
 	stats.ReadWrites
 	stats.StartAndEndTime
+	// ItemsSkipped counts the items that were not restored because they
+	// collided with an existing item under the Skip collision policy.
+	ItemsSkipped int `json:"itemsSkipped,omitempty"`
 }
 
 // NewRestoreOperation constructs and validates a restore operation.
@@ -153,7 +157,7 @@ func (op *RestoreOperation) Run(ctx context.Context) (restoreDetails *details.De
 		},
 	)
 
-	paths, err := formatDetailsForRestoration(ctx, op.Selectors, deets)
+	paths, infos, err := formatDetailsForRestoration(ctx, op.Selectors, deets, op.Options.Versions.Restore)
 	if err != nil {
 		opStats.readErr = err
 		return nil, err
@@ -172,8 +176,23 @@ func (op *RestoreOperation) Run(ctx context.Context) (restoreDetails *details.De
 
 		return nil, err
 	}
+
+	if !op.Options.Permissions.Skip {
+		fdcs, err := folderMetadataCollections(ctx, op.kopia, bup.SnapshotID, paths, opStats.bytesRead)
+		if err != nil {
+			err = errors.Wrap(err, "retrieving folder metadata")
+			opStats.readErr = err
+
+			return nil, err
+		}
+
+		dcs = append(dcs, fdcs...)
+	}
+
 	kopiaComplete <- struct{}{}
 
+	dcs = withItemInfo(dcs, infos)
+
 	opStats.cs = dcs
 	opStats.resourceCount = len(data.ResourceOwnerSet(dcs))
 
@@ -192,6 +211,7 @@ func (op *RestoreOperation) Run(ctx context.Context) (restoreDetails *details.De
 		op.account,
 		op.Selectors,
 		op.Destination,
+		op.Options,
 		dcs)
 	if err != nil {
 		err = errors.Wrap(err, "restoring service data")
@@ -229,7 +249,10 @@ func (op *RestoreOperation) persistResults(
 			opStats.writeErr)
 	}
 
-	if opStats.readErr == nil && opStats.writeErr == nil && opStats.gc.Successful == 0 {
+	if opStats.readErr == nil &&
+		opStats.writeErr == nil &&
+		opStats.gc.Successful == 0 &&
+		opStats.gc.Skipped == 0 {
 		op.Status = NoData
 	}
 
@@ -239,6 +262,7 @@ func (op *RestoreOperation) persistResults(
 	op.Results.BytesRead = opStats.bytesRead.NumBytes
 	op.Results.ItemsRead = len(opStats.cs) // TODO: file count, not collection count
 	op.Results.ItemsWritten = opStats.gc.Successful
+	op.Results.ItemsSkipped = opStats.gc.Skipped
 	op.Results.ResourceOwners = opStats.resourceCount
 
 	dur := op.Results.CompletedAt.Sub(op.Results.StartedAt)
@@ -252,6 +276,7 @@ func (op *RestoreOperation) persistResults(
 			events.Duration:      dur,
 			events.EndTime:       common.FormatTime(op.Results.CompletedAt),
 			events.ItemsRead:     op.Results.ItemsRead,
+			events.ItemsSkipped:  op.Results.ItemsSkipped,
 			events.ItemsWritten:  op.Results.ItemsWritten,
 			events.Resources:     op.Results.ResourceOwners,
 			events.RestoreID:     opStats.restoreID,
@@ -265,17 +290,308 @@ func (op *RestoreOperation) persistResults(
 }
 
 // formatDetailsForRestoration reduces the provided detail entries according to the
-// selector specifications.
+// selector specifications.  If a version is provided, the paths of that prior
+// version of each OneDrive item are produced instead of the items' paths.
+// Also returns the info of the drive items, keyed by the produced paths.
 func formatDetailsForRestoration(
 	ctx context.Context,
 	sel selectors.Selector,
 	deets *details.Details,
-) ([]path.Path, error) {
+	version string,
+) ([]path.Path, map[string]details.ItemInfo, error) {
 	fds, err := sel.Reduce(ctx, deets)
 	if err != nil {
+		return nil, nil, err
+	}
+
+	var paths []path.Path
+
+	if len(version) > 0 {
+		fds = entriesWithVersion(fds, version)
+		if len(fds.Entries) == 0 {
+			return nil, nil, errors.Errorf("no selected items have version %s", version)
+		}
+
+		paths, err = versionPaths(fds, version)
+	} else {
+		paths, err = detailsPaths(fds)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	metaPaths, err := metadataPaths(fds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	infos, err := driveItemInfos(fds, version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return append(paths, metaPaths...), infos, nil
+}
+
+// entriesWithVersion reduces the details to the OneDrive items that hold the
+// given prior version.
+func entriesWithVersion(fds *details.Details, version string) *details.Details {
+	ents := make([]details.DetailsEntry, 0, len(fds.Entries))
+
+	for _, ent := range fds.Entries {
+		if ent.OneDrive != nil && ent.OneDrive.HasVersion(version) {
+			ents = append(ents, ent)
+		}
+	}
+
+	return &details.Details{DetailsModel: details.DetailsModel{Entries: ents}}
+}
+
+// versionPaths produces the paths of the streams holding the given prior
+// version of each item.
+func versionPaths(fds *details.Details, version string) ([]path.Path, error) {
+	paths := make([]path.Path, 0, len(fds.Entries))
+
+	for _, ent := range fds.Entries {
+		vp, err := siblingPath(ent.RepoRef, func(item string) string {
+			return onedrive.VersionFileName(item, version)
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "building item version path")
+		}
+
+		paths = append(paths, vp)
+	}
+
+	return paths, nil
+}
+
+// metadataPaths produces the paths of the metadata sidecars that are stored
+// next to OneDrive and SharePoint items.  Sidecars have no details entries of
+// their own, and are restored alongside the items they belong to.
+func metadataPaths(fds *details.Details) ([]path.Path, error) {
+	var paths []path.Path
+
+	for _, ent := range fds.Entries {
+		hasMeta := (ent.OneDrive != nil && ent.OneDrive.HasMetadata) ||
+			(ent.SharePoint != nil && ent.SharePoint.HasMetadata)
+		if !hasMeta {
+			continue
+		}
+
+		mp, err := siblingPath(ent.RepoRef, func(item string) string {
+			return item + onedrive.MetaFileSuffix
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "building item metadata path")
+		}
+
+		paths = append(paths, mp)
+	}
+
+	return paths, nil
+}
+
+// folderMetadataPaths produces the paths of the metadata sidecars of the drive
+// folders that hold the items at the given paths, and of the folders above
+// them, up to the root of the drive.
+func folderMetadataPaths(paths []path.Path) ([]path.Path, error) {
+	var (
+		res  []path.Path
+		seen = map[string]struct{}{}
+	)
+
+	for _, p := range paths {
+		if p.Category() != path.FilesCategory && p.Category() != path.LibrariesCategory {
+			continue
+		}
+
+		dir, err := p.Dir()
+		if err != nil {
+			return nil, errors.Wrap(err, "getting item parent path")
+		}
+
+		for {
+			drivePath, err := path.ToOneDrivePath(dir)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := seen[dir.String()]; ok || len(drivePath.Folders) == 0 {
+				break
+			}
+
+			seen[dir.String()] = struct{}{}
+
+			mp, err := dir.Append(onedrive.FolderMetaFileName, true)
+			if err != nil {
+				return nil, errors.Wrap(err, "building folder metadata path")
+			}
+
+			res = append(res, mp)
+
+			if dir, err = dir.Dir(); err != nil {
+				return nil, errors.Wrap(err, "getting folder parent path")
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// folderMetadataCollections retrieves the metadata sidecars of the drive
+// folders that hold the items at the given paths.  Only folders that have
+// permissions of their own have a sidecar, so missing sidecars are skipped.
+func folderMetadataCollections(
+	ctx context.Context,
+	r restorer,
+	snapshotID string,
+	paths []path.Path,
+	bc kopia.ByteCounter,
+) ([]data.Collection, error) {
+	metaPaths, err := folderMetadataPaths(paths)
+	if err != nil || len(metaPaths) == 0 {
 		return nil, err
 	}
 
+	dcs, err := r.RestoreMultipleItems(ctx, snapshotID, metaPaths, bc)
+	if err == nil {
+		return dcs, nil
+	}
+
+	merr := &multierror.Error{}
+	if !errors.As(err, &merr) {
+		return nil, err
+	}
+
+	for _, e := range merr.Errors {
+		if !errors.Is(e, kopia.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	return dcs, nil
+}
+
+// driveItemInfos maps the paths of the OneDrive and SharePoint items in the
+// details, or the paths of the given prior version of the items, to the info
+// of the items.  Drive items are stored under their graph ID, so restores
+// and exports need the info to recover the items' names.
+func driveItemInfos(fds *details.Details, version string) (map[string]details.ItemInfo, error) {
+	infos := map[string]details.ItemInfo{}
+
+	for _, ent := range fds.Entries {
+		if ent.OneDrive == nil && ent.SharePoint == nil {
+			continue
+		}
+
+		p, err := siblingPath(ent.RepoRef, func(item string) string {
+			if len(version) > 0 {
+				return onedrive.VersionFileName(item, version)
+			}
+
+			return item
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "building item path")
+		}
+
+		infos[p.String()] = ent.ItemInfo
+	}
+
+	return infos, nil
+}
+
+// siblingPath produces the path of the item named by the name func, within
+// the folder that holds the item at repoRef.  The func receives the name of
+// the item at repoRef.
+func siblingPath(repoRef string, name func(string) string) (path.Path, error) {
+	p, err := path.FromDataLayerPath(repoRef, true)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing details entry path")
+	}
+
+	dir, err := p.Dir()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting item parent path")
+	}
+
+	return dir.Append(name(p.Item()), true)
+}
+
+// withItemInfo attaches the info to the restored items at the paths it
+// holds, so that consumers can read the info through data.StreamInfo.
+func withItemInfo(dcs []data.Collection, infos map[string]details.ItemInfo) []data.Collection {
+	if len(infos) == 0 {
+		return dcs
+	}
+
+	res := make([]data.Collection, 0, len(dcs))
+
+	for _, dc := range dcs {
+		res = append(res, infoCollection{Collection: dc, infos: infos})
+	}
+
+	return res
+}
+
+var (
+	_ data.StreamInfo = &infoStream{}
+	_ data.StreamSize = &infoStream{}
+)
+
+// infoCollection attaches item info to the streams of a restored collection.
+type infoCollection struct {
+	data.Collection
+	infos map[string]details.ItemInfo
+}
+
+func (ic infoCollection) Items() <-chan data.Stream {
+	res := make(chan data.Stream)
+
+	go func() {
+		defer close(res)
+
+		for s := range ic.Collection.Items() {
+			p, err := ic.FullPath().Append(s.UUID(), true)
+			if err != nil {
+				res <- s
+				continue
+			}
+
+			info, ok := ic.infos[p.String()]
+			if !ok {
+				res <- s
+				continue
+			}
+
+			res <- &infoStream{Stream: s, info: info}
+		}
+	}()
+
+	return res
+}
+
+type infoStream struct {
+	data.Stream
+	info details.ItemInfo
+}
+
+func (is *infoStream) Info() details.ItemInfo {
+	return is.info
+}
+
+func (is *infoStream) Size() int64 {
+	if ss, ok := is.Stream.(data.StreamSize); ok {
+		return ss.Size()
+	}
+
+	return 0
+}
+
+// detailsPaths parses the paths of each entry in the details.
+func detailsPaths(fds *details.Details) ([]path.Path, error) {
 	var (
 		errs     *multierror.Error
 		fdsPaths = fds.Paths()
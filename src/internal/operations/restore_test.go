@@ -5,11 +5,15 @@ import (
 	"testing"
 	"time"
 
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/alcionai/corso/src/internal/connector/exchange"
+	"github.com/alcionai/corso/src/internal/connector/mockconnector"
+	"github.com/alcionai/corso/src/internal/connector/onedrive"
 	"github.com/alcionai/corso/src/internal/connector/support"
 	"github.com/alcionai/corso/src/internal/data"
 	"github.com/alcionai/corso/src/internal/events"
@@ -19,7 +23,9 @@ import (
 	"github.com/alcionai/corso/src/internal/stats"
 	"github.com/alcionai/corso/src/internal/tester"
 	"github.com/alcionai/corso/src/pkg/account"
+	"github.com/alcionai/corso/src/pkg/backup/details"
 	"github.com/alcionai/corso/src/pkg/control"
+	"github.com/alcionai/corso/src/pkg/path"
 	"github.com/alcionai/corso/src/pkg/selectors"
 	"github.com/alcionai/corso/src/pkg/store"
 )
@@ -69,6 +75,22 @@ func (suite *RestoreOpSuite) TestRestoreOperation_PersistResults() {
 				},
 			},
 		},
+		{
+			expectStatus: Completed,
+			expectErr:    assert.NoError,
+			stats: restoreStats{
+				started:       true,
+				resourceCount: 1,
+				bytesRead: &stats.ByteCounter{
+					NumBytes: 42,
+				},
+				cs: []data.Collection{&exchange.Collection{}},
+				gc: &support.ConnectorOperationStatus{
+					ObjectCount: 1,
+					Skipped:     1,
+				},
+			},
+		},
 		{
 			expectStatus: Failed,
 			expectErr:    assert.Error,
@@ -108,6 +130,7 @@ func (suite *RestoreOpSuite) TestRestoreOperation_PersistResults() {
 			assert.Equal(t, len(test.stats.cs), op.Results.ItemsRead, "items read")
 			assert.Equal(t, test.stats.readErr, op.Results.ReadErrors, "read errors")
 			assert.Equal(t, test.stats.gc.Successful, op.Results.ItemsWritten, "items written")
+			assert.Equal(t, test.stats.gc.Skipped, op.Results.ItemsSkipped, "items skipped")
 			assert.Equal(t, test.stats.bytesRead.NumBytes, op.Results.BytesRead, "resource owners")
 			assert.Equal(t, test.stats.resourceCount, op.Results.ResourceOwners, "resource owners")
 			assert.Equal(t, test.stats.writeErr, op.Results.WriteErrors, "write errors")
@@ -117,6 +140,246 @@ func (suite *RestoreOpSuite) TestRestoreOperation_PersistResults() {
 	}
 }
 
+func (suite *RestoreOpSuite) TestMetadataPaths() {
+	t := suite.T()
+
+	odItem, err := path.Builder{}.
+		Append("drives", "drive-id", "root:", "folder", "withMeta").
+		ToDataLayerOneDrivePath("tenant", "user", true)
+	require.NoError(t, err)
+
+	odBare, err := path.Builder{}.
+		Append("drives", "drive-id", "root:", "folder", "withoutMeta").
+		ToDataLayerOneDrivePath("tenant", "user", true)
+	require.NoError(t, err)
+
+	spItem, err := path.Builder{}.
+		Append("drives", "drive-id", "root:", "withMeta").
+		ToDataLayerSharePointPath("tenant", "site", path.LibrariesCategory, true)
+	require.NoError(t, err)
+
+	deets := &details.Details{
+		DetailsModel: details.DetailsModel{
+			Entries: []details.DetailsEntry{
+				{
+					RepoRef:  odItem.String(),
+					ItemInfo: details.ItemInfo{OneDrive: &details.OneDriveInfo{HasMetadata: true}},
+				},
+				{
+					RepoRef:  odBare.String(),
+					ItemInfo: details.ItemInfo{OneDrive: &details.OneDriveInfo{}},
+				},
+				{
+					RepoRef:  spItem.String(),
+					ItemInfo: details.ItemInfo{SharePoint: &details.SharePointInfo{HasMetadata: true}},
+				},
+			},
+		},
+	}
+
+	paths, err := metadataPaths(deets)
+	require.NoError(t, err)
+
+	expect := []string{
+		odItem.String() + onedrive.MetaFileSuffix,
+		spItem.String() + onedrive.MetaFileSuffix,
+	}
+	result := []string{}
+
+	for _, p := range paths {
+		result = append(result, p.String())
+	}
+
+	assert.ElementsMatch(t, expect, result)
+}
+
+func (suite *RestoreOpSuite) TestFolderMetadataPaths() {
+	t := suite.T()
+
+	drivePath := func(isItem bool, elems ...string) path.Path {
+		p, err := path.Builder{}.
+			Append(append([]string{"drives", "drive-id", "root:"}, elems...)...).
+			ToDataLayerOneDrivePath("tenant", "user", isItem)
+		require.NoError(t, err)
+
+		return p
+	}
+
+	spItem, err := path.Builder{}.
+		Append("drives", "drive-id", "root:", "item").
+		ToDataLayerSharePointPath("tenant", "site", path.LibrariesCategory, true)
+	require.NoError(t, err)
+
+	exItem, err := path.Builder{}.
+		Append("inbox", "item").
+		ToDataLayerExchangePathForCategory("tenant", "user", path.EmailCategory, true)
+	require.NoError(t, err)
+
+	paths := []path.Path{
+		drivePath(true, "a", "b", "item"),
+		drivePath(true, "a", "b", "item"+onedrive.MetaFileSuffix),
+		drivePath(true, "a", "other"),
+		drivePath(true, "rootItem"),
+		spItem,
+		exItem,
+	}
+
+	result, err := folderMetadataPaths(paths)
+	require.NoError(t, err)
+
+	expect := []string{
+		drivePath(false, "a", "b", onedrive.FolderMetaFileName).String(),
+		drivePath(false, "a", onedrive.FolderMetaFileName).String(),
+	}
+	got := []string{}
+
+	for _, p := range result {
+		got = append(got, p.String())
+	}
+
+	assert.ElementsMatch(t, expect, got)
+}
+
+func (suite *RestoreOpSuite) TestFolderMetadataCollections() {
+	item, err := path.Builder{}.
+		Append("drives", "drive-id", "root:", "folder", "item").
+		ToDataLayerOneDrivePath("tenant", "user", true)
+	require.NoError(suite.T(), err)
+
+	notFound := errors.Wrap(kopia.ErrNotFound, "getting nested object handle")
+
+	table := []struct {
+		name      string
+		err       error
+		expectErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "all found",
+			expectErr: assert.NoError,
+		},
+		{
+			name:      "missing sidecars",
+			err:       multierror.Append(nil, notFound, notFound),
+			expectErr: assert.NoError,
+		},
+		{
+			name:      "read failure",
+			err:       multierror.Append(nil, notFound, assert.AnError),
+			expectErr: assert.Error,
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			ctx, flush := tester.NewContext()
+			defer flush()
+
+			mr := &mockRestorer{err: test.err}
+
+			_, err := folderMetadataCollections(ctx, mr, "snapshot", []path.Path{item}, nil)
+			test.expectErr(t, err)
+			assert.Len(t, mr.gotPaths, 1)
+		})
+	}
+}
+
+func (suite *RestoreOpSuite) TestWithItemInfo() {
+	t := suite.T()
+
+	folder, err := path.Builder{}.
+		Append("drives", "drive-id", "root:", "folder").
+		ToDataLayerOneDrivePath("tenant", "user", false)
+	require.NoError(t, err)
+
+	item, err := folder.Append("itemID", true)
+	require.NoError(t, err)
+
+	deets := &details.Details{
+		DetailsModel: details.DetailsModel{
+			Entries: []details.DetailsEntry{
+				{
+					RepoRef:  item.String(),
+					ItemInfo: details.ItemInfo{OneDrive: &details.OneDriveInfo{ItemName: "a.txt"}},
+				},
+			},
+		},
+	}
+
+	infos, err := driveItemInfos(deets, "")
+	require.NoError(t, err)
+
+	coll := mockconnector.NewMockExchangeCollection(folder, 2)
+	coll.Names[0] = "itemID"
+
+	dcs := withItemInfo([]data.Collection{coll}, infos)
+	require.Len(t, dcs, 1)
+	assert.Equal(t, folder, dcs[0].FullPath())
+
+	found := 0
+
+	for s := range dcs[0].Items() {
+		require.Implements(t, (*data.StreamSize)(nil), s)
+		require.Implements(t, (*data.StreamInfo)(nil), s)
+
+		info := s.(data.StreamInfo).Info()
+
+		if s.UUID() != "itemID" {
+			assert.Nil(t, info.OneDrive, "unrelated items keep their info")
+			continue
+		}
+
+		found++
+
+		require.NotNil(t, info.OneDrive)
+		assert.Equal(t, "a.txt", info.OneDrive.ItemName)
+	}
+
+	assert.Equal(t, 1, found)
+}
+
+func (suite *RestoreOpSuite) TestVersionPaths() {
+	t := suite.T()
+
+	withVersion, err := path.Builder{}.
+		Append("drives", "drive-id", "root:", "folder", "withVersion").
+		ToDataLayerOneDrivePath("tenant", "user", true)
+	require.NoError(t, err)
+
+	withoutVersion, err := path.Builder{}.
+		Append("drives", "drive-id", "root:", "folder", "withoutVersion").
+		ToDataLayerOneDrivePath("tenant", "user", true)
+	require.NoError(t, err)
+
+	deets := &details.Details{
+		DetailsModel: details.DetailsModel{
+			Entries: []details.DetailsEntry{
+				{
+					RepoRef: withVersion.String(),
+					ItemInfo: details.ItemInfo{OneDrive: &details.OneDriveInfo{
+						Versions: []details.OneDriveVersion{{ID: "1.0"}, {ID: "2.0"}},
+					}},
+				},
+				{
+					RepoRef: withoutVersion.String(),
+					ItemInfo: details.ItemInfo{OneDrive: &details.OneDriveInfo{
+						Versions: []details.OneDriveVersion{{ID: "1.0"}},
+					}},
+				},
+			},
+		},
+	}
+
+	fds := entriesWithVersion(deets, "2.0")
+	require.Len(t, fds.Entries, 1)
+	assert.Equal(t, withVersion.String(), fds.Entries[0].RepoRef)
+
+	paths, err := versionPaths(fds, "2.0")
+	require.NoError(t, err)
+	require.Len(t, paths, 1)
+
+	assert.Equal(t, withVersion.Folder(), paths[0].Folder(), "parent folder")
+	assert.Equal(t, onedrive.VersionFileName("withVersion", "2.0"), paths[0].Item())
+}
+
 // ---------------------------------------------------------------------------
 // integration
 // ---------------------------------------------------------------------------
@@ -156,7 +419,7 @@ func (suite *RestoreOpIntegrationSuite) SetupSuite() {
 	st := tester.NewPrefixedS3Storage(t)
 
 	k := kopia.NewConn(st)
-	require.NoError(t, k.Initialize(ctx))
+	require.NoError(t, k.Initialize(ctx, control.RepoFormat{}))
 
 	suite.kopiaCloser = func(ctx context.Context) {
 		k.Close(ctx)
@@ -12,6 +12,9 @@ import (
 
 const (
 	CorsoLoadTests                                = "CORSO_LOAD_TESTS"
+	CorsoAzureStorageTests                        = "CORSO_AZURE_STORAGE_TESTS"
+	CorsoGCSStorageTests                          = "CORSO_GCS_STORAGE_TESTS"
+	CorsoSFTPStorageTests                         = "CORSO_SFTP_STORAGE_TESTS"
 	CorsoCITests                                  = "CORSO_CI_TESTS"
 	CorsoCLIBackupTests                           = "CORSO_COMMAND_LINE_BACKUP_TESTS"
 	CorsoCLIConfigTests                           = "CORSO_COMMAND_LINE_CONFIG_TESTS"
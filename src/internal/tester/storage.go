@@ -1,6 +1,9 @@
 package tester
 
 import (
+	"os"
+	"path"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -11,6 +14,21 @@ import (
 
 const testRepoRootPrefix = "corso_integration_test/"
 
+var AzureStorageEnvs = []string{
+	storage.ContainerKey,
+	storage.AccountNameKey,
+}
+
+var GCSStorageEnvs = []string{
+	storage.BucketKey,
+}
+
+var SFTPStorageEnvs = []string{
+	storage.SFTPHostKey,
+	storage.SFTPUsernameKey,
+	storage.SFTPPathKey,
+}
+
 var AWSStorageCredEnvs = []string{
 	credentials.AWSAccessKeyID,
 	credentials.AWSSecretAccessKey,
@@ -44,3 +62,109 @@ func NewPrefixedS3Storage(t *testing.T) storage.Storage {
 
 	return st
 }
+
+// NewFilesystemStorage returns a storage.Storage object backed by a unique
+// t.TempDir() on the local filesystem.  Unlike NewPrefixedS3Storage, it
+// requires no external storage endpoint or credentials beyond the corso
+// passphrase.
+func NewFilesystemStorage(t *testing.T) storage.Storage {
+	st, err := storage.NewStorage(
+		storage.ProviderFilesystem,
+		storage.FilesystemConfig{
+			Path: t.TempDir(),
+		},
+		storage.CommonConfig{
+			Corso:       credentials.GetCorso(),
+			KopiaCfgDir: t.TempDir(),
+		},
+	)
+	require.NoError(t, err, "creating storage")
+
+	return st
+}
+
+// NewPrefixedAzureStorage returns a storage.Storage object initialized with
+// environment variables used for integration tests that use Azure Blob Storage
+// (or the Azurite emulator, when ENDPOINT is set).  The prefix for the storage
+// path will be unique.
+func NewPrefixedAzureStorage(t *testing.T) storage.Storage {
+	now := LogTimeOfTest(t)
+
+	st, err := storage.NewStorage(
+		storage.ProviderAzure,
+		storage.AzureConfig{
+			AzureStorage: credentials.GetAzureStorage(),
+			Container:    os.Getenv(storage.ContainerKey),
+			AccountName:  os.Getenv(storage.AccountNameKey),
+			Endpoint:     os.Getenv(storage.EndpointKey),
+			Prefix:       testRepoRootPrefix + t.Name() + "-" + now,
+		},
+		storage.CommonConfig{
+			Corso:       credentials.GetCorso(),
+			KopiaCfgDir: t.TempDir(),
+		},
+	)
+	require.NoError(t, err, "creating storage")
+
+	return st
+}
+
+// NewPrefixedGCSStorage returns a storage.Storage object initialized with
+// environment variables used for integration tests that use Google Cloud
+// Storage (or fake-gcs-server, when ENDPOINT is set).  The prefix for the
+// storage path will be unique.  fake-gcs-server must be started with a
+// -public-host matching the ENDPOINT so that object reads resolve.
+func NewPrefixedGCSStorage(t *testing.T) storage.Storage {
+	now := LogTimeOfTest(t)
+
+	st, err := storage.NewStorage(
+		storage.ProviderGCS,
+		storage.GCSConfig{
+			GCS:      credentials.GetGCS(),
+			Bucket:   os.Getenv(storage.BucketKey),
+			Endpoint: os.Getenv(storage.EndpointKey),
+			Prefix:   testRepoRootPrefix + t.Name() + "-" + now,
+		},
+		storage.CommonConfig{
+			Corso:       credentials.GetCorso(),
+			KopiaCfgDir: t.TempDir(),
+		},
+	)
+	require.NoError(t, err, "creating storage")
+
+	return st
+}
+
+// NewPrefixedSFTPStorage returns a storage.Storage object initialized with
+// environment variables used for integration tests that use an SFTP server.
+// The repo is placed in a unique directory beneath the configured path.
+func NewPrefixedSFTPStorage(t *testing.T) storage.Storage {
+	now := LogTimeOfTest(t)
+
+	port, err := strconv.Atoi(os.Getenv(storage.SFTPPortKey))
+	if err != nil {
+		port = 0
+	}
+
+	st, err := storage.NewStorage(
+		storage.ProviderSFTP,
+		storage.SFTPConfig{
+			SFTP:           credentials.GetSFTP(),
+			Host:           os.Getenv(storage.SFTPHostKey),
+			Port:           port,
+			Username:       os.Getenv(storage.SFTPUsernameKey),
+			KeyFile:        os.Getenv(storage.SFTPKeyFileKey),
+			KnownHostsFile: os.Getenv(storage.SFTPKnownHostsFileKey),
+			Path: path.Join(
+				os.Getenv(storage.SFTPPathKey),
+				testRepoRootPrefix+t.Name()+"-"+now),
+		},
+		storage.CommonConfig{
+			Corso:       credentials.GetCorso(),
+			KopiaCfgDir: t.TempDir(),
+		},
+	)
+	require.NoError(t, err, "creating storage")
+
+	return st
+}
@@ -0,0 +1,268 @@
+// Package verify checks that the data in a backup can be read back out of
+// the repository, without restoring it to M365.
+package verify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"strconv"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/cli/print"
+	"github.com/alcionai/corso/src/internal/data"
+	"github.com/alcionai/corso/src/internal/kopia"
+	"github.com/alcionai/corso/src/internal/model"
+	"github.com/alcionai/corso/src/pkg/backup"
+	"github.com/alcionai/corso/src/pkg/backup/details"
+	"github.com/alcionai/corso/src/pkg/path"
+)
+
+type snapshotReader interface {
+	VerifySnapshot(ctx context.Context, snapshotID string) error
+	RestoreMultipleItems(
+		ctx context.Context,
+		snapshotID string,
+		paths []path.Path,
+		bc kopia.ByteCounter,
+	) ([]data.Collection, error)
+}
+
+type detailsReader interface {
+	ReadBackupDetails(ctx context.Context, detailsID string) (*details.Details, error)
+}
+
+// Result is the outcome of verifying a single backup.
+type Result struct {
+	BackupID      model.StableID
+	Service       string
+	ResourceOwner string
+
+	// Items is the number of items listed in the backup details.  Sampled
+	// is the number of those items that were read back out of the snapshot,
+	// and Verified is the number that were read without error.
+	Items    int
+	Sampled  int
+	Verified int
+
+	Errs []error
+}
+
+// interface compliance checks
+var _ print.Printable = &Result{}
+
+// Passed is true if no problems were found with the backup.
+func (r Result) Passed() bool {
+	return len(r.Errs) == 0
+}
+
+func (r *Result) fail(err error) {
+	r.Errs = append(r.Errs, err)
+}
+
+// Backup verifies that the backup's data snapshot and details can be read,
+// and streams samplePct percent of the backup's items out of the snapshot.
+// Each sampled item has its data format version checked, and is read in
+// full, which causes kopia to validate the hash of every piece of content
+// that makes up the item.  Problems are recorded in the Result rather than
+// returned, so that one bad backup doesn't prevent the rest from being
+// checked.
+func Backup(
+	ctx context.Context,
+	sr snapshotReader,
+	dr detailsReader,
+	b *backup.Backup,
+	samplePct float64,
+	rnd *rand.Rand,
+) *Result {
+	res := &Result{
+		BackupID:      b.ID,
+		Service:       b.Selector.PathService().String(),
+		ResourceOwner: b.Selector.DiscreteOwner,
+	}
+
+	if err := sr.VerifySnapshot(ctx, b.SnapshotID); err != nil {
+		res.fail(errors.Wrap(err, "reading backup snapshot"))
+	}
+
+	deets, err := dr.ReadBackupDetails(ctx, b.DetailsID)
+	if err != nil {
+		res.fail(errors.Wrap(err, "reading backup details"))
+		return res
+	}
+
+	items := deets.Items()
+	res.Items = len(items)
+
+	// no point in streaming items out of a snapshot that can't be read.
+	if !res.Passed() {
+		return res
+	}
+
+	paths := make([]path.Path, 0, len(items))
+
+	for _, idx := range sample(len(items), samplePct, rnd) {
+		p, err := path.FromDataLayerPath(items[idx].RepoRef, true)
+		if err != nil {
+			res.fail(errors.Wrapf(err, "parsing item path %s", items[idx].RepoRef))
+			continue
+		}
+
+		paths = append(paths, p)
+	}
+
+	res.Sampled = len(paths)
+
+	if len(paths) == 0 {
+		return res
+	}
+
+	dcs, err := sr.RestoreMultipleItems(ctx, b.SnapshotID, paths, nil)
+	if err != nil {
+		var merr *multierror.Error
+		if errors.As(err, &merr) {
+			for _, e := range merr.Errors {
+				res.fail(errors.Wrap(e, "retrieving item"))
+			}
+		} else {
+			res.fail(errors.Wrap(err, "retrieving items"))
+		}
+	}
+
+	for _, dc := range dcs {
+		for s := range dc.Items() {
+			if err := readStream(s); err != nil {
+				res.fail(errors.Wrapf(err, "reading item %s/%s", dc.FullPath(), s.UUID()))
+				continue
+			}
+
+			res.Verified++
+		}
+	}
+
+	return res
+}
+
+// sample produces the indices of pct percent of n items, in ascending
+// order.  At least one item is sampled if pct is positive.
+func sample(n int, pct float64, rnd *rand.Rand) []int {
+	if n == 0 || pct <= 0 {
+		return nil
+	}
+
+	size := int(math.Ceil(float64(n) * math.Min(pct, 100) / 100))
+	idxs := make([]int, 0, size)
+
+	// selection sampling, which keeps the indices ordered.
+	for i := 0; i < n && len(idxs) < size; i++ {
+		if rnd.Intn(n-i) < size-len(idxs) {
+			idxs = append(idxs, i)
+		}
+	}
+
+	return idxs
+}
+
+// readStream reads the stream to completion, and ensures its length
+// matches the size recorded in the snapshot.
+func readStream(s data.Stream) error {
+	rc := s.ToReader()
+	defer rc.Close()
+
+	n, err := io.Copy(io.Discard, rc)
+	if err != nil {
+		return err
+	}
+
+	if ss, ok := s.(data.StreamSize); ok && ss.Size() != n {
+		return errors.Errorf("read %d bytes, expected %d", n, ss.Size())
+	}
+
+	return nil
+}
+
+// --------------------------------------------------------------------------------
+// CLI Output
+// --------------------------------------------------------------------------------
+
+// PrintAll writes the slice of Results to StdOut, in the format requested by the caller.
+func PrintAll(ctx context.Context, rs []*Result) {
+	if len(rs) == 0 {
+		print.Info(ctx, "No backups to verify")
+		return
+	}
+
+	ps := []print.Printable{}
+	for _, r := range rs {
+		ps = append(ps, print.Printable(r))
+	}
+
+	print.All(ctx, ps...)
+}
+
+type Printable struct {
+	BackupID      model.StableID `json:"backupID"`
+	Service       string         `json:"service"`
+	ResourceOwner string         `json:"resourceOwner"`
+	Items         int            `json:"items"`
+	Sampled       int            `json:"sampled"`
+	Verified      int            `json:"verified"`
+	Passed        bool           `json:"passed"`
+	Errors        []string       `json:"errors,omitempty"`
+}
+
+// MinimumPrintable reduces the Result to its minimally printable details.
+func (r Result) MinimumPrintable() any {
+	errs := make([]string, 0, len(r.Errs))
+	for _, e := range r.Errs {
+		errs = append(errs, e.Error())
+	}
+
+	return Printable{
+		BackupID:      r.BackupID,
+		Service:       r.Service,
+		ResourceOwner: r.ResourceOwner,
+		Items:         r.Items,
+		Sampled:       r.Sampled,
+		Verified:      r.Verified,
+		Passed:        r.Passed(),
+		Errors:        errs,
+	}
+}
+
+// Headers returns the human-readable names of properties in a Result
+// for printing out to a terminal in a columnar display.
+func (r Result) Headers() []string {
+	return []string{
+		"ID",
+		"Service",
+		"Resource Owner",
+		"Items",
+		"Sampled",
+		"Verified",
+		"Result",
+	}
+}
+
+// Values returns the values matching the Headers list for printing
+// out to a terminal in a columnar display.
+func (r Result) Values() []string {
+	status := "pass"
+	if !r.Passed() {
+		status = fmt.Sprintf("fail (%d errors)", len(r.Errs))
+	}
+
+	return []string{
+		string(r.BackupID),
+		r.Service,
+		r.ResourceOwner,
+		strconv.Itoa(r.Items),
+		strconv.Itoa(r.Sampled),
+		strconv.Itoa(r.Verified),
+		status,
+	}
+}
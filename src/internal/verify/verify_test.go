@@ -0,0 +1,294 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/data"
+	"github.com/alcionai/corso/src/internal/kopia"
+	"github.com/alcionai/corso/src/internal/model"
+	"github.com/alcionai/corso/src/internal/tester"
+	"github.com/alcionai/corso/src/pkg/backup"
+	"github.com/alcionai/corso/src/pkg/backup/details"
+	"github.com/alcionai/corso/src/pkg/path"
+	"github.com/alcionai/corso/src/pkg/selectors"
+)
+
+// ---------------------------------------------------------------------------
+// mocks
+// ---------------------------------------------------------------------------
+
+type mockStream struct {
+	id   string
+	data []byte
+	size int64
+	err  error
+}
+
+func (ms mockStream) UUID() string  { return ms.id }
+func (ms mockStream) Deleted() bool { return false }
+func (ms mockStream) Size() int64   { return ms.size }
+
+func (ms mockStream) ToReader() io.ReadCloser {
+	if ms.err != nil {
+		return io.NopCloser(&errReader{ms.err})
+	}
+
+	return io.NopCloser(bytes.NewReader(ms.data))
+}
+
+type errReader struct {
+	err error
+}
+
+func (er *errReader) Read([]byte) (int, error) {
+	return 0, er.err
+}
+
+type mockCollection struct {
+	fullPath path.Path
+	streams  []data.Stream
+}
+
+func (mc mockCollection) FullPath() path.Path         { return mc.fullPath }
+func (mc mockCollection) PreviousPath() path.Path     { return nil }
+func (mc mockCollection) State() data.CollectionState { return data.NewState }
+func (mc mockCollection) DoNotMergeItems() bool       { return false }
+func (mc mockCollection) Items() <-chan data.Stream {
+	ch := make(chan data.Stream, len(mc.streams))
+	defer close(ch)
+
+	for _, s := range mc.streams {
+		ch <- s
+	}
+
+	return ch
+}
+
+type mockSnapshotReader struct {
+	snapErr error
+	// streams keyed by item name.  Missing items are not returned.
+	streams map[string]mockStream
+}
+
+func (msr mockSnapshotReader) VerifySnapshot(context.Context, string) error {
+	return msr.snapErr
+}
+
+func (msr mockSnapshotReader) RestoreMultipleItems(
+	_ context.Context,
+	_ string,
+	paths []path.Path,
+	_ kopia.ByteCounter,
+) ([]data.Collection, error) {
+	var (
+		errs *multierror.Error
+		cols = []data.Collection{}
+	)
+
+	for _, p := range paths {
+		s, ok := msr.streams[p.Item()]
+		if !ok {
+			errs = multierror.Append(errs, kopia.ErrNotFound)
+			continue
+		}
+
+		dir, _ := p.Dir()
+		cols = append(cols, mockCollection{fullPath: dir, streams: []data.Stream{s}})
+	}
+
+	return cols, errs.ErrorOrNil()
+}
+
+type mockDetailsReader struct {
+	deets *details.Details
+	err   error
+}
+
+func (mdr mockDetailsReader) ReadBackupDetails(context.Context, string) (*details.Details, error) {
+	return mdr.deets, mdr.err
+}
+
+// ---------------------------------------------------------------------------
+// tests
+// ---------------------------------------------------------------------------
+
+type VerifyUnitSuite struct {
+	suite.Suite
+}
+
+func TestVerifyUnitSuite(t *testing.T) {
+	suite.Run(t, new(VerifyUnitSuite))
+}
+
+func stubDetails(t *testing.T, items ...string) *details.Details {
+	deets := &details.Details{}
+
+	for _, item := range items {
+		p, err := path.Builder{}.
+			Append("Inbox", item).
+			ToDataLayerExchangePathForCategory("tenant", "user", path.EmailCategory, true)
+		require.NoError(t, err)
+
+		deets.Entries = append(deets.Entries, details.DetailsEntry{
+			RepoRef:  p.String(),
+			ShortRef: p.ShortRef(),
+			ItemInfo: details.ItemInfo{Exchange: &details.ExchangeInfo{ItemType: details.ExchangeMail}},
+		})
+	}
+
+	return deets
+}
+
+func stubStreams(items ...string) map[string]mockStream {
+	ss := map[string]mockStream{}
+	for _, item := range items {
+		ss[item] = mockStream{id: item, data: []byte(item), size: int64(len(item))}
+	}
+
+	return ss
+}
+
+func (suite *VerifyUnitSuite) TestBackup() {
+	var (
+		items = []string{"a", "bb", "ccc"}
+		bup   = &backup.Backup{
+			BaseModel: model.BaseModel{ID: "bid"},
+			Selector:  selectors.NewExchangeBackup([]string{"user"}).Selector,
+		}
+	)
+
+	table := []struct {
+		name         string
+		sr           func() mockSnapshotReader
+		dr           mockDetailsReader
+		pct          float64
+		expectPass   bool
+		expectSample int
+		expectVerify int
+	}{
+		{
+			name:         "pass",
+			sr:           func() mockSnapshotReader { return mockSnapshotReader{streams: stubStreams(items...)} },
+			pct:          100,
+			expectPass:   true,
+			expectSample: 3,
+			expectVerify: 3,
+		},
+		{
+			name:         "no sample",
+			sr:           func() mockSnapshotReader { return mockSnapshotReader{streams: stubStreams(items...)} },
+			pct:          0,
+			expectPass:   true,
+			expectSample: 0,
+			expectVerify: 0,
+		},
+		{
+			name: "unreadable snapshot",
+			sr: func() mockSnapshotReader {
+				return mockSnapshotReader{snapErr: assert.AnError, streams: stubStreams(items...)}
+			},
+			pct:          100,
+			expectPass:   false,
+			expectSample: 0,
+		},
+		{
+			name:         "unreadable details",
+			sr:           func() mockSnapshotReader { return mockSnapshotReader{streams: stubStreams(items...)} },
+			dr:           mockDetailsReader{err: assert.AnError},
+			pct:          100,
+			expectPass:   false,
+			expectSample: 0,
+		},
+		{
+			name:         "missing item",
+			sr:           func() mockSnapshotReader { return mockSnapshotReader{streams: stubStreams(items[1:]...)} },
+			pct:          100,
+			expectPass:   false,
+			expectSample: 3,
+			expectVerify: 2,
+		},
+		{
+			name: "version mismatch",
+			sr: func() mockSnapshotReader {
+				ss := stubStreams(items...)
+				ss["a"] = mockStream{id: "a", err: assert.AnError}
+
+				return mockSnapshotReader{streams: ss}
+			},
+			pct:          100,
+			expectPass:   false,
+			expectSample: 3,
+			expectVerify: 2,
+		},
+		{
+			name: "truncated item",
+			sr: func() mockSnapshotReader {
+				ss := stubStreams(items...)
+				ss["ccc"] = mockStream{id: "ccc", data: []byte("cc"), size: 3}
+
+				return mockSnapshotReader{streams: ss}
+			},
+			pct:          100,
+			expectPass:   false,
+			expectSample: 3,
+			expectVerify: 2,
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			ctx, flush := tester.NewContext()
+			defer flush()
+
+			dr := test.dr
+			if dr.err == nil {
+				dr.deets = stubDetails(t, items...)
+			}
+
+			res := Backup(ctx, test.sr(), dr, bup, test.pct, rand.New(rand.NewSource(0)))
+			assert.Equal(t, test.expectPass, res.Passed(), res.Errs)
+			assert.Equal(t, bup.ID, res.BackupID)
+			assert.Equal(t, "user", res.ResourceOwner)
+			assert.Equal(t, test.expectSample, res.Sampled)
+			assert.Equal(t, test.expectVerify, res.Verified)
+		})
+	}
+}
+
+func (suite *VerifyUnitSuite) TestSample() {
+	table := []struct {
+		name   string
+		n      int
+		pct    float64
+		expect int
+	}{
+		{"none", 10, 0, 0},
+		{"no items", 0, 50, 0},
+		{"rounds up", 10, 1, 1},
+		{"quarter", 10, 25, 3},
+		{"all", 10, 100, 10},
+		{"over", 10, 150, 10},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			idxs := sample(test.n, test.pct, rand.New(rand.NewSource(1)))
+			assert.Len(t, idxs, test.expect)
+
+			for i := 1; i < len(idxs); i++ {
+				assert.Less(t, idxs[i-1], idxs[i])
+			}
+
+			for _, idx := range idxs {
+				assert.Less(t, idx, test.n)
+			}
+		})
+	}
+}
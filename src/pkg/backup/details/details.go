@@ -3,6 +3,7 @@ package details
 import (
 	"context"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -484,6 +485,9 @@ type SharePointInfo struct {
 	ParentPath string    `json:"parentPath,omitempty"`
 	Size       int64     `json:"size,omitempty"`
 	WebURL     string    `json:"webUrl,omitempty"`
+	// HasMetadata is true if the item's permissions were stored in a
+	// metadata sidecar next to the item.
+	HasMetadata bool `json:"hasMetadata,omitempty"`
 }
 
 // Headers returns the human-readable names of properties in a SharePointInfo
@@ -527,17 +531,36 @@ type OneDriveInfo struct {
 	Owner      string    `json:"owner,omitempty"`
 	ParentPath string    `json:"parentPath"`
 	Size       int64     `json:"size,omitempty"`
+	// HasMetadata is true if the item's permissions were stored in a
+	// metadata sidecar next to the item.
+	HasMetadata bool `json:"hasMetadata,omitempty"`
+	// Versions lists the prior versions of the item that were stored
+	// alongside it.  Only populated if version history was backed up.
+	Versions []OneDriveVersion `json:"versions,omitempty"`
+}
+
+// OneDriveVersion describes a prior version of a oneDrive item.
+type OneDriveVersion struct {
+	ID         string    `json:"id"`
+	Modified   time.Time `json:"modified,omitempty"`
+	ModifiedBy string    `json:"modifiedBy,omitempty"`
+	Size       int64     `json:"size,omitempty"`
 }
 
 // Headers returns the human-readable names of properties in a OneDriveInfo
 // for printing out to a terminal in a columnar display.
 func (i OneDriveInfo) Headers() []string {
-	return []string{"ItemName", "ParentPath", "Size", "Owner", "Created", "Modified"}
+	return []string{"ItemName", "ParentPath", "Size", "Owner", "Created", "Modified", "Versions"}
 }
 
 // Values returns the values matching the Headers list for printing
 // out to a terminal in a columnar display.
 func (i OneDriveInfo) Values() []string {
+	vs := make([]string, 0, len(i.Versions))
+	for _, v := range i.Versions {
+		vs = append(vs, v.ID)
+	}
+
 	return []string{
 		i.ItemName,
 		i.ParentPath,
@@ -545,9 +568,22 @@ func (i OneDriveInfo) Values() []string {
 		i.Owner,
 		common.FormatTabularDisplayTime(i.Created),
 		common.FormatTabularDisplayTime(i.Modified),
+		strings.Join(vs, ","),
 	}
 }
 
+// HasVersion returns true if the given prior version of the item was backed
+// up.
+func (i OneDriveInfo) HasVersion(id string) bool {
+	for _, v := range i.Versions {
+		if v.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (i *OneDriveInfo) UpdateParentPath(newPath path.Path) error {
 	newParent, err := path.GetDriveFolderPath(newPath)
 	if err != nil {
@@ -141,8 +141,28 @@ func (suite *DetailsUnitSuite) TestDetailsEntry_HeadersValues() {
 					},
 				},
 			},
-			expectHs: []string{"ID", "ItemName", "ParentPath", "Size", "Owner", "Created", "Modified"},
-			expectVs: []string{"deadbeef", "itemName", "parentPath", "1.0 kB", "user@email.com", nowStr, nowStr},
+			expectHs: []string{"ID", "ItemName", "ParentPath", "Size", "Owner", "Created", "Modified", "Versions"},
+			expectVs: []string{"deadbeef", "itemName", "parentPath", "1.0 kB", "user@email.com", nowStr, nowStr, ""},
+		},
+		{
+			name: "oneDrive info with versions",
+			entry: DetailsEntry{
+				RepoRef:  "reporef",
+				ShortRef: "deadbeef",
+				ItemInfo: ItemInfo{
+					OneDrive: &OneDriveInfo{
+						ItemName:   "itemName",
+						ParentPath: "parentPath",
+						Size:       1000,
+						Owner:      "user@email.com",
+						Created:    now,
+						Modified:   now,
+						Versions:   []OneDriveVersion{{ID: "1.0"}, {ID: "2.0"}},
+					},
+				},
+			},
+			expectHs: []string{"ID", "ItemName", "ParentPath", "Size", "Owner", "Created", "Modified", "Versions"},
+			expectVs: []string{"deadbeef", "itemName", "parentPath", "1.0 kB", "user@email.com", nowStr, nowStr, "1.0,2.0"},
 		},
 	}
 
@@ -0,0 +1,271 @@
+// Package retention describes the rules used to decide which backups are
+// kept in a repository, and which are pruned.
+package retention
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/cli/print"
+	"github.com/alcionai/corso/src/internal/model"
+	"github.com/alcionai/corso/src/pkg/backup"
+	"github.com/alcionai/corso/src/pkg/path"
+)
+
+// Policy is a set of retention rules for the backups of a service.  A
+// policy with an empty ResourceOwner applies to every resource owner in
+// the service that doesn't have a policy of its own.
+//
+// Each rule retains backups independently, and a backup is kept if any
+// rule retains it.  KeepLast retains the N most recent backups.  The
+// calendar rules retain the most recent backup within each of the N most
+// recent days, weeks, months, or years in which a backup was made.  A
+// zero value disables the rule.
+type Policy struct {
+	model.BaseModel
+
+	Service       string `json:"service"`
+	ResourceOwner string `json:"resourceOwner,omitempty"`
+
+	KeepLast    int `json:"keepLast,omitempty"`
+	KeepDaily   int `json:"keepDaily,omitempty"`
+	KeepWeekly  int `json:"keepWeekly,omitempty"`
+	KeepMonthly int `json:"keepMonthly,omitempty"`
+	KeepYearly  int `json:"keepYearly,omitempty"`
+}
+
+// interface compliance checks
+var _ print.Printable = &Policy{}
+
+// New produces a retention Policy for the service and resource owner.  An
+// empty owner produces a policy that covers the whole service.
+func New(service path.ServiceType, owner string) *Policy {
+	return &Policy{
+		BaseModel: model.BaseModel{
+			Tags: map[string]string{
+				model.ServiceTag: service.String(),
+			},
+		},
+		Service:       service.String(),
+		ResourceOwner: owner,
+	}
+}
+
+// Validate ensures the policy's rules are usable.
+func (p Policy) Validate() error {
+	if len(p.Service) == 0 {
+		return errors.New("missing retention policy service")
+	}
+
+	rules := map[string]int{
+		"keep-last":    p.KeepLast,
+		"keep-daily":   p.KeepDaily,
+		"keep-weekly":  p.KeepWeekly,
+		"keep-monthly": p.KeepMonthly,
+		"keep-yearly":  p.KeepYearly,
+	}
+
+	var populated bool
+
+	for name, n := range rules {
+		if n < 0 {
+			return errors.Errorf("%s must not be negative", name)
+		}
+
+		populated = populated || n > 0
+	}
+
+	// a policy without rules would prune every backup it covers.
+	if !populated {
+		return errors.New("retention policy must retain at least one backup")
+	}
+
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Evaluation
+// ---------------------------------------------------------------------------
+
+type ownerKey struct {
+	service string
+	owner   string
+}
+
+// Expired evaluates the policies against the backups, and returns the
+// backups that no rule retains.  Backups are grouped by service and
+// resource owner, and each group is evaluated against the policy for its
+// owner, falling back to the policy for its service.  Groups without any
+// policy are retained in full.
+func Expired(policies []*Policy, bs []*backup.Backup) []*backup.Backup {
+	byOwner := map[ownerKey]*Policy{}
+	for _, p := range policies {
+		byOwner[ownerKey{p.Service, p.ResourceOwner}] = p
+	}
+
+	groups := map[ownerKey][]*backup.Backup{}
+	keys := []ownerKey{}
+
+	for _, b := range bs {
+		k := ownerKey{b.Tags[model.ServiceTag], b.Selector.DiscreteOwner}
+
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+
+		groups[k] = append(groups[k], b)
+	}
+
+	expired := []*backup.Backup{}
+
+	for _, k := range keys {
+		p, ok := byOwner[k]
+		if !ok {
+			p, ok = byOwner[ownerKey{service: k.service}]
+		}
+
+		if !ok {
+			continue
+		}
+
+		expired = append(expired, p.expired(groups[k])...)
+	}
+
+	return expired
+}
+
+// bucketFunc produces the calendar period a backup falls within.
+type bucketFunc func(time.Time) string
+
+var (
+	daily   bucketFunc = func(t time.Time) string { return t.Format("2006-01-02") }
+	monthly bucketFunc = func(t time.Time) string { return t.Format("2006-01") }
+	yearly  bucketFunc = func(t time.Time) string { return t.Format("2006") }
+	weekly  bucketFunc = func(t time.Time) string {
+		y, w := t.ISOWeek()
+		return fmt.Sprintf("%d-%02d", y, w)
+	}
+)
+
+// expired returns the backups that aren't retained by any of the policy's
+// rules.  All backups are expected to belong to the same resource owner.
+func (p Policy) expired(bs []*backup.Backup) []*backup.Backup {
+	sorted := make([]*backup.Backup, len(bs))
+	copy(sorted, bs)
+
+	// newest first
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreationTime.After(sorted[j].CreationTime)
+	})
+
+	keep := map[model.StableID]struct{}{}
+
+	for i := 0; i < p.KeepLast && i < len(sorted); i++ {
+		keep[sorted[i].ID] = struct{}{}
+	}
+
+	rules := []struct {
+		n      int
+		bucket bucketFunc
+	}{
+		{p.KeepDaily, daily},
+		{p.KeepWeekly, weekly},
+		{p.KeepMonthly, monthly},
+		{p.KeepYearly, yearly},
+	}
+
+	for _, r := range rules {
+		seen := map[string]struct{}{}
+
+		for _, b := range sorted {
+			if len(seen) >= r.n {
+				break
+			}
+
+			bucket := r.bucket(b.CreationTime.UTC())
+			if _, ok := seen[bucket]; ok {
+				continue
+			}
+
+			seen[bucket] = struct{}{}
+			keep[b.ID] = struct{}{}
+		}
+	}
+
+	expired := []*backup.Backup{}
+
+	for _, b := range sorted {
+		if _, ok := keep[b.ID]; !ok {
+			expired = append(expired, b)
+		}
+	}
+
+	return expired
+}
+
+// --------------------------------------------------------------------------------
+// CLI Output
+// --------------------------------------------------------------------------------
+
+// Print writes the Policy to StdOut, in the format requested by the caller.
+func (p Policy) Print(ctx context.Context) {
+	print.Item(ctx, p)
+}
+
+// PrintAll writes the slice of Policies to StdOut, in the format requested by the caller.
+func PrintAll(ctx context.Context, ps []*Policy) {
+	if len(ps) == 0 {
+		print.Info(ctx, "No retention policies available")
+		return
+	}
+
+	pps := []print.Printable{}
+	for _, p := range ps {
+		pps = append(pps, print.Printable(p))
+	}
+
+	print.All(ctx, pps...)
+}
+
+// MinimumPrintable reduces the Policy to its minimally printable details.
+func (p Policy) MinimumPrintable() any {
+	return p
+}
+
+// Headers returns the human-readable names of properties in a Policy
+// for printing out to a terminal in a columnar display.
+func (p Policy) Headers() []string {
+	return []string{
+		"Service",
+		"Resource Owner",
+		"Last",
+		"Daily",
+		"Weekly",
+		"Monthly",
+		"Yearly",
+	}
+}
+
+// Values returns the values matching the Headers list for printing
+// out to a terminal in a columnar display.
+func (p Policy) Values() []string {
+	owner := p.ResourceOwner
+	if len(owner) == 0 {
+		owner = "*"
+	}
+
+	return []string{
+		p.Service,
+		owner,
+		strconv.Itoa(p.KeepLast),
+		strconv.Itoa(p.KeepDaily),
+		strconv.Itoa(p.KeepWeekly),
+		strconv.Itoa(p.KeepMonthly),
+		strconv.Itoa(p.KeepYearly),
+	}
+}
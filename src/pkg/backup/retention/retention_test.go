@@ -0,0 +1,193 @@
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/internal/model"
+	"github.com/alcionai/corso/src/pkg/backup"
+	"github.com/alcionai/corso/src/pkg/path"
+	"github.com/alcionai/corso/src/pkg/selectors"
+)
+
+type RetentionUnitSuite struct {
+	suite.Suite
+}
+
+func TestRetentionUnitSuite(t *testing.T) {
+	suite.Run(t, new(RetentionUnitSuite))
+}
+
+var now = time.Date(2023, 1, 18, 12, 0, 0, 0, time.UTC)
+
+func stubBackup(id string, service path.ServiceType, owner string, created time.Time) *backup.Backup {
+	sel := selectors.Selector{DiscreteOwner: owner}
+
+	return &backup.Backup{
+		BaseModel: model.BaseModel{
+			ID:   model.StableID(id),
+			Tags: map[string]string{model.ServiceTag: service.String()},
+		},
+		CreationTime: created,
+		Selector:     sel,
+	}
+}
+
+func ids(bs []*backup.Backup) []string {
+	r := make([]string, 0, len(bs))
+	for _, b := range bs {
+		r = append(r, string(b.ID))
+	}
+
+	return r
+}
+
+func (suite *RetentionUnitSuite) TestValidate() {
+	table := []struct {
+		name   string
+		policy func() *Policy
+		expect assert.ErrorAssertionFunc
+	}{
+		{
+			name: "valid",
+			policy: func() *Policy {
+				p := New(path.ExchangeService, "")
+				p.KeepLast = 1
+
+				return p
+			},
+			expect: assert.NoError,
+		},
+		{
+			name:   "no rules",
+			policy: func() *Policy { return New(path.ExchangeService, "") },
+			expect: assert.Error,
+		},
+		{
+			name: "negative rule",
+			policy: func() *Policy {
+				p := New(path.ExchangeService, "")
+				p.KeepLast = 1
+				p.KeepDaily = -1
+
+				return p
+			},
+			expect: assert.Error,
+		},
+		{
+			name:   "no service",
+			policy: func() *Policy { return &Policy{KeepLast: 1} },
+			expect: assert.Error,
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			test.expect(t, test.policy().Validate())
+		})
+	}
+}
+
+func (suite *RetentionUnitSuite) TestExpired_rules() {
+	// two backups a day for the last two weeks, newest first.
+	bs := []*backup.Backup{}
+	for i := 0; i < 28; i++ {
+		created := now.Add(-time.Duration(i) * 12 * time.Hour)
+		bs = append(bs, stubBackup(created.Format(time.RFC3339), path.ExchangeService, "u", created))
+	}
+
+	table := []struct {
+		name   string
+		policy Policy
+		expect []string
+	}{
+		{
+			name:   "keep last",
+			policy: Policy{KeepLast: 3},
+			expect: ids(bs[:3]),
+		},
+		{
+			name:   "keep daily",
+			policy: Policy{KeepDaily: 3},
+			expect: ids([]*backup.Backup{bs[0], bs[2], bs[4]}),
+		},
+		{
+			name:   "keep weekly",
+			policy: Policy{KeepWeekly: 2},
+			// 2023-01-18 is a wednesday; the prior week ends on the 15th.
+			expect: ids([]*backup.Backup{bs[0], bs[6]}),
+		},
+		{
+			name:   "keep monthly",
+			policy: Policy{KeepMonthly: 2},
+			expect: ids([]*backup.Backup{bs[0]}),
+		},
+		{
+			name:   "keep last and daily overlap",
+			policy: Policy{KeepLast: 2, KeepDaily: 2},
+			expect: ids([]*backup.Backup{bs[0], bs[1], bs[2]}),
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			p := test.policy
+			p.Service = path.ExchangeService.String()
+
+			expired := Expired([]*Policy{&p}, bs)
+			kept := map[string]struct{}{}
+
+			for _, id := range ids(bs) {
+				kept[id] = struct{}{}
+			}
+
+			for _, id := range ids(expired) {
+				delete(kept, id)
+			}
+
+			assert.ElementsMatch(t, test.expect, keys(kept))
+		})
+	}
+}
+
+func (suite *RetentionUnitSuite) TestExpired_owners() {
+	t := suite.T()
+
+	var (
+		ex1 = stubBackup("ex1", path.ExchangeService, "alice", now)
+		ex2 = stubBackup("ex2", path.ExchangeService, "alice", now.Add(-time.Hour))
+		ex3 = stubBackup("ex3", path.ExchangeService, "bob", now)
+		ex4 = stubBackup("ex4", path.ExchangeService, "bob", now.Add(-time.Hour))
+		ex5 = stubBackup("ex5", path.ExchangeService, "bob", now.Add(-2*time.Hour))
+		od1 = stubBackup("od1", path.OneDriveService, "alice", now)
+		od2 = stubBackup("od2", path.OneDriveService, "alice", now.Add(-time.Hour))
+	)
+
+	service := New(path.ExchangeService, "")
+	service.KeepLast = 1
+
+	bob := New(path.ExchangeService, "bob")
+	bob.KeepLast = 2
+
+	// onedrive has no policy, and is retained in full.
+	expired := Expired(
+		[]*Policy{service, bob},
+		[]*backup.Backup{ex1, ex2, ex3, ex4, ex5, od1, od2})
+
+	assert.ElementsMatch(t, []string{"ex2", "ex5"}, ids(expired))
+}
+
+func (suite *RetentionUnitSuite) TestExpired_noPolicies() {
+	bs := []*backup.Backup{stubBackup("a", path.ExchangeService, "u", now)}
+	assert.Empty(suite.T(), Expired(nil, bs))
+}
+
+func keys(m map[string]struct{}) []string {
+	r := make([]string, 0, len(m))
+	for k := range m {
+		r = append(r, k)
+	}
+
+	return r
+}
@@ -6,15 +6,25 @@ import (
 
 // Options holds the optional configurations for a process
 type Options struct {
-	Collision      CollisionPolicy `json:"-"`
-	DisableMetrics bool            `json:"disableMetrics"`
-	FailFast       bool            `json:"failFast"`
-	ToggleFeatures Toggles         `json:"ToggleFeatures"`
+	Collision      CollisionPolicy   `json:"-"`
+	DisableMetrics bool              `json:"disableMetrics"`
+	Export         ExportConfig      `json:"-"`
+	FailFast       bool              `json:"failFast"`
+	Format         RepoFormat        `json:"-"`
+	Permissions    PermissionsConfig `json:"-"`
+	ToggleFeatures Toggles           `json:"ToggleFeatures"`
+	Versions       VersionsConfig    `json:"-"`
 }
 
 // Defaults provides an Options with the default values set.
 func Defaults() Options {
 	return Options{
+		Collision: Copy,
+		Export: ExportConfig{
+			MailFormat:    EMLFormat,
+			ContactFormat: VCFFormat,
+			EventFormat:   ICSFormat,
+		},
 		FailFast:       true,
 		ToggleFeatures: Toggles{},
 	}
@@ -35,6 +45,37 @@ const (
 	Replace
 )
 
+// ---------------------------------------------------------------------------
+// Restore Permissions
+// ---------------------------------------------------------------------------
+
+// PermissionsConfig describes how the permissions of restored OneDrive and
+// SharePoint items are handled.
+type PermissionsConfig struct {
+	// Skip restores items without the permissions and sharing links that
+	// were granted on them at backup time.
+	Skip bool
+	// PrincipalMap replaces the users that permissions were granted to.
+	// Keys are the email or ID of the backed up user, and values are the
+	// email or ID of the user that is granted the permission instead.
+	PrincipalMap map[string]string
+}
+
+// ---------------------------------------------------------------------------
+// OneDrive Versions
+// ---------------------------------------------------------------------------
+
+// VersionsConfig describes how the version history of OneDrive files is
+// handled.
+type VersionsConfig struct {
+	// Backup stores the prior versions of each file alongside its current
+	// content.
+	Backup bool
+	// Restore is the ID of the prior version of each file to restore instead
+	// of its current content.  Files without that version are not restored.
+	Restore string
+}
+
 // ---------------------------------------------------------------------------
 // Restore Destination
 // ---------------------------------------------------------------------------
@@ -52,7 +93,8 @@ type RestoreDestination struct {
 	// owner of the item.
 	ResourceOwnerOverride string
 	// ContainerName is the name of the root of the restored container hierarchy.
-	// This field must be populated for a restore.
+	// If it is not populated items are restored into their original location,
+	// recreating any containers in the hierarchy that no longer exist.
 	ContainerName string
 }
 
@@ -62,6 +104,67 @@ func DefaultRestoreDestination(timeFormat common.TimeFormat) RestoreDestination
 	}
 }
 
+// RestoreOwner returns the resource owner that items belonging to the
+// provided owner get restored under: the ResourceOwnerOverride, if one is
+// set, or else the original owner.
+func (rd RestoreDestination) RestoreOwner(owner string) string {
+	if len(rd.ResourceOwnerOverride) > 0 {
+		return rd.ResourceOwnerOverride
+	}
+
+	return owner
+}
+
+// ---------------------------------------------------------------------------
+// Export Formats
+// ---------------------------------------------------------------------------
+
+// ExportFormat identifies the file format that exported items are written in.
+type ExportFormat string
+
+const (
+	// JSONFormat writes items as the serialized graph api objects that
+	// were captured during backup.
+	JSONFormat ExportFormat = "json"
+	// EMLFormat writes each mail message as an RFC 5322 .eml file.
+	EMLFormat ExportFormat = "eml"
+	// MboxFormat aggregates the mail messages in each folder into a
+	// single mbox file.
+	MboxFormat ExportFormat = "mbox"
+	// VCFFormat writes each contact as a vCard 4.0 .vcf file.
+	VCFFormat ExportFormat = "vcf"
+	// ICSFormat writes each event as an iCalendar .ics file.
+	ICSFormat ExportFormat = "ics"
+)
+
+// ExportConfig describes how exported data gets written to the local
+// filesystem.
+type ExportConfig struct {
+	// MailFormat is one of JSONFormat, EMLFormat, or MboxFormat.
+	MailFormat ExportFormat
+	// ContactFormat is one of JSONFormat or VCFFormat.
+	ContactFormat ExportFormat
+	// EventFormat is one of JSONFormat or ICSFormat.
+	EventFormat ExportFormat
+}
+
+// ---------------------------------------------------------------------------
+// Repository Format
+// ---------------------------------------------------------------------------
+
+// RepoFormat selects the algorithms that a new repository uses to hash,
+// encrypt, and split the backup data it stores.  Empty values use the
+// defaults.  The format is fixed once the repository is initialized.
+type RepoFormat struct {
+	// Hash identifies the content stored in the repository.
+	Hash string `json:"hash"`
+	// Encryption encrypts the content, e.g. AES256-GCM-HMAC-SHA256 or
+	// CHACHA20-POLY1305-HMAC-SHA256.
+	Encryption string `json:"encryption"`
+	// Splitter breaks large items into chunks for deduplication.
+	Splitter string `json:"splitter"`
+}
+
 // ---------------------------------------------------------------------------
 // Feature Flags and Toggles
 // ---------------------------------------------------------------------------
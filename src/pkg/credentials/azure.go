@@ -0,0 +1,38 @@
+package credentials
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// envvar consts
+const (
+	AzureStorageKey      = "AZURE_STORAGE_KEY"
+	AzureStorageSASToken = "AZURE_STORAGE_SAS_TOKEN"
+)
+
+// AzureStorage aggregates azure blob storage credentials from flag and env_var values.
+type AzureStorage struct {
+	AccountKey string // required, unless SASToken is provided
+	SASToken   string // required, unless AccountKey is provided
+}
+
+// GetAzureStorage is a helper for aggregating azure blob storage secrets and credentials.
+func GetAzureStorage() AzureStorage {
+	// todo (rkeeprs): read from either corso config file or env vars.
+	// https://github.com/alcionai/corso/issues/120
+	return AzureStorage{
+		AccountKey: os.Getenv(AzureStorageKey),
+		SASToken:   os.Getenv(AzureStorageSASToken),
+	}
+}
+
+// Validate ensures that either an account key or a SAS token is present.
+func (c AzureStorage) Validate() error {
+	if len(c.AccountKey) == 0 && len(c.SASToken) == 0 {
+		return errors.Wrap(errMissingRequired, AzureStorageKey+" or "+AzureStorageSASToken)
+	}
+
+	return nil
+}
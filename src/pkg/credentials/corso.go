@@ -8,7 +8,9 @@ import (
 
 // envvar consts
 const (
-	CorsoPassphrase = "CORSO_PASSPHRASE"
+	CorsoPassphrase     = "CORSO_PASSPHRASE"
+	CorsoNewPassphrase  = "CORSO_NEW_PASSPHRASE"
+	CorsoSyncPassphrase = "CORSO_SYNC_PASSPHRASE"
 )
 
 // Corso aggregates corso credentials from flag and env_var values.
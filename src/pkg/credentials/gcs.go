@@ -0,0 +1,29 @@
+package credentials
+
+import (
+	"os"
+)
+
+// envvar consts
+const (
+	GCSCredentialsFile = "GOOGLE_APPLICATION_CREDENTIALS"
+	GCSCredentialsJSON = "GCS_CREDENTIALS_JSON"
+)
+
+// GCS aggregates google cloud storage credentials from flag and env_var values.
+// Both values are optional.  If neither is provided, the application default
+// credentials are used.
+type GCS struct {
+	CredentialsFile string
+	CredentialsJSON string
+}
+
+// GetGCS is a helper for aggregating gcs secrets and credentials.
+func GetGCS() GCS {
+	// todo (rkeeprs): read from either corso config file or env vars.
+	// https://github.com/alcionai/corso/issues/120
+	return GCS{
+		CredentialsFile: os.Getenv(GCSCredentialsFile),
+		CredentialsJSON: os.Getenv(GCSCredentialsJSON),
+	}
+}
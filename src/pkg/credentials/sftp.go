@@ -0,0 +1,25 @@
+package credentials
+
+import (
+	"os"
+)
+
+// envvar consts
+const (
+	SFTPPassword = "SFTP_PASSWORD"
+)
+
+// SFTP aggregates sftp credentials from flag and env_var values.  The
+// password is optional; when it is absent, key file authentication is used.
+type SFTP struct {
+	Password string
+}
+
+// GetSFTP is a helper for aggregating sftp secrets and credentials.
+func GetSFTP() SFTP {
+	// todo (rkeeprs): read from either corso config file or env vars.
+	// https://github.com/alcionai/corso/issues/120
+	return SFTP{
+		Password: os.Getenv(SFTPPassword),
+	}
+}
@@ -2,29 +2,39 @@ package repository
 
 import (
 	"context"
-	"errors"
+	"math/rand"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
 
+	"github.com/alcionai/corso/src/internal/common"
 	"github.com/alcionai/corso/src/internal/events"
 	"github.com/alcionai/corso/src/internal/kopia"
 	"github.com/alcionai/corso/src/internal/model"
 	"github.com/alcionai/corso/src/internal/observe"
 	"github.com/alcionai/corso/src/internal/operations"
 	"github.com/alcionai/corso/src/internal/streamstore"
+	"github.com/alcionai/corso/src/internal/verify"
 	"github.com/alcionai/corso/src/pkg/account"
 	"github.com/alcionai/corso/src/pkg/backup"
 	"github.com/alcionai/corso/src/pkg/backup/details"
+	"github.com/alcionai/corso/src/pkg/backup/retention"
 	"github.com/alcionai/corso/src/pkg/control"
 	"github.com/alcionai/corso/src/pkg/logger"
+	"github.com/alcionai/corso/src/pkg/path"
 	"github.com/alcionai/corso/src/pkg/selectors"
 	"github.com/alcionai/corso/src/pkg/storage"
 	"github.com/alcionai/corso/src/pkg/store"
 )
 
-var ErrorRepoAlreadyExists = errors.New("a repository was already initialized with that configuration")
+var (
+	ErrorRepoAlreadyExists = errors.New("a repository was already initialized with that configuration")
+	// ErrBackupLocked is returned when deleting a backup whose data is still
+	// protected by the storage provider's object lock.
+	ErrBackupLocked = errors.New("backup is locked by the repository's object lock retention")
+)
 
 // BackupGetter deals with retrieving metadata about backups from the
 // repository.
@@ -51,8 +61,24 @@ type Repository interface {
 		sel selectors.Selector,
 		dest control.RestoreDestination,
 	) (operations.RestoreOperation, error)
+	NewExport(
+		ctx context.Context,
+		backupID string,
+		sel selectors.Selector,
+		dir string,
+	) (operations.ExportOperation, error)
 	DeleteBackup(ctx context.Context, id model.StableID) error
 	BackupGetter
+	RetentionPolicies(ctx context.Context) ([]*retention.Policy, error)
+	SetRetentionPolicy(ctx context.Context, p *retention.Policy) error
+	DeleteRetentionPolicy(ctx context.Context, service path.ServiceType, owner string) error
+	PruneBackups(ctx context.Context, dryRun bool) ([]*backup.Backup, error)
+	Maintenance(ctx context.Context, mode kopia.MaintenanceMode, force bool) (*kopia.MaintenanceStats, error)
+	Compression(ctx context.Context) (*kopia.CompressionPolicy, error)
+	SetCompression(ctx context.Context, service path.ServiceType, compressor string) error
+	VerifyBackup(ctx context.Context, id model.StableID, samplePct float64) (*verify.Result, error)
+	SyncBackups(ctx context.Context, dest Repository) ([]*backup.Backup, int, error)
+	Status(ctx context.Context) (*Status, error)
 }
 
 // Repository contains storage provider information.
@@ -89,7 +115,7 @@ func Initialize(
 	opts control.Options,
 ) (Repository, error) {
 	kopiaRef := kopia.NewConn(s)
-	if err := kopiaRef.Initialize(ctx); err != nil {
+	if err := kopiaRef.Initialize(ctx, opts.Format); err != nil {
 		// replace common internal errors so that sdk users can check results with errors.Is()
 		if kopia.IsRepoAlreadyExistsError(err) {
 			return nil, ErrorRepoAlreadyExists
@@ -130,7 +156,13 @@ func Initialize(
 		modelStore: ms,
 	}
 
-	if err := newRepoModel(ctx, ms, r.ID); err != nil {
+	// record the format with defaults resolved, so that it's known exactly.
+	format, err := kopiaRef.Format()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading repository format")
+	}
+
+	if err := newRepoModel(ctx, ms, r.ID, format); err != nil {
 		return nil, errors.New("setting up repository")
 	}
 
@@ -203,6 +235,28 @@ func Connect(
 	}, nil
 }
 
+// UpdatePassphrase will:
+//   - connect to the provider storage
+//   - re-encrypt the repository's keys with the new passphrase
+//   - rewrite the local kopia config to use the new passphrase
+//
+// The storage must still hold the current passphrase.  Fails if the
+// repository is in use by another local operation.
+func UpdatePassphrase(
+	ctx context.Context,
+	s storage.Storage,
+	newPassphrase string,
+) error {
+	kopiaRef := kopia.NewConn(s)
+	if err := kopiaRef.Connect(ctx); err != nil {
+		return err
+	}
+
+	defer kopiaRef.Close(ctx)
+
+	return kopiaRef.UpdatePassword(ctx, newPassphrase)
+}
+
 func (r *repository) Close(ctx context.Context) error {
 	if err := r.Bus.Close(); err != nil {
 		logger.Ctx(ctx).Debugw("closing the event bus", "err", err)
@@ -261,6 +315,25 @@ func (r repository) NewRestore(
 		r.Bus)
 }
 
+// NewExport generates an exportOperation runner.
+func (r repository) NewExport(
+	ctx context.Context,
+	backupID string,
+	sel selectors.Selector,
+	dir string,
+) (operations.ExportOperation, error) {
+	return operations.NewExportOperation(
+		ctx,
+		r.Opts,
+		r.dataLayer,
+		store.NewKopiaStore(r.modelStore),
+		r.Account,
+		model.StableID(backupID),
+		sel,
+		dir,
+		r.Bus)
+}
+
 // backups lists a backup by id
 func (r repository) Backup(ctx context.Context, id model.StableID) (*backup.Backup, error) {
 	sw := store.NewKopiaStore(r.modelStore)
@@ -321,6 +394,15 @@ func (r repository) DeleteBackup(ctx context.Context, id model.StableID) error {
 		return err
 	}
 
+	ol, err := r.dataLayer.ObjectLock()
+	if err != nil {
+		return errors.Wrap(err, "retrieving object lock")
+	}
+
+	if err := checkBackupLock(bu, ol, time.Now()); err != nil {
+		return err
+	}
+
 	if err := r.dataLayer.DeleteSnapshot(ctx, bu.SnapshotID); err != nil {
 		return err
 	}
@@ -330,6 +412,264 @@ func (r repository) DeleteBackup(ctx context.Context, id model.StableID) error {
 	return sw.DeleteBackup(ctx, id)
 }
 
+// RetentionPolicies lists the retention policies in the repository.
+func (r repository) RetentionPolicies(ctx context.Context) ([]*retention.Policy, error) {
+	sw := store.NewKopiaStore(r.modelStore)
+	return sw.GetRetentionPolicies(ctx)
+}
+
+// SetRetentionPolicy stores the retention policy, replacing any existing
+// policy for the same service and resource owner.
+func (r repository) SetRetentionPolicy(ctx context.Context, p *retention.Policy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	sw := store.NewKopiaStore(r.modelStore)
+
+	return sw.PutRetentionPolicy(ctx, p)
+}
+
+// DeleteRetentionPolicy removes the retention policy for the service and
+// resource owner.  An empty owner removes the service-wide policy.
+func (r repository) DeleteRetentionPolicy(
+	ctx context.Context,
+	service path.ServiceType,
+	owner string,
+) error {
+	sw := store.NewKopiaStore(r.modelStore)
+	return sw.DeleteRetentionPolicy(ctx, service, owner)
+}
+
+// PruneBackups evaluates the retention policies against the backups in the
+// repository, and deletes every backup the policies don't retain.  When
+// dryRun is true, nothing is deleted.  Returns the pruned backups.  Backups
+// still protected by the repository's object lock are skipped, and reported
+// with an ErrBackupLocked error once the rest are pruned.
+func (r repository) PruneBackups(ctx context.Context, dryRun bool) ([]*backup.Backup, error) {
+	ps, err := r.RetentionPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ps) == 0 {
+		return nil, nil
+	}
+
+	bs, err := r.BackupsByTag(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ol, err := r.dataLayer.ObjectLock()
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieving object lock")
+	}
+
+	var (
+		expired  = retention.Expired(ps, bs)
+		now      = time.Now()
+		unlocked = make([]*backup.Backup, 0, len(expired))
+		locked   int
+	)
+
+	for _, b := range expired {
+		if checkBackupLock(b, ol, now) != nil {
+			locked++
+			continue
+		}
+
+		unlocked = append(unlocked, b)
+	}
+
+	var lockErr error
+	if locked > 0 {
+		lockErr = errors.Wrapf(ErrBackupLocked, "%d expired backups can't be pruned yet", locked)
+	}
+
+	if dryRun {
+		return unlocked, lockErr
+	}
+
+	pruned := make([]*backup.Backup, 0, len(unlocked))
+
+	for _, b := range unlocked {
+		if err := r.DeleteBackup(ctx, b.ID); err != nil {
+			return pruned, err
+		}
+
+		pruned = append(pruned, b)
+	}
+
+	return pruned, lockErr
+}
+
+// checkBackupLock returns ErrBackupLocked if the data written by the backup
+// is still within the object lock's retention period.  That data is locked
+// for the period from the backup's creation, and for longer if maintenance
+// has since extended the lock.  Data the backup shares with older backups is
+// only locked for as long as maintenance keeps extending it.
+func checkBackupLock(b *backup.Backup, ol kopia.ObjectLock, now time.Time) error {
+	if !ol.Enabled() {
+		return nil
+	}
+
+	until := b.CreationTime.Add(ol.Period)
+	if now.Before(until) {
+		return errors.Wrapf(
+			ErrBackupLocked,
+			"the data written by backup %s is locked until at least %s, so the backup can't be deleted before then",
+			b.ID, common.FormatTabularDisplayTime(until))
+	}
+
+	return nil
+}
+
+// Maintenance runs storage maintenance on the repository, reclaiming the
+// space held by deleted backups.
+func (r repository) Maintenance(
+	ctx context.Context,
+	mode kopia.MaintenanceMode,
+	force bool,
+) (*kopia.MaintenanceStats, error) {
+	return r.dataLayer.Maintenance(ctx, mode, force)
+}
+
+// Compression returns the compressor used for new backup data, along with
+// any per-service overrides.
+func (r repository) Compression(ctx context.Context) (*kopia.CompressionPolicy, error) {
+	return r.dataLayer.Compression(ctx, r.Account.ID())
+}
+
+// SetCompression sets the compressor used for the service's new backup
+// data.  An UnknownService sets the default for all services, and an empty
+// compressor removes the service's override.  Existing backups are not
+// recompressed.
+func (r repository) SetCompression(
+	ctx context.Context,
+	service path.ServiceType,
+	compressor string,
+) error {
+	return r.dataLayer.SetCompression(ctx, r.Account.ID(), service, compressor)
+}
+
+// VerifyBackup checks that the backup's snapshot and details are readable,
+// and reads samplePct percent of its items back out of the repository.
+// Returns an error if the backup can't be found; problems with the backup's
+// data are reported in the result.
+func (r repository) VerifyBackup(
+	ctx context.Context,
+	id model.StableID,
+	samplePct float64,
+) (*verify.Result, error) {
+	b, err := r.Backup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ss := streamstore.New(r.dataLayer, r.Account.ID(), b.Selector.PathService())
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	return verify.Backup(ctx, r.dataLayer, ss, b, samplePct, rnd), nil
+}
+
+// SyncBackups copies every backup that dest doesn't already contain into
+// dest, along with the backup's data and details snapshots.  Backups are
+// matched by their ID, so repeated syncs only copy the backups created since
+// the last one.  Returns the copied backups, and the number of backups that
+// dest already contained.
+func (r repository) SyncBackups(ctx context.Context, dest Repository) ([]*backup.Backup, int, error) {
+	d, ok := dest.(*repository)
+	if !ok {
+		return nil, 0, errors.Errorf("unsupported destination repository type %T", dest)
+	}
+
+	if len(r.ID) > 0 && r.ID == d.ID {
+		return nil, 0, errors.New("the destination is the same repository")
+	}
+
+	bs, err := r.BackupsByTag(ctx)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "listing backups")
+	}
+
+	bms, err := d.modelStore.GetIDsForType(ctx, model.BackupSchema, nil)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "listing destination backups")
+	}
+
+	present := make(map[model.StableID]struct{}, len(bms))
+	for _, bm := range bms {
+		present[bm.ID] = struct{}{}
+	}
+
+	var (
+		copied  = make([]*backup.Backup, 0, len(bs))
+		skipped int
+	)
+
+	for _, b := range bs {
+		if _, ok := present[b.ID]; ok {
+			skipped++
+			continue
+		}
+
+		if err := d.copyBackup(ctx, r, b); err != nil {
+			return copied, skipped, errors.Wrapf(err, "copying backup %s", b.ID)
+		}
+
+		copied = append(copied, b)
+	}
+
+	return copied, skipped, nil
+}
+
+// copyBackup copies the backup's snapshots out of src, then stores the backup
+// model with the IDs of the copies.  The model is written last so that an
+// interrupted copy never leaves a backup referencing missing data.
+func (r repository) copyBackup(ctx context.Context, src repository, b *backup.Backup) error {
+	snapshotID, err := r.dataLayer.CopySnapshot(ctx, src.dataLayer, b.SnapshotID)
+	if err != nil {
+		return errors.Wrap(err, "copying data snapshot")
+	}
+
+	var detailsID string
+
+	if len(b.DetailsID) > 0 {
+		detailsID, err = r.dataLayer.CopySnapshot(ctx, src.dataLayer, b.DetailsID)
+		if err != nil {
+			r.deleteSnapshots(ctx, snapshotID)
+			return errors.Wrap(err, "copying details snapshot")
+		}
+	}
+
+	cp := *b
+	cp.SnapshotID = snapshotID
+	cp.DetailsID = detailsID
+
+	sw := store.NewKopiaStore(r.modelStore)
+
+	if err := sw.Put(ctx, model.BackupSchema, &cp); err != nil {
+		r.deleteSnapshots(ctx, snapshotID, detailsID)
+		return errors.Wrap(err, "storing backup")
+	}
+
+	return nil
+}
+
+// deleteSnapshots makes a best-effort attempt at removing the snapshots.
+func (r repository) deleteSnapshots(ctx context.Context, ids ...string) {
+	for _, id := range ids {
+		if len(id) == 0 {
+			continue
+		}
+
+		if err := r.dataLayer.DeleteSnapshot(ctx, id); err != nil {
+			logger.Ctx(ctx).Errorw("removing partially copied snapshot", "snapshot_id", id, "err", err)
+		}
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Repository ID Model
 // ---------------------------------------------------------------------------
@@ -337,14 +677,22 @@ func (r repository) DeleteBackup(ctx context.Context, id model.StableID) error {
 // repositoryModel identifies the current repository
 type repositoryModel struct {
 	model.BaseModel
+	// Format is empty for repositories initialized before it was recorded.
+	Format control.RepoFormat `json:"format"`
 }
 
 // should only be called on init.
-func newRepoModel(ctx context.Context, ms *kopia.ModelStore, repoID string) error {
+func newRepoModel(
+	ctx context.Context,
+	ms *kopia.ModelStore,
+	repoID string,
+	format control.RepoFormat,
+) error {
 	rm := repositoryModel{
 		BaseModel: model.BaseModel{
 			ID: model.StableID(repoID),
 		},
+		Format: format,
 	}
 
 	return ms.Put(ctx, model.RepositorySchema, &rm)
@@ -362,7 +710,9 @@ func getRepoModel(ctx context.Context, ms *kopia.ModelStore) (*repositoryModel,
 		return rm, nil
 	}
 
-	rm.BaseModel = *bms[0]
+	if err := ms.Get(ctx, model.RepositorySchema, bms[0].ID, rm); err != nil {
+		return nil, err
+	}
 
 	return rm, nil
 }
@@ -217,3 +217,22 @@ func (suite *RepositoryIntegrationSuite) TestNewRestore() {
 	require.NoError(t, err)
 	require.NotNil(t, ro)
 }
+
+func (suite *RepositoryIntegrationSuite) TestNewExport() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+
+	acct := tester.NewM365Account(t)
+
+	// need to initialize the repository before we can test connecting to it.
+	st := tester.NewPrefixedS3Storage(t)
+
+	r, err := repository.Initialize(ctx, acct, st, control.Options{})
+	require.NoError(t, err)
+
+	eo, err := r.NewExport(ctx, "backup-id", selectors.Selector{DiscreteOwner: "test"}, t.TempDir())
+	require.NoError(t, err)
+	require.NotNil(t, eo)
+}
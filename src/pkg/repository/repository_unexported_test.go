@@ -1,14 +1,29 @@
 package repository
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/alcionai/corso/src/internal/kopia"
+	"github.com/alcionai/corso/src/internal/model"
+	"github.com/alcionai/corso/src/internal/stats"
+	"github.com/alcionai/corso/src/internal/streamstore"
 	"github.com/alcionai/corso/src/internal/tester"
+	"github.com/alcionai/corso/src/pkg/account"
+	"github.com/alcionai/corso/src/pkg/backup"
+	"github.com/alcionai/corso/src/pkg/backup/details"
+	"github.com/alcionai/corso/src/pkg/control"
+	"github.com/alcionai/corso/src/pkg/credentials"
+	"github.com/alcionai/corso/src/pkg/path"
+	"github.com/alcionai/corso/src/pkg/selectors"
+	"github.com/alcionai/corso/src/pkg/storage"
+	"github.com/alcionai/corso/src/pkg/store"
 )
 
 type RepositoryModelSuite struct {
@@ -39,7 +54,7 @@ func (suite *RepositoryModelSuite) TestWriteGetModel() {
 		kopiaRef = kopia.NewConn(s)
 	)
 
-	require.NoError(t, kopiaRef.Initialize(ctx))
+	require.NoError(t, kopiaRef.Initialize(ctx, control.RepoFormat{}))
 	require.NoError(t, kopiaRef.Connect(ctx))
 
 	defer kopiaRef.Close(ctx)
@@ -49,9 +64,276 @@ func (suite *RepositoryModelSuite) TestWriteGetModel() {
 
 	defer ms.Close(ctx)
 
-	require.NoError(t, newRepoModel(ctx, ms, "fnords"))
+	format := kopia.DefaultRepoFormat()
+	require.NoError(t, newRepoModel(ctx, ms, "fnords", format))
 
 	got, err := getRepoModel(ctx, ms)
 	require.NoError(t, err)
 	assert.Equal(t, "fnords", string(got.ID))
+	assert.Equal(t, format, got.Format)
+}
+
+type RepositoryUnitSuite struct {
+	suite.Suite
+}
+
+func TestRepositoryUnitSuite(t *testing.T) {
+	suite.Run(t, new(RepositoryUnitSuite))
+}
+
+//revive:disable:context-as-argument
+func newFilesystemRepo(t *testing.T, ctx context.Context) *repository {
+	//revive:enable:context-as-argument
+	acct, err := account.NewAccount(
+		account.ProviderM365,
+		account.M365Config{
+			M365:          credentials.M365{AzureClientID: "id", AzureClientSecret: "secret"},
+			AzureTenantID: "tenant",
+		})
+	require.NoError(t, err)
+
+	r, err := Initialize(
+		ctx,
+		acct,
+		tester.NewFilesystemStorage(t),
+		control.Options{DisableMetrics: true})
+	require.NoError(t, err)
+
+	return r.(*repository)
+}
+
+func (suite *RepositoryUnitSuite) TestSyncBackups() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+	t.Setenv(credentials.CorsoPassphrase, "filesystem-test-passphrase")
+
+	src := newFilesystemRepo(t, ctx)
+	defer src.Close(ctx)
+
+	dest := newFilesystemRepo(t, ctx)
+	defer dest.Close(ctx)
+
+	_, _, err := src.SyncBackups(ctx, src)
+	assert.Error(t, err, "syncing to itself")
+
+	deets := &details.Details{
+		DetailsModel: details.DetailsModel{
+			Entries: []details.DetailsEntry{{RepoRef: "tenant/exchange/user/email/Inbox/item", ShortRef: "sr"}},
+		},
+	}
+
+	sel := selectors.NewExchangeBackup([]string{"user"}).Selector
+	ss := streamstore.New(src.dataLayer, src.Account.ID(), sel.PathService())
+
+	detailsID, err := ss.WriteBackupDetails(ctx, deets)
+	require.NoError(t, err)
+
+	// the details snapshot stands in for the data snapshot.
+	b := backup.New(
+		detailsID, detailsID, "completed",
+		model.StableID(uuid.NewString()),
+		sel,
+		stats.ReadWrites{},
+		stats.StartAndEndTime{})
+	require.NoError(t, store.NewKopiaStore(src.modelStore).Put(ctx, model.BackupSchema, b))
+
+	copied, skipped, err := src.SyncBackups(ctx, dest)
+	require.NoError(t, err)
+	require.Len(t, copied, 1)
+	assert.Equal(t, b.ID, copied[0].ID)
+	assert.Zero(t, skipped)
+
+	got, gotB, err := dest.BackupDetails(ctx, string(b.ID))
+	require.NoError(t, err)
+	assert.Equal(t, deets.Entries, got.Entries)
+	assert.Equal(t, b.Selector.DiscreteOwner, gotB.Selector.DiscreteOwner)
+	assert.NotEqual(t, b.SnapshotID, gotB.SnapshotID)
+	assert.NotEqual(t, b.DetailsID, gotB.DetailsID)
+	assert.Equal(t, b.Tags[model.ServiceTag], gotB.Tags[model.ServiceTag])
+
+	copied, skipped, err = src.SyncBackups(ctx, dest)
+	require.NoError(t, err)
+	assert.Empty(t, copied)
+	assert.Equal(t, 1, skipped)
+}
+
+func (suite *RepositoryUnitSuite) TestStatus() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+	t.Setenv(credentials.CorsoPassphrase, "filesystem-test-passphrase")
+
+	r := newFilesystemRepo(t, ctx)
+	defer r.Close(ctx)
+
+	sel := selectors.NewExchangeBackup([]string{"user"}).Selector
+	ss := streamstore.New(r.dataLayer, r.Account.ID(), sel.PathService())
+
+	detailsID, err := ss.WriteBackupDetails(ctx, &details.Details{})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		b := backup.New(
+			detailsID, detailsID, "completed",
+			model.StableID(uuid.NewString()),
+			sel,
+			stats.ReadWrites{},
+			stats.StartAndEndTime{})
+		require.NoError(t, store.NewKopiaStore(r.modelStore).Put(ctx, model.BackupSchema, b))
+	}
+
+	status, err := r.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, r.ID, status.ID)
+	assert.WithinDuration(t, time.Now(), status.CreatedAt, time.Minute)
+	assert.Equal(t, "Filesystem", status.Provider)
+	assert.Equal(t, kopia.DefaultRepoFormat(), status.Format)
+	assert.Equal(t, storage.Redacted, status.Config["common_corsoPassphrase"])
+	assert.Equal(
+		t,
+		[]BackupCount{{Service: path.ExchangeService.String(), ResourceOwner: "user", Count: 2}},
+		status.Backups)
+	assert.Equal(t, 1, status.Storage.SnapshotCount)
+	assert.Positive(t, status.Storage.StorageBytes)
+}
+
+func (suite *RepositoryUnitSuite) TestInitializeWithFormat() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+	t.Setenv(credentials.CorsoPassphrase, "filesystem-test-passphrase")
+
+	format := control.RepoFormat{
+		Hash:       "BLAKE3-256",
+		Encryption: "CHACHA20-POLY1305-HMAC-SHA256",
+		Splitter:   "FIXED-4M",
+	}
+
+	_, err := Initialize(
+		ctx,
+		account.Account{},
+		tester.NewFilesystemStorage(t),
+		control.Options{DisableMetrics: true, Format: control.RepoFormat{Encryption: "rot13"}})
+	assert.Error(t, err)
+
+	st := tester.NewFilesystemStorage(t)
+
+	r, err := Initialize(ctx, account.Account{}, st, control.Options{DisableMetrics: true, Format: format})
+	require.NoError(t, err)
+	require.NoError(t, r.Close(ctx))
+
+	// the format is recorded, and can be read back after reconnecting.
+	r, err = Connect(ctx, account.Account{}, st, control.Options{DisableMetrics: true})
+	require.NoError(t, err)
+
+	defer r.Close(ctx)
+
+	rm, err := getRepoModel(ctx, r.(*repository).modelStore)
+	require.NoError(t, err)
+	assert.Equal(t, format, rm.Format)
+
+	status, err := r.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, format, status.Format)
+}
+
+func (suite *RepositoryUnitSuite) TestCompression() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+	t.Setenv(credentials.CorsoPassphrase, "filesystem-test-passphrase")
+
+	r := newFilesystemRepo(t, ctx)
+	defer r.Close(ctx)
+
+	require.NoError(t, r.SetCompression(ctx, path.UnknownService, "zstd-fastest"))
+	require.NoError(t, r.SetCompression(ctx, path.ExchangeService, "zstd"))
+	require.NoError(t, r.SetCompression(ctx, path.OneDriveService, "none"))
+	assert.Error(t, r.SetCompression(ctx, path.SharePointService, "not-a-compressor"))
+
+	cp, err := r.Compression(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "zstd-fastest", cp.Default)
+	assert.Equal(
+		t,
+		map[path.ServiceType]string{
+			path.ExchangeService: "zstd",
+			path.OneDriveService: "none",
+		},
+		cp.Services)
+}
+
+func (suite *RepositoryUnitSuite) TestCountBackups() {
+	newBackup := func(service path.ServiceType, owner string) *backup.Backup {
+		return &backup.Backup{
+			BaseModel: model.BaseModel{Tags: map[string]string{model.ServiceTag: service.String()}},
+			Selector:  selectors.Selector{DiscreteOwner: owner},
+		}
+	}
+
+	bs := []*backup.Backup{
+		newBackup(path.OneDriveService, "b"),
+		newBackup(path.ExchangeService, "b"),
+		newBackup(path.ExchangeService, "a"),
+		newBackup(path.ExchangeService, "b"),
+	}
+
+	expect := []BackupCount{
+		{Service: path.ExchangeService.String(), ResourceOwner: "a", Count: 1},
+		{Service: path.ExchangeService.String(), ResourceOwner: "b", Count: 2},
+		{Service: path.OneDriveService.String(), ResourceOwner: "b", Count: 1},
+	}
+
+	assert.Equal(suite.T(), expect, countBackups(bs))
+	assert.Empty(suite.T(), countBackups(nil))
+}
+
+func (suite *RepositoryUnitSuite) TestCheckBackupLock() {
+	var (
+		now = time.Now()
+		b   = &backup.Backup{
+			BaseModel:    model.BaseModel{ID: "id"},
+			CreationTime: now.Add(-48 * time.Hour),
+		}
+	)
+
+	table := []struct {
+		name      string
+		lock      kopia.ObjectLock
+		expectErr assert.ErrorAssertionFunc
+	}{
+		{"no lock", kopia.ObjectLock{}, assert.NoError},
+		{"lock expired", kopia.ObjectLock{Mode: storage.GovernanceRetention, Period: 24 * time.Hour}, assert.NoError},
+		{"locked", kopia.ObjectLock{Mode: storage.ComplianceRetention, Period: 72 * time.Hour}, assert.Error},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			err := checkBackupLock(b, test.lock, now)
+			test.expectErr(t, err)
+
+			if err != nil {
+				assert.ErrorIs(t, err, ErrBackupLocked)
+			}
+		})
+	}
+}
+
+func (suite *RepositoryUnitSuite) TestFilesystemRepoHasNoObjectLock() {
+	ctx, flush := tester.NewContext()
+	defer flush()
+
+	t := suite.T()
+	t.Setenv(credentials.CorsoPassphrase, "filesystem-test-passphrase")
+
+	r := newFilesystemRepo(t, ctx)
+	defer r.Close(ctx)
+
+	ol, err := r.dataLayer.ObjectLock()
+	require.NoError(t, err)
+	assert.False(t, ol.Enabled())
 }
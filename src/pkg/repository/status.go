@@ -0,0 +1,316 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/cli/print"
+	"github.com/alcionai/corso/src/internal/common"
+	"github.com/alcionai/corso/src/internal/kopia"
+	"github.com/alcionai/corso/src/internal/model"
+	"github.com/alcionai/corso/src/pkg/backup"
+	"github.com/alcionai/corso/src/pkg/control"
+)
+
+// Status describes the repository, and summarizes the backups and storage
+// it holds.
+type Status struct {
+	ID        string
+	CreatedAt time.Time
+
+	Provider string
+	// Config is the storage provider config, with secrets redacted.
+	Config map[string]string
+	// Format holds the algorithms used to hash, encrypt, and split the
+	// repository's content.
+	Format control.RepoFormat
+
+	Backups []BackupCount
+	Storage kopia.StorageStats
+}
+
+// BackupCount is the number of backups of one resource owner's data in
+// one service.
+type BackupCount struct {
+	Service       string `json:"service"`
+	ResourceOwner string `json:"resourceOwner"`
+	Count         int    `json:"backups"`
+}
+
+// interface compliance checks
+var (
+	_ print.Printable = &Status{}
+	_ print.Printable = &BackupCount{}
+)
+
+// Status describes the repository and summarizes its backups and storage use.
+func (r repository) Status(ctx context.Context) (*Status, error) {
+	rm, err := getRepoModel(ctx, r.modelStore)
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieving repo info")
+	}
+
+	s := &Status{
+		ID:       string(rm.ID),
+		Provider: r.Storage.Provider.String(),
+		Config:   r.Storage.RedactedConfig(),
+		Format:   rm.Format,
+	}
+
+	// repositories initialized before the format was recorded in the repo
+	// model can still report it from the storage layer.
+	if s.Format == (control.RepoFormat{}) {
+		s.Format, err = r.dataLayer.Format()
+		if err != nil {
+			return nil, errors.Wrap(err, "retrieving repo format")
+		}
+	}
+
+	// repositories created before the repo model existed have no ID.
+	if len(rm.ID) > 0 {
+		s.CreatedAt, err = r.modelStore.ModTime(ctx, model.RepositorySchema, rm.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "retrieving repo creation time")
+		}
+	}
+
+	bs, err := r.BackupsByTag(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing backups")
+	}
+
+	s.Backups = countBackups(bs)
+
+	ss, err := r.dataLayer.StorageStats(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieving storage stats")
+	}
+
+	s.Storage = *ss
+
+	return s, nil
+}
+
+// countBackups counts the backups per service and resource owner, sorted
+// by service, then owner.
+func countBackups(bs []*backup.Backup) []BackupCount {
+	idx := map[BackupCount]int{}
+
+	for _, b := range bs {
+		k := BackupCount{
+			Service:       b.Tags[model.ServiceTag],
+			ResourceOwner: b.Selector.DiscreteOwner,
+		}
+
+		idx[k]++
+	}
+
+	counts := make([]BackupCount, 0, len(idx))
+
+	for k, n := range idx {
+		k.Count = n
+		counts = append(counts, k)
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Service != counts[j].Service {
+			return counts[i].Service < counts[j].Service
+		}
+
+		return counts[i].ResourceOwner < counts[j].ResourceOwner
+	})
+
+	return counts
+}
+
+// --------------------------------------------------------------------------------
+// CLI Output
+// --------------------------------------------------------------------------------
+
+// Print writes the Status to StdOut, in the format requested by the caller.
+func (s Status) Print(ctx context.Context) {
+	if print.JSONFormat() {
+		print.Item(ctx, s)
+		return
+	}
+
+	print.Table(ctx, s.repositoryFields())
+	print.Table(ctx, s.storageFields())
+
+	if len(s.Backups) == 0 {
+		print.Info(ctx, "No backups available")
+		return
+	}
+
+	ps := make([]print.Printable, 0, len(s.Backups))
+	for _, bc := range s.Backups {
+		ps = append(ps, bc)
+	}
+
+	print.Table(ctx, ps)
+}
+
+type StatusPrintable struct {
+	ID               string             `json:"id"`
+	CreatedAt        time.Time          `json:"createdAt"`
+	Provider         string             `json:"provider"`
+	Config           map[string]string  `json:"config"`
+	Format           control.RepoFormat `json:"format"`
+	Backups          []BackupCount      `json:"backups"`
+	SnapshotCount    int                `json:"snapshots"`
+	LogicalBytes     int64              `json:"logicalBytes"`
+	ContentBytes     int64              `json:"contentBytes"`
+	PackedBytes      int64              `json:"packedBytes"`
+	StorageBytes     int64              `json:"storageBytes"`
+	DedupeRatio      float64            `json:"dedupeRatio"`
+	CompressionRatio float64            `json:"compressionRatio"`
+}
+
+// MinimumPrintable reduces the Status to its minimally printable details.
+func (s Status) MinimumPrintable() any {
+	return StatusPrintable{
+		ID:               s.ID,
+		CreatedAt:        s.CreatedAt,
+		Provider:         s.Provider,
+		Config:           s.Config,
+		Format:           s.Format,
+		Backups:          s.Backups,
+		SnapshotCount:    s.Storage.SnapshotCount,
+		LogicalBytes:     s.Storage.LogicalBytes,
+		ContentBytes:     s.Storage.ContentBytes,
+		PackedBytes:      s.Storage.PackedBytes,
+		StorageBytes:     s.Storage.StorageBytes,
+		DedupeRatio:      s.Storage.DedupeRatio(),
+		CompressionRatio: s.Storage.CompressionRatio(),
+	}
+}
+
+// Headers returns the human-readable names of properties in a Status
+// for printing out to a terminal in a columnar display.
+func (s Status) Headers() []string {
+	return []string{
+		"ID",
+		"Created",
+		"Provider",
+		"Backups",
+		"Logical Size",
+		"Stored Size",
+	}
+}
+
+// Values returns the values matching the Headers list for printing
+// out to a terminal in a columnar display.
+func (s Status) Values() []string {
+	var backups int
+	for _, bc := range s.Backups {
+		backups += bc.Count
+	}
+
+	return []string{
+		s.ID,
+		formatCreated(s.CreatedAt),
+		s.Provider,
+		strconv.Itoa(backups),
+		humanize.Bytes(uint64(s.Storage.LogicalBytes)),
+		humanize.Bytes(uint64(s.Storage.StorageBytes)),
+	}
+}
+
+// repositoryFields lists the properties of the repository and its storage
+// provider, for tabular display.
+func (s Status) repositoryFields() []print.Printable {
+	fs := []print.Printable{
+		statusField{"Repository", "ID", s.ID},
+		statusField{"Repository", "Created", formatCreated(s.CreatedAt)},
+		statusField{"Repository", "Hash", s.Format.Hash},
+		statusField{"Repository", "Encryption", s.Format.Encryption},
+		statusField{"Repository", "Splitter", s.Format.Splitter},
+		statusField{"Repository", "Provider", s.Provider},
+	}
+
+	keys := make([]string, 0, len(s.Config))
+	for k := range s.Config {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fs = append(fs, statusField{"Repository", k, s.Config[k]})
+	}
+
+	return fs
+}
+
+// storageFields lists the storage stats, for tabular display.
+func (s Status) storageFields() []print.Printable {
+	ss := s.Storage
+
+	return []print.Printable{
+		statusField{"Storage", "Snapshots", strconv.Itoa(ss.SnapshotCount)},
+		statusField{"Storage", "Logical size", humanize.Bytes(uint64(ss.LogicalBytes))},
+		statusField{"Storage", "Deduplicated size", humanize.Bytes(uint64(ss.ContentBytes))},
+		statusField{"Storage", "Compressed size", humanize.Bytes(uint64(ss.PackedBytes))},
+		statusField{"Storage", "Storage used", humanize.Bytes(uint64(ss.StorageBytes))},
+		statusField{"Storage", "Dedupe ratio", fmt.Sprintf("%.2fx", ss.DedupeRatio())},
+		statusField{"Storage", "Compression ratio", fmt.Sprintf("%.2fx", ss.CompressionRatio())},
+	}
+}
+
+func formatCreated(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+
+	return common.FormatTabularDisplayTime(t)
+}
+
+// statusField is a single property of a Status, for tabular display.
+type statusField struct {
+	section string
+	name    string
+	value   string
+}
+
+func (sf statusField) MinimumPrintable() any {
+	return sf
+}
+
+func (sf statusField) Headers() []string {
+	return []string{sf.section, " "}
+}
+
+func (sf statusField) Values() []string {
+	return []string{sf.name, sf.value}
+}
+
+// MinimumPrintable reduces the BackupCount to its minimally printable details.
+func (bc BackupCount) MinimumPrintable() any {
+	return bc
+}
+
+// Headers returns the human-readable names of properties in a BackupCount
+// for printing out to a terminal in a columnar display.
+func (bc BackupCount) Headers() []string {
+	return []string{
+		"Service",
+		"Resource Owner",
+		"Backups",
+	}
+}
+
+// Values returns the values matching the Headers list for printing
+// out to a terminal in a columnar display.
+func (bc BackupCount) Values() []string {
+	return []string{
+		bc.Service,
+		bc.ResourceOwner,
+		strconv.Itoa(bc.Count),
+	}
+}
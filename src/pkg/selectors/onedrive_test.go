@@ -162,6 +162,8 @@ func (suite *OneDriveSelectorSuite) TestOneDriveRestore_Reduce() {
 		file  = stubRepoRef(path.OneDriveService, path.FilesCategory, "uid", "drive/driveID/root:/folderA/folderB", "file")
 		file2 = stubRepoRef(path.OneDriveService, path.FilesCategory, "uid", "drive/driveID/root:/folderA/folderC", "file2")
 		file3 = stubRepoRef(path.OneDriveService, path.FilesCategory, "uid", "drive/driveID/root:/folderD/folderE", "file3")
+		// drive items are stored under their graph ID
+		file4 = stubRepoRef(path.OneDriveService, path.FilesCategory, "uid", "drive/driveID/root:/folderD", "itemID4")
 	)
 
 	deets := &details.Details{
@@ -191,6 +193,15 @@ func (suite *OneDriveSelectorSuite) TestOneDriveRestore_Reduce() {
 						},
 					},
 				},
+				{
+					RepoRef: file4,
+					ItemInfo: details.ItemInfo{
+						OneDrive: &details.OneDriveInfo{
+							ItemType: details.OneDriveItem,
+							ItemName: "file4",
+						},
+					},
+				},
 			},
 		},
 	}
@@ -213,7 +224,7 @@ func (suite *OneDriveSelectorSuite) TestOneDriveRestore_Reduce() {
 				odr.Include(odr.AllData())
 				return odr
 			},
-			arr(file, file2, file3),
+			arr(file, file2, file3, file4),
 		},
 		{
 			"only match file",
@@ -225,6 +236,26 @@ func (suite *OneDriveSelectorSuite) TestOneDriveRestore_Reduce() {
 			},
 			arr(file2),
 		},
+		{
+			"match file by name",
+			deets,
+			func() *OneDriveRestore {
+				odr := NewOneDriveRestore(Any())
+				odr.Include(odr.Items(Any(), []string{"file4"}))
+				return odr
+			},
+			arr(file4),
+		},
+		{
+			"match file by id",
+			deets,
+			func() *OneDriveRestore {
+				odr := NewOneDriveRestore(Any())
+				odr.Include(odr.Items(Any(), []string{"itemID4"}))
+				return odr
+			},
+			arr(file4),
+		},
 		{
 			"only match folder",
 			deets,
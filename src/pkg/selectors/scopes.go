@@ -434,7 +434,20 @@ func matchesEntry[T scopeT, C categoryT](
 		return sc.matchesInfo(entry.ItemInfo)
 	}
 
-	return matchesPathValues(sc, cat, pathValues, entry.ShortRef)
+	return matchesPathValues(sc, cat, pathValues, entry.ShortRef, entryItemName(entry))
+}
+
+// entryItemName returns the display name of drive items, which are stored
+// under their graph ID.  Returns an empty string for all other items.
+func entryItemName(entry details.DetailsEntry) string {
+	switch {
+	case entry.OneDrive != nil:
+		return entry.OneDrive.ItemName
+	case entry.SharePoint != nil:
+		return entry.SharePoint.ItemName
+	}
+
+	return ""
 }
 
 // matchesPathValues will check whether the pathValues have matching entries
@@ -446,7 +459,7 @@ func matchesPathValues[T scopeT, C categoryT](
 	sc T,
 	cat C,
 	pathValues map[categorizer]string,
-	shortRef string,
+	aliases ...string,
 ) bool {
 	for _, c := range cat.pathKeys() {
 		// resourceOwners are now checked at the beginning of the reduction.
@@ -476,15 +489,16 @@ func matchesPathValues[T scopeT, C categoryT](
 			isLeaf = c.isLeaf()
 		)
 
-		switch {
-		// Leaf category - the scope can match either the path value (the item ID itself),
-		// or the shortRef hash representing the item.
-		case isLeaf && len(shortRef) > 0:
-			match = matches(sc, cc, pathVal) || matches(sc, cc, shortRef)
+		match = matches(sc, cc, pathVal)
 
-		// all other categories (root, folder, etc) just need to pass the filter
-		default:
-			match = matches(sc, cc, pathVal)
+		// Leaf category - the scope can also match one of the item's aliases: the shortRef
+		// hash representing the item, or the display name of drive items.
+		if isLeaf {
+			for _, alias := range aliases {
+				if len(alias) > 0 && !match {
+					match = matches(sc, cc, alias)
+				}
+			}
 		}
 
 		if !match {
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/internal/common"
+	"github.com/alcionai/corso/src/pkg/credentials"
+)
+
+type AzureConfig struct {
+	credentials.AzureStorage // requires: AccountKey or SASToken
+
+	Container   string // required
+	AccountName string // required
+	Prefix      string
+	Endpoint    string
+}
+
+// config key consts
+const (
+	keyAzureContainer   = "azure_container"
+	keyAzureAccountName = "azure_accountName"
+	keyAzureAccountKey  = "azure_accountKey"
+	keyAzureSASToken    = "azure_sasToken"
+	keyAzurePrefix      = "azure_prefix"
+	keyAzureEndpoint    = "azure_endpoint"
+)
+
+// config exported name consts
+const (
+	Container   = "container"
+	AccountName = "accountname"
+)
+
+func (c AzureConfig) Normalize() AzureConfig {
+	return AzureConfig{
+		AzureStorage: c.AzureStorage,
+		Container:    c.Container,
+		AccountName:  c.AccountName,
+		Prefix:       common.NormalizePrefix(c.Prefix),
+		Endpoint:     c.Endpoint,
+	}
+}
+
+// StringConfig transforms an azureConfig struct into a plain
+// map[string]string.  All values in the original struct which
+// serialize into the map are expected to be strings.
+func (c AzureConfig) StringConfig() (map[string]string, error) {
+	cn := c.Normalize()
+	cfg := map[string]string{
+		keyAzureContainer:   cn.Container,
+		keyAzureAccountName: cn.AccountName,
+		keyAzureAccountKey:  cn.AccountKey,
+		keyAzureSASToken:    cn.SASToken,
+		keyAzurePrefix:      cn.Prefix,
+		keyAzureEndpoint:    cn.Endpoint,
+	}
+
+	return cfg, c.validate()
+}
+
+// AzureConfig retrieves the AzureConfig details from the Storage config.
+func (s Storage) AzureConfig() (AzureConfig, error) {
+	c := AzureConfig{}
+
+	if len(s.Config) > 0 {
+		c.Container = orEmptyString(s.Config[keyAzureContainer])
+		c.AccountName = orEmptyString(s.Config[keyAzureAccountName])
+		c.AccountKey = orEmptyString(s.Config[keyAzureAccountKey])
+		c.SASToken = orEmptyString(s.Config[keyAzureSASToken])
+		c.Prefix = orEmptyString(s.Config[keyAzurePrefix])
+		c.Endpoint = orEmptyString(s.Config[keyAzureEndpoint])
+	}
+
+	return c, c.validate()
+}
+
+func (c AzureConfig) validate() error {
+	check := map[string]string{
+		Container:   c.Container,
+		AccountName: c.AccountName,
+	}
+	for k, v := range check {
+		if len(v) == 0 {
+			return errors.Wrap(errMissingRequired, k)
+		}
+	}
+
+	return c.AzureStorage.Validate()
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/pkg/credentials"
+)
+
+type AzureCfgSuite struct {
+	suite.Suite
+}
+
+func TestAzureCfgSuite(t *testing.T) {
+	suite.Run(t, new(AzureCfgSuite))
+}
+
+var goodAzureConfig = AzureConfig{
+	AzureStorage: credentials.AzureStorage{
+		AccountKey: "key",
+	},
+	Container:   "ctr",
+	AccountName: "acct",
+	Prefix:      "pre/",
+	Endpoint:    "http://127.0.0.1:10000/acct",
+}
+
+func (suite *AzureCfgSuite) TestAzureConfig_Config() {
+	az := goodAzureConfig
+	c, err := az.StringConfig()
+	assert.NoError(suite.T(), err)
+
+	table := []struct {
+		key    string
+		expect string
+	}{
+		{keyAzureContainer, az.Container},
+		{keyAzureAccountName, az.AccountName},
+		{keyAzureAccountKey, az.AccountKey},
+		{keyAzureSASToken, az.SASToken},
+		{keyAzurePrefix, az.Prefix},
+		{keyAzureEndpoint, az.Endpoint},
+	}
+	for _, test := range table {
+		assert.Equal(suite.T(), test.expect, c[test.key])
+	}
+}
+
+func (suite *AzureCfgSuite) TestStorage_AzureConfig() {
+	t := suite.T()
+
+	in := goodAzureConfig
+	s, err := NewStorage(ProviderAzure, in)
+	require.NoError(t, err)
+	out, err := s.AzureConfig()
+	require.NoError(t, err)
+
+	assert.Equal(t, in, out)
+}
+
+func (suite *AzureCfgSuite) TestStorage_AzureConfig_invalidCases() {
+	table := []struct {
+		name string
+		cfg  func() AzureConfig
+	}{
+		{
+			name: "missing container",
+			cfg: func() AzureConfig {
+				c := goodAzureConfig
+				c.Container = ""
+				return c
+			},
+		},
+		{
+			name: "missing account name",
+			cfg: func() AzureConfig {
+				c := goodAzureConfig
+				c.AccountName = ""
+				return c
+			},
+		},
+		{
+			name: "missing key and sas token",
+			cfg: func() AzureConfig {
+				c := goodAzureConfig
+				c.AccountKey = ""
+				return c
+			},
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			_, err := NewStorage(ProviderAzure, test.cfg())
+			assert.Error(t, err)
+		})
+	}
+
+	// sas token is an acceptable substitute for the key
+	c := goodAzureConfig
+	c.AccountKey = ""
+	c.SASToken = "sv=2021&sig=abc"
+
+	_, err := NewStorage(ProviderAzure, c)
+	assert.NoError(suite.T(), err)
+}
@@ -42,6 +42,24 @@ func (s Storage) CommonConfig() (CommonConfig, error) {
 	return c, c.validate()
 }
 
+// SetCommonConfig replaces the CommonConfig details in the Storage config.
+func (s *Storage) SetCommonConfig(c CommonConfig) error {
+	cfg, err := c.StringConfig()
+	if err != nil {
+		return err
+	}
+
+	if s.Config == nil {
+		s.Config = map[string]string{}
+	}
+
+	for k, v := range cfg {
+		s.Config[k] = v
+	}
+
+	return nil
+}
+
 // ensures all required properties are present
 func (c CommonConfig) validate() error {
 	if len(c.CorsoPassphrase) == 0 {
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/alcionai/corso/src/pkg/credentials"
@@ -91,3 +92,26 @@ func (suite *CommonCfgSuite) TestStorage_CommonConfig_InvalidCases() {
 		})
 	}
 }
+
+func (suite *CommonCfgSuite) TestStorage_SetCommonConfig() {
+	t := suite.T()
+
+	s, err := storage.NewStorage(storage.ProviderFilesystem, storage.FilesystemConfig{Path: "/tmp/repo"}, goodCommonConfig)
+	require.NoError(t, err)
+
+	in := storage.CommonConfig{
+		Corso:       credentials.Corso{CorsoPassphrase: "other"},
+		KopiaCfgDir: "/tmp/kopia",
+	}
+	require.NoError(t, s.SetCommonConfig(in))
+
+	out, err := s.CommonConfig()
+	require.NoError(t, err)
+	assert.Equal(t, in, out)
+
+	fsCfg, err := s.FilesystemConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/repo", fsCfg.Path)
+
+	assert.Error(t, s.SetCommonConfig(storage.CommonConfig{}))
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+type FilesystemConfig struct {
+	Path string // required
+}
+
+// config key consts
+const (
+	keyFilesystemPath = "filesystem_path"
+)
+
+// config exported name consts
+const (
+	FilesystemPath = "path"
+)
+
+func (c FilesystemConfig) Normalize() FilesystemConfig {
+	p := c.Path
+	if len(p) > 0 {
+		p = filepath.Clean(p)
+	}
+
+	return FilesystemConfig{
+		Path: p,
+	}
+}
+
+// StringConfig transforms a filesystemConfig struct into a plain
+// map[string]string.  All values in the original struct which
+// serialize into the map are expected to be strings.
+func (c FilesystemConfig) StringConfig() (map[string]string, error) {
+	cn := c.Normalize()
+	cfg := map[string]string{
+		keyFilesystemPath: cn.Path,
+	}
+
+	return cfg, c.validate()
+}
+
+// FilesystemConfig retrieves the FilesystemConfig details from the Storage config.
+func (s Storage) FilesystemConfig() (FilesystemConfig, error) {
+	c := FilesystemConfig{}
+
+	if len(s.Config) > 0 {
+		c.Path = orEmptyString(s.Config[keyFilesystemPath])
+	}
+
+	return c, c.validate()
+}
+
+func (c FilesystemConfig) validate() error {
+	check := map[string]string{
+		FilesystemPath: c.Path,
+	}
+	for k, v := range check {
+		if len(v) == 0 {
+			return errors.Wrap(errMissingRequired, k)
+		}
+	}
+
+	return nil
+}
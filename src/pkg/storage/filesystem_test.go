@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type FilesystemCfgSuite struct {
+	suite.Suite
+}
+
+func TestFilesystemCfgSuite(t *testing.T) {
+	suite.Run(t, new(FilesystemCfgSuite))
+}
+
+var goodFilesystemConfig = FilesystemConfig{
+	Path: "/mnt/nas/corso",
+}
+
+func (suite *FilesystemCfgSuite) TestFilesystemConfig_Config() {
+	fs := goodFilesystemConfig
+	c, err := fs.StringConfig()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), fs.Path, c[keyFilesystemPath])
+}
+
+func (suite *FilesystemCfgSuite) TestStorage_FilesystemConfig() {
+	t := suite.T()
+
+	in := goodFilesystemConfig
+	s, err := NewStorage(ProviderFilesystem, in)
+	require.NoError(t, err)
+	out, err := s.FilesystemConfig()
+	require.NoError(t, err)
+
+	assert.Equal(t, in.Path, out.Path)
+}
+
+func (suite *FilesystemCfgSuite) TestStorage_FilesystemConfig_invalidCases() {
+	t := suite.T()
+
+	// missing required properties
+	_, err := NewStorage(ProviderFilesystem, FilesystemConfig{})
+	assert.Error(t, err)
+
+	// required property not populated in storage
+	st, err := NewStorage(ProviderFilesystem, goodFilesystemConfig)
+	require.NoError(t, err)
+
+	st.Config[keyFilesystemPath] = ""
+
+	_, err = st.FilesystemConfig()
+	assert.Error(t, err)
+}
+
+func (suite *FilesystemCfgSuite) TestStorage_FilesystemConfig_Normalize() {
+	st := FilesystemConfig{
+		Path: "/mnt/nas//corso/",
+	}
+
+	result := st.Normalize()
+	assert.Equal(suite.T(), "/mnt/nas/corso", result.Path)
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/internal/common"
+	"github.com/alcionai/corso/src/pkg/credentials"
+)
+
+type GCSConfig struct {
+	credentials.GCS // optional: falls back to application default credentials
+
+	Bucket   string // required
+	Prefix   string
+	Endpoint string
+}
+
+// config key consts
+const (
+	keyGCSBucket          = "gcs_bucket"
+	keyGCSPrefix          = "gcs_prefix"
+	keyGCSEndpoint        = "gcs_endpoint"
+	keyGCSCredentialsFile = "gcs_credentialsFile"
+	keyGCSCredentialsJSON = "gcs_credentialsJSON"
+)
+
+func (c GCSConfig) Normalize() GCSConfig {
+	return GCSConfig{
+		GCS:      c.GCS,
+		Bucket:   common.NormalizeGCSBucket(c.Bucket),
+		Prefix:   common.NormalizePrefix(c.Prefix),
+		Endpoint: c.Endpoint,
+	}
+}
+
+// StringConfig transforms a gcsConfig struct into a plain
+// map[string]string.  All values in the original struct which
+// serialize into the map are expected to be strings.
+func (c GCSConfig) StringConfig() (map[string]string, error) {
+	cn := c.Normalize()
+	cfg := map[string]string{
+		keyGCSBucket:          cn.Bucket,
+		keyGCSPrefix:          cn.Prefix,
+		keyGCSEndpoint:        cn.Endpoint,
+		keyGCSCredentialsFile: cn.CredentialsFile,
+		keyGCSCredentialsJSON: cn.CredentialsJSON,
+	}
+
+	return cfg, c.validate()
+}
+
+// GCSConfig retrieves the GCSConfig details from the Storage config.
+func (s Storage) GCSConfig() (GCSConfig, error) {
+	c := GCSConfig{}
+
+	if len(s.Config) > 0 {
+		c.Bucket = orEmptyString(s.Config[keyGCSBucket])
+		c.Prefix = orEmptyString(s.Config[keyGCSPrefix])
+		c.Endpoint = orEmptyString(s.Config[keyGCSEndpoint])
+		c.CredentialsFile = orEmptyString(s.Config[keyGCSCredentialsFile])
+		c.CredentialsJSON = orEmptyString(s.Config[keyGCSCredentialsJSON])
+	}
+
+	return c, c.validate()
+}
+
+func (c GCSConfig) validate() error {
+	check := map[string]string{
+		Bucket: c.Bucket,
+	}
+	for k, v := range check {
+		if len(v) == 0 {
+			return errors.Wrap(errMissingRequired, k)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/pkg/credentials"
+)
+
+type GCSCfgSuite struct {
+	suite.Suite
+}
+
+func TestGCSCfgSuite(t *testing.T) {
+	suite.Run(t, new(GCSCfgSuite))
+}
+
+var goodGCSConfig = GCSConfig{
+	GCS: credentials.GCS{
+		CredentialsFile: "/tmp/sa.json",
+	},
+	Bucket:   "bkt",
+	Prefix:   "pre/",
+	Endpoint: "http://127.0.0.1:4443",
+}
+
+func (suite *GCSCfgSuite) TestGCSConfig_Config() {
+	gcs := goodGCSConfig
+	c, err := gcs.StringConfig()
+	assert.NoError(suite.T(), err)
+
+	table := []struct {
+		key    string
+		expect string
+	}{
+		{keyGCSBucket, gcs.Bucket},
+		{keyGCSPrefix, gcs.Prefix},
+		{keyGCSEndpoint, gcs.Endpoint},
+		{keyGCSCredentialsFile, gcs.CredentialsFile},
+		{keyGCSCredentialsJSON, gcs.CredentialsJSON},
+	}
+	for _, test := range table {
+		assert.Equal(suite.T(), test.expect, c[test.key])
+	}
+}
+
+func (suite *GCSCfgSuite) TestGCSConfig_Normalize() {
+	c := goodGCSConfig
+	c.Bucket = "gs://bkt"
+	c.Prefix = "pre"
+
+	n := c.Normalize()
+	assert.Equal(suite.T(), "bkt", n.Bucket)
+	assert.Equal(suite.T(), "pre/", n.Prefix)
+}
+
+func (suite *GCSCfgSuite) TestStorage_GCSConfig() {
+	t := suite.T()
+
+	in := goodGCSConfig
+	s, err := NewStorage(ProviderGCS, in)
+	require.NoError(t, err)
+	out, err := s.GCSConfig()
+	require.NoError(t, err)
+
+	assert.Equal(t, in, out)
+}
+
+func (suite *GCSCfgSuite) TestStorage_GCSConfig_invalidCases() {
+	c := goodGCSConfig
+	c.Bucket = ""
+
+	_, err := NewStorage(ProviderGCS, c)
+	assert.Error(suite.T(), err)
+
+	// credentials are optional, falling back to application defaults
+	c = goodGCSConfig
+	c.CredentialsFile = ""
+
+	_, err = NewStorage(ProviderGCS, c)
+	assert.NoError(suite.T(), err)
+}
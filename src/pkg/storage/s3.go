@@ -2,6 +2,8 @@ package storage
 
 import (
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -14,33 +16,59 @@ type S3Config struct {
 	Prefix         string
 	DoNotUseTLS    bool
 	DoNotVerifyTLS bool
+
+	// RetentionMode and RetentionPeriod enable S3 object lock on the blobs
+	// written by a new repository, so that they can't be deleted or
+	// overwritten until the period has passed.  The bucket must have object
+	// lock enabled.  Both are set, or neither.
+	RetentionMode   string // GOVERNANCE or COMPLIANCE
+	RetentionPeriod time.Duration
 }
 
+// S3 object lock retention modes.
+const (
+	// GovernanceRetention locks can be removed by users with the
+	// s3:BypassGovernanceRetention permission.
+	GovernanceRetention = "GOVERNANCE"
+	// ComplianceRetention locks can't be removed by any user, including
+	// the account root user, until they expire.
+	ComplianceRetention = "COMPLIANCE"
+
+	// MinRetentionPeriod is the shortest retention period allowed.
+	MinRetentionPeriod = 24 * time.Hour
+)
+
 // config key consts
 const (
-	keyS3Bucket         = "s3_bucket"
-	keyS3Endpoint       = "s3_endpoint"
-	keyS3Prefix         = "s3_prefix"
-	keyS3DoNotUseTLS    = "s3_donotusetls"
-	keyS3DoNotVerifyTLS = "s3_donotverifytls"
+	keyS3Bucket          = "s3_bucket"
+	keyS3Endpoint        = "s3_endpoint"
+	keyS3Prefix          = "s3_prefix"
+	keyS3DoNotUseTLS     = "s3_donotusetls"
+	keyS3DoNotVerifyTLS  = "s3_donotverifytls"
+	keyS3RetentionMode   = "s3_retentionmode"
+	keyS3RetentionPeriod = "s3_retentionperiod"
 )
 
 // config exported name consts
 const (
-	Bucket         = "bucket"
-	Endpoint       = "endpoint"
-	Prefix         = "prefix"
-	DoNotUseTLS    = "donotusetls"
-	DoNotVerifyTLS = "donotverifytls"
+	Bucket          = "bucket"
+	Endpoint        = "endpoint"
+	Prefix          = "prefix"
+	DoNotUseTLS     = "donotusetls"
+	DoNotVerifyTLS  = "donotverifytls"
+	RetentionMode   = "retentionmode"
+	RetentionPeriod = "retentionperiod"
 )
 
 func (c S3Config) Normalize() S3Config {
 	return S3Config{
-		Bucket:         common.NormalizeBucket(c.Bucket),
-		Endpoint:       c.Endpoint,
-		Prefix:         common.NormalizePrefix(c.Prefix),
-		DoNotUseTLS:    c.DoNotUseTLS,
-		DoNotVerifyTLS: c.DoNotVerifyTLS,
+		Bucket:          common.NormalizeBucket(c.Bucket),
+		Endpoint:        c.Endpoint,
+		Prefix:          common.NormalizePrefix(c.Prefix),
+		DoNotUseTLS:     c.DoNotUseTLS,
+		DoNotVerifyTLS:  c.DoNotVerifyTLS,
+		RetentionMode:   strings.ToUpper(c.RetentionMode),
+		RetentionPeriod: c.RetentionPeriod,
 	}
 }
 
@@ -57,7 +85,12 @@ func (c S3Config) StringConfig() (map[string]string, error) {
 		keyS3DoNotVerifyTLS: strconv.FormatBool(cn.DoNotVerifyTLS),
 	}
 
-	return cfg, c.validate()
+	if cn.ObjectLock() {
+		cfg[keyS3RetentionMode] = cn.RetentionMode
+		cfg[keyS3RetentionPeriod] = cn.RetentionPeriod.String()
+	}
+
+	return cfg, cn.validate()
 }
 
 // S3Config retrieves the S3Config details from the Storage config.
@@ -70,11 +103,26 @@ func (s Storage) S3Config() (S3Config, error) {
 		c.Prefix = orEmptyString(s.Config[keyS3Prefix])
 		c.DoNotUseTLS = common.ParseBool(s.Config[keyS3DoNotUseTLS])
 		c.DoNotVerifyTLS = common.ParseBool(s.Config[keyS3DoNotVerifyTLS])
+		c.RetentionMode = orEmptyString(s.Config[keyS3RetentionMode])
+
+		if p := orEmptyString(s.Config[keyS3RetentionPeriod]); len(p) > 0 {
+			d, err := time.ParseDuration(p)
+			if err != nil {
+				return c, errors.Wrap(err, "parsing s3 retention period")
+			}
+
+			c.RetentionPeriod = d
+		}
 	}
 
 	return c, c.validate()
 }
 
+// ObjectLock is true if the config enables S3 object lock retention.
+func (c S3Config) ObjectLock() bool {
+	return len(c.RetentionMode) > 0 || c.RetentionPeriod != 0
+}
+
 func (c S3Config) validate() error {
 	check := map[string]string{
 		Bucket: c.Bucket,
@@ -85,5 +133,23 @@ func (c S3Config) validate() error {
 		}
 	}
 
+	if !c.ObjectLock() {
+		return nil
+	}
+
+	switch c.RetentionMode {
+	case GovernanceRetention, ComplianceRetention:
+	case "":
+		return errors.Wrap(errMissingRequired, RetentionMode)
+	default:
+		return errors.Errorf(
+			"s3 retention mode must be %s or %s, got %q",
+			GovernanceRetention, ComplianceRetention, c.RetentionMode)
+	}
+
+	if c.RetentionPeriod < MinRetentionPeriod {
+		return errors.Errorf("s3 retention period must be at least %s", MinRetentionPeriod)
+	}
+
 	return nil
 }
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -66,6 +67,24 @@ func (suite *S3CfgSuite) TestStorage_S3Config() {
 	assert.Equal(t, in.Prefix, out.Prefix)
 }
 
+func (suite *S3CfgSuite) TestStorage_S3Config_ObjectLock() {
+	t := suite.T()
+
+	in := goodS3Config
+	in.RetentionMode = GovernanceRetention
+	in.RetentionPeriod = 30 * 24 * time.Hour
+
+	s, err := NewStorage(ProviderS3, in)
+	require.NoError(t, err)
+	out, err := s.S3Config()
+	require.NoError(t, err)
+
+	assert.True(t, out.ObjectLock())
+	assert.Equal(t, in.RetentionMode, out.RetentionMode)
+	assert.Equal(t, in.RetentionPeriod, out.RetentionPeriod)
+	assert.False(t, goodS3Config.ObjectLock())
+}
+
 func makeTestS3Cfg(bkt, end, pre string) S3Config {
 	return S3Config{
 		Bucket:   bkt,
@@ -81,6 +100,10 @@ func (suite *S3CfgSuite) TestStorage_S3Config_invalidCases() {
 		cfg  S3Config
 	}{
 		{"missing bucket", makeTestS3Cfg("", "end", "pre/")},
+		{"retention mode without period", S3Config{Bucket: "bkt", RetentionMode: GovernanceRetention}},
+		{"retention period without mode", S3Config{Bucket: "bkt", RetentionPeriod: 48 * time.Hour}},
+		{"unknown retention mode", S3Config{Bucket: "bkt", RetentionMode: "forever", RetentionPeriod: 48 * time.Hour}},
+		{"short retention period", S3Config{Bucket: "bkt", RetentionMode: GovernanceRetention, RetentionPeriod: time.Hour}},
 	}
 	for _, test := range table {
 		suite.T().Run(test.name, func(t *testing.T) {
@@ -145,6 +168,25 @@ func (suite *S3CfgSuite) TestStorage_S3Config_StringConfig() {
 				keyS3DoNotVerifyTLS: "true",
 			},
 		},
+		{
+			name: "object lock",
+			input: S3Config{
+				Bucket:          "bkt",
+				Endpoint:        "end",
+				Prefix:          "pre/",
+				RetentionMode:   "compliance",
+				RetentionPeriod: 720 * time.Hour,
+			},
+			expect: map[string]string{
+				keyS3Bucket:          "bkt",
+				keyS3Endpoint:        "end",
+				keyS3Prefix:          "pre/",
+				keyS3DoNotUseTLS:     "false",
+				keyS3DoNotVerifyTLS:  "false",
+				keyS3RetentionMode:   ComplianceRetention,
+				keyS3RetentionPeriod: "720h0m0s",
+			},
+		},
 	}
 	for _, test := range table {
 		suite.T().Run(test.name, func(t *testing.T) {
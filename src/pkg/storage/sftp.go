@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"path"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/pkg/credentials"
+)
+
+const defaultSFTPPort = 22
+
+type SFTPConfig struct {
+	credentials.SFTP // optional: falls back to key file authentication
+
+	Host           string // required
+	Port           int
+	Username       string // required
+	KeyFile        string // required, unless a password is provided
+	KnownHostsFile string
+	Path           string // required
+}
+
+// config key consts
+const (
+	keySFTPHost           = "sftp_host"
+	keySFTPPort           = "sftp_port"
+	keySFTPUsername       = "sftp_username"
+	keySFTPKeyFile        = "sftp_keyfile"
+	keySFTPKnownHostsFile = "sftp_knownhostsfile"
+	keySFTPPath           = "sftp_path"
+	keySFTPPassword       = "sftp_password"
+)
+
+// config exported name consts
+const (
+	Host           = "host"
+	Port           = "port"
+	Username       = "username"
+	KeyFile        = "keyfile"
+	KnownHostsFile = "knownhostsfile"
+	SFTPPath       = "sftppath"
+)
+
+func (c SFTPConfig) Normalize() SFTPConfig {
+	port := c.Port
+	if port == 0 {
+		port = defaultSFTPPort
+	}
+
+	p := c.Path
+	if len(p) > 0 {
+		// remote paths are always slash-separated, regardless of the local os.
+		p = path.Clean(p)
+	}
+
+	return SFTPConfig{
+		SFTP:           c.SFTP,
+		Host:           c.Host,
+		Port:           port,
+		Username:       c.Username,
+		KeyFile:        c.KeyFile,
+		KnownHostsFile: c.KnownHostsFile,
+		Path:           p,
+	}
+}
+
+// StringConfig transforms a sftpConfig struct into a plain
+// map[string]string.  All values in the original struct which
+// serialize into the map are expected to be strings.
+func (c SFTPConfig) StringConfig() (map[string]string, error) {
+	cn := c.Normalize()
+	cfg := map[string]string{
+		keySFTPHost:           cn.Host,
+		keySFTPPort:           strconv.Itoa(cn.Port),
+		keySFTPUsername:       cn.Username,
+		keySFTPKeyFile:        cn.KeyFile,
+		keySFTPKnownHostsFile: cn.KnownHostsFile,
+		keySFTPPath:           cn.Path,
+		keySFTPPassword:       cn.Password,
+	}
+
+	return cfg, c.validate()
+}
+
+// SFTPConfig retrieves the SFTPConfig details from the Storage config.
+func (s Storage) SFTPConfig() (SFTPConfig, error) {
+	c := SFTPConfig{}
+
+	if len(s.Config) > 0 {
+		c.Host = orEmptyString(s.Config[keySFTPHost])
+		c.Username = orEmptyString(s.Config[keySFTPUsername])
+		c.KeyFile = orEmptyString(s.Config[keySFTPKeyFile])
+		c.KnownHostsFile = orEmptyString(s.Config[keySFTPKnownHostsFile])
+		c.Path = orEmptyString(s.Config[keySFTPPath])
+		c.Password = orEmptyString(s.Config[keySFTPPassword])
+
+		if p := orEmptyString(s.Config[keySFTPPort]); len(p) > 0 {
+			port, err := strconv.Atoi(p)
+			if err != nil {
+				return c, errors.Wrap(err, "parsing sftp port")
+			}
+
+			c.Port = port
+		}
+	}
+
+	return c, c.validate()
+}
+
+func (c SFTPConfig) validate() error {
+	check := map[string]string{
+		Host:     c.Host,
+		Username: c.Username,
+		SFTPPath: c.Path,
+	}
+	for k, v := range check {
+		if len(v) == 0 {
+			return errors.Wrap(errMissingRequired, k)
+		}
+	}
+
+	if len(c.KeyFile) == 0 && len(c.Password) == 0 {
+		return errors.Wrap(errMissingRequired, KeyFile+" or "+credentials.SFTPPassword)
+	}
+
+	if c.Port < 0 || c.Port > 65535 {
+		return errors.Errorf("invalid sftp port: %d", c.Port)
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/alcionai/corso/src/pkg/credentials"
+)
+
+type SFTPCfgSuite struct {
+	suite.Suite
+}
+
+func TestSFTPCfgSuite(t *testing.T) {
+	suite.Run(t, new(SFTPCfgSuite))
+}
+
+var goodSFTPConfig = SFTPConfig{
+	Host:           "backup.example.com",
+	Port:           2222,
+	Username:       "corso",
+	KeyFile:        "/home/corso/.ssh/id_ed25519",
+	KnownHostsFile: "/home/corso/.ssh/known_hosts",
+	Path:           "/srv/corso",
+}
+
+func (suite *SFTPCfgSuite) TestSFTPConfig_Config() {
+	sftp := goodSFTPConfig
+	c, err := sftp.StringConfig()
+	assert.NoError(suite.T(), err)
+
+	table := []struct {
+		key    string
+		expect string
+	}{
+		{keySFTPHost, sftp.Host},
+		{keySFTPPort, "2222"},
+		{keySFTPUsername, sftp.Username},
+		{keySFTPKeyFile, sftp.KeyFile},
+		{keySFTPKnownHostsFile, sftp.KnownHostsFile},
+		{keySFTPPath, sftp.Path},
+		{keySFTPPassword, sftp.Password},
+	}
+	for _, test := range table {
+		assert.Equal(suite.T(), test.expect, c[test.key])
+	}
+}
+
+func (suite *SFTPCfgSuite) TestSFTPConfig_Normalize() {
+	c := goodSFTPConfig
+	c.Port = 0
+	c.Path = "/srv//corso/"
+
+	n := c.Normalize()
+	assert.Equal(suite.T(), defaultSFTPPort, n.Port)
+	assert.Equal(suite.T(), "/srv/corso", n.Path)
+}
+
+func (suite *SFTPCfgSuite) TestStorage_SFTPConfig() {
+	t := suite.T()
+
+	in := goodSFTPConfig
+	s, err := NewStorage(ProviderSFTP, in)
+	require.NoError(t, err)
+	out, err := s.SFTPConfig()
+	require.NoError(t, err)
+
+	assert.Equal(t, in, out)
+}
+
+func (suite *SFTPCfgSuite) TestStorage_SFTPConfig_invalidCases() {
+	table := []struct {
+		name string
+		cfg  func() SFTPConfig
+	}{
+		{
+			name: "missing host",
+			cfg: func() SFTPConfig {
+				c := goodSFTPConfig
+				c.Host = ""
+				return c
+			},
+		},
+		{
+			name: "missing username",
+			cfg: func() SFTPConfig {
+				c := goodSFTPConfig
+				c.Username = ""
+				return c
+			},
+		},
+		{
+			name: "missing path",
+			cfg: func() SFTPConfig {
+				c := goodSFTPConfig
+				c.Path = ""
+				return c
+			},
+		},
+		{
+			name: "missing key file and password",
+			cfg: func() SFTPConfig {
+				c := goodSFTPConfig
+				c.KeyFile = ""
+				return c
+			},
+		},
+		{
+			name: "invalid port",
+			cfg: func() SFTPConfig {
+				c := goodSFTPConfig
+				c.Port = 70000
+				return c
+			},
+		},
+	}
+	for _, test := range table {
+		suite.T().Run(test.name, func(t *testing.T) {
+			_, err := NewStorage(ProviderSFTP, test.cfg())
+			assert.Error(t, err)
+		})
+	}
+
+	// a password is an acceptable substitute for the key file
+	c := goodSFTPConfig
+	c.KeyFile = ""
+	c.SFTP = credentials.SFTP{Password: "hunter2"}
+
+	_, err := NewStorage(ProviderSFTP, c)
+	assert.NoError(suite.T(), err)
+}
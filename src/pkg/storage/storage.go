@@ -11,8 +11,12 @@ type storageProvider int
 
 //go:generate stringer -type=storageProvider -linecomment
 const (
-	ProviderUnknown storageProvider = iota // Unknown Provider
-	ProviderS3                             // S3
+	ProviderUnknown    storageProvider = iota // Unknown Provider
+	ProviderS3                                // S3
+	ProviderFilesystem                        // Filesystem
+	ProviderAzure                             // Azure
+	ProviderGCS                               // GCS
+	ProviderSFTP                              // SFTP
 )
 
 // storage parsing errors
@@ -28,6 +32,15 @@ const (
 	PrefixKey                 = "PREFIX"
 	DisableTLSKey             = "DISABLE_TLS"
 	DisableTLSVerificationKey = "DISABLE_TLS_VERIFICATION"
+	FilesystemPathKey         = "FILESYSTEM_PATH"
+	ContainerKey              = "AZURE_STORAGE_CONTAINER"
+	AccountNameKey            = "AZURE_STORAGE_ACCOUNT"
+	SFTPHostKey               = "SFTP_HOST"
+	SFTPPortKey               = "SFTP_PORT"
+	SFTPUsernameKey           = "SFTP_USERNAME"
+	SFTPKeyFileKey            = "SFTP_KEY_FILE"
+	SFTPKnownHostsFileKey     = "SFTP_KNOWN_HOSTS_FILE"
+	SFTPPathKey               = "SFTP_PATH"
 )
 
 // Storage defines a storage provider, along with any configuration
@@ -63,3 +76,36 @@ func orEmptyString(v any) string {
 
 	return v.(string)
 }
+
+// Redacted replaces the values of secrets in RedactedConfig.
+const Redacted = "<redacted>"
+
+// config keys whose values are secrets.
+var secretConfigKeys = map[string]struct{}{
+	keyCommonCorsoPassphrase: {},
+	keyAzureAccountKey:       {},
+	keyAzureSASToken:         {},
+	keyGCSCredentialsJSON:    {},
+	keySFTPPassword:          {},
+}
+
+// RedactedConfig returns a copy of the storage config that is safe to
+// display, with the values of secrets replaced by Redacted.  Empty values
+// are omitted.
+func (s Storage) RedactedConfig() map[string]string {
+	rc := make(map[string]string, len(s.Config))
+
+	for k, v := range s.Config {
+		if len(v) == 0 {
+			continue
+		}
+
+		if _, ok := secretConfigKeys[k]; ok {
+			v = Redacted
+		}
+
+		rc[k] = v
+	}
+
+	return rc
+}
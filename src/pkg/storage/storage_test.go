@@ -55,3 +55,24 @@ func (suite *StorageSuite) TestNewStorage() {
 		})
 	}
 }
+
+func (suite *StorageSuite) TestRedactedConfig() {
+	s := Storage{
+		Provider: ProviderSFTP,
+		Config: map[string]string{
+			keyCommonCorsoPassphrase: "passphrase",
+			keyCommonKopiaCfgDir:     "",
+			keySFTPHost:              "host",
+			keySFTPPassword:          "password",
+		},
+	}
+
+	expect := map[string]string{
+		keyCommonCorsoPassphrase: Redacted,
+		keySFTPHost:              "host",
+		keySFTPPassword:          Redacted,
+	}
+
+	assert.Equal(suite.T(), expect, s.RedactedConfig())
+	assert.Equal(suite.T(), "password", s.Config[keySFTPPassword], "original config is unchanged")
+}
@@ -10,11 +10,15 @@ func _() {
 	var x [1]struct{}
 	_ = x[ProviderUnknown-0]
 	_ = x[ProviderS3-1]
+	_ = x[ProviderFilesystem-2]
+	_ = x[ProviderAzure-3]
+	_ = x[ProviderGCS-4]
+	_ = x[ProviderSFTP-5]
 }
 
-const _storageProvider_name = "Unknown ProviderS3"
+const _storageProvider_name = "Unknown ProviderS3FilesystemAzureGCSSFTP"
 
-var _storageProvider_index = [...]uint8{0, 16, 18}
+var _storageProvider_index = [...]uint8{0, 16, 18, 28, 33, 36, 40}
 
 func (i storageProvider) String() string {
 	if i < 0 || i >= storageProvider(len(_storageProvider_index)-1) {
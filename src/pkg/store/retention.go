@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/alcionai/corso/src/internal/kopia"
+	"github.com/alcionai/corso/src/internal/model"
+	"github.com/alcionai/corso/src/pkg/backup/retention"
+	"github.com/alcionai/corso/src/pkg/path"
+)
+
+// GetRetentionPolicies retrieves all retention policies in the model store.
+func (w Wrapper) GetRetentionPolicies(
+	ctx context.Context,
+	filters ...FilterOption,
+) ([]*retention.Policy, error) {
+	q := &queryFilters{}
+	q.populate(filters...)
+
+	bms, err := w.GetIDsForType(ctx, model.RetentionPolicySchema, q.tags)
+	if err != nil {
+		return nil, err
+	}
+
+	ps := make([]*retention.Policy, len(bms))
+
+	for i, bm := range bms {
+		p := &retention.Policy{}
+
+		err := w.GetWithModelStoreID(ctx, model.RetentionPolicySchema, bm.ModelStoreID, p)
+		if err != nil {
+			return nil, err
+		}
+
+		ps[i] = p
+	}
+
+	return ps, nil
+}
+
+// GetRetentionPolicy retrieves the retention policy for the service and
+// resource owner.  An empty owner retrieves the service-wide policy.
+func (w Wrapper) GetRetentionPolicy(
+	ctx context.Context,
+	pst path.ServiceType,
+	owner string,
+) (*retention.Policy, error) {
+	return w.getRetentionPolicy(ctx, pst.String(), owner)
+}
+
+func (w Wrapper) getRetentionPolicy(
+	ctx context.Context,
+	service, owner string,
+) (*retention.Policy, error) {
+	ps, err := w.GetRetentionPolicies(ctx, func(qf *queryFilters) {
+		qf.tags[model.ServiceTag] = service
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range ps {
+		if p.ResourceOwner == owner {
+			return p, nil
+		}
+	}
+
+	return nil, errors.Wrap(kopia.ErrNotFound, "getting retention policy")
+}
+
+// PutRetentionPolicy stores the policy, replacing any existing policy for
+// the same service and resource owner.
+func (w Wrapper) PutRetentionPolicy(ctx context.Context, p *retention.Policy) error {
+	prev, err := w.getRetentionPolicy(ctx, p.Service, p.ResourceOwner)
+	if err != nil && !errors.Is(err, kopia.ErrNotFound) {
+		return err
+	}
+
+	if prev == nil {
+		return errors.Wrap(w.Put(ctx, model.RetentionPolicySchema, p), "storing retention policy")
+	}
+
+	p.ID = prev.ID
+	p.ModelStoreID = prev.ModelStoreID
+	p.Version = prev.Version
+
+	return errors.Wrap(w.Update(ctx, model.RetentionPolicySchema, p), "updating retention policy")
+}
+
+// DeleteRetentionPolicy deletes the retention policy for the service and
+// resource owner from the model store.
+func (w Wrapper) DeleteRetentionPolicy(
+	ctx context.Context,
+	pst path.ServiceType,
+	owner string,
+) error {
+	p, err := w.GetRetentionPolicy(ctx, pst, owner)
+	if err != nil {
+		return err
+	}
+
+	return w.Delete(ctx, model.RetentionPolicySchema, p.ID)
+}